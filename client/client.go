@@ -40,6 +40,8 @@ type Client struct {
 	Incidents *IncidentsService
 	// Playbooks is a collection of methods used to interact with playbooks.
 	Playbooks *PlaybooksService
+	// Rotations is a collection of methods used to interact with on-call rotations.
+	Rotations *RotationsService
 }
 
 // New creates a new instance of Client using the configuration from the given Mattermost Client.
@@ -62,6 +64,7 @@ func newClient(mattermostSiteURL string, httpClient *http.Client) (*Client, erro
 	c := &Client{client: httpClient, BaseURL: siteURL, UserAgent: userAgent}
 	c.Incidents = &IncidentsService{c}
 	c.Playbooks = &PlaybooksService{c}
+	c.Rotations = &RotationsService{c}
 	return c, nil
 }
 
@@ -4,29 +4,108 @@ import "time"
 
 // Incident represents an incident.
 type Incident struct {
-	ID                      string          `json:"id"`
-	Name                    string          `json:"name"`
-	Description             string          `json:"description"`
-	OwnerUserID             string          `json:"owner_user_id"`
-	ReporterUserID          string          `json:"reporter_user_id"`
-	TeamID                  string          `json:"team_id"`
-	ChannelID               string          `json:"channel_id"`
-	CreateAt                int64           `json:"create_at"`
-	EndAt                   int64           `json:"end_at"`
-	DeleteAt                int64           `json:"delete_at"`
-	ActiveStage             int             `json:"active_stage"`
-	ActiveStageTitle        string          `json:"active_stage_title"`
-	PostID                  string          `json:"post_id"`
-	PlaybookID              string          `json:"playbook_id"`
-	Checklists              []Checklist     `json:"checklists"`
-	StatusPosts             []StatusPost    `json:"status_posts"`
-	ReminderPostID          string          `json:"reminder_post_id"`
-	PreviousReminder        time.Duration   `json:"previous_reminder"`
-	BroadcastChannelID      string          `json:"broadcast_channel_id"`
-	ReminderMessageTemplate string          `json:"reminder_message_template"`
-	InvitedUserIDs          []string        `json:"invited_user_ids"`
-	InvitedGroupIDs         []string        `json:"invited_group_ids"`
-	TimelineEvents          []TimelineEvent `json:"timeline_events"`
+	ID                      string                `json:"id"`
+	Name                    string                `json:"name"`
+	Description             string                `json:"description"`
+	OwnerUserID             string                `json:"owner_user_id"`
+	ReporterUserID          string                `json:"reporter_user_id"`
+	TeamID                  string                `json:"team_id"`
+	ChannelID               string                `json:"channel_id"`
+	CreateAt                int64                 `json:"create_at"`
+	EndAt                   int64                 `json:"end_at"`
+	DeleteAt                int64                 `json:"delete_at"`
+	ActiveStage             int                   `json:"active_stage"`
+	ActiveStageTitle        string                `json:"active_stage_title"`
+	PostID                  string                `json:"post_id"`
+	PlaybookID              string                `json:"playbook_id"`
+	PlaybookRevision        int                   `json:"playbook_revision"`
+	Checklists              []Checklist           `json:"checklists"`
+	StatusPosts             []StatusPost          `json:"status_posts"`
+	ReminderPostID          string                `json:"reminder_post_id"`
+	PreviousReminder        time.Duration         `json:"previous_reminder"`
+	BroadcastChannelID      string                `json:"broadcast_channel_id"`
+	ReminderMessageTemplate string                `json:"reminder_message_template"`
+	InvitedUserIDs          []string              `json:"invited_user_ids"`
+	InvitedGroupIDs         []string              `json:"invited_group_ids"`
+	TimelineEvents          []TimelineEvent       `json:"timeline_events"`
+	Severity                string                `json:"severity"`
+	OverdueItems            []OverdueItem         `json:"overdue_items"`
+	CustomFields            []CustomField         `json:"custom_fields"`
+	CustomFieldValues       map[string][]string   `json:"custom_field_values"`
+	Roles                   []Role                `json:"roles"`
+	RoleAssignments         map[string][]string   `json:"role_assignments"`
+	AcknowledgedAt          int64                 `json:"acknowledged_at"`
+	EscalationLevel         int                   `json:"escalation_level"`
+	Links                   []LinkedIncident      `json:"links"`
+	MergedIntoID            string                `json:"merged_into_incident_id"`
+	StatusUpdateTemplate    string                `json:"status_update_template"`
+	BroadcastTargets        []BroadcastTarget     `json:"broadcast_targets"`
+	FollowerIDs             []string              `json:"follower_ids"`
+	RetrospectiveSections   RetrospectiveSections `json:"retrospective_sections"`
+	ActionItems             []ActionItem          `json:"action_items"`
+	RetrospectiveTemplate   string                `json:"retrospective_template"`
+}
+
+// LinkType describes the relation of an incident to a linked incident.
+type LinkType string
+
+const (
+	LinkRelated      LinkType = "related"
+	LinkDuplicateOf  LinkType = "duplicate_of"
+	LinkDuplicatedBy LinkType = "duplicated_by"
+	LinkParentOf     LinkType = "parent_of"
+	LinkChildOf      LinkType = "child_of"
+)
+
+// LinkedIncident is a link of an incident to another incident, seen from the first one.
+type LinkedIncident struct {
+	LinkID        string   `json:"link_id"`
+	Type          LinkType `json:"type"`
+	IncidentID    string   `json:"incident_id"`
+	Name          string   `json:"name"`
+	ChannelID     string   `json:"channel_id"`
+	CurrentStatus string   `json:"current_status"`
+	CreatorUserID string   `json:"creator_user_id"`
+	CreateAt      int64    `json:"create_at"`
+}
+
+// OverdueItem identifies a checklist item of an incident that is past its due date.
+type OverdueItem struct {
+	ChecklistNum int    `json:"checklist_num"`
+	ItemNum      int    `json:"item_num"`
+	Title        string `json:"title"`
+	AssigneeID   string `json:"assignee_id"`
+	AssigneeRole string `json:"assignee_role"`
+	DueAt        int64  `json:"due_at"`
+}
+
+// RetrospectiveSections is the structured part of the retrospective of an incident.
+type RetrospectiveSections struct {
+	Summary   string `json:"summary"`
+	RootCause string `json:"root_cause"`
+	Impact    string `json:"impact"`
+	WentWell  string `json:"what_went_well"`
+}
+
+// ActionItem is a follow-up task of the retrospective of an incident.
+type ActionItem struct {
+	ID              string `json:"id"`
+	IncidentID      string `json:"incident_id"`
+	Title           string `json:"title"`
+	OwnerUserID     string `json:"owner_user_id"`
+	DueAt           int64  `json:"due_at"`
+	CompletedAt     int64  `json:"completed_at"`
+	OverdueNotified bool   `json:"overdue_notified"`
+	CreatorUserID   string `json:"creator_user_id"`
+	CreateAt        int64  `json:"create_at"`
+	UpdateAt        int64  `json:"update_at"`
+}
+
+// ActionItemOptions specifies the parameters to create or update an action item.
+type ActionItemOptions struct {
+	Title       string `json:"title"`
+	OwnerUserID string `json:"owner_user_id,omitempty"`
+	DueAt       int64  `json:"due_at,omitempty"`
 }
 
 // StatusPost is information added to the incident when selecting from the db and sent to the
@@ -56,6 +135,10 @@ const (
 	OwnerChanged      TimelineEventType = "owner_changed"
 	AssigneeChanged   TimelineEventType = "assignee_changed"
 	RanSlashCommand   TimelineEventType = "ran_slash_command"
+	OwnerAcknowledged TimelineEventType = "owner_acknowledged"
+	IncidentEscalated TimelineEventType = "incident_escalated"
+	IncidentMerged    TimelineEventType = "incident_merged"
+	ManualEvent       TimelineEventType = "manual_event"
 )
 
 // TimelineEvent represents an event recorded to an incident's timeline.
@@ -73,6 +156,84 @@ type TimelineEvent struct {
 	CreatorUserID string            `json:"creator_user_id"`
 }
 
+// TimelineEventOptions specifies the time, summary and details of a timeline event created, or
+// edited, by a user.
+type TimelineEventOptions struct {
+	EventAt int64  `json:"event_at"` // When creating an event, 0 stands for now.
+	Summary string `json:"summary"`
+	Details string `json:"details"`
+}
+
+// TimelineEventEdit records an edit of a timeline event and the values the event had before it.
+type TimelineEventEdit struct {
+	ID              string `json:"id"`
+	EventID         string `json:"event_id"`
+	IncidentID      string `json:"incident_id"`
+	EditorUserID    string `json:"editor_user_id"`
+	EditAt          int64  `json:"edit_at"`
+	PreviousEventAt int64  `json:"previous_event_at"`
+	PreviousSummary string `json:"previous_summary"`
+	PreviousDetails string `json:"previous_details"`
+}
+
+// TimelineExportFormat is a file format to which the timeline of an incident can be exported.
+type TimelineExportFormat string
+
+const (
+	TimelineExportFormatMarkdown TimelineExportFormat = "md"
+	TimelineExportFormatCSV      TimelineExportFormat = "csv"
+	TimelineExportFormatICS      TimelineExportFormat = "ics"
+)
+
+// TimelineExportOptions specifies the format of a timeline export, and which events it includes.
+type TimelineExportOptions struct {
+	// Format of the export; defaults to markdown.
+	Format TimelineExportFormat `url:"format,omitempty"`
+
+	// EventTypes restricts the export to the events of the given types. Defaults to every type.
+	EventTypes []string `url:"event_type,omitempty"`
+
+	// Since and Until restrict the export to the events that happened between them, in
+	// milliseconds. 0 stands for no bound.
+	Since int64 `url:"since,omitempty"`
+	Until int64 `url:"until,omitempty"`
+}
+
+// WebhookDeliveryStatus describes the state of an outgoing webhook delivery.
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery represents an outgoing webhook request sent on behalf of an incident.
+type WebhookDelivery struct {
+	ID            string                   `json:"id"`
+	IncidentID    string                   `json:"incident_id"`
+	EventType     string                   `json:"event_type"`
+	URL           string                   `json:"url"`
+	Status        WebhookDeliveryStatus    `json:"status"`
+	NumAttempts   int                      `json:"num_attempts"`
+	NextAttemptAt int64                    `json:"next_attempt_at"`
+	CreateAt      int64                    `json:"create_at"`
+	UpdateAt      int64                    `json:"update_at"`
+	Attempts      []WebhookDeliveryAttempt `json:"attempts"`
+}
+
+// WebhookDeliveryAttempt represents a single attempt to deliver an outgoing webhook.
+type WebhookDeliveryAttempt struct {
+	ID         string `json:"id"`
+	DeliveryID string `json:"delivery_id"`
+	IncidentID string `json:"incident_id"`
+	Attempt    int    `json:"attempt"`
+	StatusCode int    `json:"status_code"`
+	LatencyMS  int64  `json:"latency_ms"`
+	Error      string `json:"error"`
+	CreateAt   int64  `json:"create_at"`
+}
+
 // IncidentCreateOptions specifies the parameters for IncidentsService.Create method.
 type IncidentCreateOptions struct {
 	Name        string `json:"name"`
@@ -81,6 +242,10 @@ type IncidentCreateOptions struct {
 	Description string `json:"description"`
 	PostID      string `json:"post_id"`
 	PlaybookID  string `json:"playbook_id"`
+	Severity    string `json:"severity"`
+
+	// CustomFieldValues are the values of the custom fields of the playbook, indexed by key.
+	CustomFieldValues map[string][]string `json:"custom_field_values"`
 }
 
 // Sort enumerates the available fields we can sort on.
@@ -105,6 +270,9 @@ const (
 	// SortByEndAt sorts by the "end_at" field.
 	SortByEndAt Sort = "end_at"
 
+	// SortBySeverity sorts by the "severity" field.
+	SortBySeverity Sort = "severity"
+
 	// SortBySteps sorts playbooks by the number of steps in the playbook.
 	SortBySteps Sort = "steps"
 
@@ -152,6 +320,21 @@ type IncidentListOptions struct {
 	// PlaybookID filters incidents that are derived from this playbook id.
 	// Defaults to blank (no filter).
 	PlaybookID string `url:"playbook_id,omitempty"`
+
+	// Severity filters incidents with this severity level. Defaults to blank (no filter).
+	Severity string `url:"severity,omitempty"`
+
+	// CustomFields filters incidents by the values of their custom fields, each given as
+	// key:value. Defaults to empty (no filter).
+	CustomFields []string `url:"custom_field,omitempty"`
+
+	// Role filters incidents in which the role with this key is assigned to a user. Defaults to
+	// blank (no filter).
+	Role string `url:"role,omitempty"`
+
+	// RoleUserID, together with Role, filters incidents in which the role is assigned to this
+	// user. Defaults to blank (any user).
+	RoleUserID string `url:"role_user_id,omitempty"`
 }
 
 // IncidentList contains the paginated result.
@@ -170,6 +353,7 @@ const (
 	StatusActive   Status = "Active"
 	StatusResolved Status = "Resolved"
 	StatusArchived Status = "Archived"
+	StatusMerged   Status = "Merged"
 )
 
 type GetIncidentsResults struct {
@@ -180,10 +364,77 @@ type GetIncidentsResults struct {
 	Disabled   bool       `json:"disabled"`
 }
 
+// ActionItemStatus is the type used to filter action items by status.
+type ActionItemStatus string
+
+const (
+	ActionItemOpen    ActionItemStatus = "open"
+	ActionItemDone    ActionItemStatus = "done"
+	ActionItemOverdue ActionItemStatus = "overdue"
+)
+
+// ActionItemListOptions specifies the optional parameters to the
+// IncidentsService.ListActionItems method.
+type ActionItemListOptions struct {
+	// TeamID filters action items to those of the incidents in the given team.
+	TeamID string `url:"team_id,omitempty"`
+
+	// IncidentID filters action items to those of the given incident.
+	IncidentID string `url:"incident_id,omitempty"`
+
+	// OwnerID filters by owner's Mattermost user ID. Defaults to blank (no filter).
+	OwnerID string `url:"owner_user_id,omitempty"`
+
+	// Status filters by Open, Done or Overdue. Defaults to blank (no filter).
+	Status ActionItemStatus `url:"status,omitempty"`
+}
+
+// GetActionItemsResults contains a page of action items.
+type GetActionItemsResults struct {
+	TotalCount int          `json:"total_count"`
+	PageCount  int          `json:"page_count"`
+	HasMore    bool         `json:"has_more"`
+	Items      []ActionItem `json:"items"`
+}
+
+// TimelineListOptions specifies the page of the timeline of an incident to get, and which events
+// it includes.
+type TimelineListOptions struct {
+	// Cursor is the NextCursor of the previous page; blank for the first page.
+	Cursor string `url:"cursor,omitempty"`
+
+	// PerPage is the number of events in the page; defaults to 100, and is at most 1000.
+	PerPage int `url:"per_page,omitempty"`
+
+	// Direction orders the events by time; defaults to SortAsc, oldest first.
+	Direction SortDirection `url:"direction,omitempty"`
+
+	// EventTypes filters by event type. Defaults to empty (no filter).
+	EventTypes []TimelineEventType `url:"event_type,omitempty"`
+
+	// UserID filters to the events whose subject or creator is the given user. Defaults to
+	// blank (no filter).
+	UserID string `url:"user_id,omitempty"`
+
+	// ExcludeUserJoinedLeft leaves out the events of users joining or leaving the channel.
+	ExcludeUserJoinedLeft bool `url:"exclude_user_joined_left,omitempty"`
+}
+
+// GetTimelineEventsResults contains a page of the timeline of an incident.
+type GetTimelineEventsResults struct {
+	HasMore    bool            `json:"has_more"`
+	NextCursor string          `json:"next_cursor"`
+	Items      []TimelineEvent `json:"items"`
+}
+
 // StatusUpdateOptions are the fields required to update an incident's status
 type StatusUpdateOptions struct {
 	Status            Status `json:"status"`
 	Description       string `json:"description"`
 	Message           string `json:"message"`
 	ReminderInSeconds int64  `json:"reminder"`
+
+	// CustomFieldValues replaces the values of the custom fields of the incident, indexed by key.
+	// Nil leaves the values unchanged.
+	CustomFieldValues map[string][]string `json:"custom_field_values,omitempty"`
 }
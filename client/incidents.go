@@ -6,6 +6,7 @@ package client
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 )
 
@@ -69,6 +70,24 @@ func (s *IncidentsService) GetMetadata(ctx context.Context, incidentID string) (
 	return incident, nil
 }
 
+// GetWebhookDeliveries gets the outgoing webhook deliveries of an incident, with every attempt made.
+func (s *IncidentsService) GetWebhookDeliveries(ctx context.Context, incidentID string) ([]WebhookDelivery, error) {
+	deliveriesURL := fmt.Sprintf("incidents/%s/webhook-deliveries", incidentID)
+	req, err := s.client.newRequest(http.MethodGet, deliveriesURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var deliveries []WebhookDelivery
+	resp, err := s.client.do(ctx, req, &deliveries)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	return deliveries, nil
+}
+
 // List the incidents.
 func (s *IncidentsService) List(ctx context.Context, page, perPage int, opts IncidentListOptions) (*GetIncidentsResults, error) {
 	incidentURL := "incidents"
@@ -118,6 +137,362 @@ func (s *IncidentsService) Create(ctx context.Context, opts IncidentCreateOption
 	return incident, nil
 }
 
+// AssignRole assigns the role of an incident with the key roleKey to the users userIDs, replacing
+// its current users. An empty userIDs unassigns the role.
+func (s *IncidentsService) AssignRole(ctx context.Context, incidentID, roleKey string, userIDs []string) error {
+	roleURL := fmt.Sprintf("incidents/%s/roles/%s", incidentID, roleKey)
+	req, err := s.client.newRequest(http.MethodPut, roleURL, struct {
+		UserIDs []string `json:"user_ids"`
+	}{userIDs})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.do(ctx, req, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Acknowledge acknowledges an incident on behalf of the current user, which stops its escalation.
+func (s *IncidentsService) Acknowledge(ctx context.Context, incidentID string) error {
+	acknowledgeURL := fmt.Sprintf("incidents/%s/acknowledge", incidentID)
+	req, err := s.client.newRequest(http.MethodPost, acknowledgeURL, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.do(ctx, req, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Link links an incident to another incident, so that incidentID is <linkType> linkedIncidentID,
+// and returns the ID of the new link.
+func (s *IncidentsService) Link(ctx context.Context, incidentID, linkedIncidentID string, linkType LinkType) (string, error) {
+	linkURL := fmt.Sprintf("incidents/%s/links", incidentID)
+	req, err := s.client.newRequest(http.MethodPost, linkURL, struct {
+		LinkedIncidentID string   `json:"linked_incident_id"`
+		Type             LinkType `json:"type"`
+	}{linkedIncidentID, linkType})
+	if err != nil {
+		return "", err
+	}
+
+	var result struct {
+		ID string `json:"id"`
+	}
+	resp, err := s.client.do(ctx, req, &result)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("expected status code %d", http.StatusCreated)
+	}
+
+	return result.ID, nil
+}
+
+// Unlink removes the link with the ID linkID from an incident and from the incident it links to.
+func (s *IncidentsService) Unlink(ctx context.Context, incidentID, linkID string) error {
+	linkURL := fmt.Sprintf("incidents/%s/links/%s", incidentID, linkID)
+	req, err := s.client.newRequest(http.MethodDelete, linkURL, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.do(ctx, req, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Merge merges an incident into the target incident: its timeline, status posts and channel
+// members are added to the target, and its status becomes StatusMerged.
+func (s *IncidentsService) Merge(ctx context.Context, incidentID, targetIncidentID string) error {
+	mergeURL := fmt.Sprintf("incidents/%s/merge", incidentID)
+	req, err := s.client.newRequest(http.MethodPost, mergeURL, struct {
+		TargetIncidentID string `json:"target_incident_id"`
+	}{targetIncidentID})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.do(ctx, req, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Follow subscribes the current user to the status updates of an incident, which are sent by
+// direct message.
+func (s *IncidentsService) Follow(ctx context.Context, incidentID string) error {
+	followURL := fmt.Sprintf("incidents/%s/followers", incidentID)
+	req, err := s.client.newRequest(http.MethodPut, followURL, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.do(ctx, req, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Unfollow unsubscribes the current user from the status updates of an incident.
+func (s *IncidentsService) Unfollow(ctx context.Context, incidentID string) error {
+	followURL := fmt.Sprintf("incidents/%s/followers", incidentID)
+	req, err := s.client.newRequest(http.MethodDelete, followURL, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.do(ctx, req, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateRetrospectiveSections replaces the structured sections of the retrospective of an
+// incident.
+func (s *IncidentsService) UpdateRetrospectiveSections(ctx context.Context, incidentID string, sections RetrospectiveSections) error {
+	sectionsURL := fmt.Sprintf("incidents/%s/retrospective/sections", incidentID)
+	req, err := s.client.newRequest(http.MethodPut, sectionsURL, sections)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.do(ctx, req, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AddActionItem adds an action item to the retrospective of an incident and returns its ID.
+func (s *IncidentsService) AddActionItem(ctx context.Context, incidentID string, opts ActionItemOptions) (string, error) {
+	itemsURL := fmt.Sprintf("incidents/%s/action-items", incidentID)
+	req, err := s.client.newRequest(http.MethodPost, itemsURL, opts)
+	if err != nil {
+		return "", err
+	}
+
+	var result struct {
+		ID string `json:"id"`
+	}
+	resp, err := s.client.do(ctx, req, &result)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("expected status code %d", http.StatusCreated)
+	}
+
+	return result.ID, nil
+}
+
+// UpdateActionItem changes the title, owner and due date of an action item of an incident.
+func (s *IncidentsService) UpdateActionItem(ctx context.Context, incidentID, itemID string, opts ActionItemOptions) error {
+	itemURL := fmt.Sprintf("incidents/%s/action-items/%s", incidentID, itemID)
+	req, err := s.client.newRequest(http.MethodPut, itemURL, opts)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.do(ctx, req, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SetActionItemDone completes, or reopens, an action item of an incident.
+func (s *IncidentsService) SetActionItemDone(ctx context.Context, incidentID, itemID string, done bool) error {
+	stateURL := fmt.Sprintf("incidents/%s/action-items/%s/state", incidentID, itemID)
+	req, err := s.client.newRequest(http.MethodPut, stateURL, struct {
+		Done bool `json:"done"`
+	}{done})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.do(ctx, req, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RemoveActionItem removes an action item from an incident.
+func (s *IncidentsService) RemoveActionItem(ctx context.Context, incidentID, itemID string) error {
+	itemURL := fmt.Sprintf("incidents/%s/action-items/%s", incidentID, itemID)
+	req, err := s.client.newRequest(http.MethodDelete, itemURL, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.do(ctx, req, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ListActionItems lists the action items of the incidents of a team, most urgent first.
+func (s *IncidentsService) ListActionItems(ctx context.Context, page, perPage int, opts ActionItemListOptions) (*GetActionItemsResults, error) {
+	itemsURL := "action-items"
+	itemsURL, err := addOptions(itemsURL, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build options: %w", err)
+	}
+	itemsURL, err = addPaginationOptions(itemsURL, page, perPage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build pagination options: %w", err)
+	}
+
+	req, err := s.client.newRequest(http.MethodGet, itemsURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	result := &GetActionItemsResults{}
+	resp, err := s.client.do(ctx, req, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	resp.Body.Close()
+
+	return result, nil
+}
+
+// AddTimelineEvent adds an event that is not tied to a post to the timeline of an incident and
+// returns its ID.
+func (s *IncidentsService) AddTimelineEvent(ctx context.Context, incidentID string, opts TimelineEventOptions) (string, error) {
+	timelineURL := fmt.Sprintf("incidents/%s/timeline", incidentID)
+	req, err := s.client.newRequest(http.MethodPost, timelineURL, opts)
+	if err != nil {
+		return "", err
+	}
+
+	var result struct {
+		ID string `json:"id"`
+	}
+	resp, err := s.client.do(ctx, req, &result)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("expected status code %d", http.StatusCreated)
+	}
+
+	return result.ID, nil
+}
+
+// EditTimelineEvent changes the time, summary and details of a timeline event of an incident.
+// The previous values are kept as an edit of the event.
+func (s *IncidentsService) EditTimelineEvent(ctx context.Context, incidentID, eventID string, opts TimelineEventOptions) error {
+	eventURL := fmt.Sprintf("incidents/%s/timeline/%s", incidentID, eventID)
+	req, err := s.client.newRequest(http.MethodPut, eventURL, opts)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.do(ctx, req, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetTimelineEventEdits gets the edits of a timeline event of an incident, oldest first.
+func (s *IncidentsService) GetTimelineEventEdits(ctx context.Context, incidentID, eventID string) ([]TimelineEventEdit, error) {
+	editsURL := fmt.Sprintf("incidents/%s/timeline/%s/edits", incidentID, eventID)
+	req, err := s.client.newRequest(http.MethodGet, editsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var edits []TimelineEventEdit
+	resp, err := s.client.do(ctx, req, &edits)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	return edits, nil
+}
+
+// ListTimelineEvents lists a page of the timeline of an incident. The next page is listed by
+// passing the NextCursor of the results as the Cursor of opts.
+func (s *IncidentsService) ListTimelineEvents(ctx context.Context, incidentID string, opts TimelineListOptions) (*GetTimelineEventsResults, error) {
+	timelineURL := fmt.Sprintf("incidents/%s/timeline", incidentID)
+	timelineURL, err := addOptions(timelineURL, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build options: %w", err)
+	}
+
+	req, err := s.client.newRequest(http.MethodGet, timelineURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	result := &GetTimelineEventsResults{}
+	resp, err := s.client.do(ctx, req, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	resp.Body.Close()
+
+	return result, nil
+}
+
+// ExportTimeline writes the timeline of an incident, in the format and restricted to the events
+// given by opts, to w.
+func (s *IncidentsService) ExportTimeline(ctx context.Context, incidentID string, opts TimelineExportOptions, w io.Writer) error {
+	exportURL := fmt.Sprintf("incidents/%s/timeline/export", incidentID)
+	exportURL, err := addOptions(exportURL, opts)
+	if err != nil {
+		return fmt.Errorf("failed to build options: %w", err)
+	}
+
+	req, err := s.client.newRequest(http.MethodGet, exportURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+
+	resp, err := s.client.do(ctx, req, w)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
 func (s *IncidentsService) UpdateStatus(ctx context.Context, incidentID string, status Status, description, message string, reminderInSeconds int64) error {
 	updateURL := fmt.Sprintf("incidents/%s/status", incidentID)
 	opts := StatusUpdateOptions{
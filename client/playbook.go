@@ -2,27 +2,89 @@ package client
 
 // Playbook represents the planning before an incident type is initiated.
 type Playbook struct {
-	ID                          string      `json:"id"`
-	Title                       string      `json:"title"`
-	Description                 string      `json:"description"`
-	TeamID                      string      `json:"team_id"`
-	CreatePublicIncident        bool        `json:"create_public_incident"`
-	CreateAt                    int64       `json:"create_at"`
-	DeleteAt                    int64       `json:"delete_at"`
-	NumStages                   int64       `json:"num_stages"`
-	NumSteps                    int64       `json:"num_steps"`
-	Checklists                  []Checklist `json:"checklists"`
-	MemberIDs                   []string    `json:"member_ids"`
-	BroadcastChannelID          string      `json:"broadcast_channel_id"`
-	ReminderMessageTemplate     string      `json:"reminder_message_template"`
-	ReminderTimerDefaultSeconds int64       `json:"reminder_timer_default_seconds"`
-	InvitedUserIDs              []string    `json:"invited_user_ids"`
-	InvitedGroupIDs             []string    `json:"invited_group_ids"`
-	InvitedUsersEnabled         bool        `json:"invited_users_enabled"`
-	DefaultOwnerID              string      `json:"default_owner_id"`
-	DefaultOwnerEnabled         bool        `json:"default_owner_enabled"`
-	AnnouncementChannelID       string      `json:"announcement_channel_id"`
-	AnnouncementChannelEnabled  bool        `json:"announcement_channel_enabled"`
+	ID                          string            `json:"id"`
+	Title                       string            `json:"title"`
+	Description                 string            `json:"description"`
+	TeamID                      string            `json:"team_id"`
+	CreatePublicIncident        bool              `json:"create_public_incident"`
+	CreateAt                    int64             `json:"create_at"`
+	DeleteAt                    int64             `json:"delete_at"`
+	NumStages                   int64             `json:"num_stages"`
+	NumSteps                    int64             `json:"num_steps"`
+	Checklists                  []Checklist       `json:"checklists"`
+	MemberIDs                   []string          `json:"member_ids"`
+	BroadcastChannelID          string            `json:"broadcast_channel_id"`
+	ReminderMessageTemplate     string            `json:"reminder_message_template"`
+	ReminderTimerDefaultSeconds int64             `json:"reminder_timer_default_seconds"`
+	StatusUpdateTemplate        string            `json:"status_update_template"`
+	InvitedUserIDs              []string          `json:"invited_user_ids"`
+	InvitedGroupIDs             []string          `json:"invited_group_ids"`
+	InvitedUsersEnabled         bool              `json:"invited_users_enabled"`
+	DefaultOwnerID              string            `json:"default_owner_id"`
+	DefaultOwnerEnabled         bool              `json:"default_owner_enabled"`
+	DefaultOwnerRotationID      string            `json:"default_owner_rotation_id"`
+	AnnouncementChannelID       string            `json:"announcement_channel_id"`
+	AnnouncementChannelEnabled  bool              `json:"announcement_channel_enabled"`
+	CustomFields                []CustomField     `json:"custom_fields"`
+	Roles                       []Role            `json:"roles"`
+	AcknowledgeTimeoutSeconds   int64             `json:"acknowledge_timeout_seconds"`
+	EscalationSteps             []EscalationStep  `json:"escalation_steps"`
+	BroadcastTargets            []BroadcastTarget `json:"broadcast_targets"`
+	AutoFollowerIDs             []string          `json:"auto_follower_ids"`
+	Revision                    int               `json:"revision"`
+}
+
+// CustomField is a typed field defined by a playbook, whose value is set on the incidents started
+// with the playbook. Its type is "text", "select", "multiselect", "user" or "number".
+type CustomField struct {
+	Key         string   `json:"key"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Type        string   `json:"type"`
+	Options     []string `json:"options"`
+	Required    bool     `json:"required"`
+}
+
+// Role is a named role defined by a playbook, to which users are assigned in the incidents
+// started with the playbook.
+type Role struct {
+	Key         string `json:"key"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// EscalationStep is a step of the escalation chain of a playbook, taken when the owner of an
+// incident does not acknowledge it in time. ReassignOwner makes the first of UserIDs the owner.
+type EscalationStep struct {
+	UserIDs       []string `json:"user_ids"`
+	GroupIDs      []string `json:"group_ids"`
+	ReassignOwner bool     `json:"reassign_owner"`
+}
+
+// Filters of a broadcast target.
+const (
+	BroadcastFilterAll           = "all"
+	BroadcastFilterStatusChanges = "status_changes"
+)
+
+// BroadcastTarget is a channel where the creation and the status updates of the incidents created
+// from a playbook are broadcast. Filter selects the updates broadcast, and MinSeverity, if set, the
+// least severe level broadcast.
+type BroadcastTarget struct {
+	ChannelID   string `json:"channel_id"`
+	Filter      string `json:"filter"`
+	MinSeverity string `json:"min_severity"`
+}
+
+// PlaybookRevision is a snapshot of a playbook, stored every time the playbook is created, updated
+// or restored.
+type PlaybookRevision struct {
+	ID         string   `json:"id"`
+	PlaybookID string   `json:"playbook_id"`
+	Revision   int      `json:"revision"`
+	UserID     string   `json:"user_id"`
+	CreateAt   int64    `json:"create_at"`
+	Playbook   Playbook `json:"playbook"`
 }
 
 // Checklist represents a checklist in a playbook
@@ -34,37 +96,69 @@ type Checklist struct {
 
 // ChecklistItem represents an item in a checklist
 type ChecklistItem struct {
-	ID                     string `json:"id"`
-	Title                  string `json:"title"`
-	State                  string `json:"state"`
-	StateModified          int64  `json:"state_modified"`
-	StateModifiedPostID    string `json:"state_modified_post_id"`
-	AssigneeID             string `json:"assignee_id"`
-	AssigneeModified       int64  `json:"assignee_modified"`
-	AssigneeModifiedPostID string `json:"assignee_modified_post_id"`
-	Command                string `json:"command"`
-	CommandLastRun         int64  `json:"command_last_run"`
-	Description            string `json:"description"`
+	ID                     string        `json:"id"`
+	Title                  string        `json:"title"`
+	State                  string        `json:"state"`
+	StateModified          int64         `json:"state_modified"`
+	StateModifiedPostID    string        `json:"state_modified_post_id"`
+	AssigneeID             string        `json:"assignee_id"`
+	AssigneeModified       int64         `json:"assignee_modified"`
+	AssigneeModifiedPostID string        `json:"assignee_modified_post_id"`
+	AssigneeRole           string        `json:"assignee_role"`
+	Command                string        `json:"command"`
+	CommandLastRun         int64         `json:"command_last_run"`
+	Description            string        `json:"description"`
+	DueAfterSeconds        int64         `json:"due_after_seconds"`
+	DueAt                  int64         `json:"due_at"`
+	OverdueNotified        bool          `json:"overdue_notified"`
+	PrerequisiteIDs        []string      `json:"prerequisite_ids"`
+	Condition              ItemCondition `json:"condition"`
+	AutomaticAction        ItemAction    `json:"automatic_action"`
+	ActionLastRun          int64         `json:"action_last_run"`
+}
+
+// ItemAction is an action run automatically when a checklist item reaches its trigger: "checked"
+// or "next_open". Its type is "run_command", "post_message" or "invite_users".
+type ItemAction struct {
+	Trigger   string   `json:"trigger"`
+	Type      string   `json:"type"`
+	ChannelID string   `json:"channel_id"`
+	Message   string   `json:"message"`
+	UserIDs   []string `json:"user_ids"`
+}
+
+// ItemCondition is a condition on an incident attribute deciding whether a playbook checklist
+// item is included in the incidents started with the playbook. A blank Field matches every incident.
+type ItemCondition struct {
+	Field    string   `json:"field"`
+	Operator string   `json:"operator"`
+	Values   []string `json:"values"`
 }
 
 // PlaybookCreateOptions specifies the parameters for PlaybooksService.Create method.
 type PlaybookCreateOptions struct {
-	Title                       string      `json:"title"`
-	Description                 string      `json:"description"`
-	TeamID                      string      `json:"team_id"`
-	CreatePublicIncident        bool        `json:"create_public_incident"`
-	Checklists                  []Checklist `json:"checklists"`
-	MemberIDs                   []string    `json:"member_ids"`
-	BroadcastChannelID          string      `json:"broadcast_channel_id"`
-	ReminderMessageTemplate     string      `json:"reminder_message_template"`
-	ReminderTimerDefaultSeconds int64       `json:"reminder_timer_default_seconds"`
-	InvitedUserIDs              []string    `json:"invited_user_ids"`
-	InvitedGroupIDs             []string    `json:"invited_group_ids"`
-	InviteUsersEnabled          bool        `json:"invite_users_enabled"`
-	DefaultOwnerID              string      `json:"default_owner_id"`
-	DefaultOwnerEnabled         bool        `json:"default_owner_enabled"`
-	AnnouncementChannelID       string      `json:"announcement_channel_id"`
-	AnnouncementChannelEnabled  bool        `json:"announcement_channel_enabled"`
+	Title                       string            `json:"title"`
+	Description                 string            `json:"description"`
+	TeamID                      string            `json:"team_id"`
+	CreatePublicIncident        bool              `json:"create_public_incident"`
+	Checklists                  []Checklist       `json:"checklists"`
+	MemberIDs                   []string          `json:"member_ids"`
+	BroadcastChannelID          string            `json:"broadcast_channel_id"`
+	ReminderMessageTemplate     string            `json:"reminder_message_template"`
+	ReminderTimerDefaultSeconds int64             `json:"reminder_timer_default_seconds"`
+	StatusUpdateTemplate        string            `json:"status_update_template,omitempty"`
+	InvitedUserIDs              []string          `json:"invited_user_ids"`
+	InvitedGroupIDs             []string          `json:"invited_group_ids"`
+	InviteUsersEnabled          bool              `json:"invite_users_enabled"`
+	DefaultOwnerID              string            `json:"default_owner_id"`
+	DefaultOwnerEnabled         bool              `json:"default_owner_enabled"`
+	DefaultOwnerRotationID      string            `json:"default_owner_rotation_id,omitempty"`
+	AnnouncementChannelID       string            `json:"announcement_channel_id"`
+	AnnouncementChannelEnabled  bool              `json:"announcement_channel_enabled"`
+	AcknowledgeTimeoutSeconds   int64             `json:"acknowledge_timeout_seconds,omitempty"`
+	EscalationSteps             []EscalationStep  `json:"escalation_steps,omitempty"`
+	BroadcastTargets            []BroadcastTarget `json:"broadcast_targets,omitempty"`
+	AutoFollowerIDs             []string          `json:"auto_follower_ids,omitempty"`
 }
 
 // PlaybookListOptions specifies the optional parameters to the
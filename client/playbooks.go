@@ -117,3 +117,83 @@ func (s *PlaybooksService) Delete(ctx context.Context, playbookID string) error
 
 	return nil
 }
+
+// GetRevisions lists the revisions of a playbook, latest first, without their snapshot of the
+// playbook.
+func (s *PlaybooksService) GetRevisions(ctx context.Context, playbookID string) ([]PlaybookRevision, error) {
+	revisionsURL := fmt.Sprintf("playbooks/%s/revisions", playbookID)
+	req, err := s.client.newRequest(http.MethodGet, revisionsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var revisions []PlaybookRevision
+	resp, err := s.client.do(ctx, req, &revisions)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	return revisions, nil
+}
+
+// RestoreRevision updates a playbook to the snapshot in one of its revisions.
+func (s *PlaybooksService) RestoreRevision(ctx context.Context, playbookID string, revision int) (*Playbook, error) {
+	restoreURL := fmt.Sprintf("playbooks/%s/revisions/%d/restore", playbookID, revision)
+	req, err := s.client.newRequest(http.MethodPost, restoreURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	playbook := new(Playbook)
+	resp, err := s.client.do(ctx, req, playbook)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	return playbook, nil
+}
+
+// GetInboundWebhookToken returns the token that authenticates the alerts sent to the inbound
+// webhook of a playbook. Only system admins, and team admins with access to the playbook, can get
+// it.
+func (s *PlaybooksService) GetInboundWebhookToken(ctx context.Context, playbookID string) (string, error) {
+	tokenURL := fmt.Sprintf("playbooks/%s/inbound-webhook-token", playbookID)
+	req, err := s.client.newRequest(http.MethodGet, tokenURL, nil)
+	if err != nil {
+		return "", err
+	}
+
+	var result struct {
+		Token string `json:"token"`
+	}
+	resp, err := s.client.do(ctx, req, &result)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+
+	return result.Token, nil
+}
+
+// RegenerateInboundWebhookToken replaces the inbound webhook token of a playbook and returns the
+// new token. The previous token stops authenticating alerts immediately.
+func (s *PlaybooksService) RegenerateInboundWebhookToken(ctx context.Context, playbookID string) (string, error) {
+	tokenURL := fmt.Sprintf("playbooks/%s/inbound-webhook-token/regenerate", playbookID)
+	req, err := s.client.newRequest(http.MethodPost, tokenURL, nil)
+	if err != nil {
+		return "", err
+	}
+
+	var result struct {
+		Token string `json:"token"`
+	}
+	resp, err := s.client.do(ctx, req, &result)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+
+	return result.Token, nil
+}
@@ -0,0 +1,41 @@
+package client
+
+// Rotation is an on-call schedule of a team. The user on call at a given moment is the one of
+// the override in effect, if any, or else the one of the last layer covering that moment.
+type Rotation struct {
+	ID          string     `json:"id"`
+	TeamID      string     `json:"team_id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Layers      []Layer    `json:"layers"`
+	Overrides   []Override `json:"overrides"`
+	CreateAt    int64      `json:"create_at"`
+	UpdateAt    int64      `json:"update_at"`
+	DeleteAt    int64      `json:"delete_at"`
+}
+
+// Layer hands the on-call duty to each of UserIDs in turn, for ShiftLengthSeconds each, starting
+// with the first user at StartAt. Layers later in Rotation.Layers take precedence.
+type Layer struct {
+	Name               string   `json:"name"`
+	UserIDs            []string `json:"user_ids"`
+	StartAt            int64    `json:"start_at"`
+	EndAt              int64    `json:"end_at"`
+	ShiftLengthSeconds int64    `json:"shift_length_seconds"`
+}
+
+// Override puts UserID on call from StartAt to EndAt, whatever the layers say.
+type Override struct {
+	ID      string `json:"id"`
+	UserID  string `json:"user_id"`
+	StartAt int64  `json:"start_at"`
+	EndAt   int64  `json:"end_at"`
+}
+
+// Shift is a period during which a single user is on call. UserID is empty if nobody is, and
+// EndAt is 0 if the shift never ends.
+type Shift struct {
+	UserID  string `json:"user_id"`
+	StartAt int64  `json:"start_at"`
+	EndAt   int64  `json:"end_at"`
+}
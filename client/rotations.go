@@ -0,0 +1,171 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// RotationsService handles communication with the on-call rotation related
+// methods of the Incident Collaboration API.
+type RotationsService struct {
+	client *Client
+}
+
+// Get an on-call rotation.
+func (s *RotationsService) Get(ctx context.Context, rotationID string) (*Rotation, error) {
+	rotationURL := fmt.Sprintf("oncall/rotations/%s", rotationID)
+	req, err := s.client.newRequest(http.MethodGet, rotationURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	rotation := new(Rotation)
+	resp, err := s.client.do(ctx, req, rotation)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	return rotation, nil
+}
+
+// List the on-call rotations of a team.
+func (s *RotationsService) List(ctx context.Context, teamID string) ([]Rotation, error) {
+	rotationsURL, err := addOption("oncall/rotations", "team_id", teamID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build options: %w", err)
+	}
+
+	req, err := s.client.newRequest(http.MethodGet, rotationsURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	var rotations []Rotation
+	resp, err := s.client.do(ctx, req, &rotations)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	resp.Body.Close()
+
+	return rotations, nil
+}
+
+// Create an on-call rotation, returning its ID.
+func (s *RotationsService) Create(ctx context.Context, rotation Rotation) (string, error) {
+	req, err := s.client.newRequest(http.MethodPost, "oncall/rotations", rotation)
+	if err != nil {
+		return "", err
+	}
+
+	var result struct {
+		ID string `json:"id"`
+	}
+	resp, err := s.client.do(ctx, req, &result)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("expected status code %d", http.StatusCreated)
+	}
+
+	return result.ID, nil
+}
+
+// Update an on-call rotation. Its team cannot be changed.
+func (s *RotationsService) Update(ctx context.Context, rotation Rotation) error {
+	rotationURL := fmt.Sprintf("oncall/rotations/%s", rotation.ID)
+	req, err := s.client.newRequest(http.MethodPut, rotationURL, rotation)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.do(ctx, req, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Delete an on-call rotation.
+func (s *RotationsService) Delete(ctx context.Context, rotationID string) error {
+	rotationURL := fmt.Sprintf("oncall/rotations/%s", rotationID)
+	req, err := s.client.newRequest(http.MethodDelete, rotationURL, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.do(ctx, req, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Shifts returns the next count shifts of an on-call rotation, starting with the current one.
+func (s *RotationsService) Shifts(ctx context.Context, rotationID string, count int) ([]Shift, error) {
+	shiftsURL := fmt.Sprintf("oncall/rotations/%s/shifts", rotationID)
+	shiftsURL, err := addOption(shiftsURL, "count", strconv.Itoa(count))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build options: %w", err)
+	}
+
+	req, err := s.client.newRequest(http.MethodGet, shiftsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var shifts []Shift
+	resp, err := s.client.do(ctx, req, &shifts)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	return shifts, nil
+}
+
+// AddOverride adds an override to an on-call rotation, returning its ID.
+func (s *RotationsService) AddOverride(ctx context.Context, rotationID string, override Override) (string, error) {
+	overridesURL := fmt.Sprintf("oncall/rotations/%s/overrides", rotationID)
+	req, err := s.client.newRequest(http.MethodPost, overridesURL, override)
+	if err != nil {
+		return "", err
+	}
+
+	var result struct {
+		ID string `json:"id"`
+	}
+	resp, err := s.client.do(ctx, req, &result)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+
+	return result.ID, nil
+}
+
+// RemoveOverride removes an override from an on-call rotation.
+func (s *RotationsService) RemoveOverride(ctx context.Context, rotationID, overrideID string) error {
+	overrideURL := fmt.Sprintf("oncall/rotations/%s/overrides/%s", rotationID, overrideID)
+	req, err := s.client.newRequest(http.MethodDelete, overrideURL, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.do(ctx, req, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,320 @@
+package api
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/template"
+
+	"github.com/gorilla/mux"
+	pluginapi "github.com/mattermost/mattermost-plugin-api"
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/bot"
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/config"
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/incident"
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/permissions"
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/playbook"
+	"github.com/pkg/errors"
+)
+
+// Actions taken when an alert is received, reported in the response.
+const (
+	alertActionCreated  = "created"
+	alertActionUpdated  = "updated"
+	alertActionResolved = "resolved"
+	alertActionIgnored  = "ignored"
+)
+
+// MaxAlertSize is the largest alert, in bytes, accepted by the inbound webhooks. The webhooks do
+// not require a Mattermost session, so the size of their requests is bounded.
+const MaxAlertSize = 1024 * 1024
+
+// AlertHandler handles the alerts sent by monitoring systems to the inbound webhooks of playbooks.
+type AlertHandler struct {
+	*ErrorHandler
+	incidentService incident.Service
+	playbookService playbook.Service
+	pluginAPI       *pluginapi.Client
+	poster          bot.Poster
+	log             bot.Logger
+	config          config.Service
+}
+
+// alertResult is the response to an alert.
+type alertResult struct {
+	IncidentID string `json:"incident_id"`
+	Action     string `json:"action"`
+}
+
+// NewAlertHandler returns a new alert api handler. router must not require Mattermost
+// authentication: requests are authenticated with the inbound webhook token of the playbook.
+func NewAlertHandler(router *mux.Router, incidentService incident.Service, playbookService playbook.Service,
+	api *pluginapi.Client, poster bot.Poster, log bot.Logger, configService config.Service) *AlertHandler {
+	handler := &AlertHandler{
+		ErrorHandler:    &ErrorHandler{log: log},
+		incidentService: incidentService,
+		playbookService: playbookService,
+		pluginAPI:       api,
+		poster:          poster,
+		log:             log,
+		config:          configService,
+	}
+
+	alertsRouter := router.PathPrefix("/playbooks/{id:[A-Za-z0-9]+}/alerts").Subrouter()
+	alertsRouter.HandleFunc("", handler.receiveAlert).Methods(http.MethodPost)
+
+	return handler
+}
+
+// receiveAlert handles the POST /playbooks/{id}/alerts endpoint. It starts an incident with the
+// playbook, or updates the ongoing incident already started for the same alert.
+func (h *AlertHandler) receiveAlert(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	pbook, err := h.playbookService.Get(vars["id"])
+	if err != nil {
+		if errors.Is(err, playbook.ErrNotFound) {
+			h.HandleErrorWithCode(w, http.StatusNotFound, "Not found", err)
+			return
+		}
+		h.HandleError(w, err)
+		return
+	}
+
+	if !pbook.InboundWebhookEnabled || !validAlertToken(pbook.InboundWebhookToken, alertToken(r)) {
+		h.HandleErrorWithCode(w, http.StatusUnauthorized, "Not authorized",
+			errors.Errorf("invalid inbound webhook token for playbook %s", pbook.ID))
+		return
+	}
+
+	var alert map[string]interface{}
+	r.Body = http.MaxBytesReader(w, r.Body, MaxAlertSize)
+	if err = json.NewDecoder(r.Body).Decode(&alert); err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "unable to decode alert", err)
+		return
+	}
+
+	fingerprint, err := renderAlertTemplate(pbook.InboundWebhookFingerprintTemplate, alert)
+	if err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "unable to compute the alert fingerprint", err)
+		return
+	}
+
+	name, err := renderAlertTemplate(pbook.InboundWebhookNameTemplate, alert)
+	if err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "unable to compute the incident name", err)
+		return
+	}
+	if name == "" {
+		name = fmt.Sprintf("%s alert", pbook.Title)
+	}
+
+	description, err := renderAlertTemplate(pbook.InboundWebhookDescriptionTemplate, alert)
+	if err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "unable to compute the incident description", err)
+		return
+	}
+
+	if fingerprint != "" {
+		incidentID, err2 := h.incidentService.GetIncidentIDForAlert(pbook.ID, fingerprint)
+		if err2 != nil && !errors.Is(err2, incident.ErrNotFound) {
+			h.HandleError(w, err2)
+			return
+		}
+
+		if incidentID != "" {
+			result, err3 := h.updateIncidentFromAlert(incidentID, pbook, name, description, isResolvedAlert(alert))
+			if err3 != nil {
+				h.HandleError(w, err3)
+				return
+			}
+			ReturnJSON(w, result, http.StatusOK)
+			return
+		}
+	}
+
+	if isResolvedAlert(alert) {
+		ReturnJSON(w, &alertResult{Action: alertActionIgnored}, http.StatusOK)
+		return
+	}
+
+	ownerTemplateOutput, err := renderAlertTemplate(pbook.InboundWebhookOwnerTemplate, alert)
+	if err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "unable to compute the incident owner", err)
+		return
+	}
+
+	newIncident := incident.Incident{
+		Name:             name,
+		TeamID:           pbook.TeamID,
+		PlaybookID:       pbook.ID,
+		AlertFingerprint: fingerprint,
+	}
+	public := applyPlaybook(&newIncident, pbook)
+
+	if description != "" {
+		newIncident.Description = description
+	}
+
+	if err = playbook.ValidateCustomFieldValues(newIncident.CustomFields, newIncident.CustomFieldValues); err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "invalid custom field values: "+err.Error(), err)
+		return
+	}
+
+	botUserID := h.config.GetConfiguration().BotUserID
+	if ownerTemplateOutput != "" {
+		owner, err2 := h.findAlertOwner(ownerTemplateOutput)
+		if err2 != nil {
+			h.HandleErrorWithCode(w, http.StatusBadRequest, "unable to find the incident owner", err2)
+			return
+		}
+
+		if !permissions.CanViewTeam(owner, newIncident.TeamID, h.pluginAPI) {
+			h.HandleErrorWithCode(w, http.StatusBadRequest, "owner user does not have permissions for the team",
+				errors.Errorf("user %s cannot view team %s", owner, newIncident.TeamID))
+			return
+		}
+
+		// The owner given by the alert takes precedence over the playbook's default owner.
+		newIncident.OwnerUserID = owner
+		newIncident.DefaultOwnerID = ""
+	} else if !pbook.DefaultOwnerEnabled || (pbook.DefaultOwnerID == "" && pbook.DefaultOwnerRotationID == "") {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "unable to find the incident owner",
+			errors.Errorf("playbook %s has no owner template and no default owner", pbook.ID))
+		return
+	} else {
+		// CreateIncident makes the user on call, or else the default owner, the owner. The bot
+		// owns the incident if neither can own it.
+		newIncident.OwnerUserID = botUserID
+	}
+
+	createdIncident, err := h.incidentService.CreateIncident(&newIncident, &pbook, botUserID, public)
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	ReturnJSON(w, &alertResult{IncidentID: createdIncident.ID, Action: alertActionCreated}, http.StatusCreated)
+}
+
+// updateIncidentFromAlert resolves incidentID if the alert was resolved and the playbook
+// resolves incidents automatically. Otherwise, it posts the alert to the incident channel.
+func (h *AlertHandler) updateIncidentFromAlert(incidentID string, pbook playbook.Playbook, name, description string, resolved bool) (*alertResult, error) {
+	theIncident, err := h.incidentService.GetIncident(incidentID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get incident %s", incidentID)
+	}
+
+	if resolved && pbook.InboundWebhookAutoResolve {
+		err = h.incidentService.UpdateStatus(incidentID, h.config.GetConfiguration().BotUserID, incident.StatusUpdateOptions{
+			Status:      incident.StatusResolved,
+			Description: theIncident.Description,
+			Message:     fmt.Sprintf("The alert **%s** was resolved, so this incident was resolved automatically.", name),
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to resolve incident %s", incidentID)
+		}
+
+		return &alertResult{IncidentID: incidentID, Action: alertActionResolved}, nil
+	}
+
+	message := fmt.Sprintf("The alert **%s** fired again.", name)
+	if resolved {
+		message = fmt.Sprintf("The alert **%s** was resolved.", name)
+	}
+	if description != "" {
+		message += "\n" + description
+	}
+	if _, err = h.poster.PostMessage(theIncident.ChannelID, "%s", message); err != nil {
+		return nil, errors.Wrapf(err, "failed to post alert to incident %s", incidentID)
+	}
+
+	return &alertResult{IncidentID: incidentID, Action: alertActionUpdated}, nil
+}
+
+// findAlertOwner returns the ID of the user identified by owner, which is either a username,
+// optionally prefixed with @, or a user ID.
+func (h *AlertHandler) findAlertOwner(owner string) (string, error) {
+	user, err := h.pluginAPI.User.GetByUsername(strings.TrimPrefix(owner, "@"))
+	if err == nil {
+		return user.Id, nil
+	}
+
+	user, err = h.pluginAPI.User.Get(owner)
+	if err != nil {
+		return "", errors.Wrapf(err, "no user with username or ID '%s'", owner)
+	}
+
+	return user.Id, nil
+}
+
+// alertToken returns the inbound webhook token sent with r as a bearer token. The token is not
+// accepted as a query parameter, which would leak it to access logs and proxies.
+func alertToken(r *http.Request) string {
+	if authorization := r.Header.Get("Authorization"); strings.HasPrefix(authorization, "Bearer ") {
+		return strings.TrimPrefix(authorization, "Bearer ")
+	}
+
+	return ""
+}
+
+// validAlertToken compares the token received with the playbook's in constant time. A blank
+// expected token never matches.
+func validAlertToken(expected, received string) bool {
+	if expected == "" {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(received)) == 1
+}
+
+// renderAlertTemplate executes tmpl, a text/template, with the alert as its data, and returns the
+// output without surrounding whitespace. A blank template renders a blank string.
+func renderAlertTemplate(tmpl string, alert map[string]interface{}) (string, error) {
+	if strings.TrimSpace(tmpl) == "" {
+		return "", nil
+	}
+
+	t, err := template.New("alert").Parse(tmpl)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse template")
+	}
+
+	var out bytes.Buffer
+	if err = t.Execute(&out, alert); err != nil {
+		return "", errors.Wrap(err, "failed to execute template")
+	}
+
+	// Fields missing from the alert render as "<no value>"; treat them as blank instead.
+	return strings.TrimSpace(strings.ReplaceAll(out.String(), "<no value>", "")), nil
+}
+
+// isResolvedAlert returns true if the alert's status is "resolved", as sent by Alertmanager and
+// Grafana when an alert stops firing.
+func isResolvedAlert(alert map[string]interface{}) bool {
+	status, _ := alert["status"].(string)
+	return strings.EqualFold(status, "resolved")
+}
+
+// validateAlertTemplates checks that the inbound webhook templates of pbook can be parsed.
+func validateAlertTemplates(pbook playbook.Playbook) error {
+	templates := []struct {
+		field string
+		tmpl  string
+	}{
+		{"name", pbook.InboundWebhookNameTemplate},
+		{"description", pbook.InboundWebhookDescriptionTemplate},
+		{"owner", pbook.InboundWebhookOwnerTemplate},
+		{"fingerprint", pbook.InboundWebhookFingerprintTemplate},
+	}
+
+	for _, t := range templates {
+		if _, err := template.New(t.field).Parse(t.tmpl); err != nil {
+			return errors.Wrapf(err, "invalid %s template", t.field)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,228 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	mock_poster "github.com/mattermost/mattermost-plugin-incident-collaboration/server/bot/mocks"
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/config"
+	mock_config "github.com/mattermost/mattermost-plugin-incident-collaboration/server/config/mocks"
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/incident"
+	mock_incident "github.com/mattermost/mattermost-plugin-incident-collaboration/server/incident/mocks"
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/playbook"
+	mock_playbook "github.com/mattermost/mattermost-plugin-incident-collaboration/server/playbook/mocks"
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/mattermost/mattermost-server/v5/plugin/plugintest"
+	"github.com/stretchr/testify/require"
+
+	pluginapi "github.com/mattermost/mattermost-plugin-api"
+)
+
+func TestRenderAlertTemplate(t *testing.T) {
+	var alert map[string]interface{}
+	err := json.Unmarshal([]byte(`{
+		"status": "firing",
+		"fingerprint": "c2a4d8e0",
+		"labels": {"alertname": "HighLatency", "severity": "critical"},
+		"annotations": {"summary": "p99 latency above 2s"}
+	}`), &alert)
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		tmpl     string
+		expected string
+		err      bool
+	}{
+		"blank template": {
+			tmpl:     "  ",
+			expected: "",
+		},
+		"top level field": {
+			tmpl:     "{{.fingerprint}}",
+			expected: "c2a4d8e0",
+		},
+		"nested fields": {
+			tmpl:     "{{.labels.alertname}} ({{.labels.severity}})",
+			expected: "HighLatency (critical)",
+		},
+		"missing field renders blank": {
+			tmpl:     " {{.labels.owner}} ",
+			expected: "",
+		},
+		"invalid template": {
+			tmpl: "{{.labels.alertname",
+			err:  true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			actual, err := renderAlertTemplate(tc.tmpl, alert)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestIsResolvedAlert(t *testing.T) {
+	require.True(t, isResolvedAlert(map[string]interface{}{"status": "resolved"}))
+	require.True(t, isResolvedAlert(map[string]interface{}{"status": "Resolved"}))
+	require.False(t, isResolvedAlert(map[string]interface{}{"status": "firing"}))
+	require.False(t, isResolvedAlert(map[string]interface{}{}))
+}
+
+func TestAlertToken(t *testing.T) {
+	req := httptest.NewRequest("POST", "/hooks/v0/playbooks/id/alerts?token=querytoken", nil)
+	require.Empty(t, alertToken(req))
+
+	req.Header.Set("Authorization", "Bearer headertoken")
+	require.Equal(t, "headertoken", alertToken(req))
+
+	require.True(t, validAlertToken("headertoken", alertToken(req)))
+	require.False(t, validAlertToken("othertoken", alertToken(req)))
+	require.False(t, validAlertToken("", ""))
+}
+
+func TestValidateAlertTemplates(t *testing.T) {
+	require.NoError(t, validateAlertTemplates(playbook.Playbook{
+		InboundWebhookNameTemplate:        "{{.labels.alertname}}",
+		InboundWebhookFingerprintTemplate: "{{.fingerprint}}",
+	}))
+
+	err := validateAlertTemplates(playbook.Playbook{
+		InboundWebhookOwnerTemplate: "{{.labels.owner",
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid owner template")
+}
+
+func TestReceiveAlert(t *testing.T) {
+	pbook := playbook.Playbook{
+		ID:                                "playbookid",
+		Title:                             "Latency",
+		TeamID:                            "teamid",
+		InboundWebhookEnabled:             true,
+		InboundWebhookToken:               "thetoken",
+		InboundWebhookNameTemplate:        "{{.labels.alertname}}",
+		InboundWebhookOwnerTemplate:       "{{.labels.owner}}",
+		InboundWebhookFingerprintTemplate: "{{.fingerprint}}",
+		InboundWebhookAutoResolve:         true,
+	}
+
+	type mocks struct {
+		pluginAPI       *plugintest.API
+		incidentService *mock_incident.MockService
+		playbookService *mock_playbook.MockService
+		poster          *mock_poster.MockPoster
+		logger          *mock_poster.MockLogger
+	}
+
+	setup := func(t *testing.T) (*mux.Router, mocks) {
+		mockCtrl := gomock.NewController(t)
+		m := mocks{
+			pluginAPI:       &plugintest.API{},
+			incidentService: mock_incident.NewMockService(mockCtrl),
+			playbookService: mock_playbook.NewMockService(mockCtrl),
+			poster:          mock_poster.NewMockPoster(mockCtrl),
+			logger:          mock_poster.NewMockLogger(mockCtrl),
+		}
+		configService := mock_config.NewMockService(mockCtrl)
+		configService.EXPECT().GetConfiguration().AnyTimes().Return(&config.Configuration{BotUserID: "botuserid"})
+
+		router := mux.NewRouter()
+		NewAlertHandler(router, m.incidentService, m.playbookService, pluginapi.NewClient(m.pluginAPI), m.poster, m.logger, configService)
+		m.playbookService.EXPECT().Get(pbook.ID).Return(pbook, nil)
+
+		return router, m
+	}
+
+	send := func(router *mux.Router, token, alert string) (*httptest.ResponseRecorder, alertResult) {
+		req := httptest.NewRequest(http.MethodPost, "/playbooks/"+pbook.ID+"/alerts", strings.NewReader(alert))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		var result alertResult
+		_ = json.Unmarshal(rec.Body.Bytes(), &result)
+		return rec, result
+	}
+
+	t.Run("creates an incident", func(t *testing.T) {
+		router, m := setup(t)
+
+		m.incidentService.EXPECT().GetIncidentIDForAlert(pbook.ID, "fp1").Return("", incident.ErrNotFound)
+		m.pluginAPI.On("GetUserByUsername", "alice").Return(&model.User{Id: "aliceid"}, nil)
+		m.pluginAPI.On("HasPermissionToTeam", "aliceid", pbook.TeamID, model.PERMISSION_VIEW_TEAM).Return(true)
+		m.incidentService.EXPECT().
+			CreateIncident(gomock.AssignableToTypeOf(&incident.Incident{}), gomock.Any(), "botuserid", false).
+			DoAndReturn(func(newIncident *incident.Incident, pb *playbook.Playbook, userID string, public bool) (*incident.Incident, error) {
+				require.Equal(t, "HighLatency", newIncident.Name)
+				require.Equal(t, "aliceid", newIncident.OwnerUserID)
+				require.Equal(t, "fp1", newIncident.AlertFingerprint)
+				require.Equal(t, pbook.ID, newIncident.PlaybookID)
+				return &incident.Incident{ID: "incidentid"}, nil
+			})
+
+		rec, result := send(router, pbook.InboundWebhookToken, `{"status": "firing", "fingerprint": "fp1", "labels": {"alertname": "HighLatency", "owner": "alice"}}`)
+		require.Equal(t, http.StatusCreated, rec.Code)
+		require.Equal(t, alertResult{IncidentID: "incidentid", Action: alertActionCreated}, result)
+	})
+
+	t.Run("posts a repeated alert to the existing incident", func(t *testing.T) {
+		router, m := setup(t)
+
+		m.incidentService.EXPECT().GetIncidentIDForAlert(pbook.ID, "fp1").Return("incidentid", nil)
+		m.incidentService.EXPECT().GetIncident("incidentid").Return(&incident.Incident{ID: "incidentid", ChannelID: "channelid"}, nil)
+		m.poster.EXPECT().PostMessage("channelid", "%s", gomock.Any()).Return(&model.Post{}, nil)
+
+		rec, result := send(router, pbook.InboundWebhookToken, `{"status": "firing", "fingerprint": "fp1", "labels": {"alertname": "HighLatency", "owner": "alice"}}`)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, alertResult{IncidentID: "incidentid", Action: alertActionUpdated}, result)
+	})
+
+	t.Run("resolves the existing incident", func(t *testing.T) {
+		router, m := setup(t)
+
+		m.incidentService.EXPECT().GetIncidentIDForAlert(pbook.ID, "fp1").Return("incidentid", nil)
+		m.incidentService.EXPECT().GetIncident("incidentid").Return(&incident.Incident{ID: "incidentid", ChannelID: "channelid", Description: "desc"}, nil)
+		m.incidentService.EXPECT().
+			UpdateStatus("incidentid", "botuserid", gomock.AssignableToTypeOf(incident.StatusUpdateOptions{})).
+			Do(func(incidentID, userID string, options incident.StatusUpdateOptions) {
+				require.Equal(t, incident.StatusResolved, options.Status)
+				require.Equal(t, "desc", options.Description)
+			}).
+			Return(nil)
+
+		rec, result := send(router, pbook.InboundWebhookToken, `{"status": "resolved", "fingerprint": "fp1", "labels": {"alertname": "HighLatency"}}`)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, alertResult{IncidentID: "incidentid", Action: alertActionResolved}, result)
+	})
+
+	t.Run("rejects an invalid token", func(t *testing.T) {
+		router, m := setup(t)
+
+		m.logger.EXPECT().Warnf(gomock.Any(), gomock.Any(), gomock.Any())
+
+		rec, _ := send(router, "othertoken", `{"status": "firing", "fingerprint": "fp1"}`)
+		require.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("rejects an alert that is too large", func(t *testing.T) {
+		router, m := setup(t)
+
+		m.logger.EXPECT().Warnf(gomock.Any(), gomock.Any(), gomock.Any())
+
+		alert := `{"status": "firing", "fingerprint": "fp1", "description": "` + strings.Repeat("a", MaxAlertSize) + `"}`
+		rec, _ := send(router, pbook.InboundWebhookToken, alert)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
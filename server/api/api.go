@@ -19,8 +19,11 @@ type Handler struct {
 	*ErrorHandler
 	pluginAPI *pluginapi.Client
 	APIRouter *mux.Router
-	root      *mux.Router
-	config    config.Service
+	// HooksRouter serves the endpoints called by external systems, which are not authenticated by
+	// Mattermost. Handlers registered on it must authenticate requests themselves.
+	HooksRouter *mux.Router
+	root        *mux.Router
+	config      config.Service
 }
 
 // NewHandler constructs a new handler.
@@ -38,7 +41,11 @@ func NewHandler(pluginAPI *pluginapi.Client, config config.Service, log bot.Logg
 	api.Handle("{anything:.*}", http.NotFoundHandler())
 	api.NotFoundHandler = http.NotFoundHandler()
 
+	hooks := root.PathPrefix("/hooks/v0").Subrouter()
+	hooks.NotFoundHandler = http.NotFoundHandler()
+
 	handler.APIRouter = api
+	handler.HooksRouter = hooks
 	handler.root = root
 	handler.config = config
 
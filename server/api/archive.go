@@ -0,0 +1,111 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	pluginapi "github.com/mattermost/mattermost-plugin-api"
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/archive"
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/bot"
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/permissions"
+	"github.com/pkg/errors"
+)
+
+// ArchiveHandler handles the export of incidents and playbooks to an archive, and their import
+// back. Both are restricted to system admins.
+type ArchiveHandler struct {
+	*ErrorHandler
+	archiveService *archive.Service
+	pluginAPI      *pluginapi.Client
+	log            bot.Logger
+}
+
+// NewArchiveHandler returns a new archive api handler.
+func NewArchiveHandler(router *mux.Router, archiveService *archive.Service, api *pluginapi.Client, log bot.Logger) *ArchiveHandler {
+	handler := &ArchiveHandler{
+		ErrorHandler:   &ErrorHandler{log: log},
+		archiveService: archiveService,
+		pluginAPI:      api,
+		log:            log,
+	}
+
+	archiveRouter := router.PathPrefix("/archive").Subrouter()
+	archiveRouter.HandleFunc("/export", handler.export).Methods(http.MethodGet)
+	archiveRouter.HandleFunc("/import", handler.importArchive).Methods(http.MethodPost)
+
+	return handler
+}
+
+// export handles the GET /archive/export endpoint. It returns the archive of the team given by
+// team_id, as JSON, or as a CSV summary of its incidents if format is csv.
+func (h *ArchiveHandler) export(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("Mattermost-User-ID")
+	if !permissions.IsAdmin(userID, h.pluginAPI) {
+		h.HandleErrorWithCode(w, http.StatusForbidden, "Not authorized",
+			errors.Errorf("userID %s is not an admin", userID))
+		return
+	}
+
+	teamID := r.URL.Query().Get("team_id")
+	if teamID == "" {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "Bad parameter 'team_id'; 'team_id' is required", nil)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "Bad parameter 'format'; must be json or csv", nil)
+		return
+	}
+
+	theArchive, err := h.archiveService.Export(teamID, userID)
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", archive.FileName(teamID, "csv")))
+		if err = archive.WriteCSV(w, theArchive); err != nil {
+			h.log.Warnf("failed to write the csv summary of team %s: %v", teamID, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", archive.FileName(teamID, "json")))
+	ReturnJSON(w, theArchive, http.StatusOK)
+}
+
+// importArchive handles the POST /archive/import endpoint. It imports the archive in the request
+// body, and returns what was imported and skipped.
+func (h *ArchiveHandler) importArchive(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("Mattermost-User-ID")
+	if !permissions.IsAdmin(userID, h.pluginAPI) {
+		h.HandleErrorWithCode(w, http.StatusForbidden, "Not authorized",
+			errors.Errorf("userID %s is not an admin", userID))
+		return
+	}
+
+	var theArchive archive.Archive
+	if err := json.NewDecoder(r.Body).Decode(&theArchive); err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "unable to decode archive", err)
+		return
+	}
+
+	if theArchive.Version < 1 || theArchive.Version > archive.Version {
+		h.HandleErrorWithCode(w, http.StatusBadRequest,
+			fmt.Sprintf("unsupported archive version %d", theArchive.Version), nil)
+		return
+	}
+
+	result, err := h.archiveService.Import(&theArchive, userID)
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	ReturnJSON(w, result, http.StatusOK)
+}
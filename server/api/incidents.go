@@ -61,18 +61,38 @@ func NewIncidentHandler(router *mux.Router, incidentService incident.Service, pl
 	incidentRouter := incidentsRouter.PathPrefix("/{id:[A-Za-z0-9]+}").Subrouter()
 	incidentRouter.HandleFunc("", handler.getIncident).Methods(http.MethodGet)
 	incidentRouter.HandleFunc("/metadata", handler.getIncidentMetadata).Methods(http.MethodGet)
+	incidentRouter.HandleFunc("/followers", handler.follow).Methods(http.MethodPut)
+	incidentRouter.HandleFunc("/followers", handler.unfollow).Methods(http.MethodDelete)
+	incidentRouter.HandleFunc("/timeline", handler.getTimelineEvents).Methods(http.MethodGet)
+	incidentRouter.HandleFunc("/timeline/export", handler.exportTimeline).Methods(http.MethodGet)
+	incidentRouter.HandleFunc("/timeline/{eventID:[A-Za-z0-9]+}/edits", handler.getTimelineEventEdits).Methods(http.MethodGet)
 
 	incidentRouterAuthorized := incidentRouter.PathPrefix("").Subrouter()
 	incidentRouterAuthorized.Use(handler.checkEditPermissions)
 	incidentRouterAuthorized.HandleFunc("", handler.updateIncident).Methods(http.MethodPatch)
 	incidentRouterAuthorized.HandleFunc("/owner", handler.changeOwner).Methods(http.MethodPost)
+	incidentRouterAuthorized.HandleFunc("/severity", handler.changeSeverity).Methods(http.MethodPost)
+	incidentRouterAuthorized.HandleFunc("/roles/{role}", handler.assignRole).Methods(http.MethodPut)
+	incidentRouterAuthorized.HandleFunc("/acknowledge", handler.acknowledge).Methods(http.MethodPost)
+	incidentRouterAuthorized.HandleFunc("/acknowledge/button", handler.acknowledgeButton).Methods(http.MethodPost)
 	incidentRouterAuthorized.HandleFunc("/status", handler.status).Methods(http.MethodPost)
 	incidentRouterAuthorized.HandleFunc("/update-status-dialog", handler.updateStatusDialog).Methods(http.MethodPost)
 	incidentRouterAuthorized.HandleFunc("/reminder/button-update", handler.reminderButtonUpdate).Methods(http.MethodPost)
 	incidentRouterAuthorized.HandleFunc("/reminder/button-dismiss", handler.reminderButtonDismiss).Methods(http.MethodPost)
 	incidentRouterAuthorized.HandleFunc("/no-retrospective-button", handler.noRetrospectiveButton).Methods(http.MethodPost)
+	incidentRouterAuthorized.HandleFunc("/timeline", handler.addTimelineEvent).Methods(http.MethodPost)
+	incidentRouterAuthorized.HandleFunc("/timeline/{eventID:[A-Za-z0-9]+}", handler.editTimelineEvent).Methods(http.MethodPut)
 	incidentRouterAuthorized.HandleFunc("/timeline/{eventID:[A-Za-z0-9]+}", handler.removeTimelineEvent).Methods(http.MethodDelete)
 	incidentRouterAuthorized.HandleFunc("/check-and-send-message-on-join/{channel_id:[A-Za-z0-9]+}", handler.checkAndSendMessageOnJoin).Methods(http.MethodGet)
+	incidentRouterAuthorized.HandleFunc("/webhook-deliveries", handler.getWebhookDeliveries).Methods(http.MethodGet)
+	incidentRouterAuthorized.HandleFunc("/links", handler.linkIncident).Methods(http.MethodPost)
+	incidentRouterAuthorized.HandleFunc("/links/{linkID:[A-Za-z0-9]+}", handler.unlinkIncident).Methods(http.MethodDelete)
+	incidentRouterAuthorized.HandleFunc("/resolve-children/button", handler.resolveChildrenButton).Methods(http.MethodPost)
+	incidentRouterAuthorized.HandleFunc("/merge", handler.mergeIncident).Methods(http.MethodPost)
+	incidentRouterAuthorized.HandleFunc("/action-items", handler.addActionItem).Methods(http.MethodPost)
+	incidentRouterAuthorized.HandleFunc("/action-items/{itemID:[A-Za-z0-9]+}", handler.updateActionItem).Methods(http.MethodPut)
+	incidentRouterAuthorized.HandleFunc("/action-items/{itemID:[A-Za-z0-9]+}", handler.removeActionItem).Methods(http.MethodDelete)
+	incidentRouterAuthorized.HandleFunc("/action-items/{itemID:[A-Za-z0-9]+}/state", handler.setActionItemState).Methods(http.MethodPut)
 
 	channelRouter := incidentsRouter.PathPrefix("/channel").Subrouter()
 	channelRouter.HandleFunc("/{channel_id:[A-Za-z0-9]+}", handler.getIncidentByChannel).Methods(http.MethodGet)
@@ -89,11 +109,16 @@ func NewIncidentHandler(router *mux.Router, incidentService incident.Service, pl
 	checklistItem.HandleFunc("", handler.itemEdit).Methods(http.MethodPut)
 	checklistItem.HandleFunc("/state", handler.itemSetState).Methods(http.MethodPut)
 	checklistItem.HandleFunc("/assignee", handler.itemSetAssignee).Methods(http.MethodPut)
+	checklistItem.HandleFunc("/assignee-role", handler.itemSetAssigneeRole).Methods(http.MethodPut)
 	checklistItem.HandleFunc("/run", handler.itemRun).Methods(http.MethodPost)
 
 	retrospectiveRouter := incidentRouterAuthorized.PathPrefix("/retrospective").Subrouter()
 	retrospectiveRouter.HandleFunc("", handler.updateRetrospective).Methods(http.MethodPost)
 	retrospectiveRouter.HandleFunc("/publish", handler.publishRetrospective).Methods(http.MethodPost)
+	retrospectiveRouter.HandleFunc("/sections", handler.updateRetrospectiveSections).Methods(http.MethodPut)
+
+	actionItemsRouter := router.PathPrefix("/action-items").Subrouter()
+	actionItemsRouter.HandleFunc("", handler.getActionItems).Methods(http.MethodGet)
 
 	return handler
 }
@@ -138,15 +163,23 @@ func (h *IncidentHandler) createIncidentFromPost(w http.ResponseWriter, r *http.
 	}
 
 	payloadIncident := incident.Incident{
-		OwnerUserID: incidentCreateOptions.OwnerUserID,
-		TeamID:      incidentCreateOptions.TeamID,
-		Name:        incidentCreateOptions.Name,
-		Description: incidentCreateOptions.Description,
-		PostID:      incidentCreateOptions.PostID,
-		PlaybookID:  incidentCreateOptions.PlaybookID,
+		OwnerUserID:       incidentCreateOptions.OwnerUserID,
+		TeamID:            incidentCreateOptions.TeamID,
+		Name:              incidentCreateOptions.Name,
+		Description:       incidentCreateOptions.Description,
+		PostID:            incidentCreateOptions.PostID,
+		PlaybookID:        incidentCreateOptions.PlaybookID,
+		Severity:          incidentCreateOptions.Severity,
+		CustomFieldValues: incidentCreateOptions.CustomFieldValues,
 	}
 
-	newIncident, err := h.createIncident(payloadIncident, userID)
+	newIncident, err := h.createIncident(payloadIncident, userID, nil)
+
+	var fieldErr *playbook.CustomFieldError
+	if errors.As(err, &fieldErr) {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "unable to create incident: "+fieldErr.Error(), err)
+		return
+	}
 
 	if errors.Is(err, incident.ErrPermission) {
 		h.HandleErrorWithCode(w, http.StatusForbidden, "unable to create incident", err)
@@ -222,13 +255,16 @@ func (h *IncidentHandler) createIncidentFromDialog(w http.ResponseWriter, r *htt
 		return
 	}
 
-	var playbookID, name string
+	var playbookID, name, severity string
 	if rawPlaybookID, ok := request.Submission[incident.DialogFieldPlaybookIDKey].(string); ok {
 		playbookID = rawPlaybookID
 	}
 	if rawName, ok := request.Submission[incident.DialogFieldNameKey].(string); ok {
 		name = rawName
 	}
+	if rawSeverity, ok := request.Submission[incident.DialogFieldSeverityKey].(string); ok {
+		severity = rawSeverity
+	}
 
 	payloadIncident := incident.Incident{
 		OwnerUserID: request.UserId,
@@ -236,10 +272,17 @@ func (h *IncidentHandler) createIncidentFromDialog(w http.ResponseWriter, r *htt
 		Name:        name,
 		PostID:      state.PostID,
 		PlaybookID:  playbookID,
+		Severity:    severity,
 	}
 
-	newIncident, err := h.createIncident(payloadIncident, request.UserId)
+	newIncident, err := h.createIncident(payloadIncident, request.UserId, request.Submission)
 	if err != nil {
+		var fieldErr *playbook.CustomFieldError
+		if errors.As(err, &fieldErr) {
+			writeCustomFieldDialogError(w, playbookID, fieldErr)
+			return
+		}
+
 		if errors.Is(err, incident.ErrMalformedIncident) {
 			h.HandleErrorWithCode(w, http.StatusBadRequest, "unable to create incident", err)
 			return
@@ -330,7 +373,10 @@ func (h *IncidentHandler) addToTimelineDialog(w http.ResponseWriter, r *http.Req
 	w.WriteHeader(http.StatusOK)
 }
 
-func (h *IncidentHandler) createIncident(newIncident incident.Incident, userID string) (*incident.Incident, error) {
+// createIncident checks and creates newIncident on behalf of userID. If dialogSubmission is not
+// nil, newIncident was submitted with the creation dialog, and the values of the custom fields of
+// its playbook are read from the submission.
+func (h *IncidentHandler) createIncident(newIncident incident.Incident, userID string, dialogSubmission map[string]interface{}) (*incident.Incident, error) {
 	if newIncident.ID != "" {
 		return nil, errors.Wrap(incident.ErrMalformedIncident, "incident already has an id")
 	}
@@ -355,6 +401,10 @@ func (h *IncidentHandler) createIncident(newIncident incident.Incident, userID s
 		return nil, errors.Wrap(incident.ErrMalformedIncident, "missing name of incident")
 	}
 
+	if !incident.IsValidSeverity(newIncident.Severity) {
+		return nil, errors.Wrapf(incident.ErrMalformedIncident, "invalid severity '%s'", newIncident.Severity)
+	}
+
 	// Owner should have permission to the team
 	if !permissions.CanViewTeam(newIncident.OwnerUserID, newIncident.TeamID, h.pluginAPI) {
 		return nil, errors.Wrap(incident.ErrPermission, "owner user does not have permissions for the team")
@@ -372,45 +422,17 @@ func (h *IncidentHandler) createIncident(newIncident incident.Incident, userID s
 			return nil, errors.New("userID is not a member of playbook")
 		}
 
-		newIncident.Checklists = pb.Checklists
-		public = pb.CreatePublicIncident
-
-		newIncident.BroadcastChannelID = pb.BroadcastChannelID
-		newIncident.Description = pb.Description
-		newIncident.ReminderMessageTemplate = pb.ReminderMessageTemplate
-		newIncident.PreviousReminder = time.Duration(pb.ReminderTimerDefaultSeconds) * time.Second
-
-		newIncident.InvitedUserIDs = []string{}
-		newIncident.InvitedGroupIDs = []string{}
-		if pb.InviteUsersEnabled {
-			newIncident.InvitedUserIDs = pb.InvitedUserIDs
-			newIncident.InvitedGroupIDs = pb.InvitedGroupIDs
-		}
-
-		if pb.DefaultOwnerEnabled {
-			newIncident.DefaultOwnerID = pb.DefaultOwnerID
-		}
-
-		if pb.AnnouncementChannelEnabled {
-			newIncident.AnnouncementChannelID = pb.AnnouncementChannelID
-		}
-
-		if pb.WebhookOnCreationEnabled {
-			newIncident.WebhookOnCreationURL = pb.WebhookOnCreationURL
-		}
-
-		if pb.WebhookOnStatusUpdateEnabled {
-			newIncident.WebhookOnStatusUpdateURL = pb.WebhookOnStatusUpdateURL
-		}
-
-		if pb.MessageOnJoinEnabled {
-			newIncident.MessageOnJoin = pb.MessageOnJoin
-		}
+		public = applyPlaybook(&newIncident, pb)
+		thePlaybook = &pb
+	}
 
-		newIncident.RetrospectiveReminderIntervalSeconds = pb.RetrospectiveReminderIntervalSeconds
-		newIncident.Retrospective = pb.RetrospectiveTemplate
+	// The creation dialog lists the custom fields of every playbook: read those of the chosen one.
+	if dialogSubmission != nil && len(newIncident.CustomFields) > 0 {
+		newIncident.CustomFieldValues = incident.CustomFieldValuesFromDialog(newIncident.PlaybookID, newIncident.CustomFields, dialogSubmission)
+	}
 
-		thePlaybook = &pb
+	if err := playbook.ValidateCustomFieldValues(newIncident.CustomFields, newIncident.CustomFieldValues); err != nil {
+		return nil, errors.Wrap(err, "invalid custom field values")
 	}
 
 	permission := model.PERMISSION_CREATE_PRIVATE_CHANNEL
@@ -435,6 +457,60 @@ func (h *IncidentHandler) createIncident(newIncident incident.Incident, userID s
 	return h.incidentService.CreateIncident(&newIncident, thePlaybook, userID, public)
 }
 
+// applyPlaybook copies the settings of pb into newIncident, and returns whether the channel of
+// the incident should be public.
+func applyPlaybook(newIncident *incident.Incident, pb playbook.Playbook) bool {
+	newIncident.Checklists = pb.Checklists
+	newIncident.PlaybookRevision = pb.Revision
+	newIncident.CustomFields = playbook.CloneCustomFields(pb.CustomFields)
+	newIncident.Roles = playbook.CloneRoles(pb.Roles)
+	newIncident.AcknowledgeTimeoutSeconds = pb.AcknowledgeTimeoutSeconds
+	newIncident.EscalationSteps = playbook.CloneEscalationSteps(pb.EscalationSteps)
+	newIncident.StatusUpdateTemplate = pb.StatusUpdateTemplate
+
+	newIncident.BroadcastChannelID = pb.BroadcastChannelID
+	newIncident.BroadcastTargets = playbook.CloneBroadcastTargets(pb.BroadcastTargets)
+	newIncident.Description = pb.Description
+	newIncident.ReminderMessageTemplate = pb.ReminderMessageTemplate
+	newIncident.PreviousReminder = time.Duration(pb.ReminderTimerDefaultSeconds) * time.Second
+
+	newIncident.InvitedUserIDs = []string{}
+	newIncident.InvitedGroupIDs = []string{}
+	if pb.InviteUsersEnabled {
+		newIncident.InvitedUserIDs = pb.InvitedUserIDs
+		newIncident.InvitedGroupIDs = pb.InvitedGroupIDs
+	}
+
+	if pb.DefaultOwnerEnabled {
+		newIncident.DefaultOwnerID = pb.DefaultOwnerID
+	}
+
+	if pb.AnnouncementChannelEnabled {
+		newIncident.AnnouncementChannelID = pb.AnnouncementChannelID
+	}
+
+	if pb.WebhookOnCreationEnabled {
+		newIncident.WebhookOnCreationURL = pb.WebhookOnCreationURL
+	}
+
+	if pb.WebhookOnStatusUpdateEnabled {
+		newIncident.WebhookOnStatusUpdateURL = pb.WebhookOnStatusUpdateURL
+	}
+
+	newIncident.WebhookSecret = pb.WebhookSecret
+	newIncident.WebhookSubscriptions = playbook.CloneWebhookSubscriptions(pb.WebhookSubscriptions)
+
+	if pb.MessageOnJoinEnabled {
+		newIncident.MessageOnJoin = pb.MessageOnJoin
+	}
+
+	newIncident.RetrospectiveReminderIntervalSeconds = pb.RetrospectiveReminderIntervalSeconds
+	newIncident.Retrospective = pb.RetrospectiveTemplate
+	newIncident.RetrospectiveTemplate = pb.RetrospectiveTemplate
+
+	return pb.CreatePublicIncident
+}
+
 func (h *IncidentHandler) getRequesterInfo(userID string) (permissions.RequesterInfo, error) {
 	return permissions.GetRequesterInfo(userID, h.pluginAPI)
 }
@@ -671,248 +747,783 @@ func (h *IncidentHandler) changeOwner(w http.ResponseWriter, r *http.Request) {
 	ReturnJSON(w, map[string]interface{}{}, http.StatusOK)
 }
 
-// updateStatusD handles the POST /incidents/{id}/status endpoint, user has edit permissions
-func (h *IncidentHandler) status(w http.ResponseWriter, r *http.Request) {
+// acknowledge handles the POST /incidents/{id}/acknowledge endpoint, user has edit permissions
+func (h *IncidentHandler) acknowledge(w http.ResponseWriter, r *http.Request) {
 	incidentID := mux.Vars(r)["id"]
 	userID := r.Header.Get("Mattermost-User-ID")
 
-	incidentToModify, err := h.incidentService.GetIncident(incidentID)
-	if err != nil {
+	if err := h.incidentService.AcknowledgeIncident(incidentID, userID); err != nil {
 		h.HandleError(w, err)
 		return
 	}
 
-	if !permissions.CanPostToChannel(userID, incidentToModify.ChannelID, h.pluginAPI) {
-		h.HandleErrorWithCode(w, http.StatusForbidden, "Not authorized", fmt.Errorf("user %s cannot post to incident channel %s", userID, incidentToModify.ChannelID))
-		return
-	}
+	ReturnJSON(w, map[string]interface{}{}, http.StatusOK)
+}
 
-	var options incident.StatusUpdateOptions
-	if err = json.NewDecoder(r.Body).Decode(&options); err != nil {
-		h.HandleErrorWithCode(w, http.StatusBadRequest, "unable to decode body into StatusUpdateOptions", err)
-		return
-	}
+// acknowledgeButton handles the POST /incidents/{id}/acknowledge/button endpoint, called when a
+// user clicks on the interactive button of an acknowledgment request; user has edit permissions
+func (h *IncidentHandler) acknowledgeButton(w http.ResponseWriter, r *http.Request) {
+	incidentID := mux.Vars(r)["id"]
+	userID := r.Header.Get("Mattermost-User-ID")
 
-	options.Description = strings.TrimSpace(options.Description)
-	if options.Description == "" {
-		h.HandleErrorWithCode(w, http.StatusBadRequest, "description must not be empty", errors.New("description field empty"))
+	if err := h.incidentService.AcknowledgeIncident(incidentID, userID); err != nil {
+		h.HandleErrorWithCode(w, http.StatusInternalServerError, "unable to acknowledge incident", err)
 		return
 	}
 
-	options.Message = strings.TrimSpace(options.Message)
-	if options.Message == "" {
-		h.HandleErrorWithCode(w, http.StatusBadRequest, "message must not be empty", errors.New("message field empty"))
-		return
-	}
+	ReturnJSON(w, nil, http.StatusOK)
+}
 
-	options.Reminder = options.Reminder * time.Second
+// changeSeverity handles the POST /incidents/{id}/severity endpoint, user has edit permissions
+func (h *IncidentHandler) changeSeverity(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID := r.Header.Get("Mattermost-User-ID")
 
-	options.Status = strings.TrimSpace(options.Status)
-	if options.Status == "" {
-		h.HandleErrorWithCode(w, http.StatusBadRequest, "status must not be empty", errors.New("status field empty"))
+	var params struct {
+		Severity string `json:"severity"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "could not decode request body", err)
 		return
 	}
-	switch options.Status {
-	case incident.StatusActive:
-	case incident.StatusArchived:
-	case incident.StatusReported:
-	case incident.StatusResolved:
-		break
-	default:
-		h.HandleErrorWithCode(w, http.StatusBadRequest, "invalid status", nil)
+
+	params.Severity = strings.TrimSpace(params.Severity)
+	if !incident.IsValidSeverity(params.Severity) {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "invalid severity", errors.Errorf("invalid severity '%s'", params.Severity))
 		return
 	}
 
-	err = h.incidentService.UpdateStatus(incidentID, userID, options)
-	if err != nil {
+	if err := h.incidentService.ChangeSeverity(vars["id"], userID, params.Severity); err != nil {
 		h.HandleError(w, err)
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	_, _ = w.Write([]byte(`{"status":"OK"}`))
+	ReturnJSON(w, map[string]interface{}{}, http.StatusOK)
 }
 
-// updateStatusDialog handles the POST /incidents/{id}/update-status-dialog endpoint, called when a
-// user submits the Update Status dialog.
-func (h *IncidentHandler) updateStatusDialog(w http.ResponseWriter, r *http.Request) {
-	incidentID := mux.Vars(r)["id"]
+// assignRole handles the PUT /incidents/{id}/roles/{role} endpoint, user has edit permissions
+func (h *IncidentHandler) assignRole(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
 	userID := r.Header.Get("Mattermost-User-ID")
 
-	incidentToModify, err := h.incidentService.GetIncident(incidentID)
-	if err != nil {
-		h.HandleError(w, err)
-		return
-	}
-
-	if !permissions.CanPostToChannel(userID, incidentToModify.ChannelID, h.pluginAPI) {
-		h.HandleErrorWithCode(w, http.StatusForbidden, "Not authorized", fmt.Errorf("user %s cannot post to incident channel %s", userID, incidentToModify.ChannelID))
-		return
+	var params struct {
+		UserIDs []string `json:"user_ids"`
 	}
-
-	request := model.SubmitDialogRequestFromJson(r.Body)
-	if request == nil {
-		h.HandleErrorWithCode(w, http.StatusBadRequest, "failed to decode SubmitDialogRequest", nil)
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "could not decode request body", err)
 		return
 	}
 
-	var options incident.StatusUpdateOptions
-	if message, ok := request.Submission[incident.DialogFieldMessageKey]; ok {
-		options.Message = strings.TrimSpace(message.(string))
-	}
-	if options.Message == "" {
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte(fmt.Sprintf(`{"errors": {"%s":"This field is required."}}`, incident.DialogFieldMessageKey)))
+	incdnt, err := h.incidentService.GetIncident(vars["id"])
+	if err != nil {
+		h.HandleError(w, err)
 		return
 	}
 
-	if description, ok := request.Submission[incident.DialogFieldDescriptionKey]; ok {
-		options.Description = strings.TrimSpace(description.(string))
-	}
-	if options.Description == "" {
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte(fmt.Sprintf(`{"errors": {"%s":"This field is required."}}`, incident.DialogFieldDescriptionKey)))
-		return
+	// Check if the target users have permissions
+	for _, assigneeID := range params.UserIDs {
+		if err2 := permissions.EditIncident(assigneeID, incdnt.ChannelID, h.pluginAPI); err2 != nil {
+			if errors.Is(err2, permissions.ErrNoPermissions) {
+				h.HandleErrorWithCode(w, http.StatusForbidden, "Not authorized",
+					errors.Errorf("userid: %s does not have permissions to incident channel; cannot be assigned a role", assigneeID))
+				return
+			}
+			h.HandleError(w, err2)
+			return
+		}
 	}
 
-	if reminderI, ok := request.Submission[incident.DialogFieldReminderInSecondsKey]; ok {
-		if reminder, err2 := strconv.Atoi(reminderI.(string)); err2 == nil {
-			options.Reminder = time.Duration(reminder) * time.Second
+	if err = h.incidentService.AssignRole(vars["id"], userID, vars["role"], params.UserIDs); err != nil {
+		if errors.Is(err, incident.ErrUnknownRole) {
+			h.HandleErrorWithCode(w, http.StatusBadRequest, err.Error(), err)
+			return
 		}
+		h.HandleError(w, err)
+		return
 	}
 
-	if status, ok := request.Submission[incident.DialogFieldStatusKey]; ok {
-		options.Status = status.(string)
-	}
+	ReturnJSON(w, map[string]interface{}{}, http.StatusOK)
+}
 
-	switch options.Status {
-	case incident.StatusActive:
-	case incident.StatusArchived:
-	case incident.StatusReported:
-	case incident.StatusResolved:
-		break
-	default:
-		h.HandleErrorWithCode(w, http.StatusBadRequest, "invalid status", nil)
-		return
-	}
+// getWebhookDeliveries handles the GET /incidents/{id}/webhook-deliveries endpoint, user has edit permissions
+func (h *IncidentHandler) getWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	incidentID := mux.Vars(r)["id"]
 
-	err = h.incidentService.UpdateStatus(incidentID, userID, options)
+	deliveries, err := h.incidentService.GetWebhookDeliveries(incidentID)
 	if err != nil {
 		h.HandleError(w, err)
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
+	ReturnJSON(w, deliveries, http.StatusOK)
 }
 
-// reminderButtonUpdate handles the POST /incidents/{id}/reminder/button-update endpoint, called when a
-// user clicks on the reminder interactive button
-func (h *IncidentHandler) reminderButtonUpdate(w http.ResponseWriter, r *http.Request) {
-	requestData := model.PostActionIntegrationRequestFromJson(r.Body)
-	if requestData == nil {
-		h.HandleErrorWithCode(w, http.StatusBadRequest, "missing request data", nil)
+// linkIncident handles the POST /incidents/{id}/links endpoint, user has edit permissions
+func (h *IncidentHandler) linkIncident(w http.ResponseWriter, r *http.Request) {
+	incidentID := mux.Vars(r)["id"]
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	var params struct {
+		LinkedIncidentID string `json:"linked_incident_id"`
+		Type             string `json:"type"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "could not decode request body", err)
 		return
 	}
 
-	incidentID, err := h.incidentService.GetIncidentIDForChannel(requestData.ChannelId)
+	linkedIncident, err := h.incidentService.GetIncident(params.LinkedIncidentID)
 	if err != nil {
-		h.HandleErrorWithCode(w, http.StatusInternalServerError, "error getting incident",
-			errors.Wrapf(err, "reminderButtonUpdate failed to find incidentID for channelID: %s", requestData.ChannelId))
+		if errors.Is(err, incident.ErrNotFound) {
+			h.HandleErrorWithCode(w, http.StatusNotFound, "Not found",
+				errors.Errorf("incident with id %s not found", params.LinkedIncidentID))
+			return
+		}
+		h.HandleError(w, err)
 		return
 	}
 
-	if err = permissions.EditIncident(requestData.UserId, requestData.ChannelId, h.pluginAPI); err != nil {
+	// Check that the user can also edit the linked incident
+	if err = permissions.EditIncident(userID, linkedIncident.ChannelID, h.pluginAPI); err != nil {
 		if errors.Is(err, permissions.ErrNoPermissions) {
-			ReturnJSON(w, nil, http.StatusForbidden)
+			h.HandleErrorWithCode(w, http.StatusForbidden, "Not authorized",
+				errors.Errorf("userid: %s does not have permissions to the linked incident channel", userID))
 			return
 		}
-		h.HandleErrorWithCode(w, http.StatusInternalServerError, "error getting permissions", err)
+		h.HandleError(w, err)
 		return
 	}
 
-	if err = h.incidentService.OpenUpdateStatusDialog(incidentID, requestData.TriggerId); err != nil {
-		h.HandleError(w, errors.New("reminderButtonUpdate failed to open update status dialog"))
+	linkID, err := h.incidentService.LinkIncidents(incidentID, userID, params.LinkedIncidentID, params.Type)
+	if err != nil {
+		if errors.Is(err, incident.ErrMalformedLink) {
+			h.HandleErrorWithCode(w, http.StatusBadRequest, err.Error(), err)
+			return
+		}
+		if errors.Is(err, incident.ErrDuplicateEntry) {
+			h.HandleErrorWithCode(w, http.StatusConflict, "the incidents are already linked", err)
+			return
+		}
+		h.HandleError(w, err)
 		return
 	}
 
-	ReturnJSON(w, nil, http.StatusOK)
+	ReturnJSON(w, map[string]string{"id": linkID}, http.StatusCreated)
 }
 
-// reminderButtonDismiss handles the POST /incidents/{id}/reminder/button-dismiss endpoint, called when a
-// user clicks on the reminder interactive button
-func (h *IncidentHandler) reminderButtonDismiss(w http.ResponseWriter, r *http.Request) {
-	requestData := model.PostActionIntegrationRequestFromJson(r.Body)
-	if requestData == nil {
-		h.HandleErrorWithCode(w, http.StatusBadRequest, "missing request data", nil)
+// unlinkIncident handles the DELETE /incidents/{id}/links/{linkID} endpoint, user has edit permissions
+// to both incidents
+func (h *IncidentHandler) unlinkIncident(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	theIncident, err := h.incidentService.GetIncident(vars["id"])
+	if err != nil {
+		h.HandleError(w, err)
 		return
 	}
 
-	incidentID, err := h.incidentService.GetIncidentIDForChannel(requestData.ChannelId)
-	if err != nil {
-		h.log.Errorf("reminderButtonDismiss: no incident for requestData's channelID: %s", requestData.ChannelId)
-		h.HandleErrorWithCode(w, http.StatusBadRequest, "no incident for requestData's channelID", err)
+	var link *incident.LinkedIncident
+	for i := range theIncident.Links {
+		if theIncident.Links[i].LinkID == vars["linkID"] {
+			link = &theIncident.Links[i]
+		}
+	}
+	if link == nil {
+		h.HandleErrorWithCode(w, http.StatusNotFound, "Not found",
+			errors.Errorf("incident %s has no link %s", vars["id"], vars["linkID"]))
 		return
 	}
 
-	if err = permissions.EditIncident(requestData.UserId, requestData.ChannelId, h.pluginAPI); err != nil {
+	// Check that the user can also edit the linked incident
+	if err = permissions.EditIncident(userID, link.ChannelID, h.pluginAPI); err != nil {
 		if errors.Is(err, permissions.ErrNoPermissions) {
-			ReturnJSON(w, nil, http.StatusForbidden)
+			h.HandleErrorWithCode(w, http.StatusForbidden, "Not authorized",
+				errors.Errorf("userid: %s does not have permissions to the linked incident channel", userID))
 			return
 		}
-		h.HandleErrorWithCode(w, http.StatusInternalServerError, "error getting permissions", err)
+		h.HandleError(w, err)
 		return
 	}
 
-	if err = h.incidentService.RemoveReminderPost(incidentID); err != nil {
-		h.log.Errorf("reminderButtonDismiss: error removing reminder for channelID: %s; error: %s", requestData.ChannelId, err.Error())
-		h.HandleErrorWithCode(w, http.StatusBadRequest, "error removing reminder", err)
+	if err = h.incidentService.UnlinkIncidents(vars["id"], userID, vars["linkID"]); err != nil {
+		if errors.Is(err, incident.ErrNotFound) {
+			h.HandleErrorWithCode(w, http.StatusNotFound, "Not found", err)
+			return
+		}
+		h.HandleError(w, err)
 		return
 	}
 
-	ReturnJSON(w, nil, http.StatusOK)
+	w.WriteHeader(http.StatusNoContent)
 }
 
-func (h *IncidentHandler) noRetrospectiveButton(w http.ResponseWriter, r *http.Request) {
+// resolveChildrenButton handles the POST /incidents/{id}/resolve-children/button endpoint, called
+// when a user clicks on the button offering to resolve the children of a resolved incident; user
+// has edit permissions
+func (h *IncidentHandler) resolveChildrenButton(w http.ResponseWriter, r *http.Request) {
 	incidentID := mux.Vars(r)["id"]
 	userID := r.Header.Get("Mattermost-User-ID")
 
-	incidentToCancelRetro, err := h.incidentService.GetIncident(incidentID)
+	parent, err := h.incidentService.GetIncident(incidentID)
 	if err != nil {
 		h.HandleError(w, err)
 		return
 	}
 
-	if err = permissions.EditIncident(userID, incidentToCancelRetro.ChannelID, h.pluginAPI); err != nil {
-		if errors.Is(err, permissions.ErrNoPermissions) {
-			ReturnJSON(w, nil, http.StatusForbidden)
+	// Check that the user can also edit the children to resolve
+	for _, child := range parent.Children() {
+		if !child.IsActive() {
+			continue
+		}
+		if err = permissions.EditIncident(userID, child.ChannelID, h.pluginAPI); err != nil {
+			if errors.Is(err, permissions.ErrNoPermissions) {
+				h.HandleErrorWithCode(w, http.StatusForbidden, "Not authorized",
+					errors.Errorf("userid: %s does not have permissions to the channel of child incident %s", userID, child.IncidentID))
+				return
+			}
+			h.HandleError(w, err)
 			return
 		}
-		h.HandleErrorWithCode(w, http.StatusInternalServerError, "error getting permissions", err)
-		return
 	}
 
-	if err := h.incidentService.CancelRetrospective(incidentID, userID); err != nil {
-		h.HandleErrorWithCode(w, http.StatusInternalServerError, "unable to cancel retrospective", err)
+	if _, err = h.incidentService.ResolveChildren(incidentID, userID); err != nil {
+		h.HandleErrorWithCode(w, http.StatusInternalServerError, "unable to resolve child incidents", err)
 		return
 	}
 
 	ReturnJSON(w, nil, http.StatusOK)
 }
 
-// removeTimelineEvent handles the DELETE /incidents/{id}/timeline/{eventID} endpoint.
-// User has been authenticated to edit the incident.
-func (h *IncidentHandler) removeTimelineEvent(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	id := vars["id"]
+// mergeIncident handles the POST /incidents/{id}/merge endpoint, user has edit permissions
+func (h *IncidentHandler) mergeIncident(w http.ResponseWriter, r *http.Request) {
+	incidentID := mux.Vars(r)["id"]
 	userID := r.Header.Get("Mattermost-User-ID")
-	eventID := vars["eventID"]
 
-	if err := h.incidentService.RemoveTimelineEvent(id, userID, eventID); err != nil {
-		h.HandleError(w, err)
+	var params struct {
+		TargetIncidentID string `json:"target_incident_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "could not decode request body", err)
 		return
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	target, err := h.incidentService.GetIncident(params.TargetIncidentID)
+	if err != nil {
+		if errors.Is(err, incident.ErrNotFound) {
+			h.HandleErrorWithCode(w, http.StatusNotFound, "Not found",
+				errors.Errorf("incident with id %s not found", params.TargetIncidentID))
+			return
+		}
+		h.HandleError(w, err)
+		return
+	}
+
+	// Check that the user can also edit the incident to merge into
+	if err = permissions.EditIncident(userID, target.ChannelID, h.pluginAPI); err != nil {
+		if errors.Is(err, permissions.ErrNoPermissions) {
+			h.HandleErrorWithCode(w, http.StatusForbidden, "Not authorized",
+				errors.Errorf("userid: %s does not have permissions to the target incident channel", userID))
+			return
+		}
+		h.HandleError(w, err)
+		return
+	}
+
+	if err = h.incidentService.MergeIncident(incidentID, userID, params.TargetIncidentID); err != nil {
+		if errors.Is(err, incident.ErrInvalidMerge) {
+			h.HandleErrorWithCode(w, http.StatusBadRequest, err.Error(), err)
+			return
+		}
+		h.HandleError(w, err)
+		return
+	}
+
+	ReturnJSON(w, map[string]interface{}{}, http.StatusOK)
+}
+
+// follow handles the PUT /incidents/{id}/followers endpoint, subscribing the user to the status
+// updates of the incident. Any user who can view the incident can follow it.
+func (h *IncidentHandler) follow(w http.ResponseWriter, r *http.Request) {
+	incidentID := mux.Vars(r)["id"]
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	if !h.hasViewAccess(w, incidentID, userID) {
+		return
+	}
+
+	if err := h.incidentService.FollowIncident(incidentID, userID); err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	ReturnJSON(w, map[string]interface{}{}, http.StatusOK)
+}
+
+// unfollow handles the DELETE /incidents/{id}/followers endpoint, unsubscribing the user from the
+// status updates of the incident.
+func (h *IncidentHandler) unfollow(w http.ResponseWriter, r *http.Request) {
+	incidentID := mux.Vars(r)["id"]
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	if !h.hasViewAccess(w, incidentID, userID) {
+		return
+	}
+
+	if err := h.incidentService.UnfollowIncident(incidentID, userID); err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// hasViewAccess returns true if userID can view incidentID. Otherwise, it writes the error to w.
+func (h *IncidentHandler) hasViewAccess(w http.ResponseWriter, incidentID, userID string) bool {
+	theIncident, err := h.incidentService.GetIncident(incidentID)
+	if err != nil {
+		if errors.Is(err, incident.ErrNotFound) {
+			h.HandleErrorWithCode(w, http.StatusNotFound, "Not found",
+				errors.Errorf("incident with id %s not found", incidentID))
+			return false
+		}
+		h.HandleError(w, err)
+		return false
+	}
+
+	if err = permissions.ViewIncidentFromChannelID(userID, theIncident.ChannelID, h.pluginAPI); err != nil {
+		h.HandleErrorWithCode(w, http.StatusForbidden, "User doesn't have permissions to incident.", nil)
+		return false
+	}
+
+	return true
+}
+
+// updateStatusD handles the POST /incidents/{id}/status endpoint, user has edit permissions
+func (h *IncidentHandler) status(w http.ResponseWriter, r *http.Request) {
+	incidentID := mux.Vars(r)["id"]
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	incidentToModify, err := h.incidentService.GetIncident(incidentID)
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	if !permissions.CanPostToChannel(userID, incidentToModify.ChannelID, h.pluginAPI) {
+		h.HandleErrorWithCode(w, http.StatusForbidden, "Not authorized", fmt.Errorf("user %s cannot post to incident channel %s", userID, incidentToModify.ChannelID))
+		return
+	}
+
+	var options incident.StatusUpdateOptions
+	if err = json.NewDecoder(r.Body).Decode(&options); err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "unable to decode body into StatusUpdateOptions", err)
+		return
+	}
+
+	options.Description = strings.TrimSpace(options.Description)
+	if options.Description == "" {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "description must not be empty", errors.New("description field empty"))
+		return
+	}
+
+	options.Message = strings.TrimSpace(options.Message)
+	if options.Message == "" {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "message must not be empty", errors.New("message field empty"))
+		return
+	}
+
+	options.Reminder = options.Reminder * time.Second
+
+	options.Status = strings.TrimSpace(options.Status)
+	if options.Status == "" {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "status must not be empty", errors.New("status field empty"))
+		return
+	}
+	switch options.Status {
+	case incident.StatusActive:
+	case incident.StatusArchived:
+	case incident.StatusReported:
+	case incident.StatusResolved:
+		break
+	default:
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "invalid status", nil)
+		return
+	}
+
+	if options.CustomFieldValues != nil {
+		if err = playbook.ValidateCustomFieldValues(incidentToModify.CustomFields, options.CustomFieldValues); err != nil {
+			h.HandleErrorWithCode(w, http.StatusBadRequest, err.Error(), err)
+			return
+		}
+	}
+
+	err = h.incidentService.UpdateStatus(incidentID, userID, options)
+	if errors.Is(err, incident.ErrIncidentMerged) {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "the incident was merged into another incident", err)
+		return
+	} else if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(`{"status":"OK"}`))
+}
+
+// updateStatusDialog handles the POST /incidents/{id}/update-status-dialog endpoint, called when a
+// user submits the Update Status dialog.
+func (h *IncidentHandler) updateStatusDialog(w http.ResponseWriter, r *http.Request) {
+	incidentID := mux.Vars(r)["id"]
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	incidentToModify, err := h.incidentService.GetIncident(incidentID)
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	if !permissions.CanPostToChannel(userID, incidentToModify.ChannelID, h.pluginAPI) {
+		h.HandleErrorWithCode(w, http.StatusForbidden, "Not authorized", fmt.Errorf("user %s cannot post to incident channel %s", userID, incidentToModify.ChannelID))
+		return
+	}
+
+	request := model.SubmitDialogRequestFromJson(r.Body)
+	if request == nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "failed to decode SubmitDialogRequest", nil)
+		return
+	}
+
+	var options incident.StatusUpdateOptions
+	if message, ok := request.Submission[incident.DialogFieldMessageKey]; ok {
+		options.Message = strings.TrimSpace(message.(string))
+	}
+	if options.Message == "" {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"errors": {"%s":"This field is required."}}`, incident.DialogFieldMessageKey)))
+		return
+	}
+
+	if description, ok := request.Submission[incident.DialogFieldDescriptionKey]; ok {
+		options.Description = strings.TrimSpace(description.(string))
+	}
+	if options.Description == "" {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"errors": {"%s":"This field is required."}}`, incident.DialogFieldDescriptionKey)))
+		return
+	}
+
+	if reminderI, ok := request.Submission[incident.DialogFieldReminderInSecondsKey]; ok {
+		if reminder, err2 := strconv.Atoi(reminderI.(string)); err2 == nil {
+			options.Reminder = time.Duration(reminder) * time.Second
+		}
+	}
+
+	if status, ok := request.Submission[incident.DialogFieldStatusKey]; ok {
+		options.Status = status.(string)
+	}
+
+	switch options.Status {
+	case incident.StatusActive:
+	case incident.StatusArchived:
+	case incident.StatusReported:
+	case incident.StatusResolved:
+		break
+	default:
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "invalid status", nil)
+		return
+	}
+
+	if len(incidentToModify.CustomFields) > 0 {
+		options.CustomFieldValues = incident.CustomFieldValuesFromDialog("", incidentToModify.CustomFields, request.Submission)
+		if err = playbook.ValidateCustomFieldValues(incidentToModify.CustomFields, options.CustomFieldValues); err != nil {
+			writeCustomFieldDialogError(w, "", err)
+			return
+		}
+	}
+
+	err = h.incidentService.UpdateStatus(incidentID, userID, options)
+	if errors.Is(err, incident.ErrIncidentMerged) {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "the incident was merged into another incident", err)
+		return
+	} else if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// writeCustomFieldDialogError writes err, returned by playbook.ValidateCustomFieldValues, as the
+// error of the dialog element of the invalid custom field of the playbook playbookID, blank
+// outside of the creation dialog.
+func writeCustomFieldDialogError(w http.ResponseWriter, playbookID string, err error) {
+	var fieldErr *playbook.CustomFieldError
+	if !errors.As(err, &fieldErr) {
+		fieldErr = &playbook.CustomFieldError{Message: err.Error()}
+	}
+
+	resp := &model.SubmitDialogResponse{
+		Errors: map[string]string{
+			incident.CustomFieldDialogElementName(playbookID, fieldErr.Key): fieldErr.Message,
+		},
+	}
+	_, _ = w.Write(resp.ToJson())
+}
+
+// reminderButtonUpdate handles the POST /incidents/{id}/reminder/button-update endpoint, called when a
+// user clicks on the reminder interactive button
+func (h *IncidentHandler) reminderButtonUpdate(w http.ResponseWriter, r *http.Request) {
+	requestData := model.PostActionIntegrationRequestFromJson(r.Body)
+	if requestData == nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "missing request data", nil)
+		return
+	}
+
+	incidentID, err := h.incidentService.GetIncidentIDForChannel(requestData.ChannelId)
+	if err != nil {
+		h.HandleErrorWithCode(w, http.StatusInternalServerError, "error getting incident",
+			errors.Wrapf(err, "reminderButtonUpdate failed to find incidentID for channelID: %s", requestData.ChannelId))
+		return
+	}
+
+	if err = permissions.EditIncident(requestData.UserId, requestData.ChannelId, h.pluginAPI); err != nil {
+		if errors.Is(err, permissions.ErrNoPermissions) {
+			ReturnJSON(w, nil, http.StatusForbidden)
+			return
+		}
+		h.HandleErrorWithCode(w, http.StatusInternalServerError, "error getting permissions", err)
+		return
+	}
+
+	err = h.incidentService.OpenUpdateStatusDialog(incidentID, requestData.TriggerId)
+	if errors.Is(err, incident.ErrIncidentMerged) {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "the incident was merged into another incident", err)
+		return
+	} else if err != nil {
+		h.HandleError(w, errors.New("reminderButtonUpdate failed to open update status dialog"))
+		return
+	}
+
+	ReturnJSON(w, nil, http.StatusOK)
+}
+
+// reminderButtonDismiss handles the POST /incidents/{id}/reminder/button-dismiss endpoint, called when a
+// user clicks on the reminder interactive button
+func (h *IncidentHandler) reminderButtonDismiss(w http.ResponseWriter, r *http.Request) {
+	requestData := model.PostActionIntegrationRequestFromJson(r.Body)
+	if requestData == nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "missing request data", nil)
+		return
+	}
+
+	incidentID, err := h.incidentService.GetIncidentIDForChannel(requestData.ChannelId)
+	if err != nil {
+		h.log.Errorf("reminderButtonDismiss: no incident for requestData's channelID: %s", requestData.ChannelId)
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "no incident for requestData's channelID", err)
+		return
+	}
+
+	if err = permissions.EditIncident(requestData.UserId, requestData.ChannelId, h.pluginAPI); err != nil {
+		if errors.Is(err, permissions.ErrNoPermissions) {
+			ReturnJSON(w, nil, http.StatusForbidden)
+			return
+		}
+		h.HandleErrorWithCode(w, http.StatusInternalServerError, "error getting permissions", err)
+		return
+	}
+
+	if err = h.incidentService.RemoveReminderPost(incidentID); err != nil {
+		h.log.Errorf("reminderButtonDismiss: error removing reminder for channelID: %s; error: %s", requestData.ChannelId, err.Error())
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "error removing reminder", err)
+		return
+	}
+
+	ReturnJSON(w, nil, http.StatusOK)
+}
+
+func (h *IncidentHandler) noRetrospectiveButton(w http.ResponseWriter, r *http.Request) {
+	incidentID := mux.Vars(r)["id"]
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	incidentToCancelRetro, err := h.incidentService.GetIncident(incidentID)
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	if err = permissions.EditIncident(userID, incidentToCancelRetro.ChannelID, h.pluginAPI); err != nil {
+		if errors.Is(err, permissions.ErrNoPermissions) {
+			ReturnJSON(w, nil, http.StatusForbidden)
+			return
+		}
+		h.HandleErrorWithCode(w, http.StatusInternalServerError, "error getting permissions", err)
+		return
+	}
+
+	if err := h.incidentService.CancelRetrospective(incidentID, userID); err != nil {
+		h.HandleErrorWithCode(w, http.StatusInternalServerError, "unable to cancel retrospective", err)
+		return
+	}
+
+	ReturnJSON(w, nil, http.StatusOK)
+}
+
+// removeTimelineEvent handles the DELETE /incidents/{id}/timeline/{eventID} endpoint.
+// User has been authenticated to edit the incident.
+func (h *IncidentHandler) removeTimelineEvent(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+	userID := r.Header.Get("Mattermost-User-ID")
+	eventID := vars["eventID"]
+
+	if err := h.incidentService.RemoveTimelineEvent(id, userID, eventID); err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// addTimelineEvent handles the POST /incidents/{id}/timeline endpoint, user has edit permissions
+func (h *IncidentHandler) addTimelineEvent(w http.ResponseWriter, r *http.Request) {
+	incidentID := mux.Vars(r)["id"]
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	var options incident.TimelineEventOptions
+	if err := json.NewDecoder(r.Body).Decode(&options); err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "could not decode request body", err)
+		return
+	}
+
+	eventID, err := h.incidentService.AddTimelineEvent(incidentID, userID, options)
+	if err != nil {
+		if errors.Is(err, incident.ErrMalformedTimelineEvent) {
+			h.HandleErrorWithCode(w, http.StatusBadRequest, err.Error(), err)
+			return
+		}
+		h.HandleError(w, err)
+		return
+	}
+
+	ReturnJSON(w, map[string]string{"id": eventID}, http.StatusCreated)
+}
+
+// editTimelineEvent handles the PUT /incidents/{id}/timeline/{eventID} endpoint, user has edit
+// permissions
+func (h *IncidentHandler) editTimelineEvent(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	var options incident.TimelineEventOptions
+	if err := json.NewDecoder(r.Body).Decode(&options); err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "could not decode request body", err)
+		return
+	}
+
+	if err := h.incidentService.EditTimelineEvent(vars["id"], userID, vars["eventID"], options); err != nil {
+		if errors.Is(err, incident.ErrMalformedTimelineEvent) {
+			h.HandleErrorWithCode(w, http.StatusBadRequest, err.Error(), err)
+			return
+		}
+		if errors.Is(err, incident.ErrNotFound) {
+			h.HandleErrorWithCode(w, http.StatusNotFound, "Not found", err)
+			return
+		}
+		h.HandleError(w, err)
+		return
+	}
+
+	ReturnJSON(w, map[string]interface{}{}, http.StatusOK)
+}
+
+// getTimelineEventEdits handles the GET /incidents/{id}/timeline/{eventID}/edits endpoint.
+func (h *IncidentHandler) getTimelineEventEdits(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	incidentID := vars["id"]
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	if !h.hasViewAccess(w, incidentID, userID) {
+		return
+	}
+
+	edits, err := h.incidentService.GetTimelineEventEdits(incidentID, vars["eventID"])
+	if err != nil {
+		if errors.Is(err, incident.ErrNotFound) {
+			h.HandleErrorWithCode(w, http.StatusNotFound, "Not found", err)
+			return
+		}
+		h.HandleError(w, err)
+		return
+	}
+
+	ReturnJSON(w, edits, http.StatusOK)
+}
+
+// getTimelineEvents handles the GET /incidents/{id}/timeline endpoint. It returns a page of the
+// timeline of the incident, filtered by the request.
+func (h *IncidentHandler) getTimelineEvents(w http.ResponseWriter, r *http.Request) {
+	incidentID := mux.Vars(r)["id"]
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	options, err := parseTimelineFilterOptions(r.URL)
+	if err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "Bad parameter", err)
+		return
+	}
+	if err = incident.ValidateTimelineFilterOptions(options); err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "Bad parameter", err)
+		return
+	}
+
+	if !h.hasViewAccess(w, incidentID, userID) {
+		return
+	}
+
+	results, err := h.incidentService.GetTimelineEvents(incidentID, *options)
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	ReturnJSON(w, results, http.StatusOK)
+}
+
+// exportTimeline handles the GET /incidents/{id}/timeline/export endpoint. It returns the timeline
+// of the incident as a markdown table, or as a CSV or iCalendar file, restricted to the event
+// types and the time window of the request.
+func (h *IncidentHandler) exportTimeline(w http.ResponseWriter, r *http.Request) {
+	incidentID := mux.Vars(r)["id"]
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	options, err := parseTimelineExportOptions(r.URL)
+	if err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	if err = incident.ValidateTimelineExportOptions(options); err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	if !h.hasViewAccess(w, incidentID, userID) {
+		return
+	}
+
+	data, err := h.incidentService.ExportTimeline(incidentID, *options)
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", options.Format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q",
+		incident.TimelineExportFileName(incidentID, options.Format)))
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(data); err != nil {
+		h.log.Warnf("failed to write the timeline export of incident %s: %v", incidentID, err)
+	}
 }
 
 // checkAndSendMessageOnJoin handles the GET /incident/{id}/check_and_send_message_on_join/{channel_id} endpoint.
@@ -1005,6 +1616,10 @@ func (h *IncidentHandler) itemSetState(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.incidentService.ModifyCheckedState(id, userID, params.NewState, checklistNum, itemNum); err != nil {
+		if errors.Is(err, incident.ErrChecklistItemBlocked) {
+			h.HandleErrorWithCode(w, http.StatusBadRequest, err.Error(), err)
+			return
+		}
 		h.HandleError(w, err)
 		return
 	}
@@ -1043,6 +1658,41 @@ func (h *IncidentHandler) itemSetAssignee(w http.ResponseWriter, r *http.Request
 	ReturnJSON(w, map[string]interface{}{}, http.StatusOK)
 }
 
+func (h *IncidentHandler) itemSetAssigneeRole(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+	checklistNum, err := strconv.Atoi(vars["checklist"])
+	if err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "failed to parse checklist", err)
+		return
+	}
+	itemNum, err := strconv.Atoi(vars["item"])
+	if err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "failed to parse item", err)
+		return
+	}
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	var params struct {
+		AssigneeRole string `json:"assignee_role"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "failed to unmarshal", err)
+		return
+	}
+
+	if err := h.incidentService.SetAssigneeRole(id, userID, params.AssigneeRole, checklistNum, itemNum); err != nil {
+		if errors.Is(err, incident.ErrUnknownRole) {
+			h.HandleErrorWithCode(w, http.StatusBadRequest, err.Error(), err)
+			return
+		}
+		h.HandleError(w, err)
+		return
+	}
+
+	ReturnJSON(w, map[string]interface{}{}, http.StatusOK)
+}
+
 func (h *IncidentHandler) itemRun(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	incidentID := vars["id"]
@@ -1090,6 +1740,12 @@ func (h *IncidentHandler) addChecklistItem(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	if checklistItem.DueAfterSeconds < 0 {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "bad parameter: checklist item due time",
+			errors.Errorf("negative due time of %d seconds", checklistItem.DueAfterSeconds))
+		return
+	}
+
 	if err := h.incidentService.AddChecklistItem(id, userID, checklistNum, checklistItem); err != nil {
 		h.HandleError(w, err)
 		return
@@ -1290,6 +1946,297 @@ func (h *IncidentHandler) publishRetrospective(w http.ResponseWriter, r *http.Re
 	w.WriteHeader(http.StatusOK)
 }
 
+// updateRetrospectiveSections handles the PUT /incidents/{id}/retrospective/sections endpoint,
+// user has edit permissions
+func (h *IncidentHandler) updateRetrospectiveSections(w http.ResponseWriter, r *http.Request) {
+	incidentID := mux.Vars(r)["id"]
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	var sections incident.RetrospectiveSections
+	if err := json.NewDecoder(r.Body).Decode(&sections); err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "unable to decode payload", err)
+		return
+	}
+
+	if err := h.incidentService.UpdateRetrospectiveSections(incidentID, userID, sections); err != nil {
+		h.HandleErrorWithCode(w, http.StatusInternalServerError, "unable to update retrospective sections", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// actionItemParams is the payload creating or updating an action item.
+type actionItemParams struct {
+	Title       string `json:"title"`
+	OwnerUserID string `json:"owner_user_id"`
+	DueAt       int64  `json:"due_at"`
+}
+
+// addActionItem handles the POST /incidents/{id}/action-items endpoint, user has edit permissions
+func (h *IncidentHandler) addActionItem(w http.ResponseWriter, r *http.Request) {
+	incidentID := mux.Vars(r)["id"]
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	var params actionItemParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "could not decode request body", err)
+		return
+	}
+
+	itemID, err := h.incidentService.AddActionItem(incidentID, userID, incident.ActionItem{
+		Title:       params.Title,
+		OwnerUserID: params.OwnerUserID,
+		DueAt:       params.DueAt,
+	})
+	if err != nil {
+		if errors.Is(err, incident.ErrMalformedActionItem) {
+			h.HandleErrorWithCode(w, http.StatusBadRequest, err.Error(), err)
+			return
+		}
+		h.HandleError(w, err)
+		return
+	}
+
+	ReturnJSON(w, map[string]string{"id": itemID}, http.StatusCreated)
+}
+
+// updateActionItem handles the PUT /incidents/{id}/action-items/{itemID} endpoint, user has edit
+// permissions
+func (h *IncidentHandler) updateActionItem(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	var params actionItemParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "could not decode request body", err)
+		return
+	}
+
+	err := h.incidentService.UpdateActionItem(vars["id"], userID, incident.ActionItem{
+		ID:          vars["itemID"],
+		Title:       params.Title,
+		OwnerUserID: params.OwnerUserID,
+		DueAt:       params.DueAt,
+	})
+	if err != nil {
+		if errors.Is(err, incident.ErrMalformedActionItem) {
+			h.HandleErrorWithCode(w, http.StatusBadRequest, err.Error(), err)
+			return
+		}
+		if errors.Is(err, incident.ErrNotFound) {
+			h.HandleErrorWithCode(w, http.StatusNotFound, "Not found", err)
+			return
+		}
+		h.HandleError(w, err)
+		return
+	}
+
+	ReturnJSON(w, map[string]interface{}{}, http.StatusOK)
+}
+
+// setActionItemState handles the PUT /incidents/{id}/action-items/{itemID}/state endpoint, user
+// has edit permissions
+func (h *IncidentHandler) setActionItemState(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	var params struct {
+		Done bool `json:"done"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "could not decode request body", err)
+		return
+	}
+
+	if err := h.incidentService.SetActionItemDone(vars["id"], userID, vars["itemID"], params.Done); err != nil {
+		if errors.Is(err, incident.ErrNotFound) {
+			h.HandleErrorWithCode(w, http.StatusNotFound, "Not found", err)
+			return
+		}
+		h.HandleError(w, err)
+		return
+	}
+
+	ReturnJSON(w, map[string]interface{}{}, http.StatusOK)
+}
+
+// removeActionItem handles the DELETE /incidents/{id}/action-items/{itemID} endpoint, user has
+// edit permissions
+func (h *IncidentHandler) removeActionItem(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	if err := h.incidentService.RemoveActionItem(vars["id"], userID, vars["itemID"]); err != nil {
+		if errors.Is(err, incident.ErrNotFound) {
+			h.HandleErrorWithCode(w, http.StatusNotFound, "Not found", err)
+			return
+		}
+		h.HandleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getActionItems handles the GET /action-items endpoint, listing the action items of the incidents
+// of a team that the user can view.
+func (h *IncidentHandler) getActionItems(w http.ResponseWriter, r *http.Request) {
+	options, err := parseActionItemFilterOptions(r.URL)
+	if err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "Bad parameter", err)
+		return
+	}
+	if err = incident.ValidateActionItemOptions(options); err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "Bad parameter", err)
+		return
+	}
+
+	userID := r.Header.Get("Mattermost-User-ID")
+	// More detailed permissions checked on DB level.
+	if !permissions.CanViewTeam(userID, options.TeamID, h.pluginAPI) {
+		h.HandleErrorWithCode(w, http.StatusForbidden, "permissions error", errors.Errorf(
+			"userID %s does not have view permission for teamID %s", userID, options.TeamID))
+		return
+	}
+
+	requesterInfo, err := h.getRequesterInfo(userID)
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	results, err := h.incidentService.GetActionItems(requesterInfo, *options)
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	ReturnJSON(w, results, http.StatusOK)
+}
+
+// parseActionItemFilterOptions is only for parsing. Put validation logic in
+// incident.ValidateActionItemOptions.
+func parseActionItemFilterOptions(u *url.URL) (*incident.ActionItemFilterOptions, error) {
+	teamID := u.Query().Get("team_id")
+	if teamID == "" {
+		return nil, errors.New("bad parameter 'team_id'; 'team_id' is required")
+	}
+
+	page, err := parseIntParam(u, "page")
+	if err != nil {
+		return nil, err
+	}
+
+	perPage, err := parseIntParam(u, "per_page")
+	if err != nil {
+		return nil, err
+	}
+
+	return &incident.ActionItemFilterOptions{
+		TeamID:     teamID,
+		Page:       page,
+		PerPage:    perPage,
+		IncidentID: u.Query().Get("incident_id"),
+		OwnerID:    u.Query().Get("owner_user_id"),
+		Status:     u.Query().Get("status"),
+	}, nil
+}
+
+// parseTimelineExportOptions is only for parsing. Put validation logic in
+// incident.ValidateTimelineExportOptions.
+func parseTimelineExportOptions(u *url.URL) (*incident.TimelineExportOptions, error) {
+	eventTypes := parseEventTypesParam(u)
+
+	since, err := parseInt64Param(u, "since")
+	if err != nil {
+		return nil, err
+	}
+
+	until, err := parseInt64Param(u, "until")
+	if err != nil {
+		return nil, err
+	}
+
+	return &incident.TimelineExportOptions{
+		Format:     incident.TimelineExportFormat(strings.ToLower(u.Query().Get("format"))),
+		EventTypes: eventTypes,
+		Since:      since,
+		Until:      until,
+	}, nil
+}
+
+// parseTimelineFilterOptions is only for parsing. Put validation logic in
+// incident.ValidateTimelineFilterOptions.
+func parseTimelineFilterOptions(u *url.URL) (*incident.TimelineFilterOptions, error) {
+	perPage, err := parseIntParam(u, "per_page")
+	if err != nil {
+		return nil, err
+	}
+
+	var excludeUserJoinedLeft bool
+	if param := u.Query().Get("exclude_user_joined_left"); param != "" {
+		excludeUserJoinedLeft, err = strconv.ParseBool(param)
+		if err != nil {
+			return nil, errors.Wrap(err, "bad parameter 'exclude_user_joined_left'")
+		}
+	}
+
+	return &incident.TimelineFilterOptions{
+		Cursor:                u.Query().Get("cursor"),
+		PerPage:               perPage,
+		Direction:             u.Query().Get("direction"),
+		EventTypes:            parseEventTypesParam(u),
+		UserID:                u.Query().Get("user_id"),
+		ExcludeUserJoinedLeft: excludeUserJoinedLeft,
+	}, nil
+}
+
+// parseEventTypesParam parses the event_type query parameters of u, which are either repeated or
+// comma-separated.
+func parseEventTypesParam(u *url.URL) []string {
+	var eventTypes []string
+	for _, param := range u.Query()["event_type"] {
+		for _, eventType := range strings.Split(param, ",") {
+			if eventType = strings.TrimSpace(eventType); eventType != "" {
+				eventTypes = append(eventTypes, eventType)
+			}
+		}
+	}
+
+	return eventTypes
+}
+
+// parseInt64Param parses the query parameter name of u as a 64-bit integer, defaulting to 0.
+func parseInt64Param(u *url.URL, name string) (int64, error) {
+	param := u.Query().Get(name)
+	if param == "" {
+		return 0, nil
+	}
+
+	value, err := strconv.ParseInt(param, 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "bad parameter '%s'", name)
+	}
+
+	return value, nil
+}
+
+// parseIntParam parses the query parameter name of u as an integer, defaulting to 0.
+func parseIntParam(u *url.URL, name string) (int, error) {
+	param := u.Query().Get(name)
+	if param == "" {
+		return 0, nil
+	}
+
+	value, err := strconv.Atoi(param)
+	if err != nil {
+		return 0, errors.Wrapf(err, "bad parameter '%s'", name)
+	}
+
+	return value, nil
+}
+
 // parseIncidentsFilterOptions is only for parsing. Put validation logic in incident.validateOptions.
 func parseIncidentsFilterOptions(u *url.URL) (*incident.FilterOptions, error) {
 	teamID := u.Query().Get("team_id")
@@ -1327,17 +2274,39 @@ func parseIncidentsFilterOptions(u *url.URL) (*incident.FilterOptions, error) {
 
 	playbookID := u.Query().Get("playbook_id")
 
+	severity := u.Query().Get("severity")
+
+	// Custom field filters are given as key:value, once per field.
+	var customFields map[string]string
+	for _, param := range u.Query()["custom_field"] {
+		parts := strings.SplitN(param, ":", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("bad parameter 'custom_field': '%s' is not of the form key:value", param)
+		}
+		if customFields == nil {
+			customFields = map[string]string{}
+		}
+		customFields[parts[0]] = parts[1]
+	}
+
+	role := u.Query().Get("role")
+	roleUserID := u.Query().Get("role_user_id")
+
 	return &incident.FilterOptions{
-		TeamID:     teamID,
-		Page:       page,
-		PerPage:    perPage,
-		Sort:       sort,
-		Direction:  direction,
-		Status:     status,
-		OwnerID:    ownerID,
-		SearchTerm: searchTerm,
-		MemberID:   memberID,
-		PlaybookID: playbookID,
+		TeamID:       teamID,
+		Page:         page,
+		PerPage:      perPage,
+		Sort:         sort,
+		Direction:    direction,
+		Status:       status,
+		OwnerID:      ownerID,
+		SearchTerm:   searchTerm,
+		MemberID:     memberID,
+		PlaybookID:   playbookID,
+		Severity:     severity,
+		CustomFields: customFields,
+		Role:         role,
+		RoleUserID:   roleUserID,
 	}, nil
 }
 
@@ -1448,6 +1448,32 @@ func TestIncidents(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("update incident status, incident merged", func(t *testing.T) {
+		reset(t)
+		setDefaultExpectations(t)
+		logger.EXPECT().Warnf(gomock.Any(), gomock.Any(), gomock.Any())
+
+		testIncident := incident.Incident{
+			ID:            "incidentID",
+			OwnerUserID:   "testUserID",
+			TeamID:        "testTeamID",
+			Name:          "incidentName",
+			ChannelID:     "channelID",
+			CurrentStatus: incident.StatusMerged,
+		}
+
+		incidentService.EXPECT().GetIncidentIDForChannel(testIncident.ChannelID).Return(testIncident.ID, nil)
+		pluginAPI.On("HasPermissionTo", mock.Anything, model.PERMISSION_MANAGE_SYSTEM).Return(false)
+		incidentService.EXPECT().GetIncident(testIncident.ID).Return(&testIncident, nil).Times(2)
+		pluginAPI.On("HasPermissionToChannel", mock.Anything, mock.Anything, model.PERMISSION_READ_CHANNEL).Return(true)
+		pluginAPI.On("HasPermissionToChannel", mock.Anything, mock.Anything, model.PERMISSION_CREATE_POST).Return(true)
+		incidentService.EXPECT().UpdateStatus("incidentID", "testUserID", gomock.Any()).
+			Return(errors.Wrap(incident.ErrIncidentMerged, "cannot update the status of a merged incident"))
+
+		err := c.Incidents.UpdateStatus(context.TODO(), "incidentID", icClient.StatusActive, "test description", "test message", 600)
+		requireErrorWithStatusCode(t, err, http.StatusBadRequest)
+	})
+
 	t.Run("update incident status, bad status", func(t *testing.T) {
 		reset(t)
 		setDefaultExpectations(t)
@@ -1562,4 +1588,48 @@ func TestIncidents(t *testing.T) {
 		err := c.Incidents.UpdateStatus(context.TODO(), "incidentID", "Active", "  \r \n  ", "test message", 600)
 		requireErrorWithStatusCode(t, err, http.StatusBadRequest)
 	})
+
+	t.Run("unlink incidents", func(t *testing.T) {
+		reset(t)
+
+		testIncident := incident.Incident{
+			ID:        "incidentID",
+			TeamID:    "testTeamID",
+			ChannelID: "channelID",
+			Links: []incident.LinkedIncident{
+				{LinkID: "linkID", Type: incident.LinkRelated, IncidentID: "linkedIncidentID", ChannelID: "linkedChannelID"},
+			},
+		}
+
+		pluginAPI.On("HasPermissionTo", mock.Anything, model.PERMISSION_MANAGE_SYSTEM).Return(false)
+		incidentService.EXPECT().GetIncident(testIncident.ID).Return(&testIncident, nil).Times(2)
+		pluginAPI.On("HasPermissionToChannel", "testUserID", "channelID", model.PERMISSION_READ_CHANNEL).Return(true)
+		pluginAPI.On("HasPermissionToChannel", "testUserID", "linkedChannelID", model.PERMISSION_READ_CHANNEL).Return(true)
+		incidentService.EXPECT().UnlinkIncidents("incidentID", "testUserID", "linkID").Return(nil)
+
+		err := c.Incidents.Unlink(context.TODO(), "incidentID", "linkID")
+		require.NoError(t, err)
+	})
+
+	t.Run("unlink incidents, no permission to edit the linked incident", func(t *testing.T) {
+		reset(t)
+		logger.EXPECT().Warnf(gomock.Any(), gomock.Any(), gomock.Any())
+
+		testIncident := incident.Incident{
+			ID:        "incidentID",
+			TeamID:    "testTeamID",
+			ChannelID: "channelID",
+			Links: []incident.LinkedIncident{
+				{LinkID: "linkID", Type: incident.LinkRelated, IncidentID: "linkedIncidentID", ChannelID: "linkedChannelID"},
+			},
+		}
+
+		pluginAPI.On("HasPermissionTo", mock.Anything, model.PERMISSION_MANAGE_SYSTEM).Return(false)
+		incidentService.EXPECT().GetIncident(testIncident.ID).Return(&testIncident, nil).Times(2)
+		pluginAPI.On("HasPermissionToChannel", "testUserID", "channelID", model.PERMISSION_READ_CHANNEL).Return(true)
+		pluginAPI.On("HasPermissionToChannel", "testUserID", "linkedChannelID", model.PERMISSION_READ_CHANNEL).Return(false)
+
+		err := c.Incidents.Unlink(context.TODO(), "incidentID", "linkID")
+		requireErrorWithStatusCode(t, err, http.StatusForbidden)
+	})
 }
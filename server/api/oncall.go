@@ -0,0 +1,306 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	pluginapi "github.com/mattermost/mattermost-plugin-api"
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/bot"
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/oncall"
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/permissions"
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/pkg/errors"
+)
+
+// maxShiftsPerPage is the maximum number of upcoming shifts returned at once.
+const maxShiftsPerPage = 100
+
+// OnCallHandler handles the on-call rotations of teams. Any member of a team can see and manage
+// its rotations.
+type OnCallHandler struct {
+	*ErrorHandler
+	onCallService oncall.Service
+	pluginAPI     *pluginapi.Client
+	log           bot.Logger
+}
+
+// NewOnCallHandler returns a new on-call api handler.
+func NewOnCallHandler(router *mux.Router, onCallService oncall.Service, api *pluginapi.Client, log bot.Logger) *OnCallHandler {
+	handler := &OnCallHandler{
+		ErrorHandler:  &ErrorHandler{log: log},
+		onCallService: onCallService,
+		pluginAPI:     api,
+		log:           log,
+	}
+
+	rotationsRouter := router.PathPrefix("/oncall/rotations").Subrouter()
+	rotationsRouter.HandleFunc("", handler.createRotation).Methods(http.MethodPost)
+	rotationsRouter.HandleFunc("", handler.getRotations).Methods(http.MethodGet)
+
+	rotationRouter := rotationsRouter.PathPrefix("/{id:[A-Za-z0-9]+}").Subrouter()
+	rotationRouter.HandleFunc("", handler.getRotation).Methods(http.MethodGet)
+	rotationRouter.HandleFunc("", handler.updateRotation).Methods(http.MethodPut)
+	rotationRouter.HandleFunc("", handler.deleteRotation).Methods(http.MethodDelete)
+	rotationRouter.HandleFunc("/shifts", handler.getShifts).Methods(http.MethodGet)
+	rotationRouter.HandleFunc("/overrides", handler.addOverride).Methods(http.MethodPost)
+	rotationRouter.HandleFunc("/overrides/{overrideID:[A-Za-z0-9]+}", handler.removeOverride).Methods(http.MethodDelete)
+
+	return handler
+}
+
+func (h *OnCallHandler) createRotation(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	var rotation oncall.Rotation
+	if err := json.NewDecoder(r.Body).Decode(&rotation); err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "unable to decode rotation", err)
+		return
+	}
+
+	if rotation.ID != "" {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "Rotation given already has ID", nil)
+		return
+	}
+
+	if !permissions.CanViewTeam(userID, rotation.TeamID, h.pluginAPI) {
+		h.HandleErrorWithCode(w, http.StatusForbidden, "Not authorized",
+			errors.Errorf("userID %s does not have permission to create rotations on teamID %s", userID, rotation.TeamID))
+		return
+	}
+
+	if err := h.validateRotation(rotation); err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "invalid rotation: "+err.Error(), err)
+		return
+	}
+
+	id, err := h.onCallService.Create(rotation)
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	result := struct {
+		ID string `json:"id"`
+	}{
+		ID: id,
+	}
+	w.Header().Add("Location", fmt.Sprintf("/api/v0/oncall/rotations/%s", id))
+	ReturnJSON(w, &result, http.StatusCreated)
+}
+
+// getRotations handles the GET /oncall/rotations endpoint, returning the rotations of the team
+// given by team_id.
+func (h *OnCallHandler) getRotations(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	teamID := r.URL.Query().Get("team_id")
+	if teamID == "" {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "Bad parameter 'team_id'; 'team_id' is required", nil)
+		return
+	}
+
+	if !permissions.CanViewTeam(userID, teamID, h.pluginAPI) {
+		h.HandleErrorWithCode(w, http.StatusForbidden, "Not authorized",
+			errors.Errorf("userID %s does not have permission to get rotations on teamID %s", userID, teamID))
+		return
+	}
+
+	rotations, err := h.onCallService.GetRotationsForTeam(teamID)
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	ReturnJSON(w, rotations, http.StatusOK)
+}
+
+func (h *OnCallHandler) getRotation(w http.ResponseWriter, r *http.Request) {
+	rotation, ok := h.getRotationForUser(w, r)
+	if !ok {
+		return
+	}
+
+	ReturnJSON(w, &rotation, http.StatusOK)
+}
+
+func (h *OnCallHandler) updateRotation(w http.ResponseWriter, r *http.Request) {
+	oldRotation, ok := h.getRotationForUser(w, r)
+	if !ok {
+		return
+	}
+
+	var rotation oncall.Rotation
+	if err := json.NewDecoder(r.Body).Decode(&rotation); err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "unable to decode rotation", err)
+		return
+	}
+
+	// The rotation cannot be moved to another team, nor restored once deleted.
+	rotation.ID = oldRotation.ID
+	rotation.TeamID = oldRotation.TeamID
+	rotation.CreateAt = oldRotation.CreateAt
+	rotation.DeleteAt = oldRotation.DeleteAt
+
+	if err := h.validateRotation(rotation); err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "invalid rotation: "+err.Error(), err)
+		return
+	}
+
+	if err := h.onCallService.Update(rotation); err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *OnCallHandler) deleteRotation(w http.ResponseWriter, r *http.Request) {
+	rotation, ok := h.getRotationForUser(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.onCallService.Delete(rotation.ID); err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getShifts handles the GET /oncall/rotations/{id}/shifts endpoint, returning the next count
+// shifts of the rotation, starting with the current one. count defaults to 2: who is on call now,
+// and who is next.
+func (h *OnCallHandler) getShifts(w http.ResponseWriter, r *http.Request) {
+	rotation, ok := h.getRotationForUser(w, r)
+	if !ok {
+		return
+	}
+
+	count := 2
+	if param := r.URL.Query().Get("count"); param != "" {
+		var err error
+		count, err = strconv.Atoi(param)
+		if err != nil || count < 1 || count > maxShiftsPerPage {
+			h.HandleErrorWithCode(w, http.StatusBadRequest,
+				fmt.Sprintf("Bad parameter 'count'; must be a number between 1 and %d", maxShiftsPerPage), err)
+			return
+		}
+	}
+
+	ReturnJSON(w, rotation.Shifts(model.GetMillis(), count), http.StatusOK)
+}
+
+func (h *OnCallHandler) addOverride(w http.ResponseWriter, r *http.Request) {
+	rotation, ok := h.getRotationForUser(w, r)
+	if !ok {
+		return
+	}
+
+	var override oncall.Override
+	if err := json.NewDecoder(r.Body).Decode(&override); err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "unable to decode override", err)
+		return
+	}
+
+	if err := h.validateOverride(rotation.TeamID, override); err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "invalid override: "+err.Error(), err)
+		return
+	}
+
+	id, err := h.onCallService.AddOverride(rotation.ID, override)
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	result := struct {
+		ID string `json:"id"`
+	}{
+		ID: id,
+	}
+	ReturnJSON(w, &result, http.StatusCreated)
+}
+
+func (h *OnCallHandler) removeOverride(w http.ResponseWriter, r *http.Request) {
+	rotation, ok := h.getRotationForUser(w, r)
+	if !ok {
+		return
+	}
+
+	overrideID := mux.Vars(r)["overrideID"]
+	err := h.onCallService.RemoveOverride(rotation.ID, overrideID)
+	if errors.Is(err, oncall.ErrNotFound) {
+		h.HandleErrorWithCode(w, http.StatusNotFound, fmt.Sprintf("override %s not found", overrideID), err)
+		return
+	} else if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getRotationForUser retrieves the rotation given by the id path parameter, writing an error to w
+// and returning false if it does not exist or the user is not a member of its team.
+func (h *OnCallHandler) getRotationForUser(w http.ResponseWriter, r *http.Request) (oncall.Rotation, bool) {
+	userID := r.Header.Get("Mattermost-User-ID")
+	id := mux.Vars(r)["id"]
+
+	rotation, err := h.onCallService.Get(id)
+	if errors.Is(err, oncall.ErrNotFound) {
+		h.HandleErrorWithCode(w, http.StatusNotFound, fmt.Sprintf("rotation %s not found", id), err)
+		return oncall.Rotation{}, false
+	} else if err != nil {
+		h.HandleError(w, err)
+		return oncall.Rotation{}, false
+	}
+
+	if !permissions.CanViewTeam(userID, rotation.TeamID, h.pluginAPI) {
+		h.HandleErrorWithCode(w, http.StatusForbidden, "Not authorized",
+			errors.Errorf("userID %s does not have permission to access the rotations of teamID %s", userID, rotation.TeamID))
+		return oncall.Rotation{}, false
+	}
+
+	return rotation, true
+}
+
+// validateRotation checks that the rotation is valid and that everybody on call in it is a member
+// of its team.
+func (h *OnCallHandler) validateRotation(rotation oncall.Rotation) error {
+	if err := rotation.Validate(); err != nil {
+		return err
+	}
+
+	for _, layer := range rotation.Layers {
+		for _, userID := range layer.UserIDs {
+			if !permissions.CanViewTeam(userID, rotation.TeamID, h.pluginAPI) {
+				return errors.Errorf("user %s is not a member of team %s", userID, rotation.TeamID)
+			}
+		}
+	}
+
+	for _, override := range rotation.Overrides {
+		if err := h.validateOverride(rotation.TeamID, override); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateOverride checks that the override is valid and that its user is a member of teamID.
+func (h *OnCallHandler) validateOverride(teamID string, override oncall.Override) error {
+	if err := override.Validate(); err != nil {
+		return err
+	}
+
+	if !permissions.CanViewTeam(override.UserID, teamID, h.pluginAPI) {
+		return errors.Errorf("user %s is not a member of team %s", override.UserID, teamID)
+	}
+
+	return nil
+}
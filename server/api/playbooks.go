@@ -3,14 +3,18 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/gorilla/mux"
 	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/bot"
 	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/config"
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/incident"
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/oncall"
 	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/permissions"
 	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/playbook"
 	"github.com/mattermost/mattermost-server/v5/model"
@@ -23,6 +27,7 @@ import (
 type PlaybookHandler struct {
 	*ErrorHandler
 	playbookService playbook.Service
+	onCallService   oncall.Service
 	pluginAPI       *pluginapi.Client
 	log             bot.Logger
 	config          config.Service
@@ -31,10 +36,11 @@ type PlaybookHandler struct {
 const SettingsKey = "global_settings"
 
 // NewPlaybookHandler returns a new playbook api handler
-func NewPlaybookHandler(router *mux.Router, playbookService playbook.Service, api *pluginapi.Client, log bot.Logger, configService config.Service) *PlaybookHandler {
+func NewPlaybookHandler(router *mux.Router, playbookService playbook.Service, onCallService oncall.Service, api *pluginapi.Client, log bot.Logger, configService config.Service) *PlaybookHandler {
 	handler := &PlaybookHandler{
 		ErrorHandler:    &ErrorHandler{log: log},
 		playbookService: playbookService,
+		onCallService:   onCallService,
 		pluginAPI:       api,
 		log:             log,
 		config:          configService,
@@ -47,11 +53,19 @@ func NewPlaybookHandler(router *mux.Router, playbookService playbook.Service, ap
 	playbooksRouter.HandleFunc("", handler.getPlaybooks).Methods(http.MethodGet)
 	playbooksRouter.HandleFunc("/autocomplete", handler.getPlaybooksAutoComplete).Methods(http.MethodGet)
 	playbooksRouter.HandleFunc("/count", handler.getPlaybookCount).Methods(http.MethodGet)
+	playbooksRouter.HandleFunc("/import", handler.importPlaybook).Methods(http.MethodPost)
 
 	playbookRouter := playbooksRouter.PathPrefix("/{id:[A-Za-z0-9]+}").Subrouter()
 	playbookRouter.HandleFunc("", handler.getPlaybook).Methods(http.MethodGet)
 	playbookRouter.HandleFunc("", handler.updatePlaybook).Methods(http.MethodPut)
 	playbookRouter.HandleFunc("", handler.deletePlaybook).Methods(http.MethodDelete)
+	playbookRouter.HandleFunc("/export", handler.exportPlaybook).Methods(http.MethodGet)
+	playbookRouter.HandleFunc("/inbound-webhook-token", handler.getInboundWebhookToken).Methods(http.MethodGet)
+	playbookRouter.HandleFunc("/inbound-webhook-token/regenerate", handler.regenerateInboundWebhookToken).Methods(http.MethodPost)
+	playbookRouter.HandleFunc("/revisions", handler.getPlaybookRevisions).Methods(http.MethodGet)
+	playbookRouter.HandleFunc("/revisions/diff", handler.diffPlaybookRevisions).Methods(http.MethodGet)
+	playbookRouter.HandleFunc("/revisions/{revision:[0-9]+}", handler.getPlaybookRevision).Methods(http.MethodGet)
+	playbookRouter.HandleFunc("/revisions/{revision:[0-9]+}/restore", handler.restorePlaybookRevision).Methods(http.MethodPost)
 
 	return handler
 }
@@ -70,52 +84,48 @@ func (h *PlaybookHandler) createPlaybook(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if err := permissions.CreatePlaybook(userID, pbook, h.config, h.pluginAPI, h.playbookService); err != nil {
-		h.HandleErrorWithCode(w, http.StatusForbidden, "Not authorized", err)
+	id, ok := h.validateAndCreatePlaybook(w, pbook, userID)
+	if !ok {
 		return
 	}
 
-	if pbook.WebhookOnCreationEnabled {
-		url, err := url.ParseRequestURI(pbook.WebhookOnCreationURL)
-		if err != nil {
-			h.HandleErrorWithCode(w, http.StatusBadRequest, "invalid creation webhook URL", err)
-			return
-		}
+	result := struct {
+		ID string `json:"id"`
+	}{
+		ID: id,
+	}
+	w.Header().Add("Location", fmt.Sprintf("/api/v0/playbooks/%s", pbook.ID))
+	ReturnJSON(w, &result, http.StatusCreated)
+}
 
-		if url.Scheme != "http" && url.Scheme != "https" {
-			msg := fmt.Sprintf("protocol in creation webhook URL is %s; only HTTP and HTTPS are accepted", url.Scheme)
-			h.HandleErrorWithCode(w, http.StatusBadRequest, msg, errors.Errorf(msg))
-			return
-		}
+// validateAndCreatePlaybook checks that userID may create pbook and that pbook is valid, then
+// creates it. It returns the ID of the new playbook, or false after writing the error response.
+func (h *PlaybookHandler) validateAndCreatePlaybook(w http.ResponseWriter, pbook playbook.Playbook, userID string) (string, bool) {
+	if err := permissions.CreatePlaybook(userID, pbook, h.config, h.pluginAPI, h.playbookService); err != nil {
+		h.HandleErrorWithCode(w, http.StatusForbidden, "Not authorized", err)
+		return "", false
 	}
 
-	if pbook.WebhookOnStatusUpdateEnabled {
-		url, err := url.ParseRequestURI(pbook.WebhookOnStatusUpdateURL)
-		if err != nil {
-			h.HandleErrorWithCode(w, http.StatusBadRequest, "invalid update webhook URL", err)
-			return
-		}
+	filterEscalationSteps(&pbook, h.pluginAPI)
+	if !h.validatePlaybook(w, &pbook) {
+		return "", false
+	}
 
-		if url.Scheme != "http" && url.Scheme != "https" {
-			msg := fmt.Sprintf("protocol in update webhook URL is %s; only HTTP and HTTPS are accepted", url.Scheme)
-			h.HandleErrorWithCode(w, http.StatusBadRequest, msg, errors.Errorf(msg))
-			return
-		}
+	if pbook.WebhookSecret == playbook.MaskedWebhookSecret {
+		pbook.WebhookSecret = ""
+	}
+
+	if pbook.InboundWebhookEnabled && pbook.InboundWebhookToken == "" {
+		pbook.InboundWebhookToken = model.NewId()
 	}
 
 	id, err := h.playbookService.Create(pbook, userID)
 	if err != nil {
 		h.HandleError(w, err)
-		return
+		return "", false
 	}
 
-	result := struct {
-		ID string `json:"id"`
-	}{
-		ID: id,
-	}
-	w.Header().Add("Location", fmt.Sprintf("/api/v0/playbooks/%s", pbook.ID))
-	ReturnJSON(w, &result, http.StatusCreated)
+	return id, true
 }
 
 func (h *PlaybookHandler) getPlaybook(w http.ResponseWriter, r *http.Request) {
@@ -164,41 +174,242 @@ func (h *PlaybookHandler) updatePlaybook(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if !h.validatePlaybook(w, &pbook) {
+		return
+	}
+
+	if pbook.WebhookSecret == playbook.MaskedWebhookSecret {
+		pbook.WebhookSecret = oldPlaybook.WebhookSecret
+	}
+
+	// The token is not part of the playbook's JSON: it is only changed by its own endpoint.
+	pbook.InboundWebhookToken = oldPlaybook.InboundWebhookToken
+
+	if pbook.InboundWebhookEnabled && pbook.InboundWebhookToken == "" {
+		pbook.InboundWebhookToken = model.NewId()
+	}
+
+	err = h.playbookService.Update(pbook, userID)
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// validatePlaybook checks that the settings and checklists of pbook are valid, assigning IDs to
+// its checklist items. It returns false after writing the error response if they are not.
+func (h *PlaybookHandler) validatePlaybook(w http.ResponseWriter, pbook *playbook.Playbook) bool {
 	if pbook.WebhookOnCreationEnabled {
-		url, err2 := url.ParseRequestURI(pbook.WebhookOnCreationURL)
-		if err2 != nil {
-			h.HandleErrorWithCode(w, http.StatusBadRequest, "invalid creation webhook URL", err2)
-			return
+		url, err := url.ParseRequestURI(pbook.WebhookOnCreationURL)
+		if err != nil {
+			h.HandleErrorWithCode(w, http.StatusBadRequest, "invalid creation webhook URL", err)
+			return false
 		}
 
 		if url.Scheme != "http" && url.Scheme != "https" {
 			msg := fmt.Sprintf("protocol in creation webhook URL is %s; only HTTP and HTTPS are accepted", url.Scheme)
 			h.HandleErrorWithCode(w, http.StatusBadRequest, msg, errors.Errorf(msg))
-			return
+			return false
 		}
 	}
 
 	if pbook.WebhookOnStatusUpdateEnabled {
-		url, err2 := url.ParseRequestURI(pbook.WebhookOnStatusUpdateURL)
-		if err2 != nil {
-			h.HandleErrorWithCode(w, http.StatusBadRequest, "invalid update webhook URL", err2)
-			return
+		url, err := url.ParseRequestURI(pbook.WebhookOnStatusUpdateURL)
+		if err != nil {
+			h.HandleErrorWithCode(w, http.StatusBadRequest, "invalid update webhook URL", err)
+			return false
 		}
 
 		if url.Scheme != "http" && url.Scheme != "https" {
 			msg := fmt.Sprintf("protocol in update webhook URL is %s; only HTTP and HTTPS are accepted", url.Scheme)
 			h.HandleErrorWithCode(w, http.StatusBadRequest, msg, errors.Errorf(msg))
-			return
+			return false
 		}
 	}
 
-	err = h.playbookService.Update(pbook, userID)
+	if err := validateWebhookSubscriptions(pbook.WebhookSubscriptions); err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "invalid webhook subscription: "+err.Error(), err)
+		return false
+	}
+
+	playbook.AssignChecklistItemIDs(pbook.Checklists)
+	if err := playbook.ValidatePrerequisites(pbook.Checklists); err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "invalid checklist item prerequisites: "+err.Error(), err)
+		return false
+	}
+
+	if err := validateItemActions(pbook.Checklists); err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "invalid checklist item action: "+err.Error(), err)
+		return false
+	}
+
+	if err := validateItemConditions(pbook.Checklists, pbook.CustomFields); err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "invalid checklist item condition: "+err.Error(), err)
+		return false
+	}
+
+	if err := playbook.ValidateCustomFields(pbook.CustomFields); err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "invalid custom field: "+err.Error(), err)
+		return false
+	}
+
+	if err := playbook.ValidateRoles(pbook.Roles, pbook.Checklists); err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "invalid role: "+err.Error(), err)
+		return false
+	}
+
+	if err := h.validateDefaultOwnerRotation(*pbook); err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "invalid default owner rotation: "+err.Error(), err)
+		return false
+	}
+
+	if err := playbook.ValidateEscalation(pbook.AcknowledgeTimeoutSeconds, pbook.EscalationSteps); err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "invalid escalation: "+err.Error(), err)
+		return false
+	}
+
+	if err := playbook.ValidateStatusUpdateTemplate(pbook.StatusUpdateTemplate); err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "invalid status update template: "+err.Error(), err)
+		return false
+	}
+
+	if err := validateBroadcastTargets(pbook.BroadcastTargets); err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "invalid broadcast target: "+err.Error(), err)
+		return false
+	}
+
+	if err := validateChecklistDueTimes(pbook.Checklists); err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "invalid checklist item due time: "+err.Error(), err)
+		return false
+	}
+
+	if err := validateAlertTemplates(*pbook); err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "invalid inbound webhook template: "+err.Error(), err)
+		return false
+	}
+
+	return true
+}
+
+// validateWebhookSubscriptions checks that every subscription has an HTTP or HTTPS URL and
+// subscribes to at least one known timeline event type.
+func validateWebhookSubscriptions(subscriptions []playbook.WebhookSubscription) error {
+	for _, sub := range subscriptions {
+		subURL, err := url.ParseRequestURI(sub.URL)
+		if err != nil {
+			return errors.Wrapf(err, "invalid URL %s", sub.URL)
+		}
+
+		if subURL.Scheme != "http" && subURL.Scheme != "https" {
+			return errors.Errorf("protocol in URL %s is %s; only HTTP and HTTPS are accepted", sub.URL, subURL.Scheme)
+		}
+
+		if len(sub.EventTypes) == 0 {
+			return errors.Errorf("subscription to %s has no event types", sub.URL)
+		}
+
+		for _, eventType := range sub.EventTypes {
+			if !incident.IsValidTimelineEventType(eventType) {
+				return errors.Errorf("unknown event type %s; expected one of: %s", eventType, strings.Join(incident.TimelineEventTypes, ", "))
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateBroadcastTargets checks that every broadcast target is valid, and that the minimum
+// severity of each target is a known severity level.
+func validateBroadcastTargets(targets []playbook.BroadcastTarget) error {
+	if err := playbook.ValidateBroadcastTargets(targets); err != nil {
+		return err
+	}
+
+	for _, target := range targets {
+		if !incident.IsValidSeverity(target.MinSeverity) {
+			return errors.Errorf("unknown severity %s; expected one of: %s", target.MinSeverity, strings.Join(incident.Severities, ", "))
+		}
+	}
+
+	return nil
+}
+
+// validateChecklistDueTimes checks that no checklist item has a negative due time.
+func validateChecklistDueTimes(checklists []playbook.Checklist) error {
+	for _, checklist := range checklists {
+		for _, item := range checklist.Items {
+			if item.DueAfterSeconds < 0 {
+				return errors.Errorf("item %s has a negative due time of %d seconds", item.Title, item.DueAfterSeconds)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateItemActions checks that the automatic action of every checklist item is valid.
+func validateItemActions(checklists []playbook.Checklist) error {
+	for _, checklist := range checklists {
+		for _, item := range checklist.Items {
+			if err := item.AutomaticAction.Validate(item.Command); err != nil {
+				return errors.Wrapf(err, "item %s", item.Title)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateItemConditions checks that the condition of every checklist item is valid, that
+// conditions on the severity compare it with known severity levels, and that conditions on custom
+// fields check one of customFields.
+func validateItemConditions(checklists []playbook.Checklist, customFields []playbook.CustomField) error {
+	for _, checklist := range checklists {
+		for _, item := range checklist.Items {
+			if err := item.Condition.Validate(); err != nil {
+				return errors.Wrapf(err, "item %s", item.Title)
+			}
+
+			if item.Condition.CustomFieldKey() != "" {
+				if err := playbook.ValidateCustomFieldCondition(item.Condition, customFields); err != nil {
+					return errors.Wrapf(err, "item %s", item.Title)
+				}
+				continue
+			}
+
+			if item.Condition.Field != playbook.ConditionFieldSeverity {
+				continue
+			}
+			for _, value := range item.Condition.Values {
+				if !incident.IsValidSeverity(value) {
+					return errors.Errorf("item %s: unknown severity %s; expected one of: %s", item.Title, value, strings.Join(incident.Severities, ", "))
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateDefaultOwnerRotation checks that the on-call rotation of the playbook's default owner,
+// if any, exists and belongs to the playbook's team.
+func (h *PlaybookHandler) validateDefaultOwnerRotation(pbook playbook.Playbook) error {
+	if pbook.DefaultOwnerRotationID == "" {
+		return nil
+	}
+
+	rotation, err := h.onCallService.Get(pbook.DefaultOwnerRotationID)
 	if err != nil {
-		h.HandleError(w, err)
-		return
+		return errors.Wrapf(err, "failed to get rotation %s", pbook.DefaultOwnerRotationID)
 	}
 
-	w.WriteHeader(http.StatusOK)
+	if rotation.DeleteAt != 0 || rotation.TeamID != pbook.TeamID {
+		return errors.Errorf("rotation %s does not belong to team %s", rotation.ID, pbook.TeamID)
+	}
+
+	return nil
 }
 
 // doPlaybookModificationChecks performs permissions checks that can be resolved though modification of the input.
@@ -214,6 +425,16 @@ func doPlaybookModificationChecks(pbook *playbook.Playbook, userID string, plugi
 	}
 	pbook.InvitedUserIDs = filteredUsers
 
+	filteredFollowers := []string{}
+	for _, followerID := range pbook.AutoFollowerIDs {
+		if !pluginAPI.User.HasPermissionToTeam(followerID, pbook.TeamID, model.PERMISSION_VIEW_TEAM) {
+			pluginAPI.Log.Warn("user does not have permissions to playbook's team, removing from auto followers", "teamID", pbook.TeamID, "userID", followerID)
+			continue
+		}
+		filteredFollowers = append(filteredFollowers, followerID)
+	}
+	pbook.AutoFollowerIDs = filteredFollowers
+
 	filteredGroups := []string{}
 	for _, groupID := range pbook.InvitedGroupIDs {
 		var group *model.Group
@@ -232,6 +453,8 @@ func doPlaybookModificationChecks(pbook *playbook.Playbook, userID string, plugi
 	}
 	pbook.InvitedGroupIDs = filteredGroups
 
+	filterEscalationSteps(pbook, pluginAPI)
+
 	if pbook.DefaultOwnerID != "" && !permissions.IsMemberOfTeamID(pbook.DefaultOwnerID, pbook.TeamID, pluginAPI) {
 		pluginAPI.Log.Warn("owner is not a member of the playbook's team, disabling default owner", "teamID", pbook.TeamID, "userID", pbook.DefaultOwnerID)
 		pbook.DefaultOwnerID = ""
@@ -245,9 +468,86 @@ func doPlaybookModificationChecks(pbook *playbook.Playbook, userID string, plugi
 		pbook.AnnouncementChannelEnabled = false
 	}
 
+	for i := range pbook.Checklists {
+		for j := range pbook.Checklists[i].Items {
+			action := &pbook.Checklists[i].Items[j].AutomaticAction
+
+			if action.ChannelID != "" &&
+				!pluginAPI.User.HasPermissionToChannel(userID, action.ChannelID, model.PERMISSION_CREATE_POST) {
+				pluginAPI.Log.Warn("action channel is not valid, posting to the incident channel instead", "channelID", action.ChannelID)
+				action.ChannelID = ""
+			}
+
+			if len(action.UserIDs) == 0 {
+				continue
+			}
+			filteredInvitees := []string{}
+			for _, inviteeID := range action.UserIDs {
+				if !pluginAPI.User.HasPermissionToTeam(inviteeID, pbook.TeamID, model.PERMISSION_VIEW_TEAM) {
+					pluginAPI.Log.Warn("user does not have permissions to playbook's team, removing from action invite list", "teamID", pbook.TeamID, "userID", inviteeID)
+					continue
+				}
+				filteredInvitees = append(filteredInvitees, inviteeID)
+			}
+			action.UserIDs = filteredInvitees
+		}
+	}
+
 	return nil
 }
 
+// filterEscalationSteps removes from the escalation steps of pbook the users who do not have
+// permissions to its team, and the groups that do not allow references. A step that reassigns the
+// owner stops doing so if its first user is removed, and a step left without users nor groups is
+// removed. This function modifies the pbook argument.
+func filterEscalationSteps(pbook *playbook.Playbook, pluginAPI *pluginapi.Client) {
+	filteredSteps := []playbook.EscalationStep{}
+	for i, step := range pbook.EscalationSteps {
+		filteredUsers := []string{}
+		for _, userID := range step.UserIDs {
+			if !pluginAPI.User.HasPermissionToTeam(userID, pbook.TeamID, model.PERMISSION_VIEW_TEAM) {
+				pluginAPI.Log.Warn("user does not have permissions to playbook's team, removing from escalation step", "teamID", pbook.TeamID, "userID", userID, "step", i+1)
+				continue
+			}
+			filteredUsers = append(filteredUsers, userID)
+		}
+
+		filteredGroups := []string{}
+		for _, groupID := range step.GroupIDs {
+			group, err := pluginAPI.Group.Get(groupID)
+			if err != nil {
+				pluginAPI.Log.Warn("failed to query group", "group_id", groupID)
+				continue
+			}
+
+			if !group.AllowReference {
+				pluginAPI.Log.Warn("group does not allow references, removing from escalation step", "group_id", groupID, "step", i+1)
+				continue
+			}
+
+			filteredGroups = append(filteredGroups, groupID)
+		}
+
+		if step.ReassignOwner && (len(filteredUsers) == 0 || filteredUsers[0] != step.UserIDs[0]) {
+			pluginAPI.Log.Warn("new owner was removed from escalation step, disabling owner reassignment", "step", i+1)
+			step.ReassignOwner = false
+		}
+
+		if len(filteredUsers) == 0 && len(filteredGroups) == 0 {
+			pluginAPI.Log.Warn("escalation step has no users nor groups left, removing it", "step", i+1)
+			continue
+		}
+
+		step.UserIDs = filteredUsers
+		step.GroupIDs = filteredGroups
+		filteredSteps = append(filteredSteps, step)
+	}
+
+	if pbook.EscalationSteps != nil {
+		pbook.EscalationSteps = filteredSteps
+	}
+}
+
 func (h *PlaybookHandler) deletePlaybook(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	userID := r.Header.Get("Mattermost-User-ID")
@@ -272,6 +572,391 @@ func (h *PlaybookHandler) deletePlaybook(w http.ResponseWriter, r *http.Request)
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// exportPlaybook handles the GET /playbooks/{id}/export endpoint. It returns the playbook as a
+// playbook file, in the format given by the format parameter: json, the default, or yaml.
+func (h *PlaybookHandler) exportPlaybook(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	format, err := parsePlaybookFileFormat(r.URL)
+	if err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	pbook, err := h.playbookService.Get(vars["id"])
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	if err2 := permissions.PlaybookAccess(userID, pbook, h.pluginAPI); err2 != nil {
+		h.HandleErrorWithCode(w, http.StatusForbidden, "Not authorized", err2)
+		return
+	}
+
+	file := playbook.NewFile(pbook, playbook.FileMapper{
+		User: func(id string) string {
+			user, err2 := h.pluginAPI.User.Get(id)
+			if err2 != nil {
+				h.log.Warnf("failed to get user %s to export playbook %s: %v", id, pbook.ID, err2)
+				return ""
+			}
+			return user.Username
+		},
+		Group: func(id string) string {
+			group, err2 := h.pluginAPI.Group.Get(id)
+			if err2 != nil || group.Name == nil {
+				h.log.Warnf("failed to get group %s to export playbook %s", id, pbook.ID)
+				return ""
+			}
+			return *group.Name
+		},
+		Channel: func(id string) string {
+			channel, err2 := h.pluginAPI.Channel.Get(id)
+			if err2 != nil {
+				h.log.Warnf("failed to get channel %s to export playbook %s: %v", id, pbook.ID, err2)
+				return ""
+			}
+			return channel.Name
+		},
+	})
+
+	data, err := playbook.MarshalFile(file, format)
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	contentType := "application/json"
+	if format == playbook.FileFormatYAML {
+		contentType = "application/x-yaml"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"playbook-%s.%s\"", pbook.ID, format))
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(data); err != nil {
+		h.log.Warnf("failed to write the export of playbook %s: %v", pbook.ID, err)
+	}
+}
+
+// getPlaybookRevisions handles the GET /playbooks/{id}/revisions endpoint. It returns the
+// revisions of the playbook, latest first, without their snapshot of the playbook.
+func (h *PlaybookHandler) getPlaybookRevisions(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	if !h.hasPlaybookAccess(w, vars["id"], userID) {
+		return
+	}
+
+	revisions, err := h.playbookService.GetRevisions(vars["id"])
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	ReturnJSON(w, revisions, http.StatusOK)
+}
+
+// getPlaybookRevision handles the GET /playbooks/{id}/revisions/{revision} endpoint.
+func (h *PlaybookHandler) getPlaybookRevision(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	if !h.hasPlaybookAccess(w, vars["id"], userID) {
+		return
+	}
+
+	revision, ok := h.getRevision(w, vars["id"], vars["revision"])
+	if !ok {
+		return
+	}
+
+	ReturnJSON(w, &revision, http.StatusOK)
+}
+
+// diffPlaybookRevisions handles the GET /playbooks/{id}/revisions/diff endpoint. It returns the
+// changes from the revision given by the from parameter to the one given by the to parameter.
+func (h *PlaybookHandler) diffPlaybookRevisions(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	if !h.hasPlaybookAccess(w, vars["id"], userID) {
+		return
+	}
+
+	query := r.URL.Query()
+	from, ok := h.getRevision(w, vars["id"], query.Get("from"))
+	if !ok {
+		return
+	}
+	to, ok := h.getRevision(w, vars["id"], query.Get("to"))
+	if !ok {
+		return
+	}
+
+	diff, err := playbook.DiffRevisions(from, to)
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	ReturnJSON(w, &diff, http.StatusOK)
+}
+
+// restorePlaybookRevision handles the POST /playbooks/{id}/revisions/{revision}/restore
+// endpoint. It updates the playbook to the snapshot in the revision, which stores a new revision.
+func (h *PlaybookHandler) restorePlaybookRevision(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	current, err := h.playbookService.Get(vars["id"])
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	revision, ok := h.getRevision(w, vars["id"], vars["revision"])
+	if !ok {
+		return
+	}
+
+	restored := revision.RestoredPlaybook(current)
+
+	if err2 := permissions.PlaybookModify(userID, restored, current, h.config, h.pluginAPI, h.playbookService); err2 != nil {
+		h.HandleErrorWithCode(w, http.StatusForbidden, "Not authorized", err2)
+		return
+	}
+
+	// The revision was valid when it was saved, but the users, groups and settings it references
+	// may not be anymore.
+	if err2 := doPlaybookModificationChecks(&restored, userID, h.pluginAPI); err2 != nil {
+		h.HandleErrorWithCode(w, http.StatusForbidden, "Not authorized", err2)
+		return
+	}
+
+	if !h.validatePlaybook(w, &restored) {
+		return
+	}
+
+	if restored.InboundWebhookEnabled && restored.InboundWebhookToken == "" {
+		restored.InboundWebhookToken = model.NewId()
+	}
+
+	if err = h.playbookService.Update(restored, userID); err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	saved, err := h.playbookService.Get(current.ID)
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	ReturnJSON(w, &saved, http.StatusOK)
+}
+
+// inboundWebhookTokenResult is the response of the inbound webhook token endpoints.
+type inboundWebhookTokenResult struct {
+	Token string `json:"token"`
+}
+
+// getInboundWebhookToken handles the GET /playbooks/{id}/inbound-webhook-token endpoint.
+func (h *PlaybookHandler) getInboundWebhookToken(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	pbook, err := h.playbookService.Get(vars["id"])
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	if err2 := permissions.PlaybookManageSecrets(userID, pbook, h.pluginAPI); err2 != nil {
+		h.HandleErrorWithCode(w, http.StatusForbidden, "Not authorized", err2)
+		return
+	}
+
+	ReturnJSON(w, &inboundWebhookTokenResult{Token: pbook.InboundWebhookToken}, http.StatusOK)
+}
+
+// regenerateInboundWebhookToken handles the POST /playbooks/{id}/inbound-webhook-token/regenerate
+// endpoint. The previous token stops authenticating alerts immediately.
+func (h *PlaybookHandler) regenerateInboundWebhookToken(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	pbook, err := h.playbookService.Get(vars["id"])
+	if err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	if err2 := permissions.PlaybookManageSecrets(userID, pbook, h.pluginAPI); err2 != nil {
+		h.HandleErrorWithCode(w, http.StatusForbidden, "Not authorized", err2)
+		return
+	}
+
+	pbook.InboundWebhookToken = model.NewId()
+	if err = h.playbookService.Update(pbook, userID); err != nil {
+		h.HandleError(w, err)
+		return
+	}
+
+	ReturnJSON(w, &inboundWebhookTokenResult{Token: pbook.InboundWebhookToken}, http.StatusOK)
+}
+
+// hasPlaybookAccess writes an error to w and returns false if the playbook does not exist or the
+// user cannot access it.
+func (h *PlaybookHandler) hasPlaybookAccess(w http.ResponseWriter, playbookID, userID string) bool {
+	pbook, err := h.playbookService.Get(playbookID)
+	if err != nil {
+		h.HandleError(w, err)
+		return false
+	}
+
+	if err2 := permissions.PlaybookAccess(userID, pbook, h.pluginAPI); err2 != nil {
+		h.HandleErrorWithCode(w, http.StatusForbidden, "Not authorized", err2)
+		return false
+	}
+
+	return true
+}
+
+// getRevision parses rawRevision and retrieves that revision of the playbook, writing an error
+// to w and returning false if it is invalid or does not exist.
+func (h *PlaybookHandler) getRevision(w http.ResponseWriter, playbookID, rawRevision string) (playbook.Revision, bool) {
+	number, err := strconv.Atoi(rawRevision)
+	if err != nil || number < 1 {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, fmt.Sprintf("invalid revision '%s'", rawRevision), err)
+		return playbook.Revision{}, false
+	}
+
+	revision, err := h.playbookService.GetRevision(playbookID, number)
+	if errors.Is(err, playbook.ErrNotFound) {
+		h.HandleErrorWithCode(w, http.StatusNotFound, fmt.Sprintf("revision %d not found", number), err)
+		return playbook.Revision{}, false
+	} else if err != nil {
+		h.HandleError(w, err)
+		return playbook.Revision{}, false
+	}
+
+	return revision, true
+}
+
+// importPlaybook handles the POST /playbooks/import endpoint. It creates a playbook in the team
+// given by team_id from the playbook file in the body, in the format given by the format
+// parameter: json, the default, or yaml. The response lists the users, groups and channels
+// referenced by the file that could not be found, and were left out of the playbook.
+func (h *PlaybookHandler) importPlaybook(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	teamID := r.URL.Query().Get("team_id")
+	if teamID == "" {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "Provide a team ID", nil)
+		return
+	}
+
+	format, err := parsePlaybookFileFormat(r.URL)
+	if err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "unable to read playbook file", err)
+		return
+	}
+
+	file, err := playbook.UnmarshalFile(data, format)
+	if err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	if err = file.Validate(); err != nil {
+		h.HandleErrorWithCode(w, http.StatusBadRequest, "invalid playbook file: "+err.Error(), err)
+		return
+	}
+
+	unresolved := map[string]bool{}
+	pbook := file.Playbook(teamID, playbook.FileMapper{
+		User: func(username string) string {
+			user, err2 := h.pluginAPI.User.GetByUsername(username)
+			if err2 != nil {
+				unresolved["user "+username] = true
+				return ""
+			}
+			return user.Id
+		},
+		Group: func(name string) string {
+			group, err2 := h.pluginAPI.Group.GetByName(name)
+			if err2 != nil {
+				unresolved["group "+name] = true
+				return ""
+			}
+			return group.Id
+		},
+		Channel: func(name string) string {
+			channel, err2 := h.pluginAPI.Channel.GetByName(teamID, name, false)
+			if err2 != nil {
+				unresolved["channel "+name] = true
+				return ""
+			}
+			return channel.Id
+		},
+	})
+
+	// The importing user is always a member, so that the playbook is not out of their reach.
+	isMember := false
+	for _, memberID := range pbook.MemberIDs {
+		if memberID == userID {
+			isMember = true
+			break
+		}
+	}
+	if !isMember {
+		pbook.MemberIDs = append(pbook.MemberIDs, userID)
+	}
+
+	id, ok := h.validateAndCreatePlaybook(w, pbook, userID)
+	if !ok {
+		return
+	}
+
+	result := struct {
+		ID         string   `json:"id"`
+		Unresolved []string `json:"unresolved"`
+	}{
+		ID:         id,
+		Unresolved: []string{},
+	}
+	for reference := range unresolved {
+		result.Unresolved = append(result.Unresolved, reference)
+	}
+	sort.Strings(result.Unresolved)
+
+	w.Header().Add("Location", fmt.Sprintf("/api/v0/playbooks/%s", id))
+	ReturnJSON(w, &result, http.StatusCreated)
+}
+
+// parsePlaybookFileFormat returns the format of a playbook file given by the format parameter,
+// which defaults to json.
+func parsePlaybookFileFormat(u *url.URL) (string, error) {
+	format := u.Query().Get("format")
+	switch format {
+	case "":
+		return playbook.FileFormatJSON, nil
+	case playbook.FileFormatJSON, playbook.FileFormatYAML:
+		return format, nil
+	}
+
+	return "", errors.Errorf("bad parameter 'format'; expected %s or %s", playbook.FileFormatJSON, playbook.FileFormatYAML)
+}
+
 func (h *PlaybookHandler) getPlaybooks(w http.ResponseWriter, r *http.Request) {
 	params := r.URL.Query()
 	teamID := params.Get("team_id")
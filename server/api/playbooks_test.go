@@ -21,6 +21,7 @@ import (
 	"github.com/mattermost/mattermost-server/v5/plugin/plugintest"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	pluginapi "github.com/mattermost/mattermost-plugin-api"
@@ -147,7 +148,7 @@ func TestPlaybooks(t *testing.T) {
 		logger = mock_poster.NewMockLogger(mockCtrl)
 		poster = mock_poster.NewMockPoster(mockCtrl)
 
-		NewPlaybookHandler(handler.APIRouter, playbookService, client, logger, configService)
+		NewPlaybookHandler(handler.APIRouter, playbookService, nil, client, logger, configService)
 
 		configService.EXPECT().
 			IsAtLeastE20Licensed().
@@ -174,7 +175,7 @@ func TestPlaybooks(t *testing.T) {
 		logger = mock_poster.NewMockLogger(mockCtrl)
 		poster = mock_poster.NewMockPoster(mockCtrl)
 
-		NewPlaybookHandler(handler.APIRouter, playbookService, client, logger, configService)
+		NewPlaybookHandler(handler.APIRouter, playbookService, nil, client, logger, configService)
 
 		configService.EXPECT().
 			GetConfiguration().
@@ -224,7 +225,7 @@ func TestPlaybooks(t *testing.T) {
 		logger = mock_poster.NewMockLogger(mockCtrl)
 		poster = mock_poster.NewMockPoster(mockCtrl)
 
-		NewPlaybookHandler(handler.APIRouter, playbookService, client, logger, configService)
+		NewPlaybookHandler(handler.APIRouter, playbookService, nil, client, logger, configService)
 
 		configService.EXPECT().
 			GetConfiguration().
@@ -276,7 +277,7 @@ func TestPlaybooks(t *testing.T) {
 		poster = mock_poster.NewMockPoster(mockCtrl)
 		handler = NewHandler(client, configService, logger)
 
-		NewPlaybookHandler(handler.APIRouter, playbookService, client, logger, configService)
+		NewPlaybookHandler(handler.APIRouter, playbookService, nil, client, logger, configService)
 
 		configService.EXPECT().
 			IsAtLeastE20Licensed().
@@ -335,7 +336,7 @@ func TestPlaybooks(t *testing.T) {
 		poster = mock_poster.NewMockPoster(mockCtrl)
 		handler = NewHandler(client, configService, logger)
 
-		NewPlaybookHandler(handler.APIRouter, playbookService, client, logger, configService)
+		NewPlaybookHandler(handler.APIRouter, playbookService, nil, client, logger, configService)
 
 		configService.EXPECT().
 			IsAtLeastE20Licensed().
@@ -394,7 +395,7 @@ func TestPlaybooks(t *testing.T) {
 		poster = mock_poster.NewMockPoster(mockCtrl)
 		handler = NewHandler(client, configService, logger)
 
-		NewPlaybookHandler(handler.APIRouter, playbookService, client, logger, configService)
+		NewPlaybookHandler(handler.APIRouter, playbookService, nil, client, logger, configService)
 
 		configService.EXPECT().
 			IsAtLeastE20Licensed().
@@ -448,7 +449,7 @@ func TestPlaybooks(t *testing.T) {
 		poster = mock_poster.NewMockPoster(mockCtrl)
 		handler = NewHandler(client, configService, logger)
 
-		NewPlaybookHandler(handler.APIRouter, playbookService, client, logger, configService)
+		NewPlaybookHandler(handler.APIRouter, playbookService, nil, client, logger, configService)
 
 		configService.EXPECT().
 			IsAtLeastE20Licensed().
@@ -509,7 +510,7 @@ func TestPlaybooks(t *testing.T) {
 		poster = mock_poster.NewMockPoster(mockCtrl)
 		handler = NewHandler(client, configService, logger)
 
-		NewPlaybookHandler(handler.APIRouter, playbookService, client, logger, configService)
+		NewPlaybookHandler(handler.APIRouter, playbookService, nil, client, logger, configService)
 
 		configService.EXPECT().
 			IsAtLeastE20Licensed().
@@ -1224,6 +1225,81 @@ func TestPlaybooks(t *testing.T) {
 		assert.Equal(t, http.StatusOK, resp.StatusCode)
 	})
 
+	t.Run("restore playbook revision, dropping invited users without team access", func(t *testing.T) {
+		reset(t)
+
+		current := withMember
+		current.Revision = 3
+		old := withMember
+		old.InviteUsersEnabled = true
+		old.InvitedUserIDs = []string{"testInvitedUserID1", "testInvitedUserID2"}
+
+		testrecorder := httptest.NewRecorder()
+		testreq, err := http.NewRequest("POST", "/api/v0/playbooks/playbookwithmember/revisions/1/restore", nil)
+		testreq.Header.Add("Mattermost-User-ID", "testuserid")
+		require.NoError(t, err)
+
+		playbookService.EXPECT().
+			Get("playbookwithmember").
+			Return(current, nil).
+			Times(2)
+		playbookService.EXPECT().
+			GetRevision("playbookwithmember", 1).
+			Return(playbook.Revision{PlaybookID: "playbookwithmember", Revision: 1, Playbook: old}, nil)
+
+		playbookService.EXPECT().
+			Update(gomock.AssignableToTypeOf(playbook.Playbook{}), "testuserid").
+			DoAndReturn(func(restored playbook.Playbook, userID string) error {
+				assert.Equal(t, "playbookwithmember", restored.ID)
+				assert.Equal(t, 3, restored.Revision)
+				assert.Equal(t, []string{"testInvitedUserID1"}, restored.InvitedUserIDs)
+				return nil
+			}).
+			Times(1)
+
+		pluginAPI.On("HasPermissionToTeam", "testInvitedUserID1", "testteamid", model.PERMISSION_VIEW_TEAM).Return(true)
+		pluginAPI.On("HasPermissionToTeam", "testInvitedUserID2", "testteamid", model.PERMISSION_VIEW_TEAM).Return(false)
+		pluginAPI.On("HasPermissionToTeam", "testuserid", "testteamid", model.PERMISSION_VIEW_TEAM).Return(true)
+		pluginAPI.On("GetUser", "testuserid").Return(&model.User{}, nil)
+		pluginAPI.On("LogWarn", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+		handler.ServeHTTP(testrecorder, testreq)
+
+		resp := testrecorder.Result()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("restore playbook revision with an invalid webhook subscription", func(t *testing.T) {
+		reset(t)
+
+		old := withMember
+		old.WebhookSubscriptions = []playbook.WebhookSubscription{
+			{URL: "ftp://example.com/hook", EventTypes: []string{"status_updated"}},
+		}
+
+		testrecorder := httptest.NewRecorder()
+		testreq, err := http.NewRequest("POST", "/api/v0/playbooks/playbookwithmember/revisions/1/restore", nil)
+		testreq.Header.Add("Mattermost-User-ID", "testuserid")
+		require.NoError(t, err)
+
+		playbookService.EXPECT().
+			Get("playbookwithmember").
+			Return(withMember, nil).
+			Times(1)
+		playbookService.EXPECT().
+			GetRevision("playbookwithmember", 1).
+			Return(playbook.Revision{PlaybookID: "playbookwithmember", Revision: 1, Playbook: old}, nil)
+		pluginAPI.On("GetUser", "testuserid").Return(&model.User{}, nil)
+		logger.EXPECT().Warnf(gomock.Any(), gomock.Any(), gomock.Any())
+
+		pluginAPI.On("HasPermissionToTeam", "testuserid", "testteamid", model.PERMISSION_VIEW_TEAM).Return(true)
+
+		handler.ServeHTTP(testrecorder, testreq)
+
+		resp := testrecorder.Result()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
 	t.Run("delete playbook", func(t *testing.T) {
 		reset(t)
 
@@ -1618,7 +1694,7 @@ func TestSortingPlaybooks(t *testing.T) {
 		handler = NewHandler(client, configService, logger)
 		playbookService = mock_playbook.NewMockService(mockCtrl)
 		logger = mock_poster.NewMockLogger(mockCtrl)
-		NewPlaybookHandler(handler.APIRouter, playbookService, client, logger, configService)
+		NewPlaybookHandler(handler.APIRouter, playbookService, nil, client, logger, configService)
 	}
 
 	testData := []struct {
@@ -1822,7 +1898,7 @@ func TestPagingPlaybooks(t *testing.T) {
 		logger = mock_poster.NewMockLogger(mockCtrl)
 		handler = NewHandler(client, configService, logger)
 		playbookService = mock_playbook.NewMockService(mockCtrl)
-		NewPlaybookHandler(handler.APIRouter, playbookService, client, logger, configService)
+		NewPlaybookHandler(handler.APIRouter, playbookService, nil, client, logger, configService)
 	}
 
 	testData := []struct {
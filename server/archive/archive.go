@@ -0,0 +1,160 @@
+// Package archive exports the incidents and playbooks of a team to a portable archive, and imports
+// them back, possibly into another server.
+//
+// The IDs of users, teams and channels differ between servers, so the archive records the username
+// of every user, and the name of every team and channel, it references. The import looks them up
+// by name to remap the IDs.
+package archive
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/incident"
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/playbook"
+)
+
+// Version is the version of the archive format written by Export. Import rejects archives with a
+// later version.
+const Version = 1
+
+// Archive holds the incidents and playbooks of a team, with the names needed to remap the IDs
+// they reference.
+type Archive struct {
+	Version    int                 `json:"version"`
+	ExportedAt int64               `json:"exported_at"`
+	Users      map[string]string   `json:"users"`    // Usernames, indexed by user ID.
+	Teams      map[string]string   `json:"teams"`    // Team names, indexed by team ID.
+	Channels   map[string]Channel  `json:"channels"` // Channels, indexed by channel ID.
+	Playbooks  []playbook.Playbook `json:"playbooks"`
+	Incidents  []incident.Incident `json:"incidents"`
+
+	// StatusUpdates holds the status updates of the incidents, indexed by incident ID. The posts
+	// of the status updates are not part of the incidents, so the archive records their message.
+	StatusUpdates map[string][]StatusUpdate `json:"status_updates"`
+}
+
+// StatusUpdate is a status update of an incident, as posted in its channel.
+type StatusUpdate struct {
+	Message  string `json:"message"`
+	UserID   string `json:"user_id"`
+	Status   string `json:"status"`
+	CreateAt int64  `json:"create_at"`
+}
+
+// FileName returns the name of the file to which the archive, or its CSV summary, of teamID is
+// exported today. extension is json or csv.
+func FileName(teamID, extension string) string {
+	return fmt.Sprintf("incidents-%s-%s.%s", teamID, time.Now().UTC().Format("2006-01-02"), extension)
+}
+
+// Channel identifies a channel by its name within its team.
+type Channel struct {
+	TeamID string `json:"team_id"`
+	Name   string `json:"name"`
+}
+
+// ImportResult summarizes what an import created, and what it skipped and why.
+type ImportResult struct {
+	Playbooks      int      `json:"playbooks"`
+	Incidents      int      `json:"incidents"`
+	TimelineEvents int      `json:"timeline_events"`
+	StatusUpdates  int      `json:"status_updates"`
+	Skipped        []string `json:"skipped"`
+}
+
+// idMapper maps the IDs of the users, teams and channels referenced by incidents and playbooks.
+// Each function returns a blank ID for a blank ID.
+type idMapper struct {
+	user    func(id string) string
+	team    func(id string) string
+	channel func(id string) string
+}
+
+// users maps ids, leaving out the IDs that map to a blank ID.
+func (m idMapper) users(ids []string) []string {
+	if ids == nil {
+		return nil
+	}
+
+	mapped := []string{}
+	for _, id := range ids {
+		if newID := m.user(id); newID != "" {
+			mapped = append(mapped, newID)
+		}
+	}
+	return mapped
+}
+
+// broadcastTargets maps the channels of targets, leaving out the targets whose channel maps to a
+// blank ID.
+func (m idMapper) broadcastTargets(targets []playbook.BroadcastTarget) []playbook.BroadcastTarget {
+	if targets == nil {
+		return nil
+	}
+
+	mapped := []playbook.BroadcastTarget{}
+	for _, target := range targets {
+		if target.ChannelID = m.channel(target.ChannelID); target.ChannelID != "" {
+			mapped = append(mapped, target)
+		}
+	}
+	return mapped
+}
+
+func (m idMapper) checklists(checklists []playbook.Checklist) {
+	for i := range checklists {
+		for j := range checklists[i].Items {
+			item := &checklists[i].Items[j]
+			item.AssigneeID = m.user(item.AssigneeID)
+			item.AutomaticAction.ChannelID = m.channel(item.AutomaticAction.ChannelID)
+			item.AutomaticAction.UserIDs = m.users(item.AutomaticAction.UserIDs)
+		}
+	}
+}
+
+func (m idMapper) incident(theIncident *incident.Incident) {
+	theIncident.TeamID = m.team(theIncident.TeamID)
+	theIncident.ChannelID = m.channel(theIncident.ChannelID)
+	theIncident.OwnerUserID = m.user(theIncident.OwnerUserID)
+	theIncident.ReporterUserID = m.user(theIncident.ReporterUserID)
+	theIncident.DefaultOwnerID = m.user(theIncident.DefaultOwnerID)
+	theIncident.BroadcastChannelID = m.channel(theIncident.BroadcastChannelID)
+	theIncident.AnnouncementChannelID = m.channel(theIncident.AnnouncementChannelID)
+	theIncident.BroadcastTargets = m.broadcastTargets(theIncident.BroadcastTargets)
+	theIncident.InvitedUserIDs = m.users(theIncident.InvitedUserIDs)
+	theIncident.FollowerIDs = m.users(theIncident.FollowerIDs)
+	m.checklists(theIncident.Checklists)
+
+	for roleKey, userIDs := range theIncident.RoleAssignments {
+		if mapped := m.users(userIDs); len(mapped) > 0 {
+			theIncident.RoleAssignments[roleKey] = mapped
+		} else {
+			delete(theIncident.RoleAssignments, roleKey)
+		}
+	}
+
+	for i := range theIncident.TimelineEvents {
+		event := &theIncident.TimelineEvents[i]
+		event.SubjectUserID = m.user(event.SubjectUserID)
+		event.CreatorUserID = m.user(event.CreatorUserID)
+	}
+}
+
+func (m idMapper) statusUpdates(updates []StatusUpdate) {
+	for i := range updates {
+		updates[i].UserID = m.user(updates[i].UserID)
+	}
+}
+
+func (m idMapper) playbook(pbook *playbook.Playbook) {
+	pbook.TeamID = m.team(pbook.TeamID)
+	pbook.BroadcastChannelID = m.channel(pbook.BroadcastChannelID)
+	pbook.AnnouncementChannelID = m.channel(pbook.AnnouncementChannelID)
+	pbook.BroadcastTargets = m.broadcastTargets(pbook.BroadcastTargets)
+	pbook.DefaultOwnerID = m.user(pbook.DefaultOwnerID)
+	pbook.MemberIDs = m.users(pbook.MemberIDs)
+	pbook.InvitedUserIDs = m.users(pbook.InvitedUserIDs)
+	pbook.AutoFollowerIDs = m.users(pbook.AutoFollowerIDs)
+	m.checklists(pbook.Checklists)
+}
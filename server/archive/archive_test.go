@@ -0,0 +1,103 @@
+package archive
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/incident"
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/playbook"
+)
+
+func TestIDMapper_Incident(t *testing.T) {
+	newIDs := map[string]string{
+		"user1":    "newuser1",
+		"team1":    "newteam1",
+		"channel1": "newchannel1",
+	}
+	lookup := func(id string) string {
+		return newIDs[id]
+	}
+	mapper := idMapper{user: lookup, team: lookup, channel: lookup}
+
+	theIncident := &incident.Incident{
+		TeamID:         "team1",
+		ChannelID:      "channel1",
+		OwnerUserID:    "user1",
+		ReporterUserID: "deleteduser",
+		InvitedUserIDs: []string{"user1", "deleteduser"},
+		FollowerIDs:    []string{"deleteduser", "user1"},
+		Checklists: []playbook.Checklist{{
+			Items: []playbook.ChecklistItem{{
+				AssigneeID: "user1",
+				AutomaticAction: playbook.ItemAction{
+					ChannelID: "channel1",
+					UserIDs:   []string{"deleteduser"},
+				},
+			}},
+		}},
+		TimelineEvents: []incident.TimelineEvent{{
+			SubjectUserID: "user1",
+			CreatorUserID: "deleteduser",
+		}},
+	}
+
+	mapper.incident(theIncident)
+
+	require.Equal(t, "newteam1", theIncident.TeamID)
+	require.Equal(t, "newchannel1", theIncident.ChannelID)
+	require.Equal(t, "newuser1", theIncident.OwnerUserID)
+	require.Empty(t, theIncident.ReporterUserID)
+	require.Equal(t, []string{"newuser1"}, theIncident.InvitedUserIDs)
+	require.Equal(t, []string{"newuser1"}, theIncident.FollowerIDs)
+
+	item := theIncident.Checklists[0].Items[0]
+	require.Equal(t, "newuser1", item.AssigneeID)
+	require.Equal(t, "newchannel1", item.AutomaticAction.ChannelID)
+	require.Equal(t, []string{}, item.AutomaticAction.UserIDs)
+
+	require.Equal(t, "newuser1", theIncident.TimelineEvents[0].SubjectUserID)
+	require.Empty(t, theIncident.TimelineEvents[0].CreatorUserID)
+
+	updates := []StatusUpdate{{UserID: "user1"}, {UserID: "deleteduser"}}
+	mapper.statusUpdates(updates)
+	require.Equal(t, "newuser1", updates[0].UserID)
+	require.Empty(t, updates[1].UserID)
+}
+
+func TestWriteCSV(t *testing.T) {
+	archive := &Archive{
+		Users:     map[string]string{"user1": "alice"},
+		Teams:     map[string]string{"team1": "engineering"},
+		Channels:  map[string]Channel{"channel1": {TeamID: "team1", Name: "server-down"}},
+		Playbooks: []playbook.Playbook{{ID: "playbook1", Title: "Outage"}},
+		Incidents: []incident.Incident{{
+			ID:             "incident1",
+			Name:           "Server down, again",
+			TeamID:         "team1",
+			ChannelID:      "channel1",
+			OwnerUserID:    "user1",
+			ReporterUserID: "deleteduser",
+			PlaybookID:     "playbook1",
+			CurrentStatus:  incident.StatusResolved,
+			CreateAt:       1614592800000,
+			Checklists: []playbook.Checklist{{
+				Items: []playbook.ChecklistItem{
+					{State: playbook.ChecklistItemStateClosed},
+					{State: playbook.ChecklistItemStateOpen},
+				},
+			}},
+			TimelineEvents: []incident.TimelineEvent{{}, {}, {}},
+		}},
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, WriteCSV(&out, archive))
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 2)
+	require.Equal(t, strings.Join(csvHeader, ","), lines[0])
+	require.Equal(t, `incident1,"Server down, again",engineering,server-down,Resolved,,alice,,Outage,2021-03-01T10:00:00Z,,1,2,3`, lines[1])
+}
@@ -0,0 +1,88 @@
+package archive
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/playbook"
+)
+
+// csvHeader lists the columns of the CSV summary written by WriteCSV.
+var csvHeader = []string{
+	"id",
+	"name",
+	"team",
+	"channel",
+	"status",
+	"severity",
+	"owner",
+	"reporter",
+	"playbook",
+	"created_at",
+	"ended_at",
+	"tasks_closed",
+	"tasks_total",
+	"timeline_events",
+}
+
+// WriteCSV writes a summary of the incidents in archive to w, one row per incident. Users, teams
+// and channels are written by name, and times in RFC 3339 format, in UTC.
+func WriteCSV(w io.Writer, archive *Archive) error {
+	playbookTitles := make(map[string]string, len(archive.Playbooks))
+	for _, pbook := range archive.Playbooks {
+		playbookTitles[pbook.ID] = pbook.Title
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return errors.Wrap(err, "failed to write csv header")
+	}
+
+	for _, theIncident := range archive.Incidents {
+		closed, total := 0, 0
+		for _, checklist := range theIncident.Checklists {
+			for _, item := range checklist.Items {
+				total++
+				if item.State == playbook.ChecklistItemStateClosed {
+					closed++
+				}
+			}
+		}
+
+		err := writer.Write([]string{
+			theIncident.ID,
+			theIncident.Name,
+			archive.Teams[theIncident.TeamID],
+			archive.Channels[theIncident.ChannelID].Name,
+			theIncident.CurrentStatus,
+			theIncident.Severity,
+			archive.Users[theIncident.OwnerUserID],
+			archive.Users[theIncident.ReporterUserID],
+			playbookTitles[theIncident.PlaybookID],
+			formatMillis(theIncident.CreateAt),
+			formatMillis(theIncident.EndAt),
+			strconv.Itoa(closed),
+			strconv.Itoa(total),
+			strconv.Itoa(len(theIncident.TimelineEvents)),
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to write csv row for incident %s", theIncident.ID)
+		}
+	}
+
+	writer.Flush()
+	return errors.Wrap(writer.Error(), "failed to write csv")
+}
+
+// formatMillis formats a timestamp in milliseconds, leaving zero timestamps blank.
+func formatMillis(millis int64) string {
+	if millis == 0 {
+		return ""
+	}
+
+	return time.Unix(0, millis*int64(time.Millisecond)).UTC().Format(time.RFC3339)
+}
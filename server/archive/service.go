@@ -0,0 +1,396 @@
+package archive
+
+import (
+	"fmt"
+
+	pluginapi "github.com/mattermost/mattermost-plugin-api"
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/incident"
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/permissions"
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/playbook"
+)
+
+// perPage is the page size used to read the incidents and playbooks to export.
+const perPage = 200
+
+// Service exports and imports archives.
+type Service struct {
+	pluginAPI       *pluginapi.Client
+	incidentService incident.Service
+	playbookService playbook.Service
+}
+
+// NewService creates a new archive service.
+func NewService(pluginAPI *pluginapi.Client, incidentService incident.Service, playbookService playbook.Service) *Service {
+	return &Service{
+		pluginAPI:       pluginAPI,
+		incidentService: incidentService,
+		playbookService: playbookService,
+	}
+}
+
+// Export returns an archive with every incident and playbook of teamID, on behalf of userID, who
+// must be an admin.
+func (s *Service) Export(teamID, userID string) (*Archive, error) {
+	archive := &Archive{
+		Version:    Version,
+		ExportedAt: model.GetMillis(),
+		Users:      map[string]string{},
+		Teams:      map[string]string{},
+		Channels:   map[string]Channel{},
+		Playbooks:  []playbook.Playbook{},
+		Incidents:  []incident.Incident{},
+
+		StatusUpdates: map[string][]StatusUpdate{},
+	}
+
+	requesterInfo := playbook.RequesterInfo{UserID: userID, TeamID: teamID, IsAdmin: true}
+	for page := 0; ; page++ {
+		results, err := s.playbookService.GetPlaybooksForTeam(requesterInfo, teamID, playbook.Options{
+			Sort:      playbook.SortByTitle,
+			Direction: playbook.DirectionAsc,
+			Page:      page,
+			PerPage:   perPage,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get playbooks of team %s", teamID)
+		}
+
+		// Playbooks are listed without their checklists, so get each one in full.
+		for _, listed := range results.Items {
+			pbook, err := s.playbookService.Get(listed.ID)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to get playbook %s", listed.ID)
+			}
+
+			// The secrets stay on this server; the import generates new ones.
+			pbook.InboundWebhookToken = ""
+			if pbook.WebhookSecret != "" {
+				pbook.WebhookSecret = playbook.MaskedWebhookSecret
+			}
+			archive.Playbooks = append(archive.Playbooks, pbook)
+		}
+
+		if !results.HasMore {
+			break
+		}
+	}
+
+	for page := 0; ; page++ {
+		results, err := s.incidentService.GetIncidents(permissions.RequesterInfo{UserID: userID, IsAdmin: true}, incident.FilterOptions{
+			TeamID:    teamID,
+			Page:      page,
+			PerPage:   perPage,
+			Sort:      incident.SortByCreateAt,
+			Direction: incident.DirectionAsc,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get incidents of team %s", teamID)
+		}
+
+		// Incidents are listed without their followers, so get each one in full.
+		for _, listed := range results.Items {
+			theIncident, err := s.incidentService.GetIncident(listed.ID)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to get incident %s", listed.ID)
+			}
+			theIncident.WebhookSecret = ""
+			archive.Incidents = append(archive.Incidents, *theIncident)
+			archive.StatusUpdates[theIncident.ID] = s.statusUpdates(theIncident)
+		}
+
+		if !results.HasMore {
+			break
+		}
+	}
+
+	// Record the names of everything referenced; the recorder leaves the IDs unchanged.
+	recorder := idMapper{
+		user: func(id string) string {
+			if id != "" {
+				archive.Users[id] = ""
+			}
+			return id
+		},
+		team: func(id string) string {
+			if id != "" {
+				archive.Teams[id] = ""
+			}
+			return id
+		},
+		channel: func(id string) string {
+			if id != "" {
+				archive.Channels[id] = Channel{}
+			}
+			return id
+		},
+	}
+	for i := range archive.Playbooks {
+		recorder.playbook(&archive.Playbooks[i])
+	}
+	for i := range archive.Incidents {
+		recorder.incident(&archive.Incidents[i])
+	}
+	for _, updates := range archive.StatusUpdates {
+		recorder.statusUpdates(updates)
+	}
+
+	s.resolveNames(archive)
+
+	return archive, nil
+}
+
+// statusUpdates returns the status updates of theIncident that were not deleted. Status updates
+// whose post cannot be found are left out.
+func (s *Service) statusUpdates(theIncident *incident.Incident) []StatusUpdate {
+	updates := []StatusUpdate{}
+	for _, statusPost := range theIncident.StatusPosts {
+		if statusPost.DeleteAt != 0 {
+			continue
+		}
+
+		post, err := s.pluginAPI.Post.GetPost(statusPost.ID)
+		if err != nil {
+			s.pluginAPI.Log.Warn("failed to get status post to export", "post_id", statusPost.ID, "error", err.Error())
+			continue
+		}
+
+		updates = append(updates, StatusUpdate{
+			Message:  post.Message,
+			UserID:   post.UserId,
+			Status:   statusPost.Status,
+			CreateAt: statusPost.CreateAt,
+		})
+	}
+
+	return updates
+}
+
+// resolveNames fills in the names of the users, teams and channels recorded in archive. IDs that
+// cannot be resolved, e.g. because the user or channel was deleted, are left out.
+func (s *Service) resolveNames(archive *Archive) {
+	for id := range archive.Users {
+		user, err := s.pluginAPI.User.Get(id)
+		if err != nil {
+			s.pluginAPI.Log.Warn("failed to get user to export", "user_id", id, "error", err.Error())
+			delete(archive.Users, id)
+			continue
+		}
+		archive.Users[id] = user.Username
+	}
+
+	for id := range archive.Channels {
+		channel, err := s.pluginAPI.Channel.Get(id)
+		if err != nil || channel.TeamId == "" {
+			s.pluginAPI.Log.Warn("failed to get channel to export", "channel_id", id)
+			delete(archive.Channels, id)
+			continue
+		}
+		archive.Channels[id] = Channel{TeamID: channel.TeamId, Name: channel.Name}
+		archive.Teams[channel.TeamId] = ""
+	}
+
+	for id := range archive.Teams {
+		team, err := s.pluginAPI.Team.Get(id)
+		if err != nil {
+			s.pluginAPI.Log.Warn("failed to get team to export", "team_id", id, "error", err.Error())
+			delete(archive.Teams, id)
+			continue
+		}
+		archive.Teams[id] = team.Name
+	}
+}
+
+// Import creates the playbooks and incidents of archive on behalf of importerID, who must be an
+// admin. The IDs of users, teams and channels are remapped by name. Playbooks and incidents whose
+// team, or incidents whose channel, cannot be found are skipped, as are incidents whose channel
+// already has an incident. Users that cannot be found are left out; importerID becomes the owner
+// and reporter of the incidents whose owner or reporter cannot be found, and the author of the
+// status updates whose author cannot be found.
+//
+// The status updates are posted again in the channels of the incidents, with their original
+// creation time. Other posts are not part of the archive, so the timeline events lose their link
+// to a post. The archive holds no secrets, so the playbooks get a new webhook secret and inbound
+// webhook token where they had one.
+func (s *Service) Import(archive *Archive, importerID string) (*ImportResult, error) {
+	if archive.Version < 1 || archive.Version > Version {
+		return nil, errors.Errorf("unsupported archive version %d; expected at most version %d", archive.Version, Version)
+	}
+
+	result := &ImportResult{Skipped: []string{}}
+	mapper := s.importMapper(archive)
+
+	playbookIDs := map[string]string{}
+	webhookSecrets := map[string]string{}
+	for _, archived := range archive.Playbooks {
+		pbook := archived.Clone()
+		mapper.playbook(&pbook)
+		if pbook.TeamID == "" {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("playbook %s (%s): team not found", archived.Title, archived.ID))
+			continue
+		}
+
+		if len(pbook.MemberIDs) == 0 {
+			pbook.MemberIDs = []string{importerID}
+		}
+		pbook.ID = ""
+		pbook.InboundWebhookToken = ""
+		if pbook.WebhookSecret != "" {
+			pbook.WebhookSecret = model.NewId()
+		}
+		if pbook.InboundWebhookEnabled {
+			pbook.InboundWebhookToken = model.NewId()
+		}
+
+		newID, err := s.playbookService.Create(pbook, importerID)
+		if err != nil {
+			return result, errors.Wrapf(err, "failed to import playbook %s", archived.ID)
+		}
+		playbookIDs[archived.ID] = newID
+		webhookSecrets[archived.ID] = pbook.WebhookSecret
+		result.Playbooks++
+	}
+
+	for i := range archive.Incidents {
+		archived := &archive.Incidents[i]
+		theIncident := archived.Clone()
+		mapper.incident(theIncident)
+
+		if theIncident.TeamID == "" {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("incident %s (%s): team not found", archived.Name, archived.ID))
+			continue
+		}
+		if theIncident.ChannelID == "" {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("incident %s (%s): channel not found", archived.Name, archived.ID))
+			continue
+		}
+
+		_, err := s.incidentService.GetIncidentIDForChannel(theIncident.ChannelID)
+		if err == nil {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("incident %s (%s): the channel already has an incident", archived.Name, archived.ID))
+			continue
+		} else if !errors.Is(err, incident.ErrNotFound) {
+			return result, errors.Wrapf(err, "failed to check for an incident in channel %s", theIncident.ChannelID)
+		}
+
+		if theIncident.OwnerUserID == "" {
+			theIncident.OwnerUserID = importerID
+		}
+		if theIncident.ReporterUserID == "" {
+			theIncident.ReporterUserID = importerID
+		}
+		theIncident.ID = ""
+		theIncident.PlaybookID = playbookIDs[archived.PlaybookID]
+		theIncident.WebhookSecret = webhookSecrets[archived.PlaybookID]
+		theIncident.PostID = ""
+		theIncident.ReminderPostID = ""
+		theIncident.StatusPosts = nil
+
+		timelineEvents := theIncident.TimelineEvents
+		theIncident.TimelineEvents = nil
+		for j := range timelineEvents {
+			timelineEvents[j].ID = ""
+			timelineEvents[j].PostID = ""
+		}
+
+		updates := append([]StatusUpdate(nil), archive.StatusUpdates[archived.ID]...)
+		mapper.statusUpdates(updates)
+		statusPosts, err := s.postStatusUpdates(updates, theIncident.ChannelID, importerID)
+		if err != nil {
+			return result, errors.Wrapf(err, "failed to import the status updates of incident %s", archived.ID)
+		}
+
+		created, err := s.incidentService.ImportIncident(theIncident, timelineEvents, statusPosts)
+		if err != nil {
+			return result, errors.Wrapf(err, "failed to import incident %s", archived.ID)
+		}
+		result.Incidents++
+		result.TimelineEvents += len(created.TimelineEvents)
+		result.StatusUpdates += len(created.StatusPosts)
+	}
+
+	return result, nil
+}
+
+// postStatusUpdates posts the messages of updates in channelID, with their original creation time,
+// and returns the status posts of the new posts. importerID becomes the author of the updates
+// whose author was not found.
+func (s *Service) postStatusUpdates(updates []StatusUpdate, channelID, importerID string) ([]incident.StatusPost, error) {
+	statusPosts := []incident.StatusPost{}
+	for _, update := range updates {
+		post := &model.Post{
+			ChannelId: channelID,
+			UserId:    update.UserID,
+			Message:   update.Message,
+			CreateAt:  update.CreateAt,
+		}
+		if post.UserId == "" {
+			post.UserId = importerID
+		}
+
+		if err := s.pluginAPI.Post.CreatePost(post); err != nil {
+			return nil, errors.Wrapf(err, "failed to post status update in channel %s", channelID)
+		}
+
+		statusPosts = append(statusPosts, incident.StatusPost{
+			ID:       post.Id,
+			Status:   update.Status,
+			CreateAt: post.CreateAt,
+		})
+	}
+
+	return statusPosts, nil
+}
+
+// importMapper returns a mapper from the IDs in archive to the IDs of the users, teams and
+// channels with the same names in this server. IDs without a match map to a blank ID.
+func (s *Service) importMapper(archive *Archive) idMapper {
+	userIDs := map[string]string{}
+	teamIDs := map[string]string{}
+	channelIDs := map[string]string{}
+
+	mapper := idMapper{
+		user: func(id string) string {
+			if newID, ok := userIDs[id]; ok {
+				return newID
+			}
+			userIDs[id] = ""
+			if username, ok := archive.Users[id]; ok {
+				if user, err := s.pluginAPI.User.GetByUsername(username); err == nil {
+					userIDs[id] = user.Id
+				}
+			}
+			return userIDs[id]
+		},
+		team: func(id string) string {
+			if newID, ok := teamIDs[id]; ok {
+				return newID
+			}
+			teamIDs[id] = ""
+			if name, ok := archive.Teams[id]; ok {
+				if team, err := s.pluginAPI.Team.GetByName(name); err == nil {
+					teamIDs[id] = team.Id
+				}
+			}
+			return teamIDs[id]
+		},
+	}
+	mapper.channel = func(id string) string {
+		if newID, ok := channelIDs[id]; ok {
+			return newID
+		}
+		channelIDs[id] = ""
+		if archived, ok := archive.Channels[id]; ok {
+			if teamID := mapper.team(archived.TeamID); teamID != "" {
+				if channel, err := s.pluginAPI.Channel.GetByName(teamID, archived.Name, false); err == nil {
+					channelIDs[id] = channel.Id
+				}
+			}
+		}
+		return channelIDs[id]
+	}
+
+	return mapper
+}
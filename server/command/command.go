@@ -1,6 +1,8 @@
 package command
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"math/rand"
 	"strconv"
@@ -9,9 +11,11 @@ import (
 
 	"github.com/pkg/errors"
 
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/archive"
 	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/bot"
 	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/config"
 	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/incident"
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/oncall"
 	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/permissions"
 	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/playbook"
 	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/timeutils"
@@ -30,10 +34,18 @@ const helpText = "###### Mattermost Incident Collaboration Plugin - Slash Comman
 	"* `/incident checkadd [checklist #] [item text]` - add a checklist item. \n" +
 	"* `/incident checkremove [checklist #] [item #]` - remove a checklist item. \n" +
 	"* `/incident owner [@username]` - Show or change the current owner. \n" +
+	"* `/incident severity [level]` - Show or change the incident's severity (SEV1-SEV4). \n" +
+	"* `/incident role [role] [@username...]` - Show the roles, or assign a role to one or more users. \n" +
+	"* `/incident oncall [rotation]` - Show who is on call now and next in the team's rotations. \n" +
 	"* `/incident announce ~[channels]` - Announce the current incident in other channels. \n" +
+	"* `/incident link [related|duplicate_of|parent_of|child_of] ~[channel]` - Link the current incident to the incident of another channel. \n" +
+	"* `/incident merge ~[channel]` - Merge the current incident into the incident of another channel. \n" +
+	"* `/incident follow ~[channel]` - Receive the status updates of the incident of a channel, or of the current incident, by direct message. \n" +
+	"* `/incident unfollow ~[channel]` - Stop receiving the status updates of the incident of a channel, or of the current incident. \n" +
 	"* `/incident list` - List all your incidents. \n" +
 	"* `/incident info` - Show a summary of the current incident. \n" +
-	"* `/incident timeline` - Show the timeline for the current incident. \n" +
+	"* `/incident timeline [md|csv|ics] [event types...]` - Show the timeline for the current incident, or export it in a file sent by direct message. \n" +
+	"* `/incident export [json|csv]` - Export the incidents and playbooks of this team (system admins only). \n" +
 	"\n" +
 	"Learn more [in our documentation](https://mattermost.com/pl/default-incident-response-app-documentation). \n" +
 	""
@@ -54,7 +66,7 @@ func getCommand(addTestCommands bool) *model.Command {
 		DisplayName:      "Incident",
 		Description:      "Incident Collaboration Plugin",
 		AutoComplete:     true,
-		AutoCompleteDesc: "Available commands: start, end, update, restart, check, announce, list, owner, info",
+		AutoCompleteDesc: "Available commands: start, end, update, restart, check, announce, list, owner, severity, role, oncall, link, merge, follow, unfollow, info, export",
 		AutoCompleteHint: "[command]",
 		AutocompleteData: getAutocompleteData(addTestCommands),
 	}
@@ -62,7 +74,7 @@ func getCommand(addTestCommands bool) *model.Command {
 
 func getAutocompleteData(addTestCommands bool) *model.AutocompleteData {
 	slashIncident := model.NewAutocompleteData("incident", "[command]",
-		"Available commands: start, end, update, restart, check, checkadd, checkremove, announce, list, owner, info, timeline")
+		"Available commands: start, end, update, restart, check, checkadd, checkremove, announce, list, owner, severity, role, oncall, info, timeline, export")
 
 	start := model.NewAutocompleteData("start", "", "Starts a new incident")
 	slashIncident.AddCommand(start)
@@ -107,6 +119,32 @@ func getAutocompleteData(addTestCommands bool) *model.AutocompleteData {
 		"Channel to announce incident in", "~[channel]", "", true)
 	slashIncident.AddCommand(announce)
 
+	link := model.NewAutocompleteData("link", "[type] ~[channel]",
+		"Link the current incident to the incident of another channel")
+	link.AddStaticListArgument("The type of link, from the point of view of the current incident.", true, []model.AutocompleteListItem{
+		{Item: incident.LinkRelated, HelpText: "The incidents are related"},
+		{Item: incident.LinkDuplicateOf, HelpText: "The current incident is a duplicate of the other one"},
+		{Item: incident.LinkParentOf, HelpText: "The current incident is the parent of the other one"},
+		{Item: incident.LinkChildOf, HelpText: "The current incident is a child of the other one"},
+	})
+	link.AddTextArgument("The channel of the incident to link.", "~[channel]", "")
+	slashIncident.AddCommand(link)
+
+	merge := model.NewAutocompleteData("merge", "~[channel]",
+		"Merge the current incident into the incident of another channel")
+	merge.AddTextArgument("The channel of the incident to merge into.", "~[channel]", "")
+	slashIncident.AddCommand(merge)
+
+	follow := model.NewAutocompleteData("follow", "~[channel]",
+		"Receive the status updates of an incident by direct message")
+	follow.AddTextArgument("The channel of the incident to follow. Defaults to the current incident.", "~[channel]", "")
+	slashIncident.AddCommand(follow)
+
+	unfollow := model.NewAutocompleteData("unfollow", "~[channel]",
+		"Stop receiving the status updates of an incident")
+	unfollow.AddTextArgument("The channel of the incident to unfollow. Defaults to the current incident.", "~[channel]", "")
+	slashIncident.AddCommand(unfollow)
+
 	list := model.NewAutocompleteData("list", "", "Lists all your incidents")
 	slashIncident.AddCommand(list)
 
@@ -115,12 +153,49 @@ func getAutocompleteData(addTestCommands bool) *model.AutocompleteData {
 	owner.AddTextArgument("The desired new owner.", "[@username]", "")
 	slashIncident.AddCommand(owner)
 
+	severity := model.NewAutocompleteData("severity", "[level]",
+		"Show or change the incident's severity")
+	severityItems := make([]model.AutocompleteListItem, 0, len(incident.Severities))
+	for _, level := range incident.Severities {
+		severityItems = append(severityItems, model.AutocompleteListItem{
+			Item:     level,
+			HelpText: incident.SeverityDisplayName(level),
+		})
+	}
+	severity.AddStaticListArgument("The desired severity level.", false, severityItems)
+	slashIncident.AddCommand(severity)
+
+	role := model.NewAutocompleteData("role", "[role] [@username...]",
+		"Show the roles, or assign a role to one or more users")
+	role.AddTextArgument("The role, as defined by the playbook.", "[role]", "")
+	role.AddTextArgument("The users to assign the role to.", "[@username...]", "")
+	slashIncident.AddCommand(role)
+
+	onCall := model.NewAutocompleteData("oncall", "[rotation]",
+		"Shows who is on call now and next in the team's rotations")
+	onCall.AddTextArgument("The name of the rotation.", "[rotation]", "")
+	slashIncident.AddCommand(onCall)
+
 	info := model.NewAutocompleteData("info", "", "Shows a summary of the current incident")
 	slashIncident.AddCommand(info)
 
-	timeline := model.NewAutocompleteData("timeline", "", "Shows the timeline for the current incident")
+	timeline := model.NewAutocompleteData("timeline", "[md|csv|ics] [event types...]", "Shows the timeline for the current incident, or exports it")
+	timeline.AddStaticListArgument("The format of the export. Leave blank to show the timeline.", false, []model.AutocompleteListItem{
+		{Item: "md", HelpText: "Markdown table"},
+		{Item: "csv", HelpText: "CSV file"},
+		{Item: "ics", HelpText: "iCalendar file, with one event per timeline event"},
+	})
+	timeline.AddTextArgument("The types of the events to export. Defaults to every type.", "[event types...]", "")
 	slashIncident.AddCommand(timeline)
 
+	export := model.NewAutocompleteData("export", "[json|csv]",
+		"Exports the incidents and playbooks of this team (system admins only)")
+	export.AddStaticListArgument("The format of the export.", false, []model.AutocompleteListItem{
+		{Item: "json", HelpText: "Archive of the incidents and playbooks, which can be imported back"},
+		{Item: "csv", HelpText: "Summary of the incidents"},
+	})
+	slashIncident.AddCommand(export)
+
 	if addTestCommands {
 		test := model.NewAutocompleteData("test", "", "Commands for testing and debugging.")
 
@@ -155,12 +230,14 @@ type Runner struct {
 	poster          bot.Poster
 	incidentService incident.Service
 	playbookService playbook.Service
+	onCallService   oncall.Service
 	configService   config.Service
 }
 
 // NewCommandRunner creates a command runner.
 func NewCommandRunner(ctx *plugin.Context, args *model.CommandArgs, api *pluginapi.Client,
-	logger bot.Logger, poster bot.Poster, incidentService incident.Service, playbookService playbook.Service, configService config.Service) *Runner {
+	logger bot.Logger, poster bot.Poster, incidentService incident.Service, playbookService playbook.Service, onCallService oncall.Service,
+	configService config.Service) *Runner {
 	return &Runner{
 		context:         ctx,
 		args:            args,
@@ -169,6 +246,7 @@ func NewCommandRunner(ctx *plugin.Context, args *model.CommandArgs, api *plugina
 		poster:          poster,
 		incidentService: incidentService,
 		playbookService: playbookService,
+		onCallService:   onCallService,
 		configService:   configService,
 	}
 }
@@ -226,13 +304,24 @@ func (r *Runner) actionStart(args []string) {
 		return
 	}
 
+	// The list of playbooks lacks their custom fields, which the dialog shows.
+	playbooks := make([]playbook.Playbook, 0, len(playbooksResults.Items))
+	for _, pb := range playbooksResults.Items {
+		fullPlaybook, err2 := r.playbookService.Get(pb.ID)
+		if err2 != nil {
+			r.warnUserAndLogErrorf("Error: %v", err2)
+			return
+		}
+		playbooks = append(playbooks, fullPlaybook)
+	}
+
 	session, err := r.pluginAPI.Session.Get(r.context.SessionId)
 	if err != nil {
 		r.warnUserAndLogErrorf("Error retrieving session: %v", err)
 		return
 	}
 
-	if err := r.incidentService.OpenCreateIncidentDialog(r.args.TeamId, r.args.UserId, r.args.TriggerId, postID, clientID, playbooksResults.Items, session.IsMobileApp()); err != nil {
+	if err := r.incidentService.OpenCreateIncidentDialog(r.args.TeamId, r.args.UserId, r.args.TriggerId, postID, clientID, playbooks, session.IsMobileApp()); err != nil {
 		r.warnUserAndLogErrorf("Error: %v", err)
 		return
 	}
@@ -267,6 +356,10 @@ func (r *Runner) actionCheck(args []string) {
 	}
 
 	err = r.incidentService.ToggleCheckedState(incidentID, r.args.UserId, checklist, item)
+	if errors.Is(err, incident.ErrChecklistItemBlocked) {
+		r.postCommandResponse(fmt.Sprintf("Unable to check this item: %v.", err))
+		return
+	}
 	if err != nil {
 		r.warnUserAndLogErrorf("Error checking/unchecking item: %v", err)
 	}
@@ -431,6 +524,211 @@ func (r *Runner) actionChangeOwner(args []string) {
 	}
 }
 
+func (r *Runner) actionSeverity(args []string) {
+	if len(args) > 1 {
+		r.postCommandResponse("/incident severity expects at most one argument.")
+		return
+	}
+
+	incidentID, err := r.incidentService.GetIncidentIDForChannel(r.args.ChannelId)
+	if errors.Is(err, incident.ErrNotFound) {
+		r.postCommandResponse("You can only see or change the severity from within the incident's channel.")
+		return
+	} else if err != nil {
+		r.warnUserAndLogErrorf("Error retrieving incident for channel %s: %v", r.args.ChannelId, err)
+		return
+	}
+
+	currentIncident, err := r.incidentService.GetIncident(incidentID)
+	if err != nil {
+		r.warnUserAndLogErrorf("Error retrieving incident: %v", err)
+		return
+	}
+
+	if len(args) == 0 {
+		r.postCommandResponse(fmt.Sprintf("The current severity for this incident is **%s**.", incident.SeverityDisplayName(currentIncident.Severity)))
+		return
+	}
+
+	targetSeverity := strings.ToUpper(args[0])
+	if targetSeverity == "" || !incident.IsValidSeverity(targetSeverity) {
+		r.postCommandResponse(fmt.Sprintf("Invalid severity %s. Valid levels are: %s.", args[0], strings.Join(incident.Severities, ", ")))
+		return
+	}
+
+	if currentIncident.Severity == targetSeverity {
+		r.postCommandResponse(fmt.Sprintf("The incident severity is already **%s**.", incident.SeverityDisplayName(targetSeverity)))
+		return
+	}
+
+	if err = permissions.EditIncident(r.args.UserId, r.args.ChannelId, r.pluginAPI); err != nil {
+		if errors.Is(err, permissions.ErrNoPermissions) {
+			r.postCommandResponse(fmt.Sprintf("userID `%s` is not an admin or channel member", r.args.UserId))
+			return
+		}
+		r.warnUserAndLogErrorf("Error checking permissions: %v", err)
+		return
+	}
+
+	if err = r.incidentService.ChangeSeverity(currentIncident.ID, r.args.UserId, targetSeverity); err != nil {
+		r.warnUserAndLogErrorf("Failed to change severity to %s: %v", targetSeverity, err)
+		return
+	}
+}
+
+func (r *Runner) actionRole(args []string) {
+	incidentID, err := r.incidentService.GetIncidentIDForChannel(r.args.ChannelId)
+	if errors.Is(err, incident.ErrNotFound) {
+		r.postCommandResponse("You can only see or change the roles from within the incident's channel.")
+		return
+	} else if err != nil {
+		r.warnUserAndLogErrorf("Error retrieving incident for channel %s: %v", r.args.ChannelId, err)
+		return
+	}
+
+	currentIncident, err := r.incidentService.GetIncident(incidentID)
+	if err != nil {
+		r.warnUserAndLogErrorf("Error retrieving incident: %v", err)
+		return
+	}
+
+	if len(currentIncident.Roles) == 0 {
+		r.postCommandResponse("This incident has no roles. Roles are defined in the playbook the incident was started from.")
+		return
+	}
+
+	if len(args) == 0 {
+		message := ""
+		for _, role := range currentIncident.Roles {
+			message += fmt.Sprintf("* **%s** (`%s`): %s\n", role.Name, role.Key, r.usernames(currentIncident.RoleUserIDs(role.Key)))
+		}
+		r.postCommandResponse(message)
+		return
+	}
+
+	role, ok := playbook.FindRole(currentIncident.Roles, strings.ToLower(args[0]))
+	if !ok {
+		keys := make([]string, 0, len(currentIncident.Roles))
+		for _, incidentRole := range currentIncident.Roles {
+			keys = append(keys, incidentRole.Key)
+		}
+		r.postCommandResponse(fmt.Sprintf("Unknown role %s. Valid roles are: %s.", args[0], strings.Join(keys, ", ")))
+		return
+	}
+
+	if len(args) == 1 {
+		r.postCommandResponse(fmt.Sprintf("The **%s** role is held by %s.", role.Name, r.usernames(currentIncident.RoleUserIDs(role.Key))))
+		return
+	}
+
+	if err = permissions.EditIncident(r.args.UserId, r.args.ChannelId, r.pluginAPI); err != nil {
+		if errors.Is(err, permissions.ErrNoPermissions) {
+			r.postCommandResponse(fmt.Sprintf("userID `%s` is not an admin or channel member", r.args.UserId))
+			return
+		}
+		r.warnUserAndLogErrorf("Error checking permissions: %v", err)
+		return
+	}
+
+	assigneeIDs := make([]string, 0, len(args)-1)
+	for _, arg := range args[1:] {
+		username := strings.TrimLeft(arg, "@")
+
+		user, err2 := r.pluginAPI.User.GetByUsername(username)
+		if errors.Is(err2, pluginapi.ErrNotFound) {
+			r.postCommandResponse(fmt.Sprintf("Unable to find user @%s", username))
+			return
+		} else if err2 != nil {
+			r.warnUserAndLogErrorf("Error finding user @%s: %v", username, err2)
+			return
+		}
+
+		_, err2 = r.pluginAPI.Channel.GetMember(r.args.ChannelId, user.Id)
+		if errors.Is(err2, pluginapi.ErrNotFound) {
+			r.postCommandResponse(fmt.Sprintf("User @%s must be part of this channel to be assigned a role.", username))
+			return
+		} else if err2 != nil {
+			r.warnUserAndLogErrorf("Failed to find user @%s as channel member: %v", username, err2)
+			return
+		}
+
+		assigneeIDs = append(assigneeIDs, user.Id)
+	}
+
+	if err = r.incidentService.AssignRole(currentIncident.ID, r.args.UserId, role.Key, assigneeIDs); err != nil {
+		r.warnUserAndLogErrorf("Failed to assign the %s role: %v", role.Name, err)
+		return
+	}
+}
+
+// usernames returns the usernames of userIDs, each prefixed with @ and separated by commas, or
+// "nobody" if userIDs is empty. Users that cannot be found are left out.
+func (r *Runner) usernames(userIDs []string) string {
+	usernames := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		user, err := r.pluginAPI.User.Get(userID)
+		if err != nil {
+			continue
+		}
+		usernames = append(usernames, "@"+user.Username)
+	}
+
+	if len(usernames) == 0 {
+		return "nobody"
+	}
+	return strings.Join(usernames, ", ")
+}
+
+// actionOnCall shows who is on call now and who is next in the rotations of the current team, or
+// only in those whose name starts with the arguments.
+func (r *Runner) actionOnCall(args []string) {
+	if !permissions.CanViewTeam(r.args.UserId, r.args.TeamId, r.pluginAPI) {
+		r.postCommandResponse("Must be a member of the team to see its on-call rotations.")
+		return
+	}
+
+	rotations, err := r.onCallService.GetRotationsForTeam(r.args.TeamId)
+	if err != nil {
+		r.warnUserAndLogErrorf("Error retrieving the on-call rotations: %v", err)
+		return
+	}
+
+	onCallUsername := func(userID string) string {
+		if userID == "" {
+			return "nobody"
+		}
+		return r.usernames([]string{userID})
+	}
+
+	name := strings.ToLower(strings.Join(args, " "))
+	now := model.GetMillis()
+	message := ""
+	for _, rotation := range rotations {
+		if !strings.HasPrefix(strings.ToLower(rotation.Name), name) {
+			continue
+		}
+
+		shifts := rotation.Shifts(now, 2)
+		message += fmt.Sprintf("* **%s**: %s now", rotation.Name, onCallUsername(shifts[0].UserID))
+		if len(shifts) > 1 {
+			message += fmt.Sprintf(", %s in %s", onCallUsername(shifts[1].UserID),
+				timeutils.DurationString(timeutils.GetTimeForMillis(now), timeutils.GetTimeForMillis(shifts[1].StartAt)))
+		}
+		message += "\n"
+	}
+
+	if message == "" {
+		if name == "" {
+			r.postCommandResponse("This team has no on-call rotations.")
+		} else {
+			r.postCommandResponse(fmt.Sprintf("This team has no on-call rotation named %s.", strings.Join(args, " ")))
+		}
+		return
+	}
+
+	r.postCommandResponse(message)
+}
+
 func (r *Runner) actionAnnounce(args []string) {
 	if len(args) < 1 {
 		r.postCommandResponse(helpText)
@@ -482,6 +780,188 @@ func (r *Runner) actionAnnounce(args []string) {
 	}
 }
 
+// linkedIncidentLine returns the name and status of a linked incident, for /incident info.
+func linkedIncidentLine(link incident.LinkedIncident) string {
+	return fmt.Sprintf("%s (%s)", link.Name, link.CurrentStatus)
+}
+
+func (r *Runner) actionLink(args []string) {
+	if len(args) != 2 {
+		r.postCommandResponse("Usage: `/incident link [related|duplicate_of|parent_of|child_of] ~[channel]`")
+		return
+	}
+
+	linkType := strings.ToLower(args[0])
+	if !incident.IsValidLinkType(linkType) {
+		r.postCommandResponse(fmt.Sprintf("Invalid link type %s. Valid types are: %s.", args[0],
+			strings.Join([]string{incident.LinkRelated, incident.LinkDuplicateOf, incident.LinkParentOf, incident.LinkChildOf}, ", ")))
+		return
+	}
+
+	incidentID, err := r.incidentService.GetIncidentIDForChannel(r.args.ChannelId)
+	if errors.Is(err, incident.ErrNotFound) {
+		r.postCommandResponse("You can only link an incident from within the incident's channel.")
+		return
+	} else if err != nil {
+		r.warnUserAndLogErrorf("Error retrieving incident for channel %s: %v", r.args.ChannelId, err)
+		return
+	}
+
+	targetChannel, err := r.pluginAPI.Channel.GetByName(r.args.TeamId, strings.TrimPrefix(args[1], "~"), false)
+	if err != nil {
+		r.postCommandResponse("Channel not found: " + args[1])
+		return
+	}
+
+	linkedIncidentID, err := r.incidentService.GetIncidentIDForChannel(targetChannel.Id)
+	if errors.Is(err, incident.ErrNotFound) {
+		r.postCommandResponse(fmt.Sprintf("The channel %s is not an incident channel.", args[1]))
+		return
+	} else if err != nil {
+		r.warnUserAndLogErrorf("Error retrieving incident for channel %s: %v", targetChannel.Id, err)
+		return
+	}
+
+	for _, channelID := range []string{r.args.ChannelId, targetChannel.Id} {
+		if err = permissions.EditIncident(r.args.UserId, channelID, r.pluginAPI); err != nil {
+			if errors.Is(err, permissions.ErrNoPermissions) {
+				r.postCommandResponse("You need to be a member of both incident channels to link them.")
+				return
+			}
+			r.warnUserAndLogErrorf("Error checking permissions: %v", err)
+			return
+		}
+	}
+
+	if _, err = r.incidentService.LinkIncidents(incidentID, r.args.UserId, linkedIncidentID, linkType); err != nil {
+		if errors.Is(err, incident.ErrMalformedLink) || errors.Is(err, incident.ErrDuplicateEntry) {
+			r.postCommandResponse(fmt.Sprintf("Could not link the incidents: %s.", err.Error()))
+			return
+		}
+		r.warnUserAndLogErrorf("Failed to link the incidents: %v", err)
+		return
+	}
+}
+
+func (r *Runner) actionMerge(args []string) {
+	if len(args) != 1 {
+		r.postCommandResponse("Usage: `/incident merge ~[channel]`")
+		return
+	}
+
+	incidentID, err := r.incidentService.GetIncidentIDForChannel(r.args.ChannelId)
+	if errors.Is(err, incident.ErrNotFound) {
+		r.postCommandResponse("You can only merge an incident from within the incident's channel.")
+		return
+	} else if err != nil {
+		r.warnUserAndLogErrorf("Error retrieving incident for channel %s: %v", r.args.ChannelId, err)
+		return
+	}
+
+	targetChannel, err := r.pluginAPI.Channel.GetByName(r.args.TeamId, strings.TrimPrefix(args[0], "~"), false)
+	if err != nil {
+		r.postCommandResponse("Channel not found: " + args[0])
+		return
+	}
+
+	targetIncidentID, err := r.incidentService.GetIncidentIDForChannel(targetChannel.Id)
+	if errors.Is(err, incident.ErrNotFound) {
+		r.postCommandResponse(fmt.Sprintf("The channel %s is not an incident channel.", args[0]))
+		return
+	} else if err != nil {
+		r.warnUserAndLogErrorf("Error retrieving incident for channel %s: %v", targetChannel.Id, err)
+		return
+	}
+
+	for _, channelID := range []string{r.args.ChannelId, targetChannel.Id} {
+		if err = permissions.EditIncident(r.args.UserId, channelID, r.pluginAPI); err != nil {
+			if errors.Is(err, permissions.ErrNoPermissions) {
+				r.postCommandResponse("You need to be a member of both incident channels to merge them.")
+				return
+			}
+			r.warnUserAndLogErrorf("Error checking permissions: %v", err)
+			return
+		}
+	}
+
+	if err = r.incidentService.MergeIncident(incidentID, r.args.UserId, targetIncidentID); err != nil {
+		if errors.Is(err, incident.ErrInvalidMerge) {
+			r.postCommandResponse(fmt.Sprintf("Could not merge the incidents: %s.", err.Error()))
+			return
+		}
+		r.warnUserAndLogErrorf("Failed to merge the incidents: %v", err)
+		return
+	}
+}
+
+func (r *Runner) actionFollow(args []string) {
+	incidentID, ok := r.incidentToFollow(args, "follow")
+	if !ok {
+		return
+	}
+
+	if err := r.incidentService.FollowIncident(incidentID, r.args.UserId); err != nil {
+		r.warnUserAndLogErrorf("Failed to follow the incident: %v", err)
+		return
+	}
+
+	r.postCommandResponse("You are now following the incident. Its status updates will be sent to you by direct message.")
+}
+
+func (r *Runner) actionUnfollow(args []string) {
+	incidentID, ok := r.incidentToFollow(args, "unfollow")
+	if !ok {
+		return
+	}
+
+	if err := r.incidentService.UnfollowIncident(incidentID, r.args.UserId); err != nil {
+		r.warnUserAndLogErrorf("Failed to unfollow the incident: %v", err)
+		return
+	}
+
+	r.postCommandResponse("You are no longer following the incident.")
+}
+
+// incidentToFollow returns the ID of the incident of the channel in args, or of the current
+// channel if args is empty, checking that the user can view it. Otherwise, it tells the user why
+// and returns false.
+func (r *Runner) incidentToFollow(args []string, subcommand string) (string, bool) {
+	if len(args) > 1 {
+		r.postCommandResponse(fmt.Sprintf("Usage: `/incident %s ~[channel]`", subcommand))
+		return "", false
+	}
+
+	channelID := r.args.ChannelId
+	if len(args) == 1 {
+		channel, err := r.pluginAPI.Channel.GetByName(r.args.TeamId, strings.TrimPrefix(args[0], "~"), false)
+		if err != nil {
+			r.postCommandResponse("Channel not found: " + args[0])
+			return "", false
+		}
+		channelID = channel.Id
+	}
+
+	incidentID, err := r.incidentService.GetIncidentIDForChannel(channelID)
+	if errors.Is(err, incident.ErrNotFound) {
+		r.postCommandResponse(fmt.Sprintf("Not an incident channel. Use `/incident %s ~[channel]` with the channel of an incident.", subcommand))
+		return "", false
+	} else if err != nil {
+		r.warnUserAndLogErrorf("Error retrieving incident for channel %s: %v", channelID, err)
+		return "", false
+	}
+
+	if err = permissions.ViewIncidentFromChannelID(r.args.UserId, channelID, r.pluginAPI); err != nil {
+		if errors.Is(err, permissions.ErrNoPermissions) {
+			r.postCommandResponse("You do not have permission to view this incident.")
+			return "", false
+		}
+		r.warnUserAndLogErrorf("Error checking permissions: %v", err)
+		return "", false
+	}
+
+	return incidentID, true
+}
+
 func (r *Runner) actionList() {
 	team, err := r.pluginAPI.Team.Get(r.args.TeamId)
 	if err != nil {
@@ -594,6 +1074,7 @@ func (r *Runner) actionInfo() {
 		return
 	}
 
+	now := model.GetMillis()
 	tasks := ""
 	for _, checklist := range theIncident.Checklists {
 		for _, item := range checklist.Items {
@@ -602,18 +1083,40 @@ func (r *Runner) actionInfo() {
 			if item.State == playbook.ChecklistItemStateClosed {
 				icon = ":white_check_mark: "
 				timestamp = " (" + timeutils.GetTimeForMillis(item.StateModified).Format("15:04 PM") + ")"
+			} else if item.IsOverdue(now) {
+				icon = ":alarm_clock: "
+				timestamp = " (**overdue** since " + timeutils.GetTimeForMillis(item.DueAt).Format("15:04 PM") + ")"
 			}
 
 			tasks += icon + item.Title + timestamp + "\n"
 		}
 	}
+	fields := []*model.SlackAttachmentField{
+		{Title: "Incident Name:", Value: fmt.Sprintf("**%s**", strings.Trim(theIncident.Name, " "))},
+		{Title: "Duration:", Value: timeutils.DurationString(timeutils.GetTimeForMillis(theIncident.CreateAt), time.Now())},
+		{Title: "Owner:", Value: fmt.Sprintf("@%s", owner.Username)},
+		{Title: "Severity:", Value: incident.SeverityDisplayName(theIncident.Severity)},
+	}
+	if len(theIncident.Roles) > 0 {
+		roles := ""
+		for _, role := range theIncident.Roles {
+			roles += fmt.Sprintf("%s: %s\n", role.Name, r.usernames(theIncident.RoleUserIDs(role.Key)))
+		}
+		fields = append(fields, &model.SlackAttachmentField{Title: "Roles:", Value: roles})
+	}
+	if parent, ok := theIncident.Parent(); ok {
+		fields = append(fields, &model.SlackAttachmentField{Title: "Parent Incident:", Value: linkedIncidentLine(parent)})
+	}
+	if children := theIncident.Children(); len(children) > 0 {
+		value := ""
+		for _, child := range children {
+			value += linkedIncidentLine(child) + "\n"
+		}
+		fields = append(fields, &model.SlackAttachmentField{Title: "Child Incidents:", Value: value})
+	}
+	fields = append(fields, &model.SlackAttachmentField{Title: "Tasks:", Value: tasks})
 	attachment := &model.SlackAttachment{
-		Fields: []*model.SlackAttachmentField{
-			{Title: "Incident Name:", Value: fmt.Sprintf("**%s**", strings.Trim(theIncident.Name, " "))},
-			{Title: "Duration:", Value: timeutils.DurationString(timeutils.GetTimeForMillis(theIncident.CreateAt), time.Now())},
-			{Title: "Owner:", Value: fmt.Sprintf("@%s", owner.Username)},
-			{Title: "Tasks:", Value: tasks},
-		},
+		Fields: fields,
 	}
 
 	post := &model.Post{
@@ -661,6 +1164,9 @@ func (r *Runner) actionUpdate() {
 	case errors.Is(err, incident.ErrIncidentNotActive):
 		r.postCommandResponse("This incident has already been closed.")
 		return
+	case errors.Is(err, incident.ErrIncidentMerged):
+		r.postCommandResponse("This incident was merged into another incident: update the status of that incident instead.")
+		return
 	case err != nil:
 		r.warnUserAndLogErrorf("Error: %v", err)
 		return
@@ -701,7 +1207,7 @@ func (r *Runner) actionAdd(args []string) {
 	}
 }
 
-func (r *Runner) actionTimeline() {
+func (r *Runner) actionTimeline(args []string) {
 	incidentID, err := r.incidentService.GetIncidentIDForChannel(r.args.ChannelId)
 	if err != nil {
 		if errors.Is(err, incident.ErrNotFound) {
@@ -712,6 +1218,11 @@ func (r *Runner) actionTimeline() {
 		return
 	}
 
+	if len(args) > 0 {
+		r.actionTimelineExport(incidentID, args)
+		return
+	}
+
 	incidentToRead, err := r.incidentService.GetIncident(incidentID)
 	if err != nil {
 		r.warnUserAndLogErrorf("Error retrieving incident: %v", err)
@@ -758,6 +1269,53 @@ func (r *Runner) actionTimeline() {
 	r.poster.EphemeralPost(r.args.UserId, r.args.ChannelId, &model.Post{Message: message})
 }
 
+// actionTimelineExport exports the timeline of incidentID in the format given by the first
+// argument, restricted to the event types given by the others, and uploads it to the direct
+// channel between the user and the bot.
+func (r *Runner) actionTimelineExport(incidentID string, args []string) {
+	options := incident.TimelineExportOptions{
+		Format:     incident.TimelineExportFormat(strings.ToLower(args[0])),
+		EventTypes: args[1:],
+	}
+	if err := incident.ValidateTimelineExportOptions(&options); err != nil {
+		r.postCommandResponse(fmt.Sprintf("Error: %v. Usage: `/incident timeline [md|csv|ics] [event types...]`, where the event types are among: %s.",
+			err, strings.Join(incident.TimelineEventTypes, ", ")))
+		return
+	}
+
+	data, err := r.incidentService.ExportTimeline(incidentID, options)
+	if err != nil {
+		r.warnUserAndLogErrorf("Error exporting the timeline: %v", err)
+		return
+	}
+
+	botUserID := r.configService.GetConfiguration().BotUserID
+	channel, err := r.pluginAPI.Channel.GetDirect(r.args.UserId, botUserID)
+	if err != nil {
+		r.warnUserAndLogErrorf("Error getting the direct channel with the bot: %v", err)
+		return
+	}
+
+	fileInfo, err := r.pluginAPI.File.Upload(bytes.NewReader(data), incident.TimelineExportFileName(incidentID, options.Format), channel.Id)
+	if err != nil {
+		r.warnUserAndLogErrorf("Error uploading the timeline export: %v", err)
+		return
+	}
+
+	err = r.pluginAPI.Post.CreatePost(&model.Post{
+		UserId:    botUserID,
+		ChannelId: channel.Id,
+		Message:   "Here is the export of the timeline of the incident.",
+		FileIds:   []string{fileInfo.Id},
+	})
+	if err != nil {
+		r.warnUserAndLogErrorf("Error posting the timeline export: %v", err)
+		return
+	}
+
+	r.postCommandResponse("Exported the timeline. The file was sent to you in a direct message.")
+}
+
 func (r *Runner) summaryMessage(event incident.TimelineEvent) string {
 	var username string
 	user, err := r.pluginAPI.User.Get(event.SubjectUserID)
@@ -775,6 +1333,16 @@ func (r *Runner) summaryMessage(event incident.TimelineEvent) string {
 		return "@" + username + " changed status from " + event.Summary
 	case incident.OwnerChanged:
 		return "Owner changes from " + event.Summary
+	case incident.SeverityChanged:
+		return "@" + username + " changed severity from " + event.Summary
+	case incident.RoleChanged:
+		return "@" + username + " changed role " + event.Summary
+	case incident.OwnerAcknowledged:
+		return "@" + username + " acknowledged the incident"
+	case incident.IncidentEscalated:
+		return "Escalated. " + event.Summary
+	case incident.IncidentMerged:
+		return "@" + username + ": " + event.Summary
 	case incident.TaskStateModified:
 		return "@" + username + " " + event.Summary
 	case incident.AssigneeChanged:
@@ -801,6 +1369,71 @@ func (r *Runner) timeSince(event incident.TimelineEvent, reported time.Time) str
 	return "-" + timeutils.DurationString(eventAt, reported)
 }
 
+// actionExport exports the incidents and playbooks of the current team to an archive, or to a CSV
+// summary of its incidents if the first argument is csv, and uploads it to the direct channel
+// between the user and the bot.
+func (r *Runner) actionExport(args []string) {
+	if !permissions.IsAdmin(r.args.UserId, r.pluginAPI) {
+		r.postCommandResponse("Exporting incidents is restricted to system administrators.")
+		return
+	}
+
+	format := "json"
+	if len(args) > 0 {
+		format = strings.ToLower(args[0])
+	}
+	if format != "json" && format != "csv" {
+		r.postCommandResponse("Usage: `/incident export [json|csv]`")
+		return
+	}
+
+	archiveService := archive.NewService(r.pluginAPI, r.incidentService, r.playbookService)
+	theArchive, err := archiveService.Export(r.args.TeamId, r.args.UserId)
+	if err != nil {
+		r.warnUserAndLogErrorf("Error exporting incidents: %v", err)
+		return
+	}
+
+	var content bytes.Buffer
+	if format == "csv" {
+		err = archive.WriteCSV(&content, theArchive)
+	} else {
+		err = json.NewEncoder(&content).Encode(theArchive)
+	}
+	if err != nil {
+		r.warnUserAndLogErrorf("Error writing the export: %v", err)
+		return
+	}
+
+	botUserID := r.configService.GetConfiguration().BotUserID
+	channel, err := r.pluginAPI.Channel.GetDirect(r.args.UserId, botUserID)
+	if err != nil {
+		r.warnUserAndLogErrorf("Error getting the direct channel with the bot: %v", err)
+		return
+	}
+
+	fileInfo, err := r.pluginAPI.File.Upload(&content, archive.FileName(r.args.TeamId, format), channel.Id)
+	if err != nil {
+		r.warnUserAndLogErrorf("Error uploading the export: %v", err)
+		return
+	}
+
+	err = r.pluginAPI.Post.CreatePost(&model.Post{
+		UserId:    botUserID,
+		ChannelId: channel.Id,
+		Message: fmt.Sprintf("Here is the export of the %d incidents and %d playbooks of this team.",
+			len(theArchive.Incidents), len(theArchive.Playbooks)),
+		FileIds: []string{fileInfo.Id},
+	})
+	if err != nil {
+		r.warnUserAndLogErrorf("Error posting the export: %v", err)
+		return
+	}
+
+	r.postCommandResponse(fmt.Sprintf("Exported %d incidents and %d playbooks. The file was sent to you in a direct message.",
+		len(theArchive.Incidents), len(theArchive.Playbooks)))
+}
+
 func (r *Runner) actionTestSelf(args []string) {
 	if r.pluginAPI.Configuration.GetConfig().ServiceSettings.EnableTesting == nil ||
 		!*r.pluginAPI.Configuration.GetConfig().ServiceSettings.EnableTesting {
@@ -1433,8 +2066,22 @@ func (r *Runner) Execute() error {
 		r.actionRestart()
 	case "owner":
 		r.actionOwner(parameters)
+	case "severity":
+		r.actionSeverity(parameters)
+	case "role":
+		r.actionRole(parameters)
+	case "oncall":
+		r.actionOnCall(parameters)
 	case "announce":
 		r.actionAnnounce(parameters)
+	case "link":
+		r.actionLink(parameters)
+	case "merge":
+		r.actionMerge(parameters)
+	case "follow":
+		r.actionFollow(parameters)
+	case "unfollow":
+		r.actionUnfollow(parameters)
 	case "list":
 		r.actionList()
 	case "info":
@@ -1442,7 +2089,9 @@ func (r *Runner) Execute() error {
 	case "add":
 		r.actionAdd(parameters)
 	case "timeline":
-		r.actionTimeline()
+		r.actionTimeline(parameters)
+	case "export":
+		r.actionExport(parameters)
 	case "nuke-db":
 		r.actionNukeDB(parameters)
 	case "test":
@@ -0,0 +1,207 @@
+package incident
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"text/template"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/pkg/errors"
+	stripmd "github.com/writeas/go-strip-markdown"
+
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/playbook"
+)
+
+// Results of an automatic checklist action, recorded in the details of its timeline event.
+const (
+	ActionResultSucceeded = "succeeded"
+	ActionResultFailed    = "failed"
+)
+
+// ActionEventDetails is stored, as JSON, in the details of the timeline event recorded for every
+// run of an automatic checklist action.
+type ActionEventDetails struct {
+	Action  string `json:"action"`
+	Trigger string `json:"trigger"`
+	Item    string `json:"item"`
+	Result  string `json:"result"`
+	Error   string `json:"error,omitempty"`
+}
+
+// actionMessageData is the data available to the message template of a post_message action.
+type actionMessageData struct {
+	IncidentName string
+	Description  string
+	Severity     string
+	ItemTitle    string
+}
+
+// itemIndex locates an item in the checklists of an incident.
+type itemIndex struct {
+	checklist int
+	item      int
+}
+
+// runAutomaticActions runs, on behalf of userID, the automatic actions triggered in theIncident:
+// the action of the item at checklistNumber and itemNumber if it was just checked, and the actions
+// of the items that are now the next open item of their checklist. Use a negative checklistNumber
+// if no item was checked. Each action runs at most once, and its result is recorded to the
+// timeline. Actions that fail are logged but do not return an error.
+func (s *ServiceImpl) runAutomaticActions(theIncident *Incident, userID string, checklistNumber, itemNumber int) {
+	var triggered []itemIndex
+	if playbook.IsValidChecklistItemIndex(theIncident.Checklists, checklistNumber, itemNumber) {
+		item := theIncident.Checklists[checklistNumber].Items[itemNumber]
+		if item.State == playbook.ChecklistItemStateClosed && item.AutomaticAction.Trigger == playbook.ActionTriggerChecked {
+			triggered = append(triggered, itemIndex{checklistNumber, itemNumber})
+		}
+	}
+	for i, checklist := range theIncident.Checklists {
+		next := checklist.NextOpenItem()
+		if next >= 0 && checklist.Items[next].AutomaticAction.Trigger == playbook.ActionTriggerNextOpen {
+			triggered = append(triggered, itemIndex{i, next})
+		}
+	}
+
+	for _, index := range triggered {
+		if theIncident.Checklists[index.checklist].Items[index.item].ActionLastRun != 0 {
+			continue
+		}
+
+		current, item, err := s.claimItemAction(theIncident.ID, theIncident.Checklists[index.checklist].Items[index.item].ID, index)
+		if err != nil {
+			s.logger.Errorf(errors.Wrapf(err, "failed to record the automatic action run of an item in incident %s", theIncident.ID).Error())
+			continue
+		}
+		if item == nil {
+			continue
+		}
+		theIncident.Checklists[index.checklist].Items[index.item].ActionLastRun = item.ActionLastRun
+
+		summary, runErr := s.runItemAction(current, userID, *item)
+
+		details := ActionEventDetails{
+			Action:  item.AutomaticAction.Type,
+			Trigger: item.AutomaticAction.Trigger,
+			Item:    item.Title,
+			Result:  ActionResultSucceeded,
+		}
+		if runErr != nil {
+			s.logger.Warnf("automatic action of item %s in incident %s failed: %v", item.ID, theIncident.ID, runErr)
+			details.Result = ActionResultFailed
+			details.Error = runErr.Error()
+		}
+
+		detailsJSON, err := json.Marshal(details)
+		if err != nil {
+			s.logger.Errorf(errors.Wrap(err, "failed to marshal automatic action details").Error())
+			continue
+		}
+
+		event := &TimelineEvent{
+			IncidentID:    theIncident.ID,
+			CreateAt:      item.ActionLastRun,
+			EventAt:       item.ActionLastRun,
+			EventType:     RanSlashCommand,
+			Summary:       summary,
+			Details:       string(detailsJSON),
+			SubjectUserID: userID,
+		}
+		if err = s.createTimelineEvent(current, event); err != nil {
+			s.logger.Errorf(errors.Wrap(err, "failed to create timeline event").Error())
+		}
+	}
+}
+
+// claimItemAction records, in the stored incident, that the automatic action of the item with
+// itemID at index is running, and returns the stored incident with a copy of the item. The run is
+// recorded before the action runs, so that an action changing the incident, or checking an item
+// of it, neither loses its changes nor runs twice. It returns a nil item if the item has moved or
+// its action has already run.
+func (s *ServiceImpl) claimItemAction(incidentID, itemID string, index itemIndex) (*Incident, *playbook.ChecklistItem, error) {
+	current, err := s.store.GetIncident(incidentID)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to retrieve incident")
+	}
+
+	if !playbook.IsValidChecklistItemIndex(current.Checklists, index.checklist, index.item) {
+		return nil, nil, nil
+	}
+	item := &current.Checklists[index.checklist].Items[index.item]
+	if item.ID != itemID || item.ActionLastRun != 0 {
+		return nil, nil, nil
+	}
+
+	item.ActionLastRun = model.GetMillis()
+	if err = s.store.UpdateIncident(current); err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to update incident")
+	}
+
+	claimed := *item
+	return current, &claimed, nil
+}
+
+// runItemAction runs the automatic action of item on behalf of userID. It returns the summary of
+// the timeline event recording the run.
+func (s *ServiceImpl) runItemAction(theIncident *Incident, userID string, item playbook.ChecklistItem) (string, error) {
+	action := item.AutomaticAction
+	title := stripmd.Strip(item.Title)
+
+	switch action.Type {
+	case playbook.ActionTypeRunCommand:
+		summary := fmt.Sprintf("automatically ran the slash command: `%s`", item.Command)
+		_, err := s.pluginAPI.SlashCommand.Execute(&model.CommandArgs{
+			Command:   item.Command,
+			UserId:    userID,
+			TeamId:    theIncident.TeamID,
+			ChannelId: theIncident.ChannelID,
+		})
+		return summary, errors.Wrap(err, "failed to run slash command")
+
+	case playbook.ActionTypePostMessage:
+		summary := fmt.Sprintf("automatically posted a message for checklist item **%s**", title)
+		message, err := renderActionMessage(action.Message, actionMessageData{
+			IncidentName: theIncident.Name,
+			Description:  theIncident.Description,
+			Severity:     theIncident.Severity,
+			ItemTitle:    item.Title,
+		})
+		if err != nil {
+			return summary, err
+		}
+
+		channelID := action.ChannelID
+		if channelID == "" {
+			channelID = theIncident.ChannelID
+		}
+		_, err = s.poster.PostMessage(channelID, "%s", message)
+		return summary, errors.Wrapf(err, "failed to post message to channel %s", channelID)
+
+	case playbook.ActionTypeInviteUsers:
+		summary := fmt.Sprintf("automatically invited %d users for checklist item **%s**", len(action.UserIDs), title)
+		botUserID := s.configService.GetConfiguration().BotUserID
+		for _, inviteeID := range action.UserIDs {
+			if _, err := s.pluginAPI.Channel.AddUser(theIncident.ChannelID, inviteeID, botUserID); err != nil {
+				return summary, errors.Wrapf(err, "failed to invite user %s", inviteeID)
+			}
+		}
+		return summary, nil
+	}
+
+	return fmt.Sprintf("did not run the action of checklist item **%s**", title), errors.Errorf("unknown action type %s", action.Type)
+}
+
+// renderActionMessage executes tmpl, the message template of a post_message action, with data.
+func renderActionMessage(tmpl string, data actionMessageData) (string, error) {
+	t, err := template.New("message").Parse(tmpl)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse message template")
+	}
+
+	var out bytes.Buffer
+	if err = t.Execute(&out, data); err != nil {
+		return "", errors.Wrap(err, "failed to execute message template")
+	}
+
+	return out.String(), nil
+}
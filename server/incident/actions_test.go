@@ -0,0 +1,22 @@
+package incident
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderActionMessage(t *testing.T) {
+	data := actionMessageData{
+		IncidentName: "Checkout errors",
+		Severity:     SeverityCritical,
+		ItemTitle:    "Fail over the database",
+	}
+
+	message, err := renderActionMessage("{{.ItemTitle}} is done for **{{.IncidentName}}** ({{.Severity}}).", data)
+	require.NoError(t, err)
+	require.Equal(t, "Fail over the database is done for **Checkout errors** (SEV1).", message)
+
+	_, err = renderActionMessage("{{.Owner}}", data)
+	require.Error(t, err)
+}
@@ -0,0 +1,71 @@
+package incident
+
+import (
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/playbook"
+)
+
+// isAtLeastAsSevere returns true if severity is minSeverity or a more severe level. Every incident
+// is at least as severe as a blank minSeverity, and an incident with a blank severity is not
+// severe enough for any other one.
+func isAtLeastAsSevere(severity, minSeverity string) bool {
+	if minSeverity == "" {
+		return true
+	}
+
+	for _, level := range Severities {
+		if level == severity {
+			return true
+		}
+		if level == minSeverity {
+			return false
+		}
+	}
+
+	return false
+}
+
+// broadcastTargetMatches returns true if an update of theIncident passes the filter of target.
+// statusChanged tells whether the update changes the status of the incident.
+func broadcastTargetMatches(target playbook.BroadcastTarget, theIncident *Incident, statusChanged bool) bool {
+	if target.Filter == playbook.BroadcastFilterStatusChanges && !statusChanged {
+		return false
+	}
+
+	return isAtLeastAsSevere(theIncident.Severity, target.MinSeverity)
+}
+
+// broadcastChannelIDs returns the IDs of the channels an update of theIncident is broadcast to,
+// without duplicates: firstChannelID, which receives every update if set, and the channels of the
+// broadcast targets whose filter the update passes.
+func broadcastChannelIDs(theIncident *Incident, firstChannelID string, statusChanged bool) []string {
+	var channelIDs []string
+	seen := map[string]bool{"": true}
+	add := func(channelID string) {
+		if !seen[channelID] {
+			seen[channelID] = true
+			channelIDs = append(channelIDs, channelID)
+		}
+	}
+
+	add(firstChannelID)
+	for _, target := range theIncident.BroadcastTargets {
+		if broadcastTargetMatches(target, theIncident, statusChanged) {
+			add(target.ChannelID)
+		}
+	}
+
+	return channelIDs
+}
+
+// statusUpdateChannelIDs returns the IDs of the channels a status update of theIncident is
+// broadcast to: its broadcast channel and the matching broadcast targets.
+func statusUpdateChannelIDs(theIncident *Incident, statusChanged bool) []string {
+	return broadcastChannelIDs(theIncident, theIncident.BroadcastChannelID, statusChanged)
+}
+
+// announcementChannelIDs returns the IDs of the channels the creation of theIncident is announced
+// in: its announcement channel and the matching broadcast targets. The creation counts as a status
+// change.
+func announcementChannelIDs(theIncident *Incident) []string {
+	return broadcastChannelIDs(theIncident, theIncident.AnnouncementChannelID, true)
+}
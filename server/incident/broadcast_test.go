@@ -0,0 +1,43 @@
+package incident
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/playbook"
+)
+
+func TestBroadcastChannelIDs(t *testing.T) {
+	theIncident := &Incident{
+		BroadcastChannelID:    "broadcast",
+		AnnouncementChannelID: "announcement",
+		Severity:              SeverityHigh,
+		BroadcastTargets: []playbook.BroadcastTarget{
+			{ChannelID: "engineering", Filter: playbook.BroadcastFilterAll},
+			{ChannelID: "support", Filter: playbook.BroadcastFilterStatusChanges},
+			{ChannelID: "executives", Filter: playbook.BroadcastFilterAll, MinSeverity: SeverityCritical},
+			{ChannelID: "broadcast", Filter: playbook.BroadcastFilterAll, MinSeverity: SeverityMedium},
+		},
+	}
+
+	require.Equal(t, []string{"broadcast", "engineering"}, statusUpdateChannelIDs(theIncident, false))
+	require.Equal(t, []string{"broadcast", "engineering", "support"}, statusUpdateChannelIDs(theIncident, true))
+	require.Equal(t, []string{"announcement", "engineering", "support", "broadcast"}, announcementChannelIDs(theIncident))
+
+	theIncident.Severity = SeverityCritical
+	require.Equal(t, []string{"broadcast", "engineering", "executives"}, statusUpdateChannelIDs(theIncident, false))
+
+	theIncident.Severity = ""
+	theIncident.BroadcastChannelID = ""
+	require.Equal(t, []string{"engineering"}, statusUpdateChannelIDs(theIncident, false))
+}
+
+func TestIsAtLeastAsSevere(t *testing.T) {
+	require.True(t, isAtLeastAsSevere(SeverityLow, ""))
+	require.True(t, isAtLeastAsSevere("", ""))
+	require.True(t, isAtLeastAsSevere(SeverityCritical, SeverityHigh))
+	require.True(t, isAtLeastAsSevere(SeverityHigh, SeverityHigh))
+	require.False(t, isAtLeastAsSevere(SeverityMedium, SeverityHigh))
+	require.False(t, isAtLeastAsSevere("", SeverityLow))
+}
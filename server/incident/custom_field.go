@@ -0,0 +1,110 @@
+package incident
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/playbook"
+	"github.com/mattermost/mattermost-server/v5/model"
+)
+
+// DialogFieldCustomFieldPrefix prefixes the key of a custom field in the name of its dialog
+// element, used in OpenCreateIncidentDialog and UpdateIncidentDialog.
+const DialogFieldCustomFieldPrefix = "custom_field_"
+
+// Limits of the interactive dialog elements, enforced by the server when opening a dialog.
+const (
+	dialogDisplayNameMaxLength = 24
+	dialogHelpTextMaxLength    = 150
+	dialogTextMaxLength        = 150
+)
+
+// CustomFieldDialogElementName returns the name of the dialog element of the custom field key.
+// The creation dialog lists the fields of every playbook, so it names them after their playbook
+// too: playbookID is blank in the other dialogs.
+func CustomFieldDialogElementName(playbookID, key string) string {
+	if playbookID == "" {
+		return DialogFieldCustomFieldPrefix + key
+	}
+	return DialogFieldCustomFieldPrefix + playbookID + "_" + key
+}
+
+// customFieldDialogElement returns the dialog element setting the value of field, of the playbook
+// playbookID, defaulting to values. Dialogs have no multiselect element, so multiselect fields
+// take comma-separated options.
+func customFieldDialogElement(playbookID string, field playbook.CustomField, values []string, optional bool) model.DialogElement {
+	element := model.DialogElement{
+		DisplayName: truncateRunes(field.Name, dialogDisplayNameMaxLength),
+		Name:        CustomFieldDialogElementName(playbookID, field.Key),
+		HelpText:    field.Description,
+		Optional:    optional,
+	}
+
+	switch field.Type {
+	case playbook.CustomFieldTypeSelect:
+		element.Type = "select"
+		for _, option := range field.Options {
+			element.Options = append(element.Options, &model.PostActionOptions{Text: option, Value: option})
+		}
+	case playbook.CustomFieldTypeUser:
+		element.Type = "select"
+		element.DataSource = "users"
+	case playbook.CustomFieldTypeMultiselect:
+		element.Type = "text"
+		element.MaxLength = dialogTextMaxLength
+		element.HelpText = strings.TrimSpace(field.Description + " Comma-separated, among: " + strings.Join(field.Options, ", "))
+	case playbook.CustomFieldTypeNumber:
+		element.Type = "text"
+		element.SubType = "number"
+	default:
+		element.Type = "text"
+		element.MaxLength = dialogTextMaxLength
+	}
+	element.HelpText = truncateRunes(element.HelpText, dialogHelpTextMaxLength)
+
+	if field.IsMultiValued() {
+		element.Default = strings.Join(values, ", ")
+	} else if len(values) > 0 {
+		element.Default = values[0]
+	}
+
+	return element
+}
+
+// CustomFieldValuesFromDialog returns the values of fields, of the playbook playbookID, submitted
+// in a dialog, indexed by key. Fields left blank have no value.
+func CustomFieldValuesFromDialog(playbookID string, fields []playbook.CustomField, submission map[string]interface{}) map[string][]string {
+	values := map[string][]string{}
+	for _, field := range fields {
+		var raw string
+		switch value := submission[CustomFieldDialogElementName(playbookID, field.Key)].(type) {
+		case string:
+			raw = strings.TrimSpace(value)
+		case float64:
+			raw = strconv.FormatFloat(value, 'f', -1, 64)
+		}
+		if raw == "" {
+			continue
+		}
+
+		if !field.IsMultiValued() {
+			values[field.Key] = []string{raw}
+			continue
+		}
+		for _, option := range strings.Split(raw, ",") {
+			if option = strings.TrimSpace(option); option != "" {
+				values[field.Key] = append(values[field.Key], option)
+			}
+		}
+	}
+
+	return values
+}
+
+func truncateRunes(s string, length int) string {
+	runes := []rune(s)
+	if len(runes) <= length {
+		return s
+	}
+	return string(runes[:length])
+}
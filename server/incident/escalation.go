@@ -0,0 +1,295 @@
+package incident
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/playbook"
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/timeutils"
+)
+
+// EscalationPrefix prefixes the key of the job taking the next escalation step of an incident
+// that was not acknowledged.
+const EscalationPrefix = "escalation_"
+
+// escalationKey returns the key of the job taking the escalation step of incidentID at index
+// level. The first step uses the bare incident ID. The following steps get a key of their own, as
+// a job cannot reschedule itself under its own key.
+func escalationKey(incidentID string, level int) string {
+	if level == 0 {
+		return EscalationPrefix + incidentID
+	}
+
+	return fmt.Sprintf("%s%s_%d", EscalationPrefix, incidentID, level)
+}
+
+// cancelEscalation cancels the job taking the next escalation step of theIncident, if any.
+func (s *ServiceImpl) cancelEscalation(theIncident *Incident) {
+	s.scheduler.Cancel(escalationKey(theIncident.ID, theIncident.EscalationLevel))
+
+	// Steps used to be scheduled under the bare incident ID.
+	if theIncident.EscalationLevel != 0 {
+		s.scheduler.Cancel(escalationKey(theIncident.ID, 0))
+	}
+}
+
+// requestAcknowledgment asks the owner of theIncident by DM to acknowledge it, and schedules the
+// first escalation step for when the acknowledgment timeout expires.
+func (s *ServiceImpl) requestAcknowledgment(theIncident *Incident) error {
+	timeout := time.Duration(theIncident.AcknowledgeTimeoutSeconds) * time.Second
+
+	attachment, err := s.acknowledgmentAttachment(theIncident,
+		"You own the incident **%s**. Please acknowledge it within %s.",
+		theIncident.Name, timeutils.DurationString(time.Time{}, time.Time{}.Add(timeout)))
+	if err != nil {
+		return err
+	}
+
+	if err = s.poster.DMWithAttachments(theIncident.OwnerUserID, attachment); err != nil {
+		return errors.Wrapf(err, "failed to ask user %s to acknowledge the incident", theIncident.OwnerUserID)
+	}
+
+	if len(theIncident.EscalationSteps) == 0 {
+		return nil
+	}
+
+	if err = s.SetReminder(escalationKey(theIncident.ID, theIncident.EscalationLevel), timeout); err != nil {
+		return errors.Wrap(err, "failed to schedule the escalation of the incident")
+	}
+
+	return nil
+}
+
+// acknowledgmentAttachment returns an attachment with the given text, followed by a link to the
+// incident channel, and a button to acknowledge theIncident.
+func (s *ServiceImpl) acknowledgmentAttachment(theIncident *Incident, format string, args ...interface{}) (*model.SlackAttachment, error) {
+	channelURL, _, err := s.incidentURLs(theIncident)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the URL of incident id: %s", theIncident.ID)
+	}
+
+	return &model.SlackAttachment{
+		Text: fmt.Sprintf(format, args...) + fmt.Sprintf(" [Go to the incident channel](%s).", channelURL),
+		Actions: []*model.PostAction{
+			{
+				Type: "button",
+				Name: "Acknowledge",
+				Integration: &model.PostActionIntegration{
+					URL: fmt.Sprintf("/plugins/%s/api/v0/incidents/%s/acknowledge/button",
+						s.configService.GetManifest().Id,
+						theIncident.ID),
+				},
+			},
+		},
+	}, nil
+}
+
+// AcknowledgeIncident processes a request from userID to acknowledge incidentID, which stops
+// its escalation. Acknowledging an incident that was already acknowledged is a no-op.
+func (s *ServiceImpl) AcknowledgeIncident(incidentID, userID string) error {
+	incidentToModify, err := s.store.GetIncident(incidentID)
+	if err != nil {
+		return err
+	}
+
+	if incidentToModify.AcknowledgedAt != 0 {
+		return nil
+	}
+
+	s.cancelEscalation(incidentToModify)
+
+	incidentToModify.AcknowledgedAt = model.GetMillis()
+	if err = s.store.UpdateIncident(incidentToModify); err != nil {
+		return errors.Wrapf(err, "failed to update incident")
+	}
+
+	post, err := s.modificationMessage(userID, incidentToModify.ChannelID, "acknowledged the incident.")
+	if err != nil {
+		return err
+	}
+
+	event := &TimelineEvent{
+		IncidentID:    incidentID,
+		CreateAt:      post.CreateAt,
+		EventAt:       post.CreateAt,
+		EventType:     OwnerAcknowledged,
+		PostID:        post.Id,
+		SubjectUserID: userID,
+	}
+
+	if err = s.createTimelineEvent(incidentToModify, event); err != nil {
+		return errors.Wrap(err, "failed to create timeline event")
+	}
+
+	return s.sendIncidentToClient(incidentID)
+}
+
+// handleEscalation takes the next step of the escalation chain of the incident, unless it was
+// acknowledged or is over: the users of the step who can view the incident's team are added to
+// the incident channel and asked to acknowledge the incident, and the owner is reassigned if the
+// step says so. The step after it, if any, is scheduled for when the acknowledgment timeout
+// expires again. key is the key of the job, built by escalationKey, without its prefix.
+func (s *ServiceImpl) handleEscalation(key string) {
+	// The level is 0 for the bare incident ID.
+	incidentID, level := splitNumberedKey(key)
+
+	incidentToModify, err := s.store.GetIncident(incidentID)
+	if err != nil {
+		s.logger.Errorf(errors.Wrapf(err, "handleEscalation failed to get incident id: %s", incidentID).Error())
+		return
+	}
+
+	if incidentToModify.AcknowledgedAt != 0 || !incidentToModify.IsActive() ||
+		incidentToModify.EscalationLevel >= len(incidentToModify.EscalationSteps) {
+		return
+	}
+
+	// A job for a step that was already taken, or that is not the next one, is stale. This holds for
+	// the bare incident ID too, used by the first step and by the jobs scheduled before steps had
+	// keys of their own.
+	if level != incidentToModify.EscalationLevel {
+		return
+	}
+
+	step := incidentToModify.EscalationSteps[incidentToModify.EscalationLevel]
+	incidentToModify.EscalationLevel++
+	if err = s.store.UpdateIncident(incidentToModify); err != nil {
+		s.logger.Errorf(errors.Wrapf(err, "failed to update incident id: %s", incidentID).Error())
+		return
+	}
+
+	timeout := time.Duration(incidentToModify.AcknowledgeTimeoutSeconds) * time.Second
+	timeoutString := timeutils.DurationString(time.Time{}, time.Time{}.Add(timeout))
+	attachment, err := s.acknowledgmentAttachment(incidentToModify,
+		"The incident **%s** was escalated to you, as it was not acknowledged within %s. Please acknowledge it.",
+		incidentToModify.Name, timeoutString)
+	if err != nil {
+		s.logger.Errorf(errors.Wrap(err, "handleEscalation failed to create the acknowledgment request").Error())
+		return
+	}
+
+	botUserID := s.configService.GetConfiguration().BotUserID
+	userIDs := s.escalationUserIDs(incidentToModify, step)
+	var usernames []string
+	for _, userID := range userIDs {
+		user, userErr := s.pluginAPI.User.Get(userID)
+		if userErr != nil {
+			s.pluginAPI.Log.Warn("failed to get user to escalate the incident to", "user_id", userID, "error", userErr.Error())
+			continue
+		}
+
+		if _, err = s.pluginAPI.Channel.AddUser(incidentToModify.ChannelID, userID, botUserID); err != nil {
+			s.pluginAPI.Log.Warn("failed to add user to the incident channel", "user_id", userID, "error", err.Error())
+		}
+
+		if err = s.poster.DMWithAttachments(userID, attachment); err != nil {
+			s.pluginAPI.Log.Warn("failed to ask user to acknowledge the incident", "user_id", userID, "error", err.Error())
+			continue
+		}
+
+		usernames = append(usernames, "@"+user.Username)
+	}
+
+	notified := "nobody could be notified"
+	if len(usernames) > 0 {
+		notified = "notified " + strings.Join(usernames, ", ")
+	}
+
+	post, err := s.poster.PostMessage(incidentToModify.ChannelID,
+		"This incident was not acknowledged within %s. Escalation step %d of %d: %s.",
+		timeoutString, incidentToModify.EscalationLevel, len(incidentToModify.EscalationSteps), notified)
+	if err != nil {
+		s.logger.Errorf(errors.Wrap(err, "handleEscalation failed to post to the incident channel").Error())
+		return
+	}
+
+	event := &TimelineEvent{
+		IncidentID:    incidentID,
+		CreateAt:      post.CreateAt,
+		EventAt:       post.CreateAt,
+		EventType:     IncidentEscalated,
+		Summary:       fmt.Sprintf("Step %d: %s", incidentToModify.EscalationLevel, notified),
+		PostID:        post.Id,
+		SubjectUserID: botUserID,
+	}
+
+	if err = s.createTimelineEvent(incidentToModify, event); err != nil {
+		s.logger.Errorf(errors.Wrap(err, "failed to create timeline event").Error())
+	}
+
+	// The new owner must be one of the users the incident could be escalated to.
+	if step.ReassignOwner && len(userIDs) > 0 && userIDs[0] == step.UserIDs[0] {
+		if err = s.ChangeOwner(incidentID, botUserID, step.UserIDs[0]); err != nil {
+			s.logger.Errorf(errors.Wrapf(err, "failed to reassign the owner of incident id: %s", incidentID).Error())
+		}
+	} else if err = s.sendIncidentToClient(incidentID); err != nil {
+		s.logger.Errorf(errors.Wrapf(err, "failed to send incident id: %s to the client", incidentID).Error())
+	}
+
+	if incidentToModify.EscalationLevel >= len(incidentToModify.EscalationSteps) {
+		return
+	}
+
+	if err = s.SetReminder(escalationKey(incidentID, incidentToModify.EscalationLevel), timeout); err != nil {
+		s.logger.Errorf(errors.Wrap(err, "failed to schedule the next escalation step").Error())
+	}
+}
+
+// escalationUserIDs returns the IDs of the users of step, followed by those of the members of its
+// groups, without duplicates. Users who are not members of the team of theIncident, and groups
+// that do not allow references, are left out.
+func (s *ServiceImpl) escalationUserIDs(theIncident *Incident, step playbook.EscalationStep) []string {
+	seen := map[string]bool{}
+	var userIDs []string
+	add := func(userID string) {
+		if seen[userID] {
+			return
+		}
+		seen[userID] = true
+
+		if _, err := s.pluginAPI.Team.GetMember(theIncident.TeamID, userID); err != nil {
+			s.pluginAPI.Log.Warn("user is not a member of the incident's team, not escalating to them", "team_id", theIncident.TeamID, "user_id", userID)
+			return
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	for _, userID := range step.UserIDs {
+		add(userID)
+	}
+
+	perPage := 1000
+	for _, groupID := range step.GroupIDs {
+		group, err := s.pluginAPI.Group.Get(groupID)
+		if err != nil {
+			s.pluginAPI.Log.Warn("failed to query group", "group_id", groupID)
+			continue
+		}
+
+		if !group.AllowReference {
+			s.pluginAPI.Log.Warn("group does not allow references, not escalating to it", "group_id", groupID)
+			continue
+		}
+
+		for page := 0; ; page++ {
+			users, err := s.pluginAPI.Group.GetMemberUsers(groupID, page, perPage)
+			if err != nil {
+				s.pluginAPI.Log.Warn("failed to query group", "group_id", groupID, "err", err)
+				break
+			}
+			for _, user := range users {
+				add(user.Id)
+			}
+
+			if len(users) < perPage {
+				break
+			}
+		}
+	}
+
+	return userIDs
+}
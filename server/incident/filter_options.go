@@ -45,6 +45,22 @@ type FilterOptions struct {
 	// PlaybookID filters incidents that are derived from this playbook id.
 	// Defaults to blank (no filter).
 	PlaybookID string `url:"playbook_id,omitempty"`
+
+	// Severity filters incidents with this severity level. Defaults to blank (no filter).
+	Severity string `url:"severity,omitempty"`
+
+	// CustomFields filters incidents by the values of their custom fields, indexed by field key:
+	// an incident matches if, for every key, the field has the given value among its values.
+	// Defaults to nil (no filter).
+	CustomFields map[string]string
+
+	// Role filters incidents in which the role with this key is assigned to a user. Defaults to
+	// blank (no filter).
+	Role string `url:"role,omitempty"`
+
+	// RoleUserID, together with Role, filters incidents in which the role is assigned to this
+	// user. Defaults to blank (any user).
+	RoleUserID string `url:"role_user_id,omitempty"`
 }
 
 const (
@@ -55,6 +71,7 @@ const (
 	SortByTeamID      = "team_id"
 	SortByEndAt       = "end_at"
 	SortByStatus      = "status"
+	SortBySeverity    = "severity"
 
 	DirectionAsc  = "asc"
 	DirectionDesc = "desc"
@@ -67,7 +84,8 @@ func IsValidSortBy(sortBy string) bool {
 		SortByName,
 		SortByOwnerUserID,
 		SortByTeamID,
-		SortByEndAt:
+		SortByEndAt,
+		SortBySeverity:
 		return true
 	}
 
@@ -103,6 +121,8 @@ func ValidateOptions(options *FilterOptions) error {
 		options.Sort = "EndAt"
 	case SortByStatus:
 		options.Sort = "CurrentStatus"
+	case SortBySeverity:
+		options.Sort = "Severity"
 	default:
 		return errors.New("bad parameter 'sort'")
 	}
@@ -125,5 +145,24 @@ func ValidateOptions(options *FilterOptions) error {
 		return errors.New("bad parameter 'member_id': must be 26 characters or blank")
 	}
 
+	if !IsValidSeverity(options.Severity) {
+		return errors.New("bad parameter 'severity': must be a valid severity level or blank")
+	}
+
+	for key := range options.CustomFields {
+		if key == "" {
+			return errors.New("bad parameter 'custom_field': the field key must not be blank")
+		}
+	}
+
+	if options.RoleUserID != "" {
+		if options.Role == "" {
+			return errors.New("bad parameter 'role_user_id': requires 'role'")
+		}
+		if !model.IsValidId(options.RoleUserID) {
+			return errors.New("bad parameter 'role_user_id': must be 26 characters or blank")
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,89 @@
+package incident
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/permissions"
+)
+
+// FollowIncident subscribes userID to the status updates of incidentID, which are sent by DM.
+// Following an incident twice does nothing.
+func (s *ServiceImpl) FollowIncident(incidentID, userID string) error {
+	if err := s.store.Follow(incidentID, userID); err != nil {
+		return errors.Wrapf(err, "failed to follow incident %s", incidentID)
+	}
+
+	return s.sendIncidentToClient(incidentID)
+}
+
+// UnfollowIncident unsubscribes userID from the status updates of incidentID.
+func (s *ServiceImpl) UnfollowIncident(incidentID, userID string) error {
+	if err := s.store.Unfollow(incidentID, userID); err != nil {
+		return errors.Wrapf(err, "failed to unfollow incident %s", incidentID)
+	}
+
+	return s.sendIncidentToClient(incidentID)
+}
+
+// addAutoFollowers subscribes the users of followerIDs who can view theIncident to its status
+// updates. Failures are logged, so that a user who cannot follow the incident does not stop the
+// others.
+func (s *ServiceImpl) addAutoFollowers(theIncident *Incident, followerIDs []string) {
+	for _, followerID := range followerIDs {
+		if err := permissions.ViewIncidentFromChannelID(followerID, theIncident.ChannelID, s.pluginAPI); err != nil {
+			s.pluginAPI.Log.Warn("auto follower cannot view the incident", "incident_id", theIncident.ID, "user_id", followerID)
+			continue
+		}
+
+		if err := s.store.Follow(theIncident.ID, followerID); err != nil {
+			s.pluginAPI.Log.Warn("failed to add auto follower", "incident_id", theIncident.ID, "user_id", followerID, "error", err.Error())
+			continue
+		}
+		theIncident.FollowerIDs = append(theIncident.FollowerIDs, followerID)
+	}
+}
+
+// notifyFollowers sends statusUpdate, posted by authorID, to the followers of theIncident by DM.
+// Followers who cannot view the incident anymore, and the author, are skipped.
+func (s *ServiceImpl) notifyFollowers(theIncident *Incident, authorID, statusUpdate string) {
+	if len(theIncident.FollowerIDs) == 0 {
+		return
+	}
+
+	author, err := s.pluginAPI.User.Get(authorID)
+	if err != nil {
+		s.pluginAPI.Log.Warn("failed to get the author of the status update", "user_id", authorID, "error", err.Error())
+		return
+	}
+
+	incidentLink := theIncident.Name
+	if _, detailsURL, urlErr := s.incidentURLs(theIncident); urlErr == nil {
+		incidentLink = fmt.Sprintf("[%s](%s)", theIncident.Name, detailsURL)
+	}
+
+	title := "Incident Update"
+	if !theIncident.IsActive() {
+		title = fmt.Sprintf("Incident %s", theIncident.CurrentStatus)
+	}
+
+	message := fmt.Sprintf("#### %s: %s\n", title, incidentLink)
+	message += fmt.Sprintf("By @%s | Status: %s\n", author.Username, theIncident.CurrentStatus)
+	message += "***\n"
+	message += statusUpdate
+
+	for _, followerID := range theIncident.FollowerIDs {
+		if followerID == authorID {
+			continue
+		}
+
+		if err := permissions.ViewIncidentFromChannelID(followerID, theIncident.ChannelID, s.pluginAPI); err != nil {
+			continue
+		}
+
+		if err := s.poster.DM(followerID, "%s", message); err != nil {
+			s.pluginAPI.Log.Warn("failed to send the status update to a follower", "incident_id", theIncident.ID, "user_id", followerID, "error", err.Error())
+		}
+	}
+}
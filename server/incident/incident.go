@@ -18,46 +18,116 @@ const (
 	StatusActive   = "Active"
 	StatusResolved = "Resolved"
 	StatusArchived = "Archived"
+
+	// StatusMerged is the status of an incident merged into another one. Merged incidents are
+	// hidden from GetIncidents unless their status is asked for.
+	StatusMerged = "Merged"
+)
+
+// Severity levels, from most to least severe. An incident with a blank severity has not been
+// classified yet.
+const (
+	SeverityCritical = "SEV1"
+	SeverityHigh     = "SEV2"
+	SeverityMedium   = "SEV3"
+	SeverityLow      = "SEV4"
 )
 
+// Severities lists the valid severity levels, from most to least severe.
+var Severities = []string{SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow}
+
+// severityNames holds the human readable name for each severity level.
+var severityNames = map[string]string{
+	SeverityCritical: "Critical",
+	SeverityHigh:     "High",
+	SeverityMedium:   "Medium",
+	SeverityLow:      "Low",
+}
+
+// IsValidSeverity returns true if severity is one of the known severity levels, or blank.
+func IsValidSeverity(severity string) bool {
+	if severity == "" {
+		return true
+	}
+
+	_, ok := severityNames[severity]
+	return ok
+}
+
+// SeverityDisplayName returns the severity level together with its human readable name,
+// e.g. "SEV1 - Critical". Returns "None" if severity is blank.
+func SeverityDisplayName(severity string) string {
+	if severity == "" {
+		return "None"
+	}
+
+	name, ok := severityNames[severity]
+	if !ok {
+		return severity
+	}
+
+	return severity + " - " + name
+}
+
 // Incident holds the detailed information of an incident.
 //
 // NOTE: when adding a column to the db, search for "When adding an Incident column" to see where
 // that column needs to be added in the sqlstore code.
 type Incident struct {
-	ID                                   string               `json:"id"`
-	Name                                 string               `json:"name"` // Retrieved from incident channel
-	Description                          string               `json:"description"`
-	OwnerUserID                          string               `json:"owner_user_id"`
-	ReporterUserID                       string               `json:"reporter_user_id"`
-	TeamID                               string               `json:"team_id"`
-	ChannelID                            string               `json:"channel_id"`
-	CreateAt                             int64                `json:"create_at"` // Retrieved from incident channel
-	EndAt                                int64                `json:"end_at"`
-	DeleteAt                             int64                `json:"delete_at"` // Retrieved from incidet channel
-	ActiveStage                          int                  `json:"active_stage"`
-	ActiveStageTitle                     string               `json:"active_stage_title"`
-	PostID                               string               `json:"post_id"`
-	PlaybookID                           string               `json:"playbook_id"`
-	Checklists                           []playbook.Checklist `json:"checklists"`
-	StatusPosts                          []StatusPost         `json:"status_posts"`
-	CurrentStatus                        string               `json:"current_status"`
-	ReminderPostID                       string               `json:"reminder_post_id"`
-	PreviousReminder                     time.Duration        `json:"previous_reminder"`
-	BroadcastChannelID                   string               `json:"broadcast_channel_id"`
-	ReminderMessageTemplate              string               `json:"reminder_message_template"`
-	InvitedUserIDs                       []string             `json:"invited_user_ids"`
-	InvitedGroupIDs                      []string             `json:"invited_group_ids"`
-	TimelineEvents                       []TimelineEvent      `json:"timeline_events"`
-	DefaultOwnerID                       string               `json:"default_owner_id"`
-	AnnouncementChannelID                string               `json:"announcement_channel_id"`
-	WebhookOnCreationURL                 string               `json:"webhook_on_creation_url"`
-	WebhookOnStatusUpdateURL             string               `json:"webhook_on_status_update_url"`
-	Retrospective                        string               `json:"retrospective"`
-	RetrospectivePublishedAt             int64                `json:"retrospective_published_at"` // The last time a retrospective was published. 0 if never published.
-	RetrospectiveWasCanceled             bool                 `json:"retrospective_was_canceled"`
-	RetrospectiveReminderIntervalSeconds int64                `json:"retrospective_reminder_interval_seconds"`
-	MessageOnJoin                        string               `json:"message_on_join"`
+	ID                                   string                         `json:"id"`
+	Name                                 string                         `json:"name"` // Retrieved from incident channel
+	Description                          string                         `json:"description"`
+	OwnerUserID                          string                         `json:"owner_user_id"`
+	ReporterUserID                       string                         `json:"reporter_user_id"`
+	TeamID                               string                         `json:"team_id"`
+	ChannelID                            string                         `json:"channel_id"`
+	CreateAt                             int64                          `json:"create_at"` // Retrieved from incident channel
+	EndAt                                int64                          `json:"end_at"`
+	DeleteAt                             int64                          `json:"delete_at"` // Retrieved from incidet channel
+	ActiveStage                          int                            `json:"active_stage"`
+	ActiveStageTitle                     string                         `json:"active_stage_title"`
+	PostID                               string                         `json:"post_id"`
+	PlaybookID                           string                         `json:"playbook_id"`
+	PlaybookRevision                     int                            `json:"playbook_revision"` // Revision of the playbook the incident was started from
+	Checklists                           []playbook.Checklist           `json:"checklists"`
+	StatusPosts                          []StatusPost                   `json:"status_posts"`
+	CurrentStatus                        string                         `json:"current_status"`
+	ReminderPostID                       string                         `json:"reminder_post_id"`
+	PreviousReminder                     time.Duration                  `json:"previous_reminder"`
+	BroadcastChannelID                   string                         `json:"broadcast_channel_id"`
+	ReminderMessageTemplate              string                         `json:"reminder_message_template"`
+	InvitedUserIDs                       []string                       `json:"invited_user_ids"`
+	InvitedGroupIDs                      []string                       `json:"invited_group_ids"`
+	TimelineEvents                       []TimelineEvent                `json:"timeline_events"`
+	DefaultOwnerID                       string                         `json:"default_owner_id"`
+	AnnouncementChannelID                string                         `json:"announcement_channel_id"`
+	WebhookOnCreationURL                 string                         `json:"webhook_on_creation_url"`
+	WebhookOnStatusUpdateURL             string                         `json:"webhook_on_status_update_url"`
+	Retrospective                        string                         `json:"retrospective"`
+	RetrospectivePublishedAt             int64                          `json:"retrospective_published_at"` // The last time a retrospective was published. 0 if never published.
+	RetrospectiveWasCanceled             bool                           `json:"retrospective_was_canceled"`
+	RetrospectiveReminderIntervalSeconds int64                          `json:"retrospective_reminder_interval_seconds"`
+	MessageOnJoin                        string                         `json:"message_on_join"`
+	Severity                             string                         `json:"severity"`
+	WebhookSecret                        string                         `json:"-"`                           // Copied from the playbook; used to sign outgoing webhooks.
+	WebhookSubscriptions                 []playbook.WebhookSubscription `json:"-"`                           // Copied from the playbook; may carry credentials in their URLs.
+	AlertFingerprint                     string                         `json:"alert_fingerprint"`           // Set if the incident was started by an alert sent to the playbook's inbound webhook.
+	CustomFields                         []playbook.CustomField         `json:"custom_fields"`               // Copied from the playbook.
+	CustomFieldValues                    map[string][]string            `json:"custom_field_values"`         // Values of CustomFields, indexed by key. Single-valued fields have one value.
+	Roles                                []playbook.Role                `json:"roles"`                       // Copied from the playbook.
+	RoleAssignments                      map[string][]string            `json:"role_assignments"`            // IDs of the users holding each of Roles, indexed by role key.
+	AcknowledgeTimeoutSeconds            int64                          `json:"acknowledge_timeout_seconds"` // Copied from the playbook. 0 if the incident does not need to be acknowledged.
+	EscalationSteps                      []playbook.EscalationStep      `json:"escalation_steps"`            // Copied from the playbook.
+	AcknowledgedAt                       int64                          `json:"acknowledged_at"`             // 0 if the incident was not acknowledged yet.
+	EscalationLevel                      int                            `json:"escalation_level"`            // Number of escalation steps taken so far.
+	Links                                []LinkedIncident               `json:"links"`                       // Retrieved from the links table; not saved with the incident.
+	MergedIntoID                         string                         `json:"merged_into_incident_id"`     // Set if the incident was merged into another one.
+	StatusUpdateTemplate                 string                         `json:"status_update_template"`      // Copied from the playbook.
+	BroadcastTargets                     []playbook.BroadcastTarget     `json:"broadcast_targets"`           // Copied from the playbook.
+	FollowerIDs                          []string                       `json:"follower_ids"`                // Retrieved from the followers table; not saved with the incident.
+	RetrospectiveSections                RetrospectiveSections          `json:"retrospective_sections"`
+	ActionItems                          []ActionItem                   `json:"action_items"`           // Retrieved from the action items table; not saved with the incident.
+	RetrospectiveTemplate                string                         `json:"retrospective_template"` // Copied from the playbook; the draft generated on resolution starts from it.
 }
 
 func (i *Incident) Clone() *Incident {
@@ -72,6 +142,16 @@ func (i *Incident) Clone() *Incident {
 	newIncident.TimelineEvents = append([]TimelineEvent(nil), i.TimelineEvents...)
 	newIncident.InvitedUserIDs = append([]string(nil), i.InvitedUserIDs...)
 	newIncident.InvitedGroupIDs = append([]string(nil), i.InvitedGroupIDs...)
+	newIncident.WebhookSubscriptions = playbook.CloneWebhookSubscriptions(i.WebhookSubscriptions)
+	newIncident.CustomFields = playbook.CloneCustomFields(i.CustomFields)
+	newIncident.CustomFieldValues = playbook.CloneCustomFieldValues(i.CustomFieldValues)
+	newIncident.Roles = playbook.CloneRoles(i.Roles)
+	newIncident.RoleAssignments = cloneRoleAssignments(i.RoleAssignments)
+	newIncident.EscalationSteps = playbook.CloneEscalationSteps(i.EscalationSteps)
+	newIncident.BroadcastTargets = playbook.CloneBroadcastTargets(i.BroadcastTargets)
+	newIncident.Links = append([]LinkedIncident(nil), i.Links...)
+	newIncident.FollowerIDs = append([]string(nil), i.FollowerIDs...)
+	newIncident.ActionItems = append([]ActionItem(nil), i.ActionItems...)
 
 	return &newIncident
 }
@@ -88,6 +168,17 @@ func (i *Incident) MarshalJSON() ([]byte, error) {
 		if cl.Items == nil {
 			old.Checklists[j].Items = []playbook.ChecklistItem{}
 		}
+		for k, item := range cl.Items {
+			if item.PrerequisiteIDs == nil {
+				old.Checklists[j].Items[k].PrerequisiteIDs = []string{}
+			}
+			if item.Condition.Values == nil {
+				old.Checklists[j].Items[k].Condition.Values = []string{}
+			}
+			if item.AutomaticAction.UserIDs == nil {
+				old.Checklists[j].Items[k].AutomaticAction.UserIDs = []string{}
+			}
+		}
 	}
 	if old.StatusPosts == nil {
 		old.StatusPosts = []StatusPost{}
@@ -101,13 +192,126 @@ func (i *Incident) MarshalJSON() ([]byte, error) {
 	if old.TimelineEvents == nil {
 		old.TimelineEvents = []TimelineEvent{}
 	}
+	if old.CustomFields == nil {
+		old.CustomFields = []playbook.CustomField{}
+	}
+	for j, field := range old.CustomFields {
+		if field.Options == nil {
+			old.CustomFields[j].Options = []string{}
+		}
+	}
+	if old.CustomFieldValues == nil {
+		old.CustomFieldValues = map[string][]string{}
+	}
+	if old.Roles == nil {
+		old.Roles = []playbook.Role{}
+	}
+	if old.RoleAssignments == nil {
+		old.RoleAssignments = map[string][]string{}
+	}
+	if old.EscalationSteps == nil {
+		old.EscalationSteps = []playbook.EscalationStep{}
+	}
+	if old.Links == nil {
+		old.Links = []LinkedIncident{}
+	}
+	if old.BroadcastTargets == nil {
+		old.BroadcastTargets = []playbook.BroadcastTarget{}
+	}
+	if old.FollowerIDs == nil {
+		old.FollowerIDs = []string{}
+	}
+	if old.ActionItems == nil {
+		old.ActionItems = []ActionItem{}
+	}
+	for j, step := range old.EscalationSteps {
+		if step.UserIDs == nil {
+			old.EscalationSteps[j].UserIDs = []string{}
+		}
+		if step.GroupIDs == nil {
+			old.EscalationSteps[j].GroupIDs = []string{}
+		}
+	}
 
-	return json.Marshal(old)
+	return json.Marshal(struct {
+		*Alias
+		OverdueItems []OverdueItem `json:"overdue_items"`
+	}{
+		Alias:        old,
+		OverdueItems: i.OverdueItems(model.GetMillis()),
+	})
+}
+
+// ConditionFields returns the attributes of the incident that the conditions on checklist items
+// can check, indexed by field name, including the values of its custom fields.
+func (i *Incident) ConditionFields() map[string][]string {
+	fields := map[string][]string{
+		playbook.ConditionFieldName:        {i.Name},
+		playbook.ConditionFieldDescription: {i.Description},
+		playbook.ConditionFieldSeverity:    {i.Severity},
+	}
+	for _, field := range i.CustomFields {
+		fields[playbook.ConditionFieldCustomPrefix+field.Key] = i.CustomFieldValues[field.Key]
+	}
+
+	return fields
+}
+
+// OverdueItem identifies a checklist item that is past its due date. It is computed when the
+// incident is sent to the client; it is not saved to the db.
+type OverdueItem struct {
+	ChecklistNum int    `json:"checklist_num"`
+	ItemNum      int    `json:"item_num"`
+	Title        string `json:"title"`
+	AssigneeID   string `json:"assignee_id"`
+	AssigneeRole string `json:"assignee_role"`
+	DueAt        int64  `json:"due_at"`
+}
+
+// OverdueItems returns the checklist items that are overdue at now, in checklist order. It
+// returns an empty slice if there are none.
+func (i *Incident) OverdueItems(now int64) []OverdueItem {
+	overdueItems := []OverdueItem{}
+	for checklistNum, checklist := range i.Checklists {
+		for itemNum, item := range checklist.Items {
+			if !item.IsOverdue(now) {
+				continue
+			}
+			overdueItems = append(overdueItems, OverdueItem{
+				ChecklistNum: checklistNum,
+				ItemNum:      itemNum,
+				Title:        item.Title,
+				AssigneeID:   item.AssigneeID,
+				AssigneeRole: item.AssigneeRole,
+				DueAt:        item.DueAt,
+			})
+		}
+	}
+
+	return overdueItems
+}
+
+// NextDueAt returns the earliest due date, later than now, among the checklist items that are not
+// closed yet. It returns 0 if there is none.
+func (i *Incident) NextDueAt(now int64) int64 {
+	var next int64
+	for _, checklist := range i.Checklists {
+		for _, item := range checklist.Items {
+			if item.DueAt <= now || item.State == playbook.ChecklistItemStateClosed {
+				continue
+			}
+			if next == 0 || item.DueAt < next {
+				next = item.DueAt
+			}
+		}
+	}
+
+	return next
 }
 
 func (i *Incident) IsActive() bool {
 	currentStatus := i.CurrentStatus
-	return currentStatus != StatusResolved && currentStatus != StatusArchived
+	return currentStatus != StatusResolved && currentStatus != StatusArchived && currentStatus != StatusMerged
 }
 
 func (i *Incident) ResolvedAt() int64 {
@@ -121,7 +325,8 @@ func (i *Incident) ResolvedAt() int64 {
 		if i.StatusPosts[j].DeleteAt != 0 {
 			continue
 		}
-		if i.StatusPosts[j].Status != StatusResolved && i.StatusPosts[j].Status != StatusArchived {
+		if i.StatusPosts[j].Status != StatusResolved && i.StatusPosts[j].Status != StatusArchived &&
+			i.StatusPosts[j].Status != StatusMerged {
 			break
 		}
 
@@ -152,6 +357,10 @@ type StatusUpdateOptions struct {
 	Description string        `json:"description"`
 	Message     string        `json:"message"`
 	Reminder    time.Duration `json:"reminder"`
+
+	// CustomFieldValues replaces the values of the incident's custom fields, indexed by key.
+	// Nil leaves the values unchanged.
+	CustomFieldValues map[string][]string `json:"custom_field_values"`
 }
 
 // Metadata tracks ancillary metadata about an incident.
@@ -176,8 +385,44 @@ const (
 	UserJoinedLeft         timelineEventType = "user_joined_left"
 	PublishedRetrospective timelineEventType = "published_retrospective"
 	CanceledRetrospective  timelineEventType = "canceled_retrospective"
+	SeverityChanged        timelineEventType = "severity_changed"
+	RoleChanged            timelineEventType = "role_changed"
+	OwnerAcknowledged      timelineEventType = "owner_acknowledged"
+	IncidentEscalated      timelineEventType = "incident_escalated"
+	IncidentMerged         timelineEventType = "incident_merged"
+	ManualEvent            timelineEventType = "manual_event"
 )
 
+// TimelineEventTypes lists the event types a playbook's webhook subscriptions can receive.
+var TimelineEventTypes = []string{
+	string(IncidentCreated),
+	string(TaskStateModified),
+	string(StatusUpdated),
+	string(OwnerChanged),
+	string(AssigneeChanged),
+	string(RanSlashCommand),
+	string(EventFromPost),
+	string(UserJoinedLeft),
+	string(PublishedRetrospective),
+	string(CanceledRetrospective),
+	string(SeverityChanged),
+	string(RoleChanged),
+	string(OwnerAcknowledged),
+	string(IncidentEscalated),
+	string(IncidentMerged),
+	string(ManualEvent),
+}
+
+// IsValidTimelineEventType returns true if eventType is one of TimelineEventTypes.
+func IsValidTimelineEventType(eventType string) bool {
+	for _, t := range TimelineEventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
 type TimelineEvent struct {
 	ID            string            `json:"id"`
 	IncidentID    string            `json:"incident_id"`
@@ -192,6 +437,26 @@ type TimelineEvent struct {
 	CreatorUserID string            `json:"creator_user_id"`
 }
 
+// TimelineEventOptions are the values of a timeline event created, or edited, by a user.
+type TimelineEventOptions struct {
+	EventAt int64  `json:"event_at"` // When creating an event, 0 stands for now.
+	Summary string `json:"summary"`
+	Details string `json:"details"`
+}
+
+// TimelineEventEdit records an edit of a timeline event: who made it, when, and the values the
+// event had before it.
+type TimelineEventEdit struct {
+	ID              string `json:"id"`
+	EventID         string `json:"event_id"`
+	IncidentID      string `json:"incident_id"`
+	EditorUserID    string `json:"editor_user_id"`
+	EditAt          int64  `json:"edit_at"`
+	PreviousEventAt int64  `json:"previous_event_at"`
+	PreviousSummary string `json:"previous_summary"`
+	PreviousDetails string `json:"previous_details"`
+}
+
 // GetIncidentsResults collects the results of the GetIncidents call: the list of Incidents matching
 // the HeaderFilterOptions, and the TotalCount of the matching incidents before paging was applied.
 type GetIncidentsResults struct {
@@ -267,9 +532,40 @@ var ErrIncidentActive = errors.New("incident active")
 // ErrMalformedIncident is used to indicate an incident is not valid
 var ErrMalformedIncident = errors.New("incident active")
 
+// ErrChecklistItemBlocked is used to indicate trying to start or check off a checklist item whose
+// prerequisites are not done yet.
+var ErrChecklistItemBlocked = errors.New("checklist item is blocked by its prerequisites")
+
+// ErrUnknownRole is used to indicate referring to a role that the incident does not have.
+var ErrUnknownRole = errors.New("unknown role")
+
 // ErrDuplicateEntry indicates the db could not make an insert because the entry already existed.
 var ErrDuplicateEntry = errors.New("duplicate entry")
 
+// ErrMalformedLink is used to indicate a link between incidents that is not valid, such as a link
+// making an incident its own ancestor.
+var ErrMalformedLink = errors.New("malformed link")
+
+// ErrInvalidMerge is used to indicate a merge of incidents that is not allowed, such as merging an
+// incident that was already merged.
+var ErrInvalidMerge = errors.New("invalid merge")
+
+// ErrIncidentMerged is used to indicate trying to update an incident that was merged into another
+// incident, such as updating its status.
+var ErrIncidentMerged = errors.New("incident merged")
+
+// ErrMalformedActionItem is used to indicate an action item that is not valid, such as an action
+// item without a title.
+var ErrMalformedActionItem = errors.New("malformed action item")
+
+// ErrMalformedTimelineEvent is used to indicate a timeline event that is not valid, such as an
+// event without a summary.
+var ErrMalformedTimelineEvent = errors.New("malformed timeline event")
+
+// ErrMalformedTimelineExport is used to indicate a timeline export that is not valid, such as an
+// export to an unknown format.
+var ErrMalformedTimelineExport = errors.New("malformed timeline export")
+
 // Service is the incident/service interface.
 type Service interface {
 	// GetIncidents returns filtered incidents and the total count before paging.
@@ -278,6 +574,12 @@ type Service interface {
 	// CreateIncident creates a new incident. userID is the user who initiated the CreateIncident.
 	CreateIncident(incdnt *Incident, playbook *playbook.Playbook, userID string, public bool) (*Incident, error)
 
+	// ImportIncident creates an incident, with its timeline events, status posts and followers,
+	// from an archive exported from another server. The posts of statusPosts must already exist
+	// in the channel of the incident. Unlike CreateIncident, it does not create the channel, post
+	// any message or send any webhook.
+	ImportIncident(incdnt *Incident, timelineEvents []TimelineEvent, statusPosts []StatusPost) (*Incident, error)
+
 	// OpenCreateIncidentDialog opens an interactive dialog to start a new incident.
 	OpenCreateIncidentDialog(teamID, ownerID, triggerID, postID, clientID string, playbooks []playbook.Playbook, isMobileApp bool) error
 
@@ -296,6 +598,24 @@ type Service interface {
 	// RemoveTimelineEvent removes the timeline event (sets the DeleteAt to the current time).
 	RemoveTimelineEvent(incidentID, userID, eventID string) error
 
+	// AddTimelineEvent adds an event that is not tied to a post to the timeline of incidentID, and
+	// returns its ID.
+	AddTimelineEvent(incidentID, userID string, options TimelineEventOptions) (string, error)
+
+	// EditTimelineEvent changes the time, summary and details of the timeline event eventID of
+	// incidentID, and records the previous values.
+	EditTimelineEvent(incidentID, userID, eventID string, options TimelineEventOptions) error
+
+	// GetTimelineEventEdits returns the edits of the timeline event eventID of incidentID, oldest first.
+	GetTimelineEventEdits(incidentID, eventID string) ([]TimelineEventEdit, error)
+
+	// GetTimelineEvents returns a page of the timeline of incidentID, filtered by options.
+	GetTimelineEvents(incidentID string, options TimelineFilterOptions) (*GetTimelineEventsResults, error)
+
+	// ExportTimeline returns the timeline of incidentID in the format of options, restricted to
+	// the events that pass their filters.
+	ExportTimeline(incidentID string, options TimelineExportOptions) ([]byte, error)
+
 	// UpdateStatus updates an incident's status.
 	UpdateStatus(incidentID, userID string, options StatusUpdateOptions) error
 
@@ -309,6 +629,10 @@ type Service interface {
 	// if there is no incident associated with this channel.
 	GetIncidentIDForChannel(channelID string) (string, error)
 
+	// GetIncidentIDForAlert returns the ID of the ongoing incident started by the playbook's
+	// inbound webhook for the alert with the given fingerprint. Returns ErrNotFound if there is none.
+	GetIncidentIDForAlert(playbookID, fingerprint string) (string, error)
+
 	// GetOwners returns all the owners of incidents selected
 	GetOwners(requesterInfo permissions.RequesterInfo, options FilterOptions) ([]OwnerInfo, error)
 
@@ -319,6 +643,43 @@ type Service interface {
 	// to ownerID. Changing to the same ownerID is a no-op.
 	ChangeOwner(incidentID string, userID string, ownerID string) error
 
+	// ChangeSeverity processes a request from userID to change the severity of incidentID
+	// to severity. Changing to the same severity is a no-op.
+	ChangeSeverity(incidentID, userID, severity string) error
+
+	// AssignRole processes a request from userID to assign the role of incidentID with the key
+	// roleKey to the users assigneeIDs, replacing its current users. An empty assigneeIDs
+	// unassigns the role. Assigning the role to its current users is a no-op.
+	AssignRole(incidentID, userID, roleKey string, assigneeIDs []string) error
+
+	// AcknowledgeIncident processes a request from userID to acknowledge incidentID, which stops
+	// its escalation. Acknowledging an incident that was already acknowledged is a no-op.
+	AcknowledgeIncident(incidentID, userID string) error
+
+	// LinkIncidents processes a request from userID to link incidentID to linkedIncidentID, so
+	// that incidentID is <linkType> linkedIncidentID. It returns the ID of the new link.
+	LinkIncidents(incidentID, userID, linkedIncidentID, linkType string) (string, error)
+
+	// UnlinkIncidents processes a request from userID to remove the link with the ID linkID from
+	// incidentID.
+	UnlinkIncidents(incidentID, userID, linkID string) error
+
+	// ResolveChildren processes a request from userID to resolve the children of incidentID that
+	// are still active. It returns the IDs of the resolved incidents.
+	ResolveChildren(incidentID, userID string) ([]string, error)
+
+	// MergeIncident processes a request from userID to merge sourceID into targetID: the timeline
+	// of the source, including its status updates, is copied to the target, its members are added
+	// to the target channel, and its status becomes StatusMerged.
+	MergeIncident(sourceID, userID, targetID string) error
+
+	// FollowIncident subscribes userID to the status updates of incidentID, which are sent by DM.
+	// Following an incident twice does nothing.
+	FollowIncident(incidentID, userID string) error
+
+	// UnfollowIncident unsubscribes userID from the status updates of incidentID.
+	UnfollowIncident(incidentID, userID string) error
+
 	// ModifyCheckedState modifies the state of the specified checklist item
 	// Idempotent, will not perform any actions if the checklist item is already in the specified state
 	ModifyCheckedState(incidentID, userID, newState string, checklistNumber int, itemNumber int) error
@@ -330,6 +691,11 @@ type Service interface {
 	// Idempotent, will not perform any actions if the checklist item is already assigned to assigneeID
 	SetAssignee(incidentID, userID, assigneeID string, checklistNumber, itemNumber int) error
 
+	// SetAssigneeRole assigns the specified checklist item to the role with the key roleKey, or
+	// removes its role if roleKey is blank.
+	// Idempotent, will not perform any actions if the checklist item is already assigned to roleKey
+	SetAssigneeRole(incidentID, userID, roleKey string, checklistNumber, itemNumber int) error
+
 	// RunChecklistItemSlashCommand executes the slash command associated with the specified checklist item.
 	RunChecklistItemSlashCommand(incidentID, userID string, checklistNumber, itemNumber int) (string, error)
 
@@ -387,9 +753,36 @@ type Service interface {
 	// CancelRetrospective cancels the retrospective.
 	CancelRetrospective(incidentID, userID string) error
 
+	// UpdateRetrospectiveSections updates the structured sections of the retrospective of
+	// incidentID.
+	UpdateRetrospectiveSections(incidentID, userID string, sections RetrospectiveSections) error
+
+	// AddActionItem processes a request from userID to add an action item to the retrospective of
+	// incidentID, and returns the ID of the new action item.
+	AddActionItem(incidentID, userID string, item ActionItem) (string, error)
+
+	// UpdateActionItem processes a request from userID to change the title, owner and due date of
+	// the action item of incidentID with the ID of item.
+	UpdateActionItem(incidentID, userID string, item ActionItem) error
+
+	// SetActionItemDone processes a request from userID to complete, or to reopen, the action item
+	// of incidentID with the ID itemID.
+	SetActionItemDone(incidentID, userID, itemID string, done bool) error
+
+	// RemoveActionItem processes a request from userID to remove the action item of incidentID
+	// with the ID itemID.
+	RemoveActionItem(incidentID, userID, itemID string) error
+
+	// GetActionItems returns filtered action items across the incidents the requester can view,
+	// and the total count before paging.
+	GetActionItems(requesterInfo permissions.RequesterInfo, options ActionItemFilterOptions) (*GetActionItemsResults, error)
+
 	// CheckAndSendMessageOnJoin checks if userID has viewed channelID and sends
 	// theIncident.MessageOnJoin if it exists. Returns true if the message was sent.
 	CheckAndSendMessageOnJoin(userID, incidentID, channelID string) bool
+
+	// GetWebhookDeliveries returns the outgoing webhook deliveries of incidentID, with their attempts.
+	GetWebhookDeliveries(incidentID string) ([]WebhookDelivery, error)
 }
 
 // Store defines the methods the ServiceImpl needs from the interfaceStore.
@@ -415,12 +808,26 @@ type Store interface {
 	// UpdateTimelineEvent updates an existing timeline event
 	UpdateTimelineEvent(event *TimelineEvent) error
 
+	// EditTimelineEvent updates event and stores edit, the record of its previous values.
+	EditTimelineEvent(event *TimelineEvent, edit *TimelineEventEdit) error
+
+	// GetTimelineEventEdits returns the edits of the timeline event eventID, oldest first.
+	GetTimelineEventEdits(eventID string) ([]TimelineEventEdit, error)
+
+	// GetTimelineEvents returns a page of the timeline of incidentID, filtered by options.
+	// Removed events are left out.
+	GetTimelineEvents(incidentID string, options TimelineFilterOptions) (*GetTimelineEventsResults, error)
+
 	// GetIncident gets an incident by ID.
 	GetIncident(incidentID string) (*Incident, error)
 
 	// GetIncidentByChannel gets an incident associated with the given channel id.
 	GetIncidentIDForChannel(channelID string) (string, error)
 
+	// GetIncidentIDForAlert gets the most recent incident that is neither resolved nor archived,
+	// started by playbookID for the alert with the given fingerprint.
+	GetIncidentIDForAlert(playbookID, fingerprint string) (string, error)
+
 	// GetAllIncidentMembersCount returns the count of all members of the
 	// incident associated with the given channel id since the beginning of the
 	// incident, excluding bots.
@@ -441,6 +848,54 @@ type Store interface {
 	// SetViewedChannel records that userID has viewed channelID. NOTE: does not check if there is already a
 	// record of that userID/channelID (i.e., will create duplicate rows)
 	SetViewedChannel(userID, channelID string) error
+
+	// CreateWebhookDelivery stores a new outgoing webhook delivery and returns it with its new ID.
+	CreateWebhookDelivery(delivery *WebhookDelivery) (*WebhookDelivery, error)
+
+	// UpdateWebhookDelivery updates the status and retry information of an existing webhook delivery.
+	UpdateWebhookDelivery(delivery *WebhookDelivery) error
+
+	// GetWebhookDelivery gets a webhook delivery by ID, without its attempts.
+	GetWebhookDelivery(deliveryID string) (*WebhookDelivery, error)
+
+	// GetWebhookDeliveries returns the webhook deliveries of incidentID with their attempts,
+	// most recent first.
+	GetWebhookDeliveries(incidentID string) ([]WebhookDelivery, error)
+
+	// CreateWebhookDeliveryAttempt records an attempt to deliver a webhook.
+	CreateWebhookDeliveryAttempt(attempt *WebhookDeliveryAttempt) error
+
+	// CreateLink stores a new link between two incidents and returns it with its new ID. The
+	// links of an incident are retrieved with it by GetIncident.
+	CreateLink(link *Link) (*Link, error)
+
+	// DeleteLink deletes the link with the ID linkID.
+	DeleteLink(linkID string) error
+
+	// AddStatusPosts adds existing status posts to incidentID, without changing its current
+	// status.
+	AddStatusPosts(incidentID string, statusPosts []StatusPost) error
+
+	// Follow subscribes userID to the updates of incidentID. The followers of an incident are
+	// retrieved with it by GetIncident. Following an incident twice does nothing.
+	Follow(incidentID, userID string) error
+
+	// Unfollow unsubscribes userID from the updates of incidentID.
+	Unfollow(incidentID, userID string) error
+
+	// CreateActionItem stores a new action item and returns it with its new ID. The action items
+	// of an incident are retrieved with it by GetIncident.
+	CreateActionItem(item *ActionItem) (*ActionItem, error)
+
+	// UpdateActionItem updates an existing action item.
+	UpdateActionItem(item *ActionItem) error
+
+	// DeleteActionItem deletes the action item with the ID itemID.
+	DeleteActionItem(itemID string) error
+
+	// GetActionItems returns filtered action items across the incidents the requester can view,
+	// and the total count before paging.
+	GetActionItems(requesterInfo permissions.RequesterInfo, options ActionItemFilterOptions) (*GetActionItemsResults, error)
 }
 
 // Telemetry defines the methods that the ServiceImpl needs from the RudderTelemetry.
@@ -470,6 +925,12 @@ type Telemetry interface {
 	// RemoveTimelineEvent tracks userID removing a timeline event.
 	RemoveTimelineEvent(incdnt *Incident, userID string)
 
+	// AddTimelineEvent tracks userID creating a timeline event that is not tied to a post.
+	AddTimelineEvent(incdnt *Incident, userID string)
+
+	// EditTimelineEvent tracks userID editing a timeline event.
+	EditTimelineEvent(incdnt *Incident, userID string)
+
 	// ModifyCheckedState tracks the checking and unchecking of items.
 	ModifyCheckedState(incidentID, userID string, task playbook.ChecklistItem, wasOwner bool)
 
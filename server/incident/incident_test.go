@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/playbook"
 )
 
 func TestIncident_MarshalJSON(t *testing.T) {
@@ -162,3 +164,59 @@ func TestIncident_LastResovedAt(t *testing.T) {
 		})
 	}
 }
+
+func TestIncident_OverdueItems(t *testing.T) {
+	inc := Incident{
+		OwnerUserID: "owner",
+		Checklists: []playbook.Checklist{
+			{
+				Items: []playbook.ChecklistItem{
+					{Title: "no due date"},
+					{Title: "overdue", DueAt: 100, AssigneeID: "assignee"},
+					{Title: "closed", DueAt: 100, State: playbook.ChecklistItemStateClosed},
+				},
+			},
+			{
+				Items: []playbook.ChecklistItem{
+					{Title: "due now", DueAt: 500, State: playbook.ChecklistItemStateInProgress},
+					{Title: "due later", DueAt: 900},
+					{Title: "due soon", DueAt: 600},
+					{Title: "closed before due", DueAt: 550, State: playbook.ChecklistItemStateClosed},
+				},
+			},
+		},
+	}
+
+	require.Equal(t, []OverdueItem{
+		{ChecklistNum: 0, ItemNum: 1, Title: "overdue", AssigneeID: "assignee", DueAt: 100},
+		{ChecklistNum: 1, ItemNum: 0, Title: "due now", DueAt: 500},
+	}, inc.OverdueItems(500))
+	require.Equal(t, int64(600), inc.NextDueAt(500))
+
+	require.Empty(t, inc.OverdueItems(50))
+	require.Equal(t, int64(100), inc.NextDueAt(50))
+
+	require.Len(t, inc.OverdueItems(1000), 4)
+	require.Equal(t, int64(0), inc.NextDueAt(1000))
+}
+
+func TestIncident_ConditionFields(t *testing.T) {
+	inc := &Incident{
+		Name:     "Checkout errors",
+		Severity: "SEV1",
+		CustomFields: []playbook.CustomField{
+			{Key: "services", Type: playbook.CustomFieldTypeMultiselect, Options: []string{"api", "database"}},
+			{Key: "customer_id", Type: playbook.CustomFieldTypeText},
+		},
+		CustomFieldValues: map[string][]string{"services": {"api", "database"}},
+	}
+
+	fields := inc.ConditionFields()
+	require.Equal(t, []string{"Checkout errors"}, fields[playbook.ConditionFieldName])
+	require.Equal(t, []string{"SEV1"}, fields[playbook.ConditionFieldSeverity])
+	require.Equal(t, []string{"api", "database"}, fields[playbook.ConditionFieldCustomPrefix+"services"])
+	require.Empty(t, fields[playbook.ConditionFieldCustomPrefix+"customer_id"])
+
+	condition := playbook.ItemCondition{Field: playbook.ConditionFieldCustomPrefix + "services", Operator: playbook.ConditionOperatorIs, Values: []string{"database"}}
+	require.True(t, condition.Matches(fields))
+}
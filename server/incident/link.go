@@ -0,0 +1,345 @@
+package incident
+
+import (
+	"fmt"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/pkg/errors"
+)
+
+// Types of the links between incidents, from the point of view of the incident holding the link:
+// a link of type LinkChildOf means that the incident is a child of the linked incident.
+const (
+	LinkRelated      = "related"
+	LinkDuplicateOf  = "duplicate_of"
+	LinkDuplicatedBy = "duplicated_by"
+	LinkParentOf     = "parent_of"
+	LinkChildOf      = "child_of"
+)
+
+// linkDescriptions describes each type of link, to complete "this incident is ...".
+var linkDescriptions = map[string]string{
+	LinkRelated:      "related to",
+	LinkDuplicateOf:  "a duplicate of",
+	LinkDuplicatedBy: "duplicated by",
+	LinkParentOf:     "the parent of",
+	LinkChildOf:      "a child of",
+}
+
+// IsValidLinkType returns true if linkType is one of the known types of links.
+func IsValidLinkType(linkType string) bool {
+	_, ok := linkDescriptions[linkType]
+	return ok
+}
+
+// InverseLinkType returns the type of a link seen from the linked incident: the inverse of
+// LinkParentOf is LinkChildOf, and the inverse of LinkRelated is LinkRelated.
+func InverseLinkType(linkType string) string {
+	switch linkType {
+	case LinkDuplicateOf:
+		return LinkDuplicatedBy
+	case LinkDuplicatedBy:
+		return LinkDuplicateOf
+	case LinkParentOf:
+		return LinkChildOf
+	case LinkChildOf:
+		return LinkParentOf
+	default:
+		return linkType
+	}
+}
+
+// Link relates two incidents: IncidentID is <Type> LinkedIncidentID. A link is stored once, with
+// a type among LinkRelated, LinkDuplicateOf and LinkChildOf, and is seen from both incidents.
+type Link struct {
+	ID               string `json:"id"`
+	IncidentID       string `json:"incident_id"`
+	LinkedIncidentID string `json:"linked_incident_id"`
+	Type             string `json:"type"`
+	CreatorUserID    string `json:"creator_user_id"`
+	CreateAt         int64  `json:"create_at"`
+}
+
+// normalize returns the link with a type that can be stored, swapping the incidents if needed.
+func (l Link) normalize() Link {
+	if l.Type == LinkDuplicatedBy || l.Type == LinkParentOf {
+		l.IncidentID, l.LinkedIncidentID = l.LinkedIncidentID, l.IncidentID
+		l.Type = InverseLinkType(l.Type)
+	}
+	return l
+}
+
+// LinkedIncident is a link seen from one of its incidents, together with the name and status of
+// the incident at the other end. It is retrieved with the incident; it is not saved with it.
+type LinkedIncident struct {
+	LinkID        string `json:"link_id"`
+	Type          string `json:"type"` // Relation of the incident to the linked one.
+	IncidentID    string `json:"incident_id"`
+	Name          string `json:"name"`
+	ChannelID     string `json:"channel_id"`
+	CurrentStatus string `json:"current_status"`
+	CreatorUserID string `json:"creator_user_id"`
+	CreateAt      int64  `json:"create_at"`
+}
+
+// IsActive returns true if the linked incident is neither resolved, archived nor merged.
+func (l LinkedIncident) IsActive() bool {
+	return l.CurrentStatus != StatusResolved && l.CurrentStatus != StatusArchived && l.CurrentStatus != StatusMerged
+}
+
+// Children returns the incidents linked to the incident as its children.
+func (i *Incident) Children() []LinkedIncident {
+	var children []LinkedIncident
+	for _, link := range i.Links {
+		if link.Type == LinkParentOf {
+			children = append(children, link)
+		}
+	}
+	return children
+}
+
+// Parent returns the incident linked to the incident as its parent, or false if it has none.
+func (i *Incident) Parent() (LinkedIncident, bool) {
+	for _, link := range i.Links {
+		if link.Type == LinkChildOf {
+			return link, true
+		}
+	}
+	return LinkedIncident{}, false
+}
+
+// findLink returns the link of the incident to linkedIncidentID, or false if there is none.
+func (i *Incident) findLink(linkedIncidentID string) (LinkedIncident, bool) {
+	for _, link := range i.Links {
+		if link.IncidentID == linkedIncidentID {
+			return link, true
+		}
+	}
+	return LinkedIncident{}, false
+}
+
+// maxLinkDepth bounds the walk up the parents of an incident when checking for cycles.
+const maxLinkDepth = 100
+
+// LinkIncidents processes a request from userID to link incidentID to linkedIncidentID, so that
+// incidentID is <linkType> linkedIncidentID. Two incidents can only be linked once, an incident
+// has at most one parent, and an incident cannot be an ancestor of its parent.
+func (s *ServiceImpl) LinkIncidents(incidentID, userID, linkedIncidentID, linkType string) (string, error) {
+	if !IsValidLinkType(linkType) {
+		return "", errors.Wrapf(ErrMalformedLink, "unknown link type '%s'", linkType)
+	}
+
+	if incidentID == linkedIncidentID {
+		return "", errors.Wrap(ErrMalformedLink, "an incident cannot be linked to itself")
+	}
+
+	theIncident, err := s.store.GetIncident(incidentID)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get incident %s", incidentID)
+	}
+
+	linkedIncident, err := s.store.GetIncident(linkedIncidentID)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get incident %s", linkedIncidentID)
+	}
+
+	if _, ok := theIncident.findLink(linkedIncidentID); ok {
+		return "", errors.Wrap(ErrDuplicateEntry, "the incidents are already linked")
+	}
+
+	link := Link{
+		IncidentID:       incidentID,
+		LinkedIncidentID: linkedIncidentID,
+		Type:             linkType,
+		CreatorUserID:    userID,
+		CreateAt:         model.GetMillis(),
+	}.normalize()
+
+	if link.Type == LinkChildOf {
+		if err = s.checkParent(link.IncidentID, link.LinkedIncidentID); err != nil {
+			return "", err
+		}
+	}
+
+	newLink, err := s.store.CreateLink(&link)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create link")
+	}
+
+	s.postLinkMessage(userID, theIncident, linkedIncident, "marked this incident as %s %s.", linkDescriptions[linkType])
+	s.postLinkMessage(userID, linkedIncident, theIncident, "marked this incident as %s %s.", linkDescriptions[InverseLinkType(linkType)])
+
+	for _, id := range []string{incidentID, linkedIncidentID} {
+		if err = s.sendIncidentToClient(id); err != nil {
+			return "", err
+		}
+	}
+
+	return newLink.ID, nil
+}
+
+// checkParent returns an error if childID cannot become a child of parentID: because it already
+// has a parent, or because it is an ancestor of parentID.
+func (s *ServiceImpl) checkParent(childID, parentID string) error {
+	child, err := s.store.GetIncident(childID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get incident %s", childID)
+	}
+
+	if _, ok := child.Parent(); ok {
+		return errors.Wrap(ErrMalformedLink, "the child incident already has a parent")
+	}
+
+	ancestorID := parentID
+	for depth := 0; depth < maxLinkDepth; depth++ {
+		ancestor, err2 := s.store.GetIncident(ancestorID)
+		if err2 != nil {
+			return errors.Wrapf(err2, "failed to get incident %s", ancestorID)
+		}
+
+		parent, ok := ancestor.Parent()
+		if !ok {
+			return nil
+		}
+		if parent.IncidentID == childID {
+			return errors.Wrap(ErrMalformedLink, "the child incident is an ancestor of the parent incident")
+		}
+		ancestorID = parent.IncidentID
+	}
+
+	return errors.Wrap(ErrMalformedLink, "too many ancestors")
+}
+
+// UnlinkIncidents processes a request from userID to remove the link with the ID linkID from
+// incidentID.
+func (s *ServiceImpl) UnlinkIncidents(incidentID, userID, linkID string) error {
+	theIncident, err := s.store.GetIncident(incidentID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get incident %s", incidentID)
+	}
+
+	var link LinkedIncident
+	found := false
+	for _, l := range theIncident.Links {
+		if l.LinkID == linkID {
+			link = l
+			found = true
+		}
+	}
+	if !found {
+		return errors.Wrapf(ErrNotFound, "incident %s has no link %s", incidentID, linkID)
+	}
+
+	linkedIncident, err := s.store.GetIncident(link.IncidentID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get incident %s", link.IncidentID)
+	}
+
+	if err = s.store.DeleteLink(linkID); err != nil {
+		return errors.Wrap(err, "failed to delete link")
+	}
+
+	s.postLinkMessage(userID, theIncident, linkedIncident, "removed the link of this incident to %s.")
+	s.postLinkMessage(userID, linkedIncident, theIncident, "removed the link of this incident to %s.")
+
+	for _, id := range []string{incidentID, link.IncidentID} {
+		if err = s.sendIncidentToClient(id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// postLinkMessage posts the modification message of userID to the channel of theIncident. The
+// message is formatted with args, followed by a link to the channel of linkedIncident.
+func (s *ServiceImpl) postLinkMessage(userID string, theIncident, linkedIncident *Incident, format string, args ...interface{}) {
+	linkedChannel := linkedIncident.Name
+	if channelURL, _, err := s.incidentURLs(linkedIncident); err == nil {
+		linkedChannel = fmt.Sprintf("[%s](%s)", linkedIncident.Name, channelURL)
+	}
+
+	message := fmt.Sprintf(format, append(args, linkedChannel)...)
+	if _, err := s.modificationMessage(userID, theIncident.ChannelID, message); err != nil {
+		s.pluginAPI.Log.Warn("failed to post the link message", "incident_id", theIncident.ID, "error", err.Error())
+	}
+}
+
+// offerToResolveChildren posts a message with a button to resolve the children of theIncident
+// that are still active, if any.
+func (s *ServiceImpl) offerToResolveChildren(theIncident *Incident) error {
+	numActive := 0
+	for _, child := range theIncident.Children() {
+		if child.IsActive() {
+			numActive++
+		}
+	}
+	if numActive == 0 {
+		return nil
+	}
+
+	attachments := []*model.SlackAttachment{
+		{
+			Actions: []*model.PostAction{
+				{
+					Type: "button",
+					Name: "Resolve Child Incidents",
+					Integration: &model.PostActionIntegration{
+						URL: fmt.Sprintf("/plugins/%s/api/v0/incidents/%s/resolve-children/button",
+							s.configService.GetManifest().Id,
+							theIncident.ID),
+					},
+				},
+			},
+		},
+	}
+
+	children := "child incidents that are"
+	if numActive == 1 {
+		children = "child incident that is"
+	}
+
+	if _, err := s.poster.PostMessageWithAttachments(theIncident.ChannelID, attachments,
+		"This incident has %d %s not resolved yet. Do you want to resolve them too?", numActive, children); err != nil {
+		return errors.Wrap(err, "failed to offer to resolve the child incidents")
+	}
+
+	return nil
+}
+
+// ResolveChildren processes a request from userID to resolve the children of incidentID that are
+// still active. It returns the IDs of the resolved incidents.
+func (s *ServiceImpl) ResolveChildren(incidentID, userID string) ([]string, error) {
+	parent, err := s.store.GetIncident(incidentID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get incident %s", incidentID)
+	}
+
+	message := fmt.Sprintf("Resolved together with the parent incident %s.", parent.Name)
+	if channelURL, _, urlErr := s.incidentURLs(parent); urlErr == nil {
+		message = fmt.Sprintf("Resolved together with the parent incident [%s](%s).", parent.Name, channelURL)
+	}
+
+	var resolvedIDs []string
+	for _, child := range parent.Children() {
+		if !child.IsActive() {
+			continue
+		}
+
+		childIncident, err2 := s.store.GetIncident(child.IncidentID)
+		if err2 != nil {
+			return resolvedIDs, errors.Wrapf(err2, "failed to get incident %s", child.IncidentID)
+		}
+
+		if err2 = s.UpdateStatus(child.IncidentID, userID, StatusUpdateOptions{
+			Status:      StatusResolved,
+			Description: childIncident.Description,
+			Message:     message,
+		}); err2 != nil {
+			return resolvedIDs, errors.Wrapf(err2, "failed to resolve incident %s", child.IncidentID)
+		}
+		resolvedIDs = append(resolvedIDs, child.IncidentID)
+	}
+
+	return resolvedIDs, nil
+}
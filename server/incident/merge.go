@@ -0,0 +1,208 @@
+package incident
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// MergeIncident processes a request from userID to merge sourceID into targetID. The timeline
+// events of the source are copied to the target with the name of the source in their summary, and
+// the members of its channel are added to the target channel. The status posts of the source stay
+// with it, as they make its status history, but each of its status updates is on the timeline of
+// the target. The source channel is left with a pointer to the target, and the status of the
+// source becomes StatusMerged. A merge that failed can be retried: the events it already copied
+// are not copied again.
+func (s *ServiceImpl) MergeIncident(sourceID, userID, targetID string) error {
+	if sourceID == targetID {
+		return errors.Wrap(ErrInvalidMerge, "an incident cannot be merged into itself")
+	}
+
+	source, err := s.store.GetIncident(sourceID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get incident %s", sourceID)
+	}
+
+	target, err := s.store.GetIncident(targetID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get incident %s", targetID)
+	}
+
+	if source.CurrentStatus == StatusMerged {
+		return errors.Wrap(ErrInvalidMerge, "the incident was already merged")
+	}
+	if target.CurrentStatus == StatusMerged {
+		return errors.Wrap(ErrInvalidMerge, "cannot merge into an incident that was merged")
+	}
+	if source.TeamID != target.TeamID {
+		return errors.Wrap(ErrInvalidMerge, "the incidents belong to different teams")
+	}
+
+	alreadyCopied := map[string]bool{}
+	for _, event := range target.TimelineEvents {
+		alreadyCopied[mergedEventKey(event)] = true
+	}
+
+	for _, event := range s.mergedEvents(source) {
+		if alreadyCopied[mergedEventKey(event)] {
+			continue
+		}
+
+		copied := event
+		copied.ID = ""
+		copied.IncidentID = targetID
+		if _, err = s.store.CreateTimelineEvent(&copied); err != nil {
+			return errors.Wrapf(err, "failed to copy timeline event %s", event.ID)
+		}
+	}
+
+	s.addMembersToChannel(source.ChannelID, target.ChannelID)
+
+	sourceLink, targetLink := source.Name, target.Name
+	if channelURL, _, urlErr := s.incidentURLs(source); urlErr == nil {
+		sourceLink = fmt.Sprintf("[%s](%s)", source.Name, channelURL)
+	}
+	if channelURL, _, urlErr := s.incidentURLs(target); urlErr == nil {
+		targetLink = fmt.Sprintf("[%s](%s)", target.Name, channelURL)
+	}
+
+	post, err := s.modificationMessage(userID, source.ChannelID,
+		fmt.Sprintf("merged this incident into %s. The incident continues there.", targetLink))
+	if err != nil {
+		return err
+	}
+
+	source.MergedIntoID = targetID
+	if err = s.store.UpdateIncident(source); err != nil {
+		return errors.Wrap(err, "failed to update incident")
+	}
+
+	source.StatusPosts = append(source.StatusPosts, StatusPost{
+		ID:       post.Id,
+		Status:   StatusMerged,
+		CreateAt: post.CreateAt,
+	})
+	if err = s.store.UpdateStatus(&SQLStatusPost{
+		IncidentID: sourceID,
+		PostID:     post.Id,
+		Status:     StatusMerged,
+		EndAt:      source.ResolvedAt(),
+	}); err != nil {
+		return errors.Wrap(err, "failed to write status post to store. There is now inconsistent state.")
+	}
+
+	s.RemoveReminder(sourceID)
+	s.scheduler.Cancel(RetrospectivePrefix + sourceID)
+	s.cancelEscalation(source)
+	if err = s.removeReminderPost(source); err != nil {
+		s.pluginAPI.Log.Warn("failed to remove the reminder post", "incident_id", sourceID, "error", err.Error())
+	}
+
+	event := &TimelineEvent{
+		IncidentID:    sourceID,
+		CreateAt:      post.CreateAt,
+		EventAt:       post.CreateAt,
+		EventType:     IncidentMerged,
+		Summary:       fmt.Sprintf("Merged into %s", target.Name),
+		PostID:        post.Id,
+		SubjectUserID: userID,
+	}
+	if err = s.createTimelineEvent(source, event); err != nil {
+		return errors.Wrap(err, "failed to create timeline event")
+	}
+
+	targetPost, err := s.modificationMessage(userID, target.ChannelID,
+		fmt.Sprintf("merged the incident %s into this incident.", sourceLink))
+	if err != nil {
+		return err
+	}
+
+	event = &TimelineEvent{
+		IncidentID:    targetID,
+		CreateAt:      targetPost.CreateAt,
+		EventAt:       targetPost.CreateAt,
+		EventType:     IncidentMerged,
+		Summary:       fmt.Sprintf("Merged %s into this incident", source.Name),
+		PostID:        targetPost.Id,
+		SubjectUserID: userID,
+	}
+	if err = s.createTimelineEvent(target, event); err != nil {
+		return errors.Wrap(err, "failed to create timeline event")
+	}
+
+	for _, id := range []string{sourceID, targetID} {
+		if err = s.sendIncidentToClient(id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mergedEvents returns the timeline events of source to add to the incident it is merged into,
+// with the name of source in their summary. The status updates of source that have no timeline
+// event, e.g. because they predate the timeline, get one, attributed to the author of their post.
+func (s *ServiceImpl) mergedEvents(source *Incident) []TimelineEvent {
+	var events []TimelineEvent
+	hasEvent := map[string]bool{}
+	for _, event := range source.TimelineEvents {
+		if event.EventType == StatusUpdated {
+			hasEvent[event.PostID] = true
+		}
+
+		event.Summary = fmt.Sprintf("[%s] %s", source.Name, event.Summary)
+		events = append(events, event)
+	}
+
+	for _, statusPost := range source.StatusPosts {
+		if statusPost.DeleteAt != 0 || hasEvent[statusPost.ID] {
+			continue
+		}
+
+		event := TimelineEvent{
+			CreateAt:  statusPost.CreateAt,
+			EventAt:   statusPost.CreateAt,
+			EventType: StatusUpdated,
+			Summary:   fmt.Sprintf("[%s] %s", source.Name, statusPost.Status),
+			PostID:    statusPost.ID,
+		}
+		if post, err := s.pluginAPI.Post.GetPost(statusPost.ID); err == nil {
+			event.SubjectUserID = post.UserId
+		} else {
+			s.pluginAPI.Log.Warn("failed to get status post", "post_id", statusPost.ID, "error", err.Error())
+		}
+		events = append(events, event)
+	}
+
+	return events
+}
+
+// mergedEventKey identifies a timeline event copied to the incident the source is merged into.
+func mergedEventKey(event TimelineEvent) string {
+	return fmt.Sprintf("%s %d %s %s", event.EventType, event.EventAt, event.PostID, event.Summary)
+}
+
+// addMembersToChannel adds the members of the channel fromChannelID to the channel toChannelID.
+// Failures are logged, so that a member who cannot be added does not stop the others.
+func (s *ServiceImpl) addMembersToChannel(fromChannelID, toChannelID string) {
+	botUserID := s.configService.GetConfiguration().BotUserID
+
+	perPage := 200
+	for page := 0; ; page++ {
+		members, err := s.pluginAPI.Channel.ListMembers(fromChannelID, page, perPage)
+		if err != nil {
+			s.pluginAPI.Log.Warn("failed to list the channel members", "channel_id", fromChannelID, "error", err.Error())
+			return
+		}
+
+		for _, member := range members {
+			if _, err = s.pluginAPI.Channel.AddUser(toChannelID, member.UserId, botUserID); err != nil {
+				s.pluginAPI.Log.Warn("failed to add user to the incident channel", "user_id", member.UserId, "error", err.Error())
+			}
+		}
+
+		if len(members) < perPage {
+			return
+		}
+	}
+}
@@ -38,6 +38,35 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 	return m.recorder
 }
 
+// AcknowledgeIncident mocks base method
+func (m *MockService) AcknowledgeIncident(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcknowledgeIncident", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AcknowledgeIncident indicates an expected call of AcknowledgeIncident
+func (mr *MockServiceMockRecorder) AcknowledgeIncident(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcknowledgeIncident", reflect.TypeOf((*MockService)(nil).AcknowledgeIncident), arg0, arg1)
+}
+
+// AddActionItem mocks base method
+func (m *MockService) AddActionItem(arg0, arg1 string, arg2 incident.ActionItem) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddActionItem", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddActionItem indicates an expected call of AddActionItem
+func (mr *MockServiceMockRecorder) AddActionItem(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddActionItem", reflect.TypeOf((*MockService)(nil).AddActionItem), arg0, arg1, arg2)
+}
+
 // AddChecklistItem mocks base method
 func (m *MockService) AddChecklistItem(arg0, arg1 string, arg2 int, arg3 playbook.ChecklistItem) error {
 	m.ctrl.T.Helper()
@@ -66,6 +95,35 @@ func (mr *MockServiceMockRecorder) AddPostToTimeline(arg0, arg1, arg2, arg3 inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPostToTimeline", reflect.TypeOf((*MockService)(nil).AddPostToTimeline), arg0, arg1, arg2, arg3)
 }
 
+// AddTimelineEvent mocks base method
+func (m *MockService) AddTimelineEvent(arg0, arg1 string, arg2 incident.TimelineEventOptions) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTimelineEvent", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddTimelineEvent indicates an expected call of AddTimelineEvent
+func (mr *MockServiceMockRecorder) AddTimelineEvent(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTimelineEvent", reflect.TypeOf((*MockService)(nil).AddTimelineEvent), arg0, arg1, arg2)
+}
+
+// AssignRole mocks base method
+func (m *MockService) AssignRole(arg0, arg1, arg2 string, arg3 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignRole", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignRole indicates an expected call of AssignRole
+func (mr *MockServiceMockRecorder) AssignRole(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignRole", reflect.TypeOf((*MockService)(nil).AssignRole), arg0, arg1, arg2, arg3)
+}
+
 // CancelRetrospective mocks base method
 func (m *MockService) CancelRetrospective(arg0, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -94,6 +152,20 @@ func (mr *MockServiceMockRecorder) ChangeOwner(arg0, arg1, arg2 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeOwner", reflect.TypeOf((*MockService)(nil).ChangeOwner), arg0, arg1, arg2)
 }
 
+// ChangeSeverity mocks base method
+func (m *MockService) ChangeSeverity(arg0, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeSeverity", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangeSeverity indicates an expected call of ChangeSeverity
+func (mr *MockServiceMockRecorder) ChangeSeverity(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeSeverity", reflect.TypeOf((*MockService)(nil).ChangeSeverity), arg0, arg1, arg2)
+}
+
 // ChangeCreationDate mocks base method
 func (m *MockService) ChangeCreationDate(arg0 string, arg1 time.Time) error {
 	m.ctrl.T.Helper()
@@ -151,6 +223,64 @@ func (mr *MockServiceMockRecorder) EditChecklistItem(arg0, arg1, arg2, arg3, arg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditChecklistItem", reflect.TypeOf((*MockService)(nil).EditChecklistItem), arg0, arg1, arg2, arg3, arg4, arg5, arg6)
 }
 
+// EditTimelineEvent mocks base method
+func (m *MockService) EditTimelineEvent(arg0, arg1, arg2 string, arg3 incident.TimelineEventOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditTimelineEvent", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EditTimelineEvent indicates an expected call of EditTimelineEvent
+func (mr *MockServiceMockRecorder) EditTimelineEvent(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditTimelineEvent", reflect.TypeOf((*MockService)(nil).EditTimelineEvent), arg0, arg1, arg2, arg3)
+}
+
+// ExportTimeline mocks base method
+func (m *MockService) ExportTimeline(arg0 string, arg1 incident.TimelineExportOptions) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportTimeline", arg0, arg1)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportTimeline indicates an expected call of ExportTimeline
+func (mr *MockServiceMockRecorder) ExportTimeline(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportTimeline", reflect.TypeOf((*MockService)(nil).ExportTimeline), arg0, arg1)
+}
+
+// FollowIncident mocks base method
+func (m *MockService) FollowIncident(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FollowIncident", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FollowIncident indicates an expected call of FollowIncident
+func (mr *MockServiceMockRecorder) FollowIncident(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FollowIncident", reflect.TypeOf((*MockService)(nil).FollowIncident), arg0, arg1)
+}
+
+// GetActionItems mocks base method
+func (m *MockService) GetActionItems(arg0 permissions.RequesterInfo, arg1 incident.ActionItemFilterOptions) (*incident.GetActionItemsResults, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActionItems", arg0, arg1)
+	ret0, _ := ret[0].(*incident.GetActionItemsResults)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActionItems indicates an expected call of GetActionItems
+func (mr *MockServiceMockRecorder) GetActionItems(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActionItems", reflect.TypeOf((*MockService)(nil).GetActionItems), arg0, arg1)
+}
+
 // GetChecklistAutocomplete mocks base method
 func (m *MockService) GetChecklistAutocomplete(arg0 string) ([]model.AutocompleteListItem, error) {
 	m.ctrl.T.Helper()
@@ -181,6 +311,21 @@ func (mr *MockServiceMockRecorder) GetChecklistItemAutocomplete(arg0 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChecklistItemAutocomplete", reflect.TypeOf((*MockService)(nil).GetChecklistItemAutocomplete), arg0)
 }
 
+// GetIncidentIDForAlert mocks base method
+func (m *MockService) GetIncidentIDForAlert(arg0, arg1 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIncidentIDForAlert", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIncidentIDForAlert indicates an expected call of GetIncidentIDForAlert
+func (mr *MockServiceMockRecorder) GetIncidentIDForAlert(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncidentIDForAlert", reflect.TypeOf((*MockService)(nil).GetIncidentIDForAlert), arg0, arg1)
+}
+
 // GetOwners mocks base method
 func (m *MockService) GetOwners(arg0 permissions.RequesterInfo, arg1 incident.FilterOptions) ([]incident.OwnerInfo, error) {
 	m.ctrl.T.Helper()
@@ -256,6 +401,51 @@ func (mr *MockServiceMockRecorder) GetIncidents(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncidents", reflect.TypeOf((*MockService)(nil).GetIncidents), arg0, arg1)
 }
 
+// GetTimelineEventEdits mocks base method
+func (m *MockService) GetTimelineEventEdits(arg0, arg1 string) ([]incident.TimelineEventEdit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTimelineEventEdits", arg0, arg1)
+	ret0, _ := ret[0].([]incident.TimelineEventEdit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTimelineEventEdits indicates an expected call of GetTimelineEventEdits
+func (mr *MockServiceMockRecorder) GetTimelineEventEdits(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTimelineEventEdits", reflect.TypeOf((*MockService)(nil).GetTimelineEventEdits), arg0, arg1)
+}
+
+// GetTimelineEvents mocks base method
+func (m *MockService) GetTimelineEvents(arg0 string, arg1 incident.TimelineFilterOptions) (*incident.GetTimelineEventsResults, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTimelineEvents", arg0, arg1)
+	ret0, _ := ret[0].(*incident.GetTimelineEventsResults)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTimelineEvents indicates an expected call of GetTimelineEvents
+func (mr *MockServiceMockRecorder) GetTimelineEvents(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTimelineEvents", reflect.TypeOf((*MockService)(nil).GetTimelineEvents), arg0, arg1)
+}
+
+// GetWebhookDeliveries mocks base method
+func (m *MockService) GetWebhookDeliveries(arg0 string) ([]incident.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDeliveries", arg0)
+	ret0, _ := ret[0].([]incident.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDeliveries indicates an expected call of GetWebhookDeliveries
+func (mr *MockServiceMockRecorder) GetWebhookDeliveries(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDeliveries", reflect.TypeOf((*MockService)(nil).GetWebhookDeliveries), arg0)
+}
+
 // HandleReminder mocks base method
 func (m *MockService) HandleReminder(arg0 string) {
 	m.ctrl.T.Helper()
@@ -268,6 +458,21 @@ func (mr *MockServiceMockRecorder) HandleReminder(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleReminder", reflect.TypeOf((*MockService)(nil).HandleReminder), arg0)
 }
 
+// ImportIncident mocks base method
+func (m *MockService) ImportIncident(arg0 *incident.Incident, arg1 []incident.TimelineEvent, arg2 []incident.StatusPost) (*incident.Incident, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportIncident", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*incident.Incident)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportIncident indicates an expected call of ImportIncident
+func (mr *MockServiceMockRecorder) ImportIncident(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportIncident", reflect.TypeOf((*MockService)(nil).ImportIncident), arg0, arg1, arg2)
+}
+
 // IsOwner mocks base method
 func (m *MockService) IsOwner(arg0, arg1 string) bool {
 	m.ctrl.T.Helper()
@@ -282,6 +487,35 @@ func (mr *MockServiceMockRecorder) IsOwner(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsOwner", reflect.TypeOf((*MockService)(nil).IsOwner), arg0, arg1)
 }
 
+// LinkIncidents mocks base method
+func (m *MockService) LinkIncidents(arg0, arg1, arg2, arg3 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LinkIncidents", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LinkIncidents indicates an expected call of LinkIncidents
+func (mr *MockServiceMockRecorder) LinkIncidents(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LinkIncidents", reflect.TypeOf((*MockService)(nil).LinkIncidents), arg0, arg1, arg2, arg3)
+}
+
+// MergeIncident mocks base method
+func (m *MockService) MergeIncident(arg0 string, arg1 string, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeIncident", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MergeIncident indicates an expected call of MergeIncident
+func (mr *MockServiceMockRecorder) MergeIncident(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeIncident", reflect.TypeOf((*MockService)(nil).MergeIncident), arg0, arg1, arg2)
+}
+
 // ModifyCheckedState mocks base method
 func (m *MockService) ModifyCheckedState(arg0, arg1, arg2 string, arg3, arg4 int) error {
 	m.ctrl.T.Helper()
@@ -394,6 +628,20 @@ func (mr *MockServiceMockRecorder) PublishRetrospective(arg0, arg1, arg2 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishRetrospective", reflect.TypeOf((*MockService)(nil).PublishRetrospective), arg0, arg1, arg2)
 }
 
+// RemoveActionItem mocks base method
+func (m *MockService) RemoveActionItem(arg0, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveActionItem", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveActionItem indicates an expected call of RemoveActionItem
+func (mr *MockServiceMockRecorder) RemoveActionItem(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveActionItem", reflect.TypeOf((*MockService)(nil).RemoveActionItem), arg0, arg1, arg2)
+}
+
 // RemoveChecklistItem mocks base method
 func (m *MockService) RemoveChecklistItem(arg0, arg1 string, arg2, arg3 int) error {
 	m.ctrl.T.Helper()
@@ -448,6 +696,21 @@ func (mr *MockServiceMockRecorder) RemoveTimelineEvent(arg0, arg1, arg2 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTimelineEvent", reflect.TypeOf((*MockService)(nil).RemoveTimelineEvent), arg0, arg1, arg2)
 }
 
+// ResolveChildren mocks base method
+func (m *MockService) ResolveChildren(arg0, arg1 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveChildren", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveChildren indicates an expected call of ResolveChildren
+func (mr *MockServiceMockRecorder) ResolveChildren(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveChildren", reflect.TypeOf((*MockService)(nil).ResolveChildren), arg0, arg1)
+}
+
 // RunChecklistItemSlashCommand mocks base method
 func (m *MockService) RunChecklistItemSlashCommand(arg0, arg1 string, arg2, arg3 int) (string, error) {
 	m.ctrl.T.Helper()
@@ -463,6 +726,20 @@ func (mr *MockServiceMockRecorder) RunChecklistItemSlashCommand(arg0, arg1, arg2
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunChecklistItemSlashCommand", reflect.TypeOf((*MockService)(nil).RunChecklistItemSlashCommand), arg0, arg1, arg2, arg3)
 }
 
+// SetActionItemDone mocks base method
+func (m *MockService) SetActionItemDone(arg0, arg1, arg2 string, arg3 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetActionItemDone", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetActionItemDone indicates an expected call of SetActionItemDone
+func (mr *MockServiceMockRecorder) SetActionItemDone(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetActionItemDone", reflect.TypeOf((*MockService)(nil).SetActionItemDone), arg0, arg1, arg2, arg3)
+}
+
 // SetAssignee mocks base method
 func (m *MockService) SetAssignee(arg0, arg1, arg2 string, arg3, arg4 int) error {
 	m.ctrl.T.Helper()
@@ -477,6 +754,20 @@ func (mr *MockServiceMockRecorder) SetAssignee(arg0, arg1, arg2, arg3, arg4 inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAssignee", reflect.TypeOf((*MockService)(nil).SetAssignee), arg0, arg1, arg2, arg3, arg4)
 }
 
+// SetAssigneeRole mocks base method
+func (m *MockService) SetAssigneeRole(arg0, arg1, arg2 string, arg3, arg4 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAssigneeRole", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAssigneeRole indicates an expected call of SetAssigneeRole
+func (mr *MockServiceMockRecorder) SetAssigneeRole(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAssigneeRole", reflect.TypeOf((*MockService)(nil).SetAssigneeRole), arg0, arg1, arg2, arg3, arg4)
+}
+
 // SetReminder mocks base method
 func (m *MockService) SetReminder(arg0 string, arg1 time.Duration) error {
 	m.ctrl.T.Helper()
@@ -505,6 +796,48 @@ func (mr *MockServiceMockRecorder) ToggleCheckedState(arg0, arg1, arg2, arg3 int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ToggleCheckedState", reflect.TypeOf((*MockService)(nil).ToggleCheckedState), arg0, arg1, arg2, arg3)
 }
 
+// UnfollowIncident mocks base method
+func (m *MockService) UnfollowIncident(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnfollowIncident", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnfollowIncident indicates an expected call of UnfollowIncident
+func (mr *MockServiceMockRecorder) UnfollowIncident(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnfollowIncident", reflect.TypeOf((*MockService)(nil).UnfollowIncident), arg0, arg1)
+}
+
+// UnlinkIncidents mocks base method
+func (m *MockService) UnlinkIncidents(arg0, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlinkIncidents", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnlinkIncidents indicates an expected call of UnlinkIncidents
+func (mr *MockServiceMockRecorder) UnlinkIncidents(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlinkIncidents", reflect.TypeOf((*MockService)(nil).UnlinkIncidents), arg0, arg1, arg2)
+}
+
+// UpdateActionItem mocks base method
+func (m *MockService) UpdateActionItem(arg0, arg1 string, arg2 incident.ActionItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateActionItem", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateActionItem indicates an expected call of UpdateActionItem
+func (mr *MockServiceMockRecorder) UpdateActionItem(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateActionItem", reflect.TypeOf((*MockService)(nil).UpdateActionItem), arg0, arg1, arg2)
+}
+
 // UpdateRetrospective mocks base method
 func (m *MockService) UpdateRetrospective(arg0, arg1, arg2 string) error {
 	m.ctrl.T.Helper()
@@ -519,6 +852,20 @@ func (mr *MockServiceMockRecorder) UpdateRetrospective(arg0, arg1, arg2 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRetrospective", reflect.TypeOf((*MockService)(nil).UpdateRetrospective), arg0, arg1, arg2)
 }
 
+// UpdateRetrospectiveSections mocks base method
+func (m *MockService) UpdateRetrospectiveSections(arg0, arg1 string, arg2 incident.RetrospectiveSections) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRetrospectiveSections", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRetrospectiveSections indicates an expected call of UpdateRetrospectiveSections
+func (mr *MockServiceMockRecorder) UpdateRetrospectiveSections(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRetrospectiveSections", reflect.TypeOf((*MockService)(nil).UpdateRetrospectiveSections), arg0, arg1, arg2)
+}
+
 // UpdateStatus mocks base method
 func (m *MockService) UpdateStatus(arg0, arg1 string, arg2 incident.StatusUpdateOptions) error {
 	m.ctrl.T.Helper()
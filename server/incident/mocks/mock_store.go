@@ -36,6 +36,20 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// AddStatusPosts mocks base method
+func (m *MockStore) AddStatusPosts(arg0 string, arg1 []incident.StatusPost) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddStatusPosts", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddStatusPosts indicates an expected call of AddStatusPosts
+func (mr *MockStoreMockRecorder) AddStatusPosts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddStatusPosts", reflect.TypeOf((*MockStore)(nil).AddStatusPosts), arg0, arg1)
+}
+
 // ChangeCreationDate mocks base method
 func (m *MockStore) ChangeCreationDate(arg0 string, arg1 time.Time) error {
 	m.ctrl.T.Helper()
@@ -50,6 +64,21 @@ func (mr *MockStoreMockRecorder) ChangeCreationDate(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeCreationDate", reflect.TypeOf((*MockStore)(nil).ChangeCreationDate), arg0, arg1)
 }
 
+// CreateActionItem mocks base method
+func (m *MockStore) CreateActionItem(arg0 *incident.ActionItem) (*incident.ActionItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateActionItem", arg0)
+	ret0, _ := ret[0].(*incident.ActionItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateActionItem indicates an expected call of CreateActionItem
+func (mr *MockStoreMockRecorder) CreateActionItem(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateActionItem", reflect.TypeOf((*MockStore)(nil).CreateActionItem), arg0)
+}
+
 // CreateIncident mocks base method
 func (m *MockStore) CreateIncident(arg0 *incident.Incident) (*incident.Incident, error) {
 	m.ctrl.T.Helper()
@@ -65,6 +94,21 @@ func (mr *MockStoreMockRecorder) CreateIncident(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIncident", reflect.TypeOf((*MockStore)(nil).CreateIncident), arg0)
 }
 
+// CreateLink mocks base method
+func (m *MockStore) CreateLink(arg0 *incident.Link) (*incident.Link, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLink", arg0)
+	ret0, _ := ret[0].(*incident.Link)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLink indicates an expected call of CreateLink
+func (mr *MockStoreMockRecorder) CreateLink(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLink", reflect.TypeOf((*MockStore)(nil).CreateLink), arg0)
+}
+
 // CreateTimelineEvent mocks base method
 func (m *MockStore) CreateTimelineEvent(arg0 *incident.TimelineEvent) (*incident.TimelineEvent, error) {
 	m.ctrl.T.Helper()
@@ -80,6 +124,106 @@ func (mr *MockStoreMockRecorder) CreateTimelineEvent(arg0 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTimelineEvent", reflect.TypeOf((*MockStore)(nil).CreateTimelineEvent), arg0)
 }
 
+// CreateWebhookDelivery mocks base method
+func (m *MockStore) CreateWebhookDelivery(arg0 *incident.WebhookDelivery) (*incident.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookDelivery", arg0)
+	ret0, _ := ret[0].(*incident.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhookDelivery indicates an expected call of CreateWebhookDelivery
+func (mr *MockStoreMockRecorder) CreateWebhookDelivery(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookDelivery", reflect.TypeOf((*MockStore)(nil).CreateWebhookDelivery), arg0)
+}
+
+// CreateWebhookDeliveryAttempt mocks base method
+func (m *MockStore) CreateWebhookDeliveryAttempt(arg0 *incident.WebhookDeliveryAttempt) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookDeliveryAttempt", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWebhookDeliveryAttempt indicates an expected call of CreateWebhookDeliveryAttempt
+func (mr *MockStoreMockRecorder) CreateWebhookDeliveryAttempt(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookDeliveryAttempt", reflect.TypeOf((*MockStore)(nil).CreateWebhookDeliveryAttempt), arg0)
+}
+
+// DeleteActionItem mocks base method
+func (m *MockStore) DeleteActionItem(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteActionItem", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteActionItem indicates an expected call of DeleteActionItem
+func (mr *MockStoreMockRecorder) DeleteActionItem(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteActionItem", reflect.TypeOf((*MockStore)(nil).DeleteActionItem), arg0)
+}
+
+// DeleteLink mocks base method
+func (m *MockStore) DeleteLink(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLink", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteLink indicates an expected call of DeleteLink
+func (mr *MockStoreMockRecorder) DeleteLink(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLink", reflect.TypeOf((*MockStore)(nil).DeleteLink), arg0)
+}
+
+// EditTimelineEvent mocks base method
+func (m *MockStore) EditTimelineEvent(arg0 *incident.TimelineEvent, arg1 *incident.TimelineEventEdit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditTimelineEvent", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EditTimelineEvent indicates an expected call of EditTimelineEvent
+func (mr *MockStoreMockRecorder) EditTimelineEvent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditTimelineEvent", reflect.TypeOf((*MockStore)(nil).EditTimelineEvent), arg0, arg1)
+}
+
+// Follow mocks base method
+func (m *MockStore) Follow(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Follow", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Follow indicates an expected call of Follow
+func (mr *MockStoreMockRecorder) Follow(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Follow", reflect.TypeOf((*MockStore)(nil).Follow), arg0, arg1)
+}
+
+// GetActionItems mocks base method
+func (m *MockStore) GetActionItems(arg0 permissions.RequesterInfo, arg1 incident.ActionItemFilterOptions) (*incident.GetActionItemsResults, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActionItems", arg0, arg1)
+	ret0, _ := ret[0].(*incident.GetActionItemsResults)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActionItems indicates an expected call of GetActionItems
+func (mr *MockStoreMockRecorder) GetActionItems(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActionItems", reflect.TypeOf((*MockStore)(nil).GetActionItems), arg0, arg1)
+}
+
 // GetAllIncidentMembersCount mocks base method
 func (m *MockStore) GetAllIncidentMembersCount(arg0 string) (int64, error) {
 	m.ctrl.T.Helper()
@@ -95,6 +239,21 @@ func (mr *MockStoreMockRecorder) GetAllIncidentMembersCount(arg0 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllIncidentMembersCount", reflect.TypeOf((*MockStore)(nil).GetAllIncidentMembersCount), arg0)
 }
 
+// GetIncidentIDForAlert mocks base method
+func (m *MockStore) GetIncidentIDForAlert(arg0, arg1 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIncidentIDForAlert", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIncidentIDForAlert indicates an expected call of GetIncidentIDForAlert
+func (mr *MockStoreMockRecorder) GetIncidentIDForAlert(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncidentIDForAlert", reflect.TypeOf((*MockStore)(nil).GetIncidentIDForAlert), arg0, arg1)
+}
+
 // GetOwners mocks base method
 func (m *MockStore) GetOwners(arg0 permissions.RequesterInfo, arg1 incident.FilterOptions) ([]incident.OwnerInfo, error) {
 	m.ctrl.T.Helper()
@@ -170,6 +329,66 @@ func (mr *MockStoreMockRecorder) GetTimelineEvent(arg0, arg1 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTimelineEvent", reflect.TypeOf((*MockStore)(nil).GetTimelineEvent), arg0, arg1)
 }
 
+// GetTimelineEventEdits mocks base method
+func (m *MockStore) GetTimelineEventEdits(arg0 string) ([]incident.TimelineEventEdit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTimelineEventEdits", arg0)
+	ret0, _ := ret[0].([]incident.TimelineEventEdit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTimelineEventEdits indicates an expected call of GetTimelineEventEdits
+func (mr *MockStoreMockRecorder) GetTimelineEventEdits(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTimelineEventEdits", reflect.TypeOf((*MockStore)(nil).GetTimelineEventEdits), arg0)
+}
+
+// GetTimelineEvents mocks base method
+func (m *MockStore) GetTimelineEvents(arg0 string, arg1 incident.TimelineFilterOptions) (*incident.GetTimelineEventsResults, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTimelineEvents", arg0, arg1)
+	ret0, _ := ret[0].(*incident.GetTimelineEventsResults)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTimelineEvents indicates an expected call of GetTimelineEvents
+func (mr *MockStoreMockRecorder) GetTimelineEvents(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTimelineEvents", reflect.TypeOf((*MockStore)(nil).GetTimelineEvents), arg0, arg1)
+}
+
+// GetWebhookDeliveries mocks base method
+func (m *MockStore) GetWebhookDeliveries(arg0 string) ([]incident.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDeliveries", arg0)
+	ret0, _ := ret[0].([]incident.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDeliveries indicates an expected call of GetWebhookDeliveries
+func (mr *MockStoreMockRecorder) GetWebhookDeliveries(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).GetWebhookDeliveries), arg0)
+}
+
+// GetWebhookDelivery mocks base method
+func (m *MockStore) GetWebhookDelivery(arg0 string) (*incident.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDelivery", arg0)
+	ret0, _ := ret[0].(*incident.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDelivery indicates an expected call of GetWebhookDelivery
+func (mr *MockStoreMockRecorder) GetWebhookDelivery(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDelivery", reflect.TypeOf((*MockStore)(nil).GetWebhookDelivery), arg0)
+}
+
 // HasViewedChannel mocks base method
 func (m *MockStore) HasViewedChannel(arg0, arg1 string) bool {
 	m.ctrl.T.Helper()
@@ -212,6 +431,34 @@ func (mr *MockStoreMockRecorder) SetViewedChannel(arg0, arg1 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetViewedChannel", reflect.TypeOf((*MockStore)(nil).SetViewedChannel), arg0, arg1)
 }
 
+// Unfollow mocks base method
+func (m *MockStore) Unfollow(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unfollow", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unfollow indicates an expected call of Unfollow
+func (mr *MockStoreMockRecorder) Unfollow(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unfollow", reflect.TypeOf((*MockStore)(nil).Unfollow), arg0, arg1)
+}
+
+// UpdateActionItem mocks base method
+func (m *MockStore) UpdateActionItem(arg0 *incident.ActionItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateActionItem", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateActionItem indicates an expected call of UpdateActionItem
+func (mr *MockStoreMockRecorder) UpdateActionItem(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateActionItem", reflect.TypeOf((*MockStore)(nil).UpdateActionItem), arg0)
+}
+
 // UpdateIncident mocks base method
 func (m *MockStore) UpdateIncident(arg0 *incident.Incident) error {
 	m.ctrl.T.Helper()
@@ -253,3 +500,17 @@ func (mr *MockStoreMockRecorder) UpdateTimelineEvent(arg0 interface{}) *gomock.C
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTimelineEvent", reflect.TypeOf((*MockStore)(nil).UpdateTimelineEvent), arg0)
 }
+
+// UpdateWebhookDelivery mocks base method
+func (m *MockStore) UpdateWebhookDelivery(arg0 *incident.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhookDelivery", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWebhookDelivery indicates an expected call of UpdateWebhookDelivery
+func (mr *MockStoreMockRecorder) UpdateWebhookDelivery(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhookDelivery", reflect.TypeOf((*MockStore)(nil).UpdateWebhookDelivery), arg0)
+}
@@ -5,6 +5,7 @@ package incident
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -18,15 +19,42 @@ type Reminder struct {
 
 const RetrospectivePrefix = "retro_"
 
+// DueItemsPrefix prefixes the key of the job reminding of the overdue checklist items of an incident.
+const DueItemsPrefix = "due_"
+
 // HandleReminder is the handler for all reminder events.
 func (s *ServiceImpl) HandleReminder(key string) {
 	if strings.HasPrefix(key, RetrospectivePrefix) {
 		s.handleReminderToFillRetro(strings.TrimPrefix(key, RetrospectivePrefix))
+	} else if strings.HasPrefix(key, WebhookPrefix) {
+		s.handleWebhookDelivery(strings.TrimPrefix(key, WebhookPrefix))
+	} else if strings.HasPrefix(key, DueItemsPrefix) {
+		s.handleOverdueItemsReminder(strings.TrimPrefix(key, DueItemsPrefix))
+	} else if strings.HasPrefix(key, ActionItemsPrefix) {
+		s.handleOverdueActionItemsReminder(strings.TrimPrefix(key, ActionItemsPrefix))
+	} else if strings.HasPrefix(key, EscalationPrefix) {
+		s.handleEscalation(strings.TrimPrefix(key, EscalationPrefix))
 	} else {
 		s.handleStatusUpdateReminder(key)
 	}
 }
 
+// splitNumberedKey returns the ID and the number of a job key built as <id>_<number>, without its
+// prefix, e.g. by webhookAttemptKey or escalationKey. The number is 0 for a bare ID.
+func splitNumberedKey(key string) (string, int) {
+	i := strings.LastIndex(key, "_")
+	if i == -1 {
+		return key, 0
+	}
+
+	number, err := strconv.Atoi(key[i+1:])
+	if err != nil {
+		return key, 0
+	}
+
+	return key[:i], number
+}
+
 func (s *ServiceImpl) handleReminderToFillRetro(incidentID string) {
 	incidentToRemind, err := s.GetIncident(incidentID)
 	if err != nil {
@@ -60,6 +88,105 @@ func (s *ServiceImpl) handleReminderToFillRetro(incidentID string) {
 	}()
 }
 
+// handleOverdueItemsReminder reminds by DM the assignee of every overdue checklist item of the
+// incident, or the users holding its role, or the owner if the item is not assigned. Each item is
+// reminded of only once.
+func (s *ServiceImpl) handleOverdueItemsReminder(incidentID string) {
+	incidentToModify, err := s.GetIncident(incidentID)
+	if err != nil {
+		s.logger.Errorf(errors.Wrapf(err, "handleOverdueItemsReminder failed to get incident id: %s", incidentID).Error())
+		return
+	}
+
+	// Tasks do not need to be done anymore once the incident is over.
+	if !incidentToModify.IsActive() {
+		return
+	}
+
+	channelURL, _, err := s.incidentURLs(incidentToModify)
+	if err != nil {
+		s.logger.Errorf(errors.Wrapf(err, "handleOverdueItemsReminder failed to get the URL of incident id: %s", incidentID).Error())
+		return
+	}
+
+	now := model.GetMillis()
+	notified := false
+	for _, overdueItem := range incidentToModify.OverdueItems(now) {
+		item := &incidentToModify.Checklists[overdueItem.ChecklistNum].Items[overdueItem.ItemNum]
+		if item.OverdueNotified {
+			continue
+		}
+
+		userIDs := incidentToModify.ItemAssigneeIDs(*item)
+		if len(userIDs) == 0 {
+			userIDs = []string{incidentToModify.OwnerUserID}
+		}
+
+		reminded := false
+		for _, userID := range userIDs {
+			if err = s.poster.DM(userID, "The task **%s** of the incident [%s](%s) is overdue.", item.Title, incidentToModify.Name, channelURL); err != nil {
+				s.logger.Errorf(errors.Wrapf(err, "failed to remind user %s of an overdue task", userID).Error())
+				continue
+			}
+			reminded = true
+		}
+		if !reminded {
+			continue
+		}
+
+		item.OverdueNotified = true
+		notified = true
+	}
+
+	if notified {
+		if err = s.store.UpdateIncident(incidentToModify); err != nil {
+			s.logger.Errorf(errors.Wrapf(err, "failed to update incident id: %s", incidentID).Error())
+			return
+		}
+		s.poster.PublishWebsocketEventToChannel(incidentUpdatedWSEvent, incidentToModify, incidentToModify.ChannelID)
+	}
+
+	if incidentToModify.NextDueAt(now) == 0 {
+		return
+	}
+
+	// Jobs can't be rescheduled within themselves with the same key. As a temporary workaround do it in a delayed goroutine
+	go func() {
+		time.Sleep(time.Second * 2)
+		if scheduleErr := s.scheduleOverdueItemsReminder(incidentToModify); scheduleErr != nil {
+			s.logger.Errorf(errors.Wrap(scheduleErr, "failed to reocurr overdue tasks reminder").Error())
+		}
+	}()
+}
+
+// scheduleOverdueItemsReminder schedules the reminder of the overdue checklist items of
+// theIncident for the next due date, if any.
+func (s *ServiceImpl) scheduleOverdueItemsReminder(theIncident *Incident) error {
+	nextDueAt := theIncident.NextDueAt(model.GetMillis())
+	if nextDueAt == 0 {
+		return nil
+	}
+
+	if _, err := s.scheduler.ScheduleOnce(DueItemsPrefix+theIncident.ID, time.Unix(0, nextDueAt*int64(time.Millisecond))); err != nil {
+		return errors.Wrap(err, "unable to schedule overdue tasks reminder")
+	}
+
+	return nil
+}
+
+// setChecklistItemDueDates sets the due date of the checklist items of theIncident that have a
+// due time, counting from the creation of the incident.
+func setChecklistItemDueDates(theIncident *Incident) {
+	for i := range theIncident.Checklists {
+		for j := range theIncident.Checklists[i].Items {
+			item := &theIncident.Checklists[i].Items[j]
+			if item.DueAfterSeconds > 0 {
+				item.DueAt = theIncident.CreateAt + item.DueAfterSeconds*1000
+			}
+		}
+	}
+}
+
 func (s *ServiceImpl) handleStatusUpdateReminder(incidentID string) {
 	incidentToModify, err := s.GetIncident(incidentID)
 	if err != nil {
@@ -0,0 +1,518 @@
+package incident
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/permissions"
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/timeutils"
+)
+
+// ActionItemsPrefix prefixes the key of the job reminding of the overdue action items of an
+// incident.
+const ActionItemsPrefix = "action_items_"
+
+// actionItemsReminderRetryDelay is how long to wait before reminding again of the overdue action
+// items whose reminder could not be sent.
+const actionItemsReminderRetryDelay = 5 * time.Minute
+
+// RetrospectiveSections is the structured part of a retrospective, completing its free text.
+type RetrospectiveSections struct {
+	Summary   string `json:"summary"`
+	RootCause string `json:"root_cause"`
+	Impact    string `json:"impact"`
+	WentWell  string `json:"what_went_well"`
+}
+
+// IsEmpty returns true if none of the sections are filled in.
+func (r RetrospectiveSections) IsEmpty() bool {
+	return r == RetrospectiveSections{}
+}
+
+// render returns the sections that are filled in as markdown, in a fixed order.
+func (r RetrospectiveSections) render() string {
+	sections := []struct {
+		title string
+		text  string
+	}{
+		{"Summary", r.Summary},
+		{"Root Cause", r.RootCause},
+		{"Impact", r.Impact},
+		{"What Went Well", r.WentWell},
+	}
+
+	var rendered []string
+	for _, section := range sections {
+		if strings.TrimSpace(section.text) == "" {
+			continue
+		}
+		rendered = append(rendered, fmt.Sprintf("#### %s\n%s", section.title, strings.TrimSpace(section.text)))
+	}
+
+	return strings.Join(rendered, "\n\n")
+}
+
+// ActionItem is a follow-up task of a retrospective, with an owner and an optional due date. It
+// is stored in its own table and retrieved with its incident.
+type ActionItem struct {
+	ID              string `json:"id"`
+	IncidentID      string `json:"incident_id"`
+	Title           string `json:"title"`
+	OwnerUserID     string `json:"owner_user_id"`
+	DueAt           int64  `json:"due_at"`       // 0 if the action item has no due date.
+	CompletedAt     int64  `json:"completed_at"` // 0 if the action item is still open.
+	OverdueNotified bool   `json:"overdue_notified"`
+	CreatorUserID   string `json:"creator_user_id"`
+	CreateAt        int64  `json:"create_at"`
+	UpdateAt        int64  `json:"update_at"`
+}
+
+// IsDone returns true if the action item was completed.
+func (a ActionItem) IsDone() bool {
+	return a.CompletedAt != 0
+}
+
+// IsOverdue returns true if the action item is still open and its due date has passed at now.
+func (a ActionItem) IsOverdue(now int64) bool {
+	return !a.IsDone() && a.DueAt != 0 && a.DueAt <= now
+}
+
+// ValidateActionItem checks that the action item has a title and that its owner, if any, has a
+// valid ID.
+func ValidateActionItem(item ActionItem) error {
+	if strings.TrimSpace(item.Title) == "" {
+		return errors.New("action item title must not be empty")
+	}
+	if item.OwnerUserID != "" && !model.IsValidId(item.OwnerUserID) {
+		return errors.Errorf("invalid owner user id '%s'", item.OwnerUserID)
+	}
+	if item.DueAt < 0 {
+		return errors.New("action item due date must not be negative")
+	}
+
+	return nil
+}
+
+// NextActionItemDueAt returns the earliest due date, later than after, among the open action
+// items that were not reminded of yet. It returns 0 if there is none.
+func (i *Incident) NextActionItemDueAt(after int64) int64 {
+	var next int64
+	for _, item := range i.ActionItems {
+		if item.DueAt <= after || item.IsDone() || item.OverdueNotified {
+			continue
+		}
+		if next == 0 || item.DueAt < next {
+			next = item.DueAt
+		}
+	}
+
+	return next
+}
+
+// findActionItem returns the index of the action item with the ID itemID, or -1 if the incident
+// has none.
+func (i *Incident) findActionItem(itemID string) int {
+	for j, item := range i.ActionItems {
+		if item.ID == itemID {
+			return j
+		}
+	}
+	return -1
+}
+
+// Action item statuses used to filter the action items across incidents.
+const (
+	ActionItemStatusOpen    = "open"
+	ActionItemStatusDone    = "done"
+	ActionItemStatusOverdue = "overdue"
+)
+
+// ActionItemFilterOptions specifies the optional parameters when getting action items across
+// incidents.
+type ActionItemFilterOptions struct {
+	// Gets the action items of the incidents of this team.
+	TeamID string `url:"team_id,omitempty"`
+
+	// Pagination options.
+	Page    int `url:"page,omitempty"`
+	PerPage int `url:"per_page,omitempty"`
+
+	// IncidentID filters by incident. Defaults to blank (no filter).
+	IncidentID string `url:"incident_id,omitempty"`
+
+	// OwnerID filters by the Mattermost user ID of the owner. Defaults to blank (no filter).
+	OwnerID string `url:"owner_user_id,omitempty"`
+
+	// Status filters by status: ActionItemStatusOpen, ActionItemStatusDone or
+	// ActionItemStatusOverdue. Defaults to blank (no filter).
+	Status string `url:"status,omitempty"`
+}
+
+// ValidateActionItemOptions checks options and sets the default page size.
+func ValidateActionItemOptions(options *ActionItemFilterOptions) error {
+	if options.PerPage == 0 {
+		options.PerPage = PerPageDefault
+	}
+
+	if !model.IsValidId(options.TeamID) {
+		return errors.New("bad parameter 'team_id': must be 26 characters")
+	}
+
+	if options.IncidentID != "" && !model.IsValidId(options.IncidentID) {
+		return errors.New("bad parameter 'incident_id': must be 26 characters")
+	}
+
+	if options.OwnerID != "" && !model.IsValidId(options.OwnerID) {
+		return errors.New("bad parameter 'owner_user_id': must be 26 characters")
+	}
+
+	switch options.Status {
+	case "", ActionItemStatusOpen, ActionItemStatusDone, ActionItemStatusOverdue:
+	default:
+		return errors.Errorf("bad parameter 'status': unknown status '%s'", options.Status)
+	}
+
+	if options.Page < 0 || options.PerPage < 0 {
+		return errors.New("bad parameter: 'page' and 'per_page' must not be negative")
+	}
+
+	return nil
+}
+
+// GetActionItemsResults holds a page of action items and the total count before paging.
+type GetActionItemsResults struct {
+	TotalCount int          `json:"total_count"`
+	PageCount  int          `json:"page_count"`
+	HasMore    bool         `json:"has_more"`
+	Items      []ActionItem `json:"items"`
+}
+
+// renderActionItems returns the action items as a markdown list, with the usernames of their
+// owners, indexed by user ID, and their due dates.
+func renderActionItems(items []ActionItem, usernames map[string]string) string {
+	if len(items) == 0 {
+		return ""
+	}
+
+	rendered := "#### Action Items\n"
+	for _, item := range items {
+		checkbox := "[ ]"
+		if item.IsDone() {
+			checkbox = "[x]"
+		}
+		rendered += fmt.Sprintf("- %s %s", checkbox, item.Title)
+		if username, ok := usernames[item.OwnerUserID]; ok {
+			rendered += fmt.Sprintf(" (@%s)", username)
+		}
+		if item.DueAt != 0 {
+			rendered += fmt.Sprintf(", due %s", timeutils.GetTimeForMillis(item.DueAt).UTC().Format("Jan 2, 2006"))
+		}
+		rendered += "\n"
+	}
+
+	return strings.TrimSuffix(rendered, "\n")
+}
+
+// renderRetrospective returns the message publishing a retrospective: its free text, followed by
+// its sections and action items.
+func renderRetrospective(text string, sections RetrospectiveSections, items []ActionItem, usernames map[string]string) string {
+	var parts []string
+	for _, part := range []string{strings.TrimSpace(text), sections.render(), renderActionItems(items, usernames)} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	return strings.Join(parts, "\n\n")
+}
+
+// actionItemOwnerUsernames returns the usernames of the owners of items, indexed by user ID.
+// Owners that cannot be retrieved are left out.
+func (s *ServiceImpl) actionItemOwnerUsernames(items []ActionItem) map[string]string {
+	usernames := map[string]string{}
+	for _, item := range items {
+		if item.OwnerUserID == "" {
+			continue
+		}
+		if _, ok := usernames[item.OwnerUserID]; ok {
+			continue
+		}
+		owner, err := s.pluginAPI.User.Get(item.OwnerUserID)
+		if err != nil {
+			s.logger.Warnf("failed to get the owner %s of an action item: %v", item.OwnerUserID, err)
+			continue
+		}
+		usernames[item.OwnerUserID] = owner.Username
+	}
+
+	return usernames
+}
+
+// UpdateRetrospectiveSections updates the structured sections of the retrospective of incidentID.
+func (s *ServiceImpl) UpdateRetrospectiveSections(incidentID, userID string, sections RetrospectiveSections) error {
+	incidentToModify, err := s.store.GetIncident(incidentID)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve incident")
+	}
+
+	incidentToModify.RetrospectiveSections = sections
+
+	if err = s.store.UpdateIncident(incidentToModify); err != nil {
+		return errors.Wrap(err, "failed to update incident")
+	}
+
+	s.poster.PublishWebsocketEventToChannel(incidentUpdatedWSEvent, incidentToModify, incidentToModify.ChannelID)
+	s.telemetry.UpdateRetrospective(incidentToModify, userID)
+
+	return nil
+}
+
+// AddActionItem processes a request from userID to add an action item to the retrospective of
+// incidentID, and returns the ID of the new action item. The owner, if any, must be able to view
+// the incident.
+func (s *ServiceImpl) AddActionItem(incidentID, userID string, item ActionItem) (string, error) {
+	if err := ValidateActionItem(item); err != nil {
+		return "", errors.Wrap(ErrMalformedActionItem, err.Error())
+	}
+
+	theIncident, err := s.store.GetIncident(incidentID)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get incident %s", incidentID)
+	}
+
+	if err = s.checkActionItemOwner(theIncident, item.OwnerUserID); err != nil {
+		return "", err
+	}
+
+	now := model.GetMillis()
+	newItem, err := s.store.CreateActionItem(&ActionItem{
+		IncidentID:    incidentID,
+		Title:         strings.TrimSpace(item.Title),
+		OwnerUserID:   item.OwnerUserID,
+		DueAt:         item.DueAt,
+		CreatorUserID: userID,
+		CreateAt:      now,
+		UpdateAt:      now,
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create action item")
+	}
+
+	theIncident.ActionItems = append(theIncident.ActionItems, *newItem)
+	s.rescheduleActionItemsReminder(theIncident)
+
+	if err = s.sendIncidentToClient(incidentID); err != nil {
+		return "", err
+	}
+
+	return newItem.ID, nil
+}
+
+// UpdateActionItem processes a request from userID to change the title, owner and due date of the
+// action item of incidentID with the ID of item. Changing the due date reminds the owner again
+// once the new due date has passed.
+func (s *ServiceImpl) UpdateActionItem(incidentID, userID string, item ActionItem) error {
+	if err := ValidateActionItem(item); err != nil {
+		return errors.Wrap(ErrMalformedActionItem, err.Error())
+	}
+
+	theIncident, err := s.store.GetIncident(incidentID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get incident %s", incidentID)
+	}
+
+	index := theIncident.findActionItem(item.ID)
+	if index == -1 {
+		return errors.Wrapf(ErrNotFound, "incident %s has no action item %s", incidentID, item.ID)
+	}
+
+	if err = s.checkActionItemOwner(theIncident, item.OwnerUserID); err != nil {
+		return err
+	}
+
+	itemToModify := &theIncident.ActionItems[index]
+	if itemToModify.DueAt != item.DueAt || itemToModify.OwnerUserID != item.OwnerUserID {
+		itemToModify.OverdueNotified = false
+	}
+	itemToModify.Title = strings.TrimSpace(item.Title)
+	itemToModify.OwnerUserID = item.OwnerUserID
+	itemToModify.DueAt = item.DueAt
+	itemToModify.UpdateAt = model.GetMillis()
+
+	if err = s.store.UpdateActionItem(itemToModify); err != nil {
+		return errors.Wrap(err, "failed to update action item")
+	}
+
+	s.rescheduleActionItemsReminder(theIncident)
+
+	return s.sendIncidentToClient(incidentID)
+}
+
+// SetActionItemDone processes a request from userID to complete, or to reopen, the action item of
+// incidentID with the ID itemID. Completing a completed action item is a no-op.
+func (s *ServiceImpl) SetActionItemDone(incidentID, userID, itemID string, done bool) error {
+	theIncident, err := s.store.GetIncident(incidentID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get incident %s", incidentID)
+	}
+
+	index := theIncident.findActionItem(itemID)
+	if index == -1 {
+		return errors.Wrapf(ErrNotFound, "incident %s has no action item %s", incidentID, itemID)
+	}
+
+	itemToModify := &theIncident.ActionItems[index]
+	if itemToModify.IsDone() == done {
+		return nil
+	}
+
+	now := model.GetMillis()
+	itemToModify.CompletedAt = 0
+	if done {
+		itemToModify.CompletedAt = now
+	}
+	itemToModify.UpdateAt = now
+
+	if err = s.store.UpdateActionItem(itemToModify); err != nil {
+		return errors.Wrap(err, "failed to update action item")
+	}
+
+	s.rescheduleActionItemsReminder(theIncident)
+
+	return s.sendIncidentToClient(incidentID)
+}
+
+// RemoveActionItem processes a request from userID to remove the action item of incidentID with
+// the ID itemID.
+func (s *ServiceImpl) RemoveActionItem(incidentID, userID, itemID string) error {
+	theIncident, err := s.store.GetIncident(incidentID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get incident %s", incidentID)
+	}
+
+	index := theIncident.findActionItem(itemID)
+	if index == -1 {
+		return errors.Wrapf(ErrNotFound, "incident %s has no action item %s", incidentID, itemID)
+	}
+
+	if err = s.store.DeleteActionItem(itemID); err != nil {
+		return errors.Wrap(err, "failed to delete action item")
+	}
+
+	theIncident.ActionItems = append(theIncident.ActionItems[:index], theIncident.ActionItems[index+1:]...)
+	s.rescheduleActionItemsReminder(theIncident)
+
+	return s.sendIncidentToClient(incidentID)
+}
+
+// GetActionItems returns filtered action items across the incidents the requester can view, and
+// the total count before paging.
+func (s *ServiceImpl) GetActionItems(requesterInfo permissions.RequesterInfo, options ActionItemFilterOptions) (*GetActionItemsResults, error) {
+	return s.store.GetActionItems(requesterInfo, options)
+}
+
+// checkActionItemOwner checks that ownerID, if not blank, can view theIncident.
+func (s *ServiceImpl) checkActionItemOwner(theIncident *Incident, ownerID string) error {
+	if ownerID == "" {
+		return nil
+	}
+
+	if err := permissions.ViewIncidentFromChannelID(ownerID, theIncident.ChannelID, s.pluginAPI); err != nil {
+		if errors.Is(err, permissions.ErrNoPermissions) {
+			return errors.Wrapf(ErrMalformedActionItem, "user %s cannot view the incident", ownerID)
+		}
+		return errors.Wrap(err, "failed to check the permissions of the action item owner")
+	}
+
+	return nil
+}
+
+// rescheduleActionItemsReminder replaces the reminder of the overdue action items of theIncident,
+// scheduling it for the next due date, if any. Failures are logged, since the action items were
+// saved.
+func (s *ServiceImpl) rescheduleActionItemsReminder(theIncident *Incident) {
+	s.scheduler.Cancel(ActionItemsPrefix + theIncident.ID)
+	if err := s.scheduleActionItemsReminder(theIncident, 0, 0); err != nil {
+		s.logger.Errorf(errors.Wrapf(err, "failed to schedule the action items reminder of incident id: %s", theIncident.ID).Error())
+	}
+}
+
+// scheduleActionItemsReminder schedules the reminder of the overdue action items of theIncident for
+// the next due date later than after, if any, or for retryAt if it comes first. An action item
+// whose due date has passed but that was not reminded of yet is reminded of right away when after
+// is 0. retryAt is 0 if there is no reminder to retry.
+func (s *ServiceImpl) scheduleActionItemsReminder(theIncident *Incident, after, retryAt int64) error {
+	nextDueAt := theIncident.NextActionItemDueAt(after)
+	if retryAt != 0 && (nextDueAt == 0 || retryAt < nextDueAt) {
+		nextDueAt = retryAt
+	}
+	if nextDueAt == 0 {
+		return nil
+	}
+
+	if _, err := s.scheduler.ScheduleOnce(ActionItemsPrefix+theIncident.ID, time.Unix(0, nextDueAt*int64(time.Millisecond))); err != nil {
+		return errors.Wrap(err, "unable to schedule action items reminder")
+	}
+
+	return nil
+}
+
+// handleOverdueActionItemsReminder reminds by DM the owner of every overdue action item of the
+// incident, or the owner of the incident if the action item has none. Unlike checklist items,
+// action items are reminded of after the incident is over. Each action item is reminded of only
+// once per due date; the reminders that could not be sent are retried after
+// actionItemsReminderRetryDelay.
+func (s *ServiceImpl) handleOverdueActionItemsReminder(incidentID string) {
+	theIncident, err := s.GetIncident(incidentID)
+	if err != nil {
+		s.logger.Errorf(errors.Wrapf(err, "handleOverdueActionItemsReminder failed to get incident id: %s", incidentID).Error())
+		return
+	}
+
+	_, detailsURL, err := s.incidentURLs(theIncident)
+	if err != nil {
+		s.logger.Errorf(errors.Wrapf(err, "handleOverdueActionItemsReminder failed to get the URL of incident id: %s", incidentID).Error())
+		return
+	}
+
+	now := model.GetMillis()
+	var retryAt int64
+	for i := range theIncident.ActionItems {
+		item := &theIncident.ActionItems[i]
+		if !item.IsOverdue(now) || item.OverdueNotified {
+			continue
+		}
+
+		ownerID := item.OwnerUserID
+		if ownerID == "" {
+			ownerID = theIncident.OwnerUserID
+		}
+
+		if err = s.poster.DM(ownerID, "The action item **%s** from the retrospective of the incident [%s](%s) is overdue.", item.Title, theIncident.Name, detailsURL); err != nil {
+			s.logger.Errorf(errors.Wrapf(err, "failed to remind user %s of an overdue action item", ownerID).Error())
+			retryAt = now + actionItemsReminderRetryDelay.Milliseconds()
+			continue
+		}
+
+		item.OverdueNotified = true
+		if err = s.store.UpdateActionItem(item); err != nil {
+			s.logger.Errorf(errors.Wrapf(err, "failed to update action item id: %s", item.ID).Error())
+		}
+	}
+
+	if retryAt == 0 && theIncident.NextActionItemDueAt(now) == 0 {
+		return
+	}
+
+	// Jobs can't be rescheduled within themselves with the same key. As a temporary workaround do it in a delayed goroutine
+	go func() {
+		time.Sleep(time.Second * 2)
+		if err2 := s.scheduleActionItemsReminder(theIncident, now, retryAt); err2 != nil {
+			s.logger.Errorf(errors.Wrap(err2, "failed to reocurr action items reminder").Error())
+		}
+	}()
+}
@@ -0,0 +1,262 @@
+package incident
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/playbook"
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/timeutils"
+	"github.com/mattermost/mattermost-server/v5/model"
+)
+
+// timelineEventTitles are the titles of the timeline events in a retrospective draft, indexed by
+// event type.
+var timelineEventTitles = map[timelineEventType]string{
+	IncidentCreated:        "Incident reported",
+	TaskStateModified:      "Task state changed",
+	StatusUpdated:          "Status changed",
+	OwnerChanged:           "Owner changed",
+	AssigneeChanged:        "Assignee changed",
+	RanSlashCommand:        "Slash command",
+	EventFromPost:          "Event",
+	UserJoinedLeft:         "Participants changed",
+	PublishedRetrospective: "Retrospective published",
+	CanceledRetrospective:  "Retrospective canceled",
+	SeverityChanged:        "Severity changed",
+	RoleChanged:            "Role changed",
+	OwnerAcknowledged:      "Owner acknowledged",
+	IncidentEscalated:      "Incident escalated",
+	IncidentMerged:         "Incident merged",
+	ManualEvent:            "Event",
+}
+
+// retrospectiveDraftData is what a retrospective draft is generated from, besides the incident.
+type retrospectiveDraftData struct {
+	StatusPosts  map[string]*model.Post // Status posts of the incident, indexed by ID. Missing posts are left out.
+	Usernames    map[string]string      // Usernames of the status post authors and timeline event subjects, indexed by user ID.
+	Participants []string               // Usernames of the participants of the incident.
+}
+
+// draftTimelineEntry is an entry of the timeline of a retrospective draft.
+type draftTimelineEntry struct {
+	at   int64
+	text string
+}
+
+// formatDraftTime formats millis, a time in a retrospective draft.
+func formatDraftTime(millis int64) string {
+	return timeutils.GetTimeForMillis(millis).UTC().Format("Jan 2, 2006 15:04 MST")
+}
+
+// renderDraftTimeline renders the timeline events and the status posts of theIncident, oldest
+// first. Status changes are left out: the status posts, rendered with their message, record them.
+func renderDraftTimeline(theIncident *Incident, data retrospectiveDraftData) string {
+	var entries []draftTimelineEntry
+
+	for _, event := range theIncident.TimelineEvents {
+		if event.DeleteAt != 0 || event.EventType == StatusUpdated {
+			continue
+		}
+
+		title, ok := timelineEventTitles[event.EventType]
+		if !ok {
+			title = string(event.EventType)
+		}
+
+		text := fmt.Sprintf("**%s**", title)
+		if event.Summary != "" {
+			text += ": " + event.Summary
+		} else if username, ok := data.Usernames[event.SubjectUserID]; ok {
+			text += " by @" + username
+		}
+		entries = append(entries, draftTimelineEntry{at: event.EventAt, text: text})
+	}
+
+	for _, statusPost := range theIncident.StatusPosts {
+		post, ok := data.StatusPosts[statusPost.ID]
+		if statusPost.DeleteAt != 0 || !ok {
+			continue
+		}
+
+		text := "**Status update**"
+		if statusPost.Status != "" {
+			text += ": " + statusPost.Status
+		}
+		if username, ok := data.Usernames[post.UserId]; ok {
+			text += " by @" + username
+		}
+		for _, line := range strings.Split(strings.TrimSpace(post.Message), "\n") {
+			text += "\n  > " + line
+		}
+		entries = append(entries, draftTimelineEntry{at: statusPost.CreateAt, text: text})
+	}
+
+	if len(entries) == 0 {
+		return ""
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].at < entries[j].at
+	})
+
+	rendered := "#### Timeline\n"
+	for _, entry := range entries {
+		rendered += fmt.Sprintf("- `%s` %s\n", formatDraftTime(entry.at), entry.text)
+	}
+
+	return strings.TrimSuffix(rendered, "\n")
+}
+
+// renderDraftDurations renders the time theIncident took to become active and to be resolved.
+func renderDraftDurations(theIncident *Incident) string {
+	reportedAt := theIncident.CreateAt
+	resolvedAt := theIncident.ResolvedAt()
+
+	var activeAt int64
+	for _, statusPost := range theIncident.StatusPosts {
+		if statusPost.DeleteAt == 0 && statusPost.Status == StatusActive {
+			activeAt = statusPost.CreateAt
+			break
+		}
+	}
+
+	duration := func(start, end int64) string {
+		return timeutils.DurationString(timeutils.GetTimeForMillis(start), timeutils.GetTimeForMillis(end))
+	}
+
+	var lines []string
+	if activeAt != 0 {
+		lines = append(lines, "- Reported → Active: "+duration(reportedAt, activeAt))
+		if resolvedAt != 0 {
+			lines = append(lines, "- Active → Resolved: "+duration(activeAt, resolvedAt))
+		}
+	}
+	if resolvedAt != 0 {
+		lines = append(lines, "- Reported → Resolved: "+duration(reportedAt, resolvedAt))
+	}
+
+	if len(lines) == 0 {
+		return ""
+	}
+
+	return "#### Key Durations\n" + strings.Join(lines, "\n")
+}
+
+// renderDraftParticipants renders participants, a list of usernames.
+func renderDraftParticipants(participants []string) string {
+	if len(participants) == 0 {
+		return ""
+	}
+
+	sorted := make([]string, 0, len(participants))
+	for _, username := range participants {
+		sorted = append(sorted, "@"+username)
+	}
+	sort.Strings(sorted)
+
+	return "#### Participants\n" + strings.Join(sorted, ", ")
+}
+
+// renderDraftChecklists renders how many tasks of each checklist of theIncident were done.
+func renderDraftChecklists(theIncident *Incident) string {
+	var lines []string
+	done, total := 0, 0
+	for _, checklist := range theIncident.Checklists {
+		checklistDone := 0
+		for _, item := range checklist.Items {
+			if item.State == playbook.ChecklistItemStateClosed {
+				checklistDone++
+			}
+		}
+		done += checklistDone
+		total += len(checklist.Items)
+		lines = append(lines, fmt.Sprintf("- %s: %d/%d", checklist.Title, checklistDone, len(checklist.Items)))
+	}
+
+	if total == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("#### Checklists\n%d/%d tasks done\n", done, total) + strings.Join(lines, "\n")
+}
+
+// renderRetrospectiveDraft returns the retrospective draft of theIncident: its retrospective
+// template, followed by its timeline, key durations, participants and checklist completion.
+func renderRetrospectiveDraft(theIncident *Incident, data retrospectiveDraftData) string {
+	var parts []string
+	for _, part := range []string{
+		strings.TrimSpace(theIncident.RetrospectiveTemplate),
+		renderDraftTimeline(theIncident, data),
+		renderDraftDurations(theIncident),
+		renderDraftParticipants(data.Participants),
+		renderDraftChecklists(theIncident),
+	} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	return strings.Join(parts, "\n\n")
+}
+
+// hasUneditedRetrospective returns true if the retrospective of the incident was not written yet:
+// it is empty, or still the template it was started from.
+func (i *Incident) hasUneditedRetrospective() bool {
+	return strings.TrimSpace(i.Retrospective) == "" || i.Retrospective == i.RetrospectiveTemplate
+}
+
+// retrospectiveDraft returns the retrospective draft of theIncident. Status posts and users that
+// cannot be retrieved are left out of the draft.
+func (s *ServiceImpl) retrospectiveDraft(theIncident *Incident) string {
+	data := retrospectiveDraftData{
+		StatusPosts: map[string]*model.Post{},
+		Usernames:   map[string]string{},
+	}
+
+	addUsername := func(userID string) {
+		if _, ok := data.Usernames[userID]; ok || userID == "" {
+			return
+		}
+		if user, err := s.pluginAPI.User.Get(userID); err == nil {
+			data.Usernames[userID] = user.Username
+		}
+	}
+
+	for _, statusPost := range theIncident.StatusPosts {
+		post, err := s.pluginAPI.Post.GetPost(statusPost.ID)
+		if err != nil {
+			s.pluginAPI.Log.Warn("failed to get the status post", "post_id", statusPost.ID, "error", err.Error())
+			continue
+		}
+		data.StatusPosts[post.Id] = post
+		addUsername(post.UserId)
+	}
+
+	for _, event := range theIncident.TimelineEvents {
+		addUsername(event.SubjectUserID)
+	}
+
+	perPage := 200
+	for page := 0; ; page++ {
+		members, err := s.pluginAPI.Channel.ListMembers(theIncident.ChannelID, page, perPage)
+		if err != nil {
+			s.pluginAPI.Log.Warn("failed to list the channel members", "channel_id", theIncident.ChannelID, "error", err.Error())
+			break
+		}
+
+		for _, member := range members {
+			user, err2 := s.pluginAPI.User.Get(member.UserId)
+			if err2 != nil || user.IsBot {
+				continue
+			}
+			data.Participants = append(data.Participants, user.Username)
+		}
+
+		if len(members) < perPage {
+			break
+		}
+	}
+
+	return renderRetrospectiveDraft(theIncident, data)
+}
@@ -0,0 +1,79 @@
+package incident
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/playbook"
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderRetrospectiveDraft(t *testing.T) {
+	reportedAt := time.Date(2021, time.March, 4, 12, 0, 0, 0, time.UTC)
+	millis := func(d time.Duration) int64 {
+		return reportedAt.Add(d).UnixNano() / int64(time.Millisecond)
+	}
+
+	t.Run("nothing to draft", func(t *testing.T) {
+		require.Equal(t, "", renderRetrospectiveDraft(&Incident{}, retrospectiveDraftData{}))
+	})
+
+	t.Run("full draft", func(t *testing.T) {
+		theIncident := &Incident{
+			CreateAt:              millis(0),
+			RetrospectiveTemplate: "What happened?\n",
+			TimelineEvents: []TimelineEvent{
+				{EventAt: millis(0), EventType: IncidentCreated, SubjectUserID: "reporter"},
+				{EventAt: millis(40 * time.Minute), EventType: OwnerChanged, Summary: "@alice to @bob"},
+				{EventAt: millis(10 * time.Minute), EventType: StatusUpdated, Summary: "Reported to Active"},
+				{EventAt: millis(20 * time.Minute), EventType: EventFromPost, Summary: "deleted", DeleteAt: 1},
+			},
+			StatusPosts: []StatusPost{
+				{ID: "active", Status: StatusActive, CreateAt: millis(10 * time.Minute)},
+				{ID: "missing", Status: StatusActive, CreateAt: millis(30 * time.Minute)},
+				{ID: "resolved", Status: StatusResolved, CreateAt: millis(2*time.Hour + 10*time.Minute)},
+			},
+			Checklists: []playbook.Checklist{
+				{Title: "Triage", Items: []playbook.ChecklistItem{{State: playbook.ChecklistItemStateClosed}, {}}},
+				{Title: "Cleanup", Items: []playbook.ChecklistItem{{State: playbook.ChecklistItemStateClosed}}},
+			},
+		}
+		data := retrospectiveDraftData{
+			StatusPosts: map[string]*model.Post{
+				"active":   {Id: "active", UserId: "alice", Message: "Investigating.\nThe database is down."},
+				"resolved": {Id: "resolved", UserId: "bob", Message: "Fixed."},
+			},
+			Usernames:    map[string]string{"reporter": "carol", "alice": "alice", "bob": "bob"},
+			Participants: []string{"bob", "alice"},
+		}
+
+		expected := "What happened?\n\n" +
+			"#### Timeline\n" +
+			"- `Mar 4, 2021 12:00 UTC` **Incident reported** by @carol\n" +
+			"- `Mar 4, 2021 12:10 UTC` **Status update**: Active by @alice\n" +
+			"  > Investigating.\n" +
+			"  > The database is down.\n" +
+			"- `Mar 4, 2021 12:40 UTC` **Owner changed**: @alice to @bob\n" +
+			"- `Mar 4, 2021 14:10 UTC` **Status update**: Resolved by @bob\n" +
+			"  > Fixed.\n\n" +
+			"#### Key Durations\n" +
+			"- Reported → Active: 10m\n" +
+			"- Active → Resolved: 2h\n" +
+			"- Reported → Resolved: 2h 10m\n\n" +
+			"#### Participants\n" +
+			"@alice, @bob\n\n" +
+			"#### Checklists\n" +
+			"2/3 tasks done\n" +
+			"- Triage: 1/2\n" +
+			"- Cleanup: 1/1"
+		require.Equal(t, expected, renderRetrospectiveDraft(theIncident, data))
+	})
+}
+
+func TestHasUneditedRetrospective(t *testing.T) {
+	require.True(t, (&Incident{}).hasUneditedRetrospective())
+	require.True(t, (&Incident{Retrospective: "template", RetrospectiveTemplate: "template"}).hasUneditedRetrospective())
+	require.False(t, (&Incident{Retrospective: "written", RetrospectiveTemplate: "template"}).hasUneditedRetrospective())
+	require.False(t, (&Incident{Retrospective: "written"}).hasUneditedRetrospective())
+}
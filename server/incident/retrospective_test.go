@@ -0,0 +1,70 @@
+package incident
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderRetrospective(t *testing.T) {
+	dueAt := time.Date(2021, time.March, 4, 12, 0, 0, 0, time.UTC).UnixNano() / int64(time.Millisecond)
+
+	t.Run("text only", func(t *testing.T) {
+		require.Equal(t, "What happened", renderRetrospective(" What happened\n", RetrospectiveSections{}, nil, nil))
+	})
+
+	t.Run("sections and action items", func(t *testing.T) {
+		sections := RetrospectiveSections{
+			Summary:   "The database went down.",
+			RootCause: "A full disk.",
+			WentWell:  "Quick detection.",
+		}
+		items := []ActionItem{
+			{Title: "Add disk alerts", OwnerUserID: "owner", DueAt: dueAt},
+			{Title: "Write a runbook", CompletedAt: 1},
+			{Title: "Review quotas", OwnerUserID: "unknown"},
+		}
+
+		expected := "What happened\n\n" +
+			"#### Summary\nThe database went down.\n\n" +
+			"#### Root Cause\nA full disk.\n\n" +
+			"#### What Went Well\nQuick detection.\n\n" +
+			"#### Action Items\n" +
+			"- [ ] Add disk alerts (@alice), due Mar 4, 2021\n" +
+			"- [x] Write a runbook\n" +
+			"- [ ] Review quotas"
+		require.Equal(t, expected, renderRetrospective("What happened", sections, items, map[string]string{"owner": "alice"}))
+	})
+}
+
+func TestNextActionItemDueAt(t *testing.T) {
+	theIncident := &Incident{
+		ActionItems: []ActionItem{
+			{ID: "no due date"},
+			{ID: "done", DueAt: 100, CompletedAt: 50},
+			{ID: "notified", DueAt: 200, OverdueNotified: true},
+			{ID: "past", DueAt: 300},
+			{ID: "next", DueAt: 500},
+			{ID: "later", DueAt: 800},
+		},
+	}
+
+	require.Equal(t, int64(300), theIncident.NextActionItemDueAt(0))
+	require.Equal(t, int64(500), theIncident.NextActionItemDueAt(400))
+	require.Equal(t, int64(0), theIncident.NextActionItemDueAt(800))
+}
+
+func TestValidateActionItemOptions(t *testing.T) {
+	teamID := "teamidteamidteamidteamidte"
+
+	options := ActionItemFilterOptions{TeamID: teamID}
+	require.NoError(t, ValidateActionItemOptions(&options))
+	require.Equal(t, PerPageDefault, options.PerPage)
+
+	require.NoError(t, ValidateActionItemOptions(&ActionItemFilterOptions{TeamID: teamID, Status: ActionItemStatusOverdue}))
+	require.Error(t, ValidateActionItemOptions(&ActionItemFilterOptions{}))
+	require.Error(t, ValidateActionItemOptions(&ActionItemFilterOptions{TeamID: teamID, Status: "late"}))
+	require.Error(t, ValidateActionItemOptions(&ActionItemFilterOptions{TeamID: teamID, OwnerID: "owner"}))
+	require.Error(t, ValidateActionItemOptions(&ActionItemFilterOptions{TeamID: teamID, Page: -1}))
+}
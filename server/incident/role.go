@@ -0,0 +1,67 @@
+package incident
+
+import (
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/playbook"
+)
+
+// RoleUserIDs returns the IDs of the users holding the role of the incident with the key roleKey.
+func (i *Incident) RoleUserIDs(roleKey string) []string {
+	return i.RoleAssignments[roleKey]
+}
+
+// ItemAssigneeIDs returns the IDs of the users the checklist item is assigned to: its assignee if
+// it has one, or else the users holding its role. It returns nil if the item is not assigned.
+func (i *Incident) ItemAssigneeIDs(item playbook.ChecklistItem) []string {
+	if item.AssigneeID != "" {
+		return []string{item.AssigneeID}
+	}
+	if item.AssigneeRole != "" {
+		return i.RoleUserIDs(item.AssigneeRole)
+	}
+
+	return nil
+}
+
+func cloneRoleAssignments(assignments map[string][]string) map[string][]string {
+	if assignments == nil {
+		return nil
+	}
+
+	newAssignments := make(map[string][]string, len(assignments))
+	for key, userIDs := range assignments {
+		newAssignments[key] = append([]string(nil), userIDs...)
+	}
+	return newAssignments
+}
+
+// uniqueIDs returns ids without blank and repeated IDs, in their original order.
+func uniqueIDs(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, id)
+	}
+	return unique
+}
+
+// sameIDs returns true if a and b hold the same IDs, in any order. Neither holds repeated IDs.
+func sameIDs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	inA := make(map[string]bool, len(a))
+	for _, id := range a {
+		inA[id] = true
+	}
+	for _, id := range b {
+		if !inA[id] {
+			return false
+		}
+	}
+	return true
+}
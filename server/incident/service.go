@@ -1,7 +1,6 @@
 package incident
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -14,6 +13,7 @@ import (
 
 	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/bot"
 	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/config"
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/oncall"
 	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/permissions"
 	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/playbook"
 	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/timeutils"
@@ -39,6 +39,7 @@ type ServiceImpl struct {
 	logger        bot.Logger
 	scheduler     JobOnceScheduler
 	telemetry     Telemetry
+	onCall        oncall.Service
 }
 
 var allNonSpaceNonWordRegex = regexp.MustCompile(`[^\w\s]`)
@@ -49,6 +50,9 @@ const DialogFieldPlaybookIDKey = "playbookID"
 // DialogFieldNameKey is the key for the incident name field used in OpenCreateIncidentDialog.
 const DialogFieldNameKey = "incidentName"
 
+// DialogFieldSeverityKey is the key for the severity select field used in OpenCreateIncidentDialog.
+const DialogFieldSeverityKey = "severity"
+
 // DialogFieldDescriptionKey is the key for the description textarea field used in UpdateIncidentDialog
 const DialogFieldDescriptionKey = "description"
 
@@ -78,7 +82,7 @@ const DialogFieldItemCommandKey = "command"
 
 // NewService creates a new incident ServiceImpl.
 func NewService(pluginAPI *pluginapi.Client, store Store, poster bot.Poster, logger bot.Logger,
-	configService config.Service, scheduler JobOnceScheduler, telemetry Telemetry) *ServiceImpl {
+	configService config.Service, scheduler JobOnceScheduler, telemetry Telemetry, onCall oncall.Service) *ServiceImpl {
 	return &ServiceImpl{
 		pluginAPI:     pluginAPI,
 		store:         store,
@@ -87,6 +91,7 @@ func NewService(pluginAPI *pluginapi.Client, store Store, poster bot.Poster, log
 		configService: configService,
 		scheduler:     scheduler,
 		telemetry:     telemetry,
+		onCall:        onCall,
 		httpClient:    &http.Client{Timeout: 30 * time.Second},
 	}
 }
@@ -96,80 +101,33 @@ func (s *ServiceImpl) GetIncidents(requesterInfo permissions.RequesterInfo, opti
 	return s.store.GetIncidents(requesterInfo, options)
 }
 
-func (s *ServiceImpl) broadcastIncidentCreation(theIncident *Incident, owner *model.User) error {
+// broadcastIncidentCreation announces the creation of theIncident in each of channelIDs. A channel
+// where it cannot be announced does not stop the others; the error lists the failed channels.
+func (s *ServiceImpl) broadcastIncidentCreation(theIncident *Incident, owner *model.User, channelIDs []string) error {
 	incidentChannel, err := s.pluginAPI.Channel.Get(theIncident.ChannelID)
 	if err != nil {
 		return err
 	}
 
-	if err := permissions.IsChannelActiveInTeam(theIncident.AnnouncementChannelID, theIncident.TeamID, s.pluginAPI); err != nil {
-		return err
-	}
-
 	announcementMsg := fmt.Sprintf("#### New Incident: ~%s\n", incidentChannel.Name)
 	announcementMsg += fmt.Sprintf("**Owner**: @%s\n", owner.Username)
 
-	if _, err := s.poster.PostMessage(theIncident.AnnouncementChannelID, announcementMsg); err != nil {
-		return err
-	}
-
-	return nil
-}
-
-// sendWebhookOnCreation sends a POST request to the creation webhook URL.
-// It blocks until a response is received.
-func (s *ServiceImpl) sendWebhookOnCreation(theIncident Incident) error {
-	siteURL := s.pluginAPI.Configuration.GetConfig().ServiceSettings.SiteURL
-	if siteURL == nil {
-		s.pluginAPI.Log.Warn("cannot send webhook on creation, please set siteURL")
-		return errors.New("Could not send webhook, please set siteURL")
-	}
-
-	team, err := s.pluginAPI.Team.Get(theIncident.TeamID)
-	if err != nil {
-		return err
-	}
-
-	channel, err := s.pluginAPI.Channel.Get(theIncident.ChannelID)
-	if err != nil {
-		return err
-	}
-
-	channelURL := getChannelURL(*siteURL, team.Name, channel.Name)
-
-	detailsURL := getDetailsURL(*siteURL, team.Name, s.configService.GetManifest().Id, theIncident.ID)
-
-	payload := struct {
-		Incident
-		ChannelURL string `json:"channel_url"`
-		DetailsURL string `json:"details_url"`
-	}{
-		Incident:   theIncident,
-		ChannelURL: channelURL,
-		DetailsURL: detailsURL,
-	}
-
-	body, err := json.Marshal(payload)
-	if err != nil {
-		return err
-	}
-
-	req, err := http.NewRequest("POST", theIncident.WebhookOnCreationURL, bytes.NewReader(body))
-	if err != nil {
-		return err
-	}
-
-	req.Header.Set("Content-Type", "application/json")
+	var failedChannelIDs []string
+	for _, channelID := range channelIDs {
+		if err := permissions.IsChannelActiveInTeam(channelID, theIncident.TeamID, s.pluginAPI); err != nil {
+			s.pluginAPI.Log.Warn("failed to broadcast the incident creation to channel", "ChannelID", channelID, "error", err.Error())
+			failedChannelIDs = append(failedChannelIDs, channelID)
+			continue
+		}
 
-	resp, err := s.httpClient.Do(req)
-	if err != nil {
-		return err
+		if _, err := s.poster.PostMessage(channelID, announcementMsg); err != nil {
+			s.pluginAPI.Log.Warn("failed to broadcast the incident creation to channel", "ChannelID", channelID, "error", err.Error())
+			failedChannelIDs = append(failedChannelIDs, channelID)
+		}
 	}
 
-	defer resp.Body.Close()
-
-	if resp.StatusCode < 200 || resp.StatusCode > 299 {
-		return errors.Errorf("response code is %d; expected a status code in the 2xx range", resp.StatusCode)
+	if len(failedChannelIDs) > 0 {
+		return errors.Errorf("failed to announce the incident in channels %s", strings.Join(failedChannelIDs, ", "))
 	}
 
 	return nil
@@ -177,6 +135,16 @@ func (s *ServiceImpl) sendWebhookOnCreation(theIncident Incident) error {
 
 // CreateIncident creates a new incident. userID is the user who initiated the CreateIncident.
 func (s *ServiceImpl) CreateIncident(incdnt *Incident, pb *playbook.Playbook, userID string, public bool) (*Incident, error) {
+	// The user on call in the playbook's rotation, if anybody is, takes over from its fixed default owner.
+	if pb != nil && pb.DefaultOwnerEnabled && pb.DefaultOwnerRotationID != "" {
+		onCallUserID, err := s.onCall.UserOnCall(pb.DefaultOwnerRotationID, model.GetMillis())
+		if err != nil {
+			s.pluginAPI.Log.Warn("failed to get the user on call for the default owner", "rotationID", pb.DefaultOwnerRotationID, "err", err.Error())
+		} else if onCallUserID != "" {
+			incdnt.DefaultOwnerID = onCallUserID
+		}
+	}
+
 	if incdnt.DefaultOwnerID != "" {
 		// Check if the user is a member of the incident's team
 		if !permissions.IsMemberOfTeamID(incdnt.DefaultOwnerID, incdnt.TeamID, s.pluginAPI) {
@@ -229,11 +197,19 @@ func (s *ServiceImpl) CreateIncident(incdnt *Incident, pb *playbook.Playbook, us
 		}
 	}
 
+	// Leave out the playbook items whose condition the new incident does not match.
+	incdnt.Checklists = playbook.FilterChecklists(incdnt.Checklists, incdnt.ConditionFields())
+	setChecklistItemDueDates(incdnt)
+
 	incdnt, err = s.store.CreateIncident(incdnt)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to create incident")
 	}
 
+	if err = s.scheduleOverdueItemsReminder(incdnt); err != nil {
+		s.pluginAPI.Log.Warn("failed to schedule the overdue tasks reminder", "incident_id", incdnt.ID, "error", err.Error())
+	}
+
 	s.telemetry.CreateIncident(incdnt, userID, public)
 
 	invitedUserIDs := incdnt.InvitedUserIDs
@@ -309,6 +285,10 @@ func (s *ServiceImpl) CreateIncident(incdnt *Incident, pb *playbook.Playbook, us
 		}
 	}
 
+	if pb != nil {
+		s.addAutoFollowers(incdnt, pb.AutoFollowerIDs)
+	}
+
 	reporter, err := s.pluginAPI.User.Get(incdnt.ReporterUserID)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to resolve user %s", incdnt.ReporterUserID)
@@ -329,11 +309,9 @@ func (s *ServiceImpl) CreateIncident(incdnt *Incident, pb *playbook.Playbook, us
 		return nil, errors.Wrapf(err, "failed to post to incident channel")
 	}
 
-	if incdnt.AnnouncementChannelID != "" {
-		if err2 := s.broadcastIncidentCreation(incdnt, owner); err2 != nil {
-			s.pluginAPI.Log.Warn("failed to broadcast the incident creation to channel", "ChannelID", incdnt.AnnouncementChannelID)
-
-			if _, err = s.poster.PostMessage(channel.Id, "Failed to announce the creation of this incident in the configured channel."); err != nil {
+	if channelIDs := announcementChannelIDs(incdnt); len(channelIDs) > 0 {
+		if err2 := s.broadcastIncidentCreation(incdnt, owner, channelIDs); err2 != nil {
+			if _, err = s.poster.PostMessage(channel.Id, "Failed to announce the creation of this incident in the configured channels."); err != nil {
 				return nil, errors.Wrapf(err, "failed to post to incident channel")
 			}
 		}
@@ -348,18 +326,24 @@ func (s *ServiceImpl) CreateIncident(incdnt *Incident, pb *playbook.Playbook, us
 		SubjectUserID: incdnt.ReporterUserID,
 	}
 
-	if _, err = s.store.CreateTimelineEvent(event); err != nil {
+	if err = s.createTimelineEvent(incdnt, event); err != nil {
 		return incdnt, errors.Wrap(err, "failed to create timeline event")
 	}
 	incdnt.TimelineEvents = append(incdnt.TimelineEvents, *event)
 
+	s.runAutomaticActions(incdnt, userID, -1, -1)
+
+	if incdnt.AcknowledgeTimeoutSeconds > 0 {
+		if err = s.requestAcknowledgment(incdnt); err != nil {
+			s.pluginAPI.Log.Warn("failed to request the acknowledgment of the incident", "incident_id", incdnt.ID, "error", err.Error())
+		}
+	}
+
 	if incdnt.WebhookOnCreationURL != "" {
-		go func() {
-			if err = s.sendWebhookOnCreation(*incdnt); err != nil {
-				s.pluginAPI.Log.Warn("failed to send a POST request to the creation webhook URL", "webhook URL", incdnt.WebhookOnCreationURL, "error", err)
-				_, _ = s.poster.PostMessage(channel.Id, "Incident creation announcement through the outgoing webhook failed. Contact your System Admin for more information.")
-			}
-		}()
+		if err = s.queueWebhook(*incdnt, WebhookEventIncidentCreated, incdnt.WebhookOnCreationURL); err != nil {
+			s.pluginAPI.Log.Warn("failed to queue the creation webhook", "webhook URL", incdnt.WebhookOnCreationURL, "error", err)
+			_, _ = s.poster.PostMessage(channel.Id, "Incident creation announcement through the outgoing webhook failed. Contact your System Admin for more information.")
+		}
 	}
 
 	if incdnt.PostID == "" {
@@ -383,6 +367,42 @@ func (s *ServiceImpl) CreateIncident(incdnt *Incident, pb *playbook.Playbook, us
 	return incdnt, nil
 }
 
+// ImportIncident creates an incident, with its timeline events, status posts and followers, from
+// an archive exported from another server.
+func (s *ServiceImpl) ImportIncident(incdnt *Incident, timelineEvents []TimelineEvent, statusPosts []StatusPost) (*Incident, error) {
+	// The followers are not saved with the incident.
+	followerIDs := incdnt.FollowerIDs
+
+	incdnt, err := s.store.CreateIncident(incdnt)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create incident")
+	}
+
+	if err = s.store.AddStatusPosts(incdnt.ID, statusPosts); err != nil {
+		return nil, errors.Wrapf(err, "failed to add status posts to incident %s", incdnt.ID)
+	}
+	incdnt.StatusPosts = append([]StatusPost(nil), statusPosts...)
+
+	for _, followerID := range followerIDs {
+		if err = s.store.Follow(incdnt.ID, followerID); err != nil {
+			return nil, errors.Wrapf(err, "failed to add follower to incident %s", incdnt.ID)
+		}
+	}
+	incdnt.FollowerIDs = append([]string(nil), followerIDs...)
+
+	for i := range timelineEvents {
+		event := timelineEvents[i]
+		event.IncidentID = incdnt.ID
+		created, err2 := s.store.CreateTimelineEvent(&event)
+		if err2 != nil {
+			return nil, errors.Wrapf(err2, "failed to create timeline event for incident %s", incdnt.ID)
+		}
+		incdnt.TimelineEvents = append(incdnt.TimelineEvents, *created)
+	}
+
+	return incdnt, nil
+}
+
 // OpenCreateIncidentDialog opens a interactive dialog to start a new incident.
 func (s *ServiceImpl) OpenCreateIncidentDialog(teamID, ownerID, triggerID, postID, clientID string, playbooks []playbook.Playbook, isMobileApp bool) error {
 	dialog, err := s.newIncidentDialog(teamID, ownerID, postID, clientID, playbooks, isMobileApp)
@@ -410,9 +430,15 @@ func (s *ServiceImpl) OpenUpdateStatusDialog(incidentID string, triggerID string
 		return errors.Wrap(err, "failed to retrieve incident")
 	}
 
+	if currentIncident.CurrentStatus == StatusMerged {
+		return errors.Wrap(ErrIncidentMerged, "cannot update the status of a merged incident")
+	}
+
 	message := ""
 	newestPostID := findNewestNonDeletedPostID(currentIncident.StatusPosts)
-	if newestPostID != "" {
+	if currentIncident.StatusUpdateTemplate != "" {
+		message = s.renderStatusUpdateTemplate(currentIncident)
+	} else if newestPostID != "" {
 		var post *model.Post
 		post, err = s.pluginAPI.Post.GetPost(newestPostID)
 		if err != nil {
@@ -423,7 +449,7 @@ func (s *ServiceImpl) OpenUpdateStatusDialog(incidentID string, triggerID string
 		message = currentIncident.ReminderMessageTemplate
 	}
 
-	dialog, err := s.newUpdateIncidentDialog(currentIncident.Description, message, currentIncident.BroadcastChannelID, currentIncident.CurrentStatus, currentIncident.PreviousReminder)
+	dialog, err := s.newUpdateIncidentDialog(currentIncident.Description, message, currentIncident.BroadcastChannelID, len(currentIncident.BroadcastTargets), currentIncident.CurrentStatus, currentIncident.PreviousReminder, currentIncident.CustomFields, currentIncident.CustomFieldValues)
 	if err != nil {
 		return errors.Wrap(err, "failed to create update status dialog")
 	}
@@ -518,6 +544,11 @@ func (s *ServiceImpl) AddPostToTimeline(incidentID, userID, postID, summary stri
 		return errors.Wrap(err, "failed to find post")
 	}
 
+	incidentModified, err := s.store.GetIncident(incidentID)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve incident")
+	}
+
 	event := &TimelineEvent{
 		IncidentID:    incidentID,
 		CreateAt:      model.GetMillis(),
@@ -531,15 +562,10 @@ func (s *ServiceImpl) AddPostToTimeline(incidentID, userID, postID, summary stri
 		CreatorUserID: userID,
 	}
 
-	if _, err = s.store.CreateTimelineEvent(event); err != nil {
+	if err = s.createTimelineEvent(incidentModified, event); err != nil {
 		return errors.Wrap(err, "failed to create timeline event")
 	}
 
-	incidentModified, err := s.store.GetIncident(incidentID)
-	if err != nil {
-		return errors.Wrap(err, "failed to retrieve incident")
-	}
-
 	s.telemetry.AddPostToTimeline(incidentModified, userID)
 
 	if err = s.sendIncidentToClient(incidentID); err != nil {
@@ -575,7 +601,16 @@ func (s *ServiceImpl) RemoveTimelineEvent(incidentID, userID, eventID string) er
 	return nil
 }
 
-func (s *ServiceImpl) broadcastStatusUpdate(statusUpdate string, theIncident *Incident, authorID, originalPostID string) error {
+// broadcastStatusUpdate broadcasts statusUpdate, posted in the incident channel as originalPostID,
+// to the broadcast channel of theIncident and to the broadcast targets whose filter it passes.
+// statusChanged tells whether the update changed the status of the incident. A channel where the
+// update cannot be posted does not stop the others.
+func (s *ServiceImpl) broadcastStatusUpdate(statusUpdate string, theIncident *Incident, authorID, originalPostID string, statusChanged bool) error {
+	channelIDs := statusUpdateChannelIDs(theIncident, statusChanged)
+	if len(channelIDs) == 0 {
+		return nil
+	}
+
 	incidentChannel, err := s.pluginAPI.Channel.Get(theIncident.ChannelID)
 	if err != nil {
 		return err
@@ -598,66 +633,10 @@ func (s *ServiceImpl) broadcastStatusUpdate(statusUpdate string, theIncident *In
 	broadcastedMsg += "***\n"
 	broadcastedMsg += statusUpdate
 
-	if _, err := s.poster.PostMessage(theIncident.BroadcastChannelID, broadcastedMsg); err != nil {
-		return err
-	}
-
-	return nil
-}
-
-// sendWebhookOnUpdateStatus sends a POST request to the status update webhook URL.
-// It blocks until a response is received.
-func (s *ServiceImpl) sendWebhookOnUpdateStatus(theIncident Incident) error {
-	siteURL := s.pluginAPI.Configuration.GetConfig().ServiceSettings.SiteURL
-	if siteURL == nil {
-		s.pluginAPI.Log.Warn("cannot send webhook on update, please set siteURL")
-		return errors.New("siteURL not set")
-	}
-
-	team, err := s.pluginAPI.Team.Get(theIncident.TeamID)
-	if err != nil {
-		return err
-	}
-
-	channel, err := s.pluginAPI.Channel.Get(theIncident.ChannelID)
-	if err != nil {
-		return err
-	}
-
-	channelURL := getChannelURL(*siteURL, team.Name, channel.Name)
-
-	detailsURL := getDetailsURL(*siteURL, team.Name, s.configService.GetManifest().Id, theIncident.ID)
-
-	payload := struct {
-		Incident
-		ChannelURL string `json:"channel_url"`
-		DetailsURL string `json:"details_url"`
-	}{
-		Incident:   theIncident,
-		ChannelURL: channelURL,
-		DetailsURL: detailsURL,
-	}
-
-	body, err := json.Marshal(payload)
-	if err != nil {
-		return err
-	}
-
-	req, err := http.NewRequest("POST", theIncident.WebhookOnStatusUpdateURL, bytes.NewReader(body))
-	if err != nil {
-		return err
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := s.httpClient.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode < 200 || resp.StatusCode > 299 {
-		return errors.Errorf("response code is %d; expected a status code in the 2xx range", resp.StatusCode)
+	for _, channelID := range channelIDs {
+		if _, err := s.poster.PostMessage(channelID, broadcastedMsg); err != nil {
+			s.pluginAPI.Log.Warn("failed to broadcast the status update to channel", "ChannelID", channelID, "error", err.Error())
+		}
 	}
 
 	return nil
@@ -670,8 +649,17 @@ func (s *ServiceImpl) UpdateStatus(incidentID, userID string, options StatusUpda
 		return errors.Wrap(err, "failed to retrieve incident")
 	}
 
+	// The status of a merged incident is the one of the incident it was merged into.
+	if incidentToModify.CurrentStatus == StatusMerged {
+		return errors.Wrap(ErrIncidentMerged, "cannot update the status of a merged incident")
+	}
+
 	previousStatus := incidentToModify.CurrentStatus
 	incidentToModify.CurrentStatus = options.Status
+	incidentToModify.Description = options.Description
+	if options.CustomFieldValues != nil {
+		incidentToModify.CustomFieldValues = options.CustomFieldValues
+	}
 
 	post := model.Post{
 		Message:   options.Message,
@@ -692,7 +680,15 @@ func (s *ServiceImpl) UpdateStatus(incidentID, userID string, options StatusUpda
 		})
 
 	incidentToModify.PreviousReminder = options.Reminder
-	incidentToModify.Description = options.Description
+
+	// Seed the retrospective with a draft, unless it was already written.
+	if options.Status == StatusResolved &&
+		previousStatus != StatusArchived &&
+		previousStatus != StatusResolved &&
+		incidentToModify.RetrospectivePublishedAt == 0 &&
+		incidentToModify.hasUneditedRetrospective() {
+		incidentToModify.Retrospective = s.retrospectiveDraft(incidentToModify)
+	}
 
 	if err = s.store.UpdateIncident(incidentToModify); err != nil {
 		return errors.Wrap(err, "failed to update incident")
@@ -707,10 +703,12 @@ func (s *ServiceImpl) UpdateStatus(incidentID, userID string, options StatusUpda
 		return errors.Wrap(err, "failed to write status post to store. There is now inconsistent state.")
 	}
 
-	if err2 := s.broadcastStatusUpdate(options.Message, incidentToModify, userID, post.Id); err2 != nil {
-		s.pluginAPI.Log.Warn("failed to broadcast the status update to channel", "ChannelID", incidentToModify.BroadcastChannelID)
+	if err2 := s.broadcastStatusUpdate(options.Message, incidentToModify, userID, post.Id, previousStatus != options.Status); err2 != nil {
+		s.pluginAPI.Log.Warn("failed to broadcast the status update", "IncidentID", incidentToModify.ID, "error", err2.Error())
 	}
 
+	s.notifyFollowers(incidentToModify, userID, options.Message)
+
 	// If we are resolving the incident, send the reminder to fill out the retrospective
 	// Also start the recurring reminder if enabled.
 	if incidentToModify.RetrospectivePublishedAt == 0 &&
@@ -729,6 +727,14 @@ func (s *ServiceImpl) UpdateStatus(incidentID, userID string, options StatusUpda
 		}
 	}
 
+	if options.Status == StatusResolved &&
+		previousStatus != StatusArchived &&
+		previousStatus != StatusResolved {
+		if err = s.offerToResolveChildren(incidentToModify); err != nil {
+			s.pluginAPI.Log.Warn("failed to offer to resolve the child incidents", "incident_id", incidentID, "error", err.Error())
+		}
+	}
+
 	// Remove pending reminder (if any), even if current reminder was set to "none" (0 minutes)
 	s.RemoveReminder(incidentID)
 
@@ -756,7 +762,7 @@ func (s *ServiceImpl) UpdateStatus(incidentID, userID string, options StatusUpda
 		SubjectUserID: userID,
 	}
 
-	if _, err = s.store.CreateTimelineEvent(event); err != nil {
+	if err = s.createTimelineEvent(incidentToModify, event); err != nil {
 		return errors.Wrap(err, "failed to create timeline event")
 	}
 
@@ -767,12 +773,10 @@ func (s *ServiceImpl) UpdateStatus(incidentID, userID string, options StatusUpda
 	}
 
 	if incidentToModify.WebhookOnStatusUpdateURL != "" {
-		go func() {
-			if err := s.sendWebhookOnUpdateStatus(*incidentToModify); err != nil {
-				s.pluginAPI.Log.Warn("failed to send a POST request to the update status webhook URL", "webhook URL", incidentToModify.WebhookOnStatusUpdateURL, "error", err)
-				_, _ = s.poster.PostMessage(incidentToModify.ChannelID, "Incident update announcement through the outgoing webhook failed. Contact your System Admin for more information.")
-			}
-		}()
+		if err = s.queueWebhook(*incidentToModify, WebhookEventStatusUpdated, incidentToModify.WebhookOnStatusUpdateURL); err != nil {
+			s.pluginAPI.Log.Warn("failed to queue the update status webhook", "webhook URL", incidentToModify.WebhookOnStatusUpdateURL, "error", err)
+			_, _ = s.poster.PostMessage(incidentToModify.ChannelID, "Incident update announcement through the outgoing webhook failed. Contact your System Admin for more information.")
+		}
 	}
 
 	return nil
@@ -864,6 +868,12 @@ func (s *ServiceImpl) GetIncidentIDForChannel(channelID string) (string, error)
 	return incidentID, nil
 }
 
+// GetIncidentIDForAlert returns the ID of the ongoing incident started by the playbook's inbound
+// webhook for the alert with the given fingerprint. Returns ErrNotFound if there is none.
+func (s *ServiceImpl) GetIncidentIDForAlert(playbookID, fingerprint string) (string, error) {
+	return s.store.GetIncidentIDForAlert(playbookID, fingerprint)
+}
+
 // GetOwners returns all the owners of the incidents selected by options
 func (s *ServiceImpl) GetOwners(requesterInfo permissions.RequesterInfo, options FilterOptions) ([]OwnerInfo, error) {
 	return s.store.GetOwners(requesterInfo, options)
@@ -922,7 +932,7 @@ func (s *ServiceImpl) ChangeOwner(incidentID, userID, ownerID string) error {
 		SubjectUserID: userID,
 	}
 
-	if _, err = s.store.CreateTimelineEvent(event); err != nil {
+	if err = s.createTimelineEvent(incidentToModify, event); err != nil {
 		return errors.Wrap(err, "failed to create timeline event")
 	}
 
@@ -935,6 +945,145 @@ func (s *ServiceImpl) ChangeOwner(incidentID, userID, ownerID string) error {
 	return nil
 }
 
+// ChangeSeverity processes a request from userID to change the severity of incidentID
+// to severity. Changing to the same severity is a no-op.
+func (s *ServiceImpl) ChangeSeverity(incidentID, userID, severity string) error {
+	if !IsValidSeverity(severity) {
+		return errors.Errorf("invalid severity '%s'", severity)
+	}
+
+	incidentToModify, err := s.store.GetIncident(incidentID)
+	if err != nil {
+		return err
+	}
+
+	if incidentToModify.Severity == severity {
+		return nil
+	}
+
+	oldSeverity := incidentToModify.Severity
+	incidentToModify.Severity = severity
+	if err = s.store.UpdateIncident(incidentToModify); err != nil {
+		return errors.Wrapf(err, "failed to update incident")
+	}
+
+	mainChannelID := incidentToModify.ChannelID
+	modifyMessage := fmt.Sprintf("changed the incident severity from **%s** to **%s**.",
+		SeverityDisplayName(oldSeverity), SeverityDisplayName(severity))
+	post, err := s.modificationMessage(userID, mainChannelID, modifyMessage)
+	if err != nil {
+		return err
+	}
+
+	event := &TimelineEvent{
+		IncidentID:    incidentID,
+		CreateAt:      post.CreateAt,
+		EventAt:       post.CreateAt,
+		EventType:     SeverityChanged,
+		Summary:       fmt.Sprintf("%s to %s", SeverityDisplayName(oldSeverity), SeverityDisplayName(severity)),
+		PostID:        post.Id,
+		SubjectUserID: userID,
+	}
+
+	if err = s.createTimelineEvent(incidentToModify, event); err != nil {
+		return errors.Wrap(err, "failed to create timeline event")
+	}
+
+	if err = s.sendIncidentToClient(incidentID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AssignRole processes a request from userID to assign the role of incidentID with the key
+// roleKey to the users assigneeIDs, replacing its current users. An empty assigneeIDs unassigns
+// the role. Assigning the role to its current users is a no-op.
+func (s *ServiceImpl) AssignRole(incidentID, userID, roleKey string, assigneeIDs []string) error {
+	incidentToModify, err := s.store.GetIncident(incidentID)
+	if err != nil {
+		return err
+	}
+
+	role, ok := playbook.FindRole(incidentToModify.Roles, roleKey)
+	if !ok {
+		return errors.Wrapf(ErrUnknownRole, "incident has no role %s", roleKey)
+	}
+
+	assigneeIDs = uniqueIDs(assigneeIDs)
+	oldAssigneeIDs := incidentToModify.RoleUserIDs(roleKey)
+	if sameIDs(oldAssigneeIDs, assigneeIDs) {
+		return nil
+	}
+
+	oldAssignees, err := s.usernameList(oldAssigneeIDs)
+	if err != nil {
+		return err
+	}
+	newAssignees, err := s.usernameList(assigneeIDs)
+	if err != nil {
+		return err
+	}
+
+	if incidentToModify.RoleAssignments == nil {
+		incidentToModify.RoleAssignments = map[string][]string{}
+	}
+	if len(assigneeIDs) == 0 {
+		delete(incidentToModify.RoleAssignments, roleKey)
+	} else {
+		incidentToModify.RoleAssignments[roleKey] = assigneeIDs
+	}
+	if err = s.store.UpdateIncident(incidentToModify); err != nil {
+		return errors.Wrapf(err, "failed to update incident")
+	}
+
+	mainChannelID := incidentToModify.ChannelID
+	modifyMessage := fmt.Sprintf("changed the **%s** role from **%s** to **%s**.",
+		role.Name, oldAssignees, newAssignees)
+	post, err := s.modificationMessage(userID, mainChannelID, modifyMessage)
+	if err != nil {
+		return err
+	}
+
+	event := &TimelineEvent{
+		IncidentID:    incidentID,
+		CreateAt:      post.CreateAt,
+		EventAt:       post.CreateAt,
+		EventType:     RoleChanged,
+		Summary:       fmt.Sprintf("%s: %s to %s", role.Name, oldAssignees, newAssignees),
+		PostID:        post.Id,
+		SubjectUserID: userID,
+	}
+
+	if err = s.createTimelineEvent(incidentToModify, event); err != nil {
+		return errors.Wrap(err, "failed to create timeline event")
+	}
+
+	if err = s.sendIncidentToClient(incidentID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// usernameList returns the usernames of userIDs, each prefixed with @ and separated by commas, or
+// noAssigneeName if userIDs is empty.
+func (s *ServiceImpl) usernameList(userIDs []string) (string, error) {
+	if len(userIDs) == 0 {
+		return noAssigneeName, nil
+	}
+
+	usernames := make([]string, 0, len(userIDs))
+	for _, id := range userIDs {
+		user, err := s.pluginAPI.User.Get(id)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to to resolve user %s", id)
+		}
+		usernames = append(usernames, "@"+user.Username)
+	}
+	return strings.Join(usernames, ", "), nil
+}
+
 // ModifyCheckedState checks or unchecks the specified checklist item. Idempotent, will not perform
 // any action if the checklist item is already in the given checked state
 func (s *ServiceImpl) ModifyCheckedState(incidentID, userID, newState string, checklistNumber, itemNumber int) error {
@@ -952,6 +1101,16 @@ func (s *ServiceImpl) ModifyCheckedState(incidentID, userID, newState string, ch
 		return nil
 	}
 
+	if newState != playbook.ChecklistItemStateOpen {
+		if blockingItems := playbook.BlockingItems(incidentToModify.Checklists, itemToCheck); len(blockingItems) > 0 {
+			titles := make([]string, 0, len(blockingItems))
+			for _, item := range blockingItems {
+				titles = append(titles, fmt.Sprintf("'%s'", item.Title))
+			}
+			return errors.Wrapf(ErrChecklistItemBlocked, "item '%s' is waiting for %s", itemToCheck.Title, strings.Join(titles, ", "))
+		}
+	}
+
 	// Send modification message before the actual modification because we need the postID
 	// from the notification message.
 	mainChannelID := incidentToModify.ChannelID
@@ -985,10 +1144,12 @@ func (s *ServiceImpl) ModifyCheckedState(incidentID, userID, newState string, ch
 		SubjectUserID: userID,
 	}
 
-	if _, err = s.store.CreateTimelineEvent(event); err != nil {
+	if err = s.createTimelineEvent(incidentToModify, event); err != nil {
 		return errors.Wrap(err, "failed to create timeline event")
 	}
 
+	s.runAutomaticActions(incidentToModify, userID, checklistNumber, itemNumber)
+
 	if err = s.sendIncidentToClient(incidentID); err != nil {
 		return err
 	}
@@ -1083,7 +1244,80 @@ func (s *ServiceImpl) SetAssignee(incidentID, userID, assigneeID string, checkli
 		SubjectUserID: userID,
 	}
 
-	if _, err = s.store.CreateTimelineEvent(event); err != nil {
+	if err = s.createTimelineEvent(incidentToModify, event); err != nil {
+		return errors.Wrap(err, "failed to create timeline event")
+	}
+
+	if err = s.sendIncidentToClient(incidentID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SetAssigneeRole assigns the specified checklist item to the role with the key roleKey, or
+// removes its role if roleKey is blank.
+// Idempotent, will not perform any actions if the checklist item is already assigned to roleKey
+func (s *ServiceImpl) SetAssigneeRole(incidentID, userID, roleKey string, checklistNumber, itemNumber int) error {
+	incidentToModify, err := s.checklistItemParamsVerify(incidentID, userID, checklistNumber, itemNumber)
+	if err != nil {
+		return err
+	}
+
+	if !playbook.IsValidChecklistItemIndex(incidentToModify.Checklists, checklistNumber, itemNumber) {
+		return errors.New("invalid checklist item indices")
+	}
+
+	itemToCheck := incidentToModify.Checklists[checklistNumber].Items[itemNumber]
+	if roleKey == itemToCheck.AssigneeRole {
+		return nil
+	}
+
+	newRoleName := noAssigneeName
+	if roleKey != "" {
+		role, ok := playbook.FindRole(incidentToModify.Roles, roleKey)
+		if !ok {
+			return errors.Wrapf(ErrUnknownRole, "incident has no role %s", roleKey)
+		}
+		newRoleName = role.Name
+	}
+
+	oldRoleName := noAssigneeName
+	if oldRole, ok := playbook.FindRole(incidentToModify.Roles, itemToCheck.AssigneeRole); ok {
+		oldRoleName = oldRole.Name
+	}
+
+	mainChannelID := incidentToModify.ChannelID
+	modifyMessage := fmt.Sprintf("changed the role of checklist item **%s** from **%s** to **%s**",
+		stripmd.Strip(itemToCheck.Title), oldRoleName, newRoleName)
+
+	// Send modification message before the actual modification because we need the postID
+	// from the notification message.
+	post, err := s.modificationMessage(userID, mainChannelID, modifyMessage)
+	if err != nil {
+		return err
+	}
+
+	itemToCheck.AssigneeRole = roleKey
+	itemToCheck.AssigneeModified = model.GetMillis()
+	itemToCheck.AssigneeModifiedPostID = post.Id
+	incidentToModify.Checklists[checklistNumber].Items[itemNumber] = itemToCheck
+
+	if err = s.store.UpdateIncident(incidentToModify); err != nil {
+		return errors.Wrapf(err, "failed to update incident; it is now in an inconsistent state")
+	}
+
+	event := &TimelineEvent{
+		IncidentID:    incidentID,
+		CreateAt:      itemToCheck.AssigneeModified,
+		EventAt:       itemToCheck.AssigneeModified,
+		EventType:     AssigneeChanged,
+		Summary:       modifyMessage,
+		PostID:        post.Id,
+		SubjectUserID: userID,
+	}
+
+	if err = s.createTimelineEvent(incidentToModify, event); err != nil {
 		return errors.Wrap(err, "failed to create timeline event")
 	}
 
@@ -1146,7 +1380,7 @@ func (s *ServiceImpl) RunChecklistItemSlashCommand(incidentID, userID string, ch
 		SubjectUserID: userID,
 	}
 
-	if _, err = s.store.CreateTimelineEvent(event); err != nil {
+	if err = s.createTimelineEvent(incident, event); err != nil {
 		return "", errors.Wrap(err, "failed to create timeline event")
 	}
 
@@ -1164,12 +1398,40 @@ func (s *ServiceImpl) AddChecklistItem(incidentID, userID string, checklistNumbe
 		return err
 	}
 
+	if checklistItem.DueAfterSeconds < 0 {
+		return errors.Errorf("negative due time of %d seconds", checklistItem.DueAfterSeconds)
+	}
+
+	// Automatic actions are only configured, and checked against the permissions of their
+	// author, in playbooks.
+	checklistItem.AutomaticAction = playbook.ItemAction{}
+	checklistItem.ActionLastRun = 0
+
+	// The due date of an item added to a running incident counts from when it is added.
+	checklistItem.DueAt = 0
+	checklistItem.OverdueNotified = false
+	if checklistItem.DueAfterSeconds > 0 {
+		checklistItem.DueAt = model.GetMillis() + checklistItem.DueAfterSeconds*1000
+	}
+
 	incidentToModify.Checklists[checklistNumber].Items = append(incidentToModify.Checklists[checklistNumber].Items, checklistItem)
 
+	if err = playbook.ValidatePrerequisites(incidentToModify.Checklists); err != nil {
+		return errors.Wrap(err, "invalid prerequisites")
+	}
+
 	if err = s.store.UpdateIncident(incidentToModify); err != nil {
 		return errors.Wrapf(err, "failed to update incident")
 	}
 
+	// The new item may be due before the items the reminder is scheduled for.
+	if checklistItem.DueAt != 0 {
+		s.scheduler.Cancel(DueItemsPrefix + incidentID)
+		if err = s.scheduleOverdueItemsReminder(incidentToModify); err != nil {
+			s.logger.Errorf(errors.Wrapf(err, "failed to reschedule the overdue tasks reminder of incident id: %s", incidentID).Error())
+		}
+	}
+
 	s.poster.PublishWebsocketEventToChannel(incidentUpdatedWSEvent, incidentToModify, incidentToModify.ChannelID)
 	s.telemetry.AddTask(incidentID, userID, checklistItem)
 
@@ -1188,6 +1450,7 @@ func (s *ServiceImpl) RemoveChecklistItem(incidentID, userID string, checklistNu
 		incidentToModify.Checklists[checklistNumber].Items[:itemNumber],
 		incidentToModify.Checklists[checklistNumber].Items[itemNumber+1:]...,
 	)
+	playbook.RemovePrerequisite(incidentToModify.Checklists, checklistItem.ID)
 
 	if err = s.store.UpdateIncident(incidentToModify); err != nil {
 		return errors.Wrapf(err, "failed to update incident")
@@ -1357,6 +1620,12 @@ func (s *ServiceImpl) UserHasJoinedChannel(userID, channelID, actorID string) {
 		return
 	}
 
+	theIncident, err := s.store.GetIncident(incidentID)
+	if err != nil {
+		s.logger.Errorf("failed to get incident for incidentID: %s; error: %s", incidentID, err.Error())
+		return
+	}
+
 	user, err := s.pluginAPI.User.Get(userID)
 	if err != nil {
 		s.logger.Errorf("failed to resolve user for userID: %s; error: %s", userID, err.Error())
@@ -1393,7 +1662,7 @@ func (s *ServiceImpl) UserHasJoinedChannel(userID, channelID, actorID string) {
 		CreatorUserID: actorID,
 	}
 
-	if _, err = s.store.CreateTimelineEvent(event); err != nil {
+	if err = s.createTimelineEvent(theIncident, event); err != nil {
 		s.logger.Errorf("failed to create timeline event; error: %s", err.Error())
 	}
 
@@ -1450,6 +1719,12 @@ func (s *ServiceImpl) UserHasLeftChannel(userID, channelID, actorID string) {
 		return
 	}
 
+	theIncident, err := s.store.GetIncident(incidentID)
+	if err != nil {
+		s.logger.Errorf("failed to get incident for incidentID: %s; error: %s", incidentID, err.Error())
+		return
+	}
+
 	user, err := s.pluginAPI.User.Get(userID)
 	if err != nil {
 		s.logger.Errorf("failed to resolve user for userID: %s; error: %s", userID, err.Error())
@@ -1486,7 +1761,7 @@ func (s *ServiceImpl) UserHasLeftChannel(userID, channelID, actorID string) {
 		CreatorUserID: actorID,
 	}
 
-	if _, err = s.store.CreateTimelineEvent(event); err != nil {
+	if err = s.createTimelineEvent(theIncident, event); err != nil {
 		s.logger.Errorf("failed to create timeline event; error: %s", err.Error())
 	}
 
@@ -1603,31 +1878,59 @@ func (s *ServiceImpl) newIncidentDialog(teamID, ownerID, postID, clientID string
 
 	introText := fmt.Sprintf("**Owner:** %v\n\nPlaybooks are necessary to start an incident.%s", getUserDisplayName(user), newPlaybookMarkdown)
 
+	severityOptions := make([]*model.PostActionOptions, 0, len(Severities))
+	for _, severity := range Severities {
+		severityOptions = append(severityOptions, &model.PostActionOptions{
+			Text:  SeverityDisplayName(severity),
+			Value: severity,
+		})
+	}
+
+	elements := []model.DialogElement{
+		{
+			DisplayName: "Playbook",
+			Name:        DialogFieldPlaybookIDKey,
+			Type:        "select",
+			Options:     options,
+		},
+		{
+			DisplayName: "Incident Name",
+			Name:        DialogFieldNameKey,
+			Type:        "text",
+			MinLength:   2,
+			MaxLength:   64,
+		},
+		{
+			DisplayName: "Severity",
+			Name:        DialogFieldSeverityKey,
+			Type:        "select",
+			Options:     severityOptions,
+			Optional:    true,
+		},
+	}
+
+	// The custom fields depend on the playbook, which is not known until the dialog is submitted:
+	// list the fields of every playbook, named after it, and only read and check those of the
+	// chosen playbook on submission.
+	for _, pb := range playbooks {
+		for _, field := range pb.CustomFields {
+			element := customFieldDialogElement(pb.ID, field, nil, true)
+			element.HelpText = truncateRunes(strings.TrimSpace(fmt.Sprintf("Only for the playbook %s. %s", pb.Title, element.HelpText)), dialogHelpTextMaxLength)
+			elements = append(elements, element)
+		}
+	}
+
 	return &model.Dialog{
 		Title:            "Incident Details",
 		IntroductionText: introText,
-		Elements: []model.DialogElement{
-			{
-				DisplayName: "Playbook",
-				Name:        DialogFieldPlaybookIDKey,
-				Type:        "select",
-				Options:     options,
-			},
-			{
-				DisplayName: "Incident Name",
-				Name:        DialogFieldNameKey,
-				Type:        "text",
-				MinLength:   2,
-				MaxLength:   64,
-			},
-		},
-		SubmitLabel:    "Start Incident",
-		NotifyOnCancel: false,
-		State:          string(state),
+		Elements:         elements,
+		SubmitLabel:      "Start Incident",
+		NotifyOnCancel:   false,
+		State:            string(state),
 	}, nil
 }
 
-func (s *ServiceImpl) newUpdateIncidentDialog(description, message, broadcastChannelID, status string, reminderTimer time.Duration) (*model.Dialog, error) {
+func (s *ServiceImpl) newUpdateIncidentDialog(description, message, broadcastChannelID string, numBroadcastTargets int, status string, reminderTimer time.Duration, customFields []playbook.CustomField, customFieldValues map[string][]string) (*model.Dialog, error) {
 	introductionText := "Update your incident status."
 
 	broadcastChannel, err := s.pluginAPI.Channel.Get(broadcastChannelID)
@@ -1645,6 +1948,10 @@ func (s *ServiceImpl) newUpdateIncidentDialog(description, message, broadcastCha
 
 	}
 
+	if numBroadcastTargets > 0 {
+		introductionText += " Depending on its status and severity, it may also be broadcasted to the other channels configured in the playbook."
+	}
+
 	reminderOptions := []*model.PostActionOptions{
 		{
 			Text:  "None",
@@ -1700,41 +2007,47 @@ func (s *ServiceImpl) newUpdateIncidentDialog(description, message, broadcastCha
 		}
 	}
 
+	elements := []model.DialogElement{
+		{
+			DisplayName: "Status",
+			Name:        DialogFieldStatusKey,
+			Type:        "select",
+			Options:     statusOptions,
+			Optional:    false,
+			Default:     status,
+		},
+		{
+			DisplayName: "Description",
+			Name:        DialogFieldDescriptionKey,
+			Type:        "textarea",
+			Default:     description,
+		},
+		{
+			DisplayName: "Change since last update",
+			Name:        DialogFieldMessageKey,
+			Type:        "textarea",
+			Default:     message,
+		},
+		{
+			DisplayName: "Reminder for next update",
+			Name:        DialogFieldReminderInSecondsKey,
+			Type:        "select",
+			Options:     reminderOptions,
+			Optional:    true,
+			Default:     fmt.Sprintf("%d", reminderTimer/time.Second),
+		},
+	}
+
+	for _, field := range customFields {
+		elements = append(elements, customFieldDialogElement("", field, customFieldValues[field.Key], !field.Required))
+	}
+
 	return &model.Dialog{
 		Title:            "Update Incident Status",
 		IntroductionText: introductionText,
-		Elements: []model.DialogElement{
-			{
-				DisplayName: "Status",
-				Name:        DialogFieldStatusKey,
-				Type:        "select",
-				Options:     statusOptions,
-				Optional:    false,
-				Default:     status,
-			},
-			{
-				DisplayName: "Description",
-				Name:        DialogFieldDescriptionKey,
-				Type:        "textarea",
-				Default:     description,
-			},
-			{
-				DisplayName: "Change since last update",
-				Name:        DialogFieldMessageKey,
-				Type:        "textarea",
-				Default:     message,
-			},
-			{
-				DisplayName: "Reminder for next update",
-				Name:        DialogFieldReminderInSecondsKey,
-				Type:        "select",
-				Options:     reminderOptions,
-				Optional:    true,
-				Default:     fmt.Sprintf("%d", reminderTimer/time.Second),
-			},
-		},
-		SubmitLabel:    "Update Status",
-		NotifyOnCancel: false,
+		Elements:         elements,
+		SubmitLabel:      "Update Status",
+		NotifyOnCancel:   false,
 	}, nil
 }
 
@@ -1858,7 +2171,8 @@ func (s *ServiceImpl) PublishRetrospective(incidentID, text, publisherID string)
 		s.configService.GetManifest().Id,
 		incidentToPublish.ID,
 	)
-	if _, err = s.poster.PostMessage(incidentToPublish.ChannelID, "@channel Retrospective has been published by @%s\n[See the full retrospective](%s)\n%s", publisherUser.Username, retrospectiveURL, text); err != nil {
+	message := renderRetrospective(text, incidentToPublish.RetrospectiveSections, incidentToPublish.ActionItems, s.actionItemOwnerUsernames(incidentToPublish.ActionItems))
+	if _, err = s.poster.PostMessage(incidentToPublish.ChannelID, "@channel Retrospective has been published by @%s\n[See the full retrospective](%s)\n%s", publisherUser.Username, retrospectiveURL, message); err != nil {
 		return errors.Wrap(err, "failed to post to channel")
 	}
 
@@ -1870,7 +2184,7 @@ func (s *ServiceImpl) PublishRetrospective(incidentID, text, publisherID string)
 		SubjectUserID: publisherID,
 	}
 
-	if _, err = s.store.CreateTimelineEvent(event); err != nil {
+	if err = s.createTimelineEvent(incidentToPublish, event); err != nil {
 		return errors.Wrap(err, "failed to create timeline event")
 	}
 
@@ -1915,7 +2229,7 @@ func (s *ServiceImpl) CancelRetrospective(incidentID, cancelerID string) error {
 		SubjectUserID: cancelerID,
 	}
 
-	if _, err = s.store.CreateTimelineEvent(event); err != nil {
+	if err = s.createTimelineEvent(incidentToCancel, event); err != nil {
 		return errors.Wrap(err, "failed to create timeline event")
 	}
 
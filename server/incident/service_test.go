@@ -6,8 +6,10 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 
 	"github.com/golang/mock/gomock"
 	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/config"
@@ -52,7 +54,7 @@ func TestCreateIncident(t *testing.T) {
 		mattermostConfig.SetDefaults()
 		pluginAPI.On("GetConfig").Return(mattermostConfig)
 
-		s := incident.NewService(client, store, poster, logger, configService, scheduler, telemetryService)
+		s := incident.NewService(client, store, poster, logger, configService, scheduler, telemetryService, nil)
 
 		_, err := s.CreateIncident(incdnt, nil, "testUserID", true)
 		require.Equal(t, err, incident.ErrChannelDisplayNameInvalid)
@@ -82,7 +84,7 @@ func TestCreateIncident(t *testing.T) {
 		pluginAPI.On("GetConfig").Return(mattermostConfig)
 		pluginAPI.On("CreateChannel", mock.Anything).Return(nil, &model.AppError{Id: "model.channel.is_valid.2_or_more.app_error"})
 
-		s := incident.NewService(client, store, poster, logger, configService, scheduler, telemetryService)
+		s := incident.NewService(client, store, poster, logger, configService, scheduler, telemetryService, nil)
 
 		_, err := s.CreateIncident(incdnt, nil, "testUserID", true)
 		require.Equal(t, err, incident.ErrChannelDisplayNameInvalid)
@@ -131,7 +133,7 @@ func TestCreateIncident(t *testing.T) {
 		poster.EXPECT().PostMessage("channel_id", "This incident has been started and is commanded by @username.").
 			Return(&model.Post{Id: "testId"}, nil)
 
-		s := incident.NewService(client, store, poster, logger, configService, scheduler, telemetryService)
+		s := incident.NewService(client, store, poster, logger, configService, scheduler, telemetryService, nil)
 
 		_, err := s.CreateIncident(incdnt, nil, "user_id", true)
 		require.NoError(t, err)
@@ -162,7 +164,7 @@ func TestCreateIncident(t *testing.T) {
 		pluginAPI.On("GetConfig").Return(mattermostConfig)
 		pluginAPI.On("CreateChannel", mock.Anything).Return(nil, &model.AppError{Id: "store.sql_channel.save_channel.exists.app_error"})
 
-		s := incident.NewService(client, store, poster, logger, configService, scheduler, telemetryService)
+		s := incident.NewService(client, store, poster, logger, configService, scheduler, telemetryService, nil)
 
 		_, err := s.CreateIncident(incdnt, nil, "user_id", true)
 		require.EqualError(t, err, "failed to create incident channel: : , ")
@@ -205,7 +207,7 @@ func TestCreateIncident(t *testing.T) {
 		poster.EXPECT().PostMessage("channel_id", "This incident has been started and is commanded by @username.").
 			Return(&model.Post{Id: "testid"}, nil)
 
-		s := incident.NewService(client, store, poster, logger, configService, scheduler, telemetryService)
+		s := incident.NewService(client, store, poster, logger, configService, scheduler, telemetryService, nil)
 
 		_, err := s.CreateIncident(incdnt, nil, "user_id", true)
 		require.NoError(t, err)
@@ -250,7 +252,7 @@ func TestCreateIncident(t *testing.T) {
 		poster.EXPECT().PostMessage("channel_id", "This incident has been started and is commanded by @username.").
 			Return(&model.Post{Id: "testId"}, nil)
 
-		s := incident.NewService(client, store, poster, logger, configService, scheduler, telemetryService)
+		s := incident.NewService(client, store, poster, logger, configService, scheduler, telemetryService, nil)
 
 		_, err := s.CreateIncident(incdnt, nil, "user_id", true)
 		pluginAPI.AssertExpectations(t)
@@ -274,12 +276,15 @@ func TestCreateIncident(t *testing.T) {
 			DetailsURL string `json:"details_url"`
 		}
 
-		webhookChan := make(chan webhookPayload)
+		webhookChan := make(chan webhookPayload, 1)
 
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			body, err := ioutil.ReadAll(r.Body)
 			require.NoError(t, err)
 
+			require.Equal(t, incident.SignWebhookPayload("webhook_secret", body), r.Header.Get(incident.WebhookSignatureHeader))
+			require.Equal(t, "delivery_id", r.Header.Get(incident.WebhookDeliveryHeader))
+
 			var p webhookPayload
 			err = json.Unmarshal(body, &p)
 			require.NoError(t, err)
@@ -294,11 +299,21 @@ func TestCreateIncident(t *testing.T) {
 			TeamID:               teamID,
 			OwnerUserID:          "user_id",
 			WebhookOnCreationURL: server.URL,
+			WebhookSecret:        "webhook_secret",
 		}
 
+		var delivery incident.WebhookDelivery
+
 		store.EXPECT().CreateIncident(gomock.Any()).Return(incdnt, nil)
 		store.EXPECT().CreateTimelineEvent(gomock.AssignableToTypeOf(&incident.TimelineEvent{}))
 		store.EXPECT().UpdateIncident(gomock.Any()).Return(nil)
+		store.EXPECT().CreateWebhookDelivery(gomock.AssignableToTypeOf(&incident.WebhookDelivery{})).
+			DoAndReturn(func(d *incident.WebhookDelivery) (*incident.WebhookDelivery, error) {
+				d.ID = "delivery_id"
+				delivery = *d
+				return d, nil
+			})
+		scheduler.EXPECT().ScheduleOnce(incident.WebhookPrefix+"delivery_id", gomock.Any()).Return(nil, nil)
 
 		configService.EXPECT().GetManifest().Return(&model.Manifest{Id: "com.mattermost.plugin-incident-management"}).Times(2)
 		configService.EXPECT().GetConfiguration().Return(&config.Configuration{BotUserID: "bot_user_id"}).AnyTimes()
@@ -320,10 +335,30 @@ func TestCreateIncident(t *testing.T) {
 		pluginAPI.On("GetTeam", teamID).Return(&model.Team{Id: teamID, Name: "ad-1"}, nil)
 		pluginAPI.On("GetChannel", mock.Anything).Return(&model.Channel{Id: "channel_id", Name: "incident-channel-name"}, nil)
 
-		s := incident.NewService(client, store, poster, logger, configService, scheduler, telemetryService)
+		s := incident.NewService(client, store, poster, logger, configService, scheduler, telemetryService, nil)
 
 		createdIncident, err := s.CreateIncident(incdnt, nil, "user_id", true)
 		require.NoError(t, err)
+		require.Equal(t, incident.WebhookEventIncidentCreated, delivery.EventType)
+		require.Equal(t, incident.WebhookDeliveryPending, delivery.Status)
+		require.Equal(t, server.URL, delivery.URL)
+
+		store.EXPECT().GetWebhookDelivery("delivery_id").Return(&delivery, nil)
+		store.EXPECT().GetIncident(incdnt.ID).Return(incdnt, nil)
+		store.EXPECT().CreateWebhookDeliveryAttempt(gomock.AssignableToTypeOf(&incident.WebhookDeliveryAttempt{})).
+			Do(func(attempt *incident.WebhookDeliveryAttempt) {
+				require.Equal(t, 1, attempt.Attempt)
+				require.Equal(t, http.StatusOK, attempt.StatusCode)
+				require.Empty(t, attempt.Error)
+			})
+		store.EXPECT().UpdateWebhookDelivery(gomock.AssignableToTypeOf(&incident.WebhookDelivery{})).
+			Do(func(d *incident.WebhookDelivery) {
+				require.Equal(t, incident.WebhookDeliveryDelivered, d.Status)
+				require.Equal(t, 1, d.NumAttempts)
+				require.Zero(t, d.NextAttemptAt)
+			})
+
+		s.HandleReminder(incident.WebhookPrefix + "delivery_id")
 
 		select {
 		case payload := <-webhookChan:
@@ -341,6 +376,119 @@ func TestCreateIncident(t *testing.T) {
 
 		pluginAPI.AssertExpectations(t)
 	})
+
+	t.Run("webhook subscriptions receive the timeline events they subscribe to", func(t *testing.T) {
+		controller := gomock.NewController(t)
+		pluginAPI := &plugintest.API{}
+		client := pluginapi.NewClient(pluginAPI)
+		store := mock_incident.NewMockStore(controller)
+		poster := mock_bot.NewMockPoster(controller)
+		logger := mock_bot.NewMockLogger(controller)
+		configService := mock_config.NewMockService(controller)
+		telemetryService := &telemetry.NoopTelemetry{}
+		scheduler := mock_incident.NewMockJobOnceScheduler(controller)
+
+		type webhookPayload struct {
+			incident.TimelineEvent
+			IncidentName string `json:"incident_name"`
+			ChannelURL   string `json:"channel_url"`
+			DetailsURL   string `json:"details_url"`
+		}
+
+		webhookChan := make(chan webhookPayload, 1)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := ioutil.ReadAll(r.Body)
+			require.NoError(t, err)
+
+			var p webhookPayload
+			err = json.Unmarshal(body, &p)
+			require.NoError(t, err)
+
+			webhookChan <- p
+		}))
+
+		teamID := model.NewId()
+		incdnt := &incident.Incident{
+			ID:          "incidentID",
+			Name:        "Incident Name",
+			TeamID:      teamID,
+			OwnerUserID: "user_id",
+			WebhookSubscriptions: []playbook.WebhookSubscription{
+				{URL: server.URL, EventTypes: []string{string(incident.IncidentCreated)}},
+				{URL: server.URL + "/status", EventTypes: []string{string(incident.StatusUpdated)}},
+			},
+		}
+
+		var delivery incident.WebhookDelivery
+
+		store.EXPECT().CreateIncident(gomock.Any()).Return(incdnt, nil)
+		store.EXPECT().CreateTimelineEvent(gomock.AssignableToTypeOf(&incident.TimelineEvent{}))
+		store.EXPECT().UpdateIncident(gomock.Any()).Return(nil)
+		store.EXPECT().CreateWebhookDelivery(gomock.AssignableToTypeOf(&incident.WebhookDelivery{})).
+			DoAndReturn(func(d *incident.WebhookDelivery) (*incident.WebhookDelivery, error) {
+				d.ID = "delivery_id"
+				delivery = *d
+				return d, nil
+			})
+		scheduler.EXPECT().ScheduleOnce(incident.WebhookPrefix+"delivery_id", gomock.Any()).Return(nil, nil)
+
+		configService.EXPECT().GetManifest().Return(&model.Manifest{Id: "com.mattermost.plugin-incident-management"}).Times(2)
+		configService.EXPECT().GetConfiguration().Return(&config.Configuration{BotUserID: "bot_user_id"}).AnyTimes()
+
+		poster.EXPECT().PublishWebsocketEventToChannel("incident_updated", gomock.Any(), "channel_id")
+		poster.EXPECT().PostMessage("channel_id", gomock.Any()).Return(&model.Post{Id: "testId"}, nil)
+
+		mattermostConfig := &model.Config{}
+		mattermostConfig.SetDefaults()
+		siteURL := "http://example.com"
+		mattermostConfig.ServiceSettings.SiteURL = &siteURL
+		pluginAPI.On("GetConfig").Return(mattermostConfig)
+		pluginAPI.On("CreateChannel", mock.Anything).Return(&model.Channel{Id: "channel_id", TeamId: "team_id"}, nil)
+		pluginAPI.On("AddUserToChannel", "channel_id", "user_id", "bot_user_id").Return(nil, nil)
+		pluginAPI.On("UpdateChannelMemberRoles", "channel_id", "user_id", mock.Anything).Return(nil, nil)
+		pluginAPI.On("CreateTeamMember", "team_id", "bot_user_id").Return(nil, nil)
+		pluginAPI.On("AddChannelMember", "channel_id", "bot_user_id").Return(nil, nil)
+		pluginAPI.On("GetUser", "user_id").Return(&model.User{Id: "user_id", Username: "username"}, nil)
+		pluginAPI.On("GetTeam", teamID).Return(&model.Team{Id: teamID, Name: "ad-1"}, nil)
+		pluginAPI.On("GetChannel", mock.Anything).Return(&model.Channel{Id: "channel_id", Name: "incident-channel-name"}, nil)
+
+		s := incident.NewService(client, store, poster, logger, configService, scheduler, telemetryService, nil)
+
+		createdIncident, err := s.CreateIncident(incdnt, nil, "user_id", true)
+		require.NoError(t, err)
+		require.Equal(t, string(incident.IncidentCreated), delivery.EventType)
+		require.Equal(t, server.URL, delivery.URL)
+
+		store.EXPECT().GetWebhookDelivery("delivery_id").Return(&delivery, nil)
+		store.EXPECT().GetIncident(incdnt.ID).Return(incdnt, nil)
+		store.EXPECT().CreateWebhookDeliveryAttempt(gomock.AssignableToTypeOf(&incident.WebhookDeliveryAttempt{}))
+		store.EXPECT().UpdateWebhookDelivery(gomock.AssignableToTypeOf(&incident.WebhookDelivery{})).
+			Do(func(d *incident.WebhookDelivery) {
+				require.Equal(t, incident.WebhookDeliveryDelivered, d.Status)
+			})
+
+		s.HandleReminder(incident.WebhookPrefix + "delivery_id")
+
+		select {
+		case payload := <-webhookChan:
+			require.Equal(t, incident.IncidentCreated, payload.EventType)
+			require.Equal(t, createdIncident.ID, payload.IncidentID)
+			require.Equal(t, "testId", payload.PostID)
+			require.Equal(t, "Incident Name", payload.IncidentName)
+			require.Equal(t,
+				"http://example.com/ad-1/channels/incident-channel-name",
+				payload.ChannelURL)
+			require.Equal(t,
+				"http://example.com/ad-1/com.mattermost.plugin-incident-management/incidents/"+createdIncident.ID,
+				payload.DetailsURL)
+
+		case <-time.After(time.Second * 5):
+			require.Fail(t, "did not receive webhook")
+		}
+
+		pluginAPI.AssertExpectations(t)
+	})
 }
 
 func TestUpdateStatus(t *testing.T) {
@@ -362,12 +510,14 @@ func TestUpdateStatus(t *testing.T) {
 			StatusUpdate incident.StatusUpdateOptions `json:"status_update"`
 		}
 
-		webhookChan := make(chan webhookPayload)
+		webhookChan := make(chan webhookPayload, 1)
 
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			body, err := ioutil.ReadAll(r.Body)
 			require.NoError(t, err)
 
+			require.Empty(t, r.Header.Get(incident.WebhookSignatureHeader))
+
 			var p webhookPayload
 			err = json.Unmarshal(body, &p)
 			require.NoError(t, err)
@@ -401,6 +551,15 @@ func TestUpdateStatus(t *testing.T) {
 		store.EXPECT().UpdateStatus(gomock.AssignableToTypeOf(&incident.SQLStatusPost{})).Return(nil)
 		store.EXPECT().GetIncident(gomock.Any()).Return(incdnt, nil).Times(2)
 
+		var delivery incident.WebhookDelivery
+		store.EXPECT().CreateWebhookDelivery(gomock.AssignableToTypeOf(&incident.WebhookDelivery{})).
+			DoAndReturn(func(d *incident.WebhookDelivery) (*incident.WebhookDelivery, error) {
+				d.ID = "delivery_id"
+				delivery = *d
+				return d, nil
+			})
+		scheduler.EXPECT().ScheduleOnce(incident.WebhookPrefix+"delivery_id", gomock.Any()).Return(nil, nil)
+
 		configService.EXPECT().GetManifest().Return(&model.Manifest{Id: "com.mattermost.plugin-incident-management"}).Times(2)
 
 		poster.EXPECT().PublishWebsocketEventToChannel("incident_updated", gomock.Any(), channelID)
@@ -417,10 +576,18 @@ func TestUpdateStatus(t *testing.T) {
 		pluginAPI.On("GetUser", "user_id").Return(&model.User{}, nil)
 		pluginAPI.On("GetConfig").Return(&model.Config{ServiceSettings: model.ServiceSettings{SiteURL: &siteURL}})
 
-		s := incident.NewService(client, store, poster, logger, configService, scheduler, telemetryService)
+		s := incident.NewService(client, store, poster, logger, configService, scheduler, telemetryService, nil)
 
 		err := s.UpdateStatus(incdnt.ID, "user_id", statusUpdateOptions)
 		require.NoError(t, err)
+		require.Equal(t, incident.WebhookEventStatusUpdated, delivery.EventType)
+
+		store.EXPECT().GetWebhookDelivery("delivery_id").Return(&delivery, nil)
+		store.EXPECT().GetIncident(incdnt.ID).Return(incdnt, nil)
+		store.EXPECT().CreateWebhookDeliveryAttempt(gomock.AssignableToTypeOf(&incident.WebhookDeliveryAttempt{})).Return(nil)
+		store.EXPECT().UpdateWebhookDelivery(gomock.AssignableToTypeOf(&incident.WebhookDelivery{})).Return(nil)
+
+		s.HandleReminder(incident.WebhookPrefix + "delivery_id")
 
 		select {
 		case payload := <-webhookChan:
@@ -436,6 +603,823 @@ func TestUpdateStatus(t *testing.T) {
 			require.Fail(t, "did not receive webhook on status update")
 		}
 	})
+
+	t.Run("status of a merged incident is not updated", func(t *testing.T) {
+		controller := gomock.NewController(t)
+		pluginAPI := &plugintest.API{}
+		client := pluginapi.NewClient(pluginAPI)
+		store := mock_incident.NewMockStore(controller)
+		poster := mock_bot.NewMockPoster(controller)
+		logger := mock_bot.NewMockLogger(controller)
+		configService := mock_config.NewMockService(controller)
+		telemetryService := &telemetry.NoopTelemetry{}
+		scheduler := mock_incident.NewMockJobOnceScheduler(controller)
+
+		incdnt := &incident.Incident{
+			ID:            "incident_id",
+			ChannelID:     "channel_id",
+			CurrentStatus: incident.StatusMerged,
+		}
+		store.EXPECT().GetIncident(incdnt.ID).Return(incdnt, nil)
+
+		s := incident.NewService(client, store, poster, logger, configService, scheduler, telemetryService, nil)
+
+		err := s.UpdateStatus(incdnt.ID, "user_id", incident.StatusUpdateOptions{Status: incident.StatusActive, Message: "message", Description: "description"})
+		require.ErrorIs(t, err, incident.ErrIncidentMerged)
+		require.Equal(t, incident.StatusMerged, incdnt.CurrentStatus)
+	})
+}
+
+func TestHandleWebhookDelivery(t *testing.T) {
+	t.Run("delivery is marked as failed after the last attempt", func(t *testing.T) {
+		controller := gomock.NewController(t)
+		pluginAPI := &plugintest.API{}
+		client := pluginapi.NewClient(pluginAPI)
+		store := mock_incident.NewMockStore(controller)
+		poster := mock_bot.NewMockPoster(controller)
+		logger := mock_bot.NewMockLogger(controller)
+		configService := mock_config.NewMockService(controller)
+		telemetryService := &telemetry.NoopTelemetry{}
+		scheduler := mock_incident.NewMockJobOnceScheduler(controller)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		incdnt := &incident.Incident{
+			ID:        "incident_id",
+			ChannelID: "channel_id",
+		}
+		delivery := &incident.WebhookDelivery{
+			ID:          "delivery_id",
+			IncidentID:  incdnt.ID,
+			EventType:   incident.WebhookEventStatusUpdated,
+			URL:         server.URL,
+			Payload:     "{}",
+			Status:      incident.WebhookDeliveryPending,
+			NumAttempts: 4,
+		}
+
+		store.EXPECT().GetWebhookDelivery(delivery.ID).Return(delivery, nil)
+		store.EXPECT().GetIncident(incdnt.ID).Return(incdnt, nil)
+		store.EXPECT().CreateWebhookDeliveryAttempt(gomock.AssignableToTypeOf(&incident.WebhookDeliveryAttempt{})).
+			Do(func(attempt *incident.WebhookDeliveryAttempt) {
+				require.Equal(t, 5, attempt.Attempt)
+				require.Equal(t, http.StatusServiceUnavailable, attempt.StatusCode)
+				require.NotEmpty(t, attempt.Error)
+			})
+		store.EXPECT().UpdateWebhookDelivery(gomock.AssignableToTypeOf(&incident.WebhookDelivery{})).
+			Do(func(d *incident.WebhookDelivery) {
+				require.Equal(t, incident.WebhookDeliveryFailed, d.Status)
+				require.Equal(t, 5, d.NumAttempts)
+				require.Zero(t, d.NextAttemptAt)
+			})
+		poster.EXPECT().PostMessage("channel_id", gomock.Any()).Return(&model.Post{}, nil)
+		pluginAPI.On("LogWarn", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+		s := incident.NewService(client, store, poster, logger, configService, scheduler, telemetryService, nil)
+
+		s.HandleReminder(incident.WebhookPrefix + delivery.ID)
+	})
+
+	t.Run("failed attempt schedules a retry under the key of the next attempt", func(t *testing.T) {
+		controller := gomock.NewController(t)
+		pluginAPI := &plugintest.API{}
+		client := pluginapi.NewClient(pluginAPI)
+		store := mock_incident.NewMockStore(controller)
+		poster := mock_bot.NewMockPoster(controller)
+		logger := mock_bot.NewMockLogger(controller)
+		configService := mock_config.NewMockService(controller)
+		telemetryService := &telemetry.NoopTelemetry{}
+		scheduler := mock_incident.NewMockJobOnceScheduler(controller)
+
+		incdnt := &incident.Incident{
+			ID:        "incident_id",
+			ChannelID: "channel_id",
+		}
+		delivery := &incident.WebhookDelivery{
+			ID:          "delivery_id",
+			IncidentID:  incdnt.ID,
+			EventType:   incident.WebhookEventStatusUpdated,
+			URL:         "http://" + strings.Repeat("a", 2*incident.MaxWebhookAttemptErrorLength) + ".invalid/",
+			Payload:     "{}",
+			Status:      incident.WebhookDeliveryPending,
+			NumAttempts: 1,
+		}
+
+		store.EXPECT().GetWebhookDelivery(delivery.ID).Return(delivery, nil)
+		store.EXPECT().GetIncident(incdnt.ID).Return(incdnt, nil)
+		store.EXPECT().CreateWebhookDeliveryAttempt(gomock.AssignableToTypeOf(&incident.WebhookDeliveryAttempt{})).
+			Do(func(attempt *incident.WebhookDeliveryAttempt) {
+				require.Equal(t, 2, attempt.Attempt)
+				require.NotEmpty(t, attempt.Error)
+				require.LessOrEqual(t, utf8.RuneCountInString(attempt.Error), incident.MaxWebhookAttemptErrorLength)
+			})
+		store.EXPECT().UpdateWebhookDelivery(gomock.AssignableToTypeOf(&incident.WebhookDelivery{})).
+			Do(func(d *incident.WebhookDelivery) {
+				require.Equal(t, incident.WebhookDeliveryPending, d.Status)
+				require.Equal(t, 2, d.NumAttempts)
+				require.NotZero(t, d.NextAttemptAt)
+			})
+		scheduler.EXPECT().ScheduleOnce(incident.WebhookPrefix+"delivery_id_3", gomock.Any()).Return(nil, nil)
+
+		s := incident.NewService(client, store, poster, logger, configService, scheduler, telemetryService, nil)
+
+		s.HandleReminder(incident.WebhookPrefix + "delivery_id_2")
+	})
+
+	t.Run("stale attempt is skipped", func(t *testing.T) {
+		controller := gomock.NewController(t)
+		pluginAPI := &plugintest.API{}
+		client := pluginapi.NewClient(pluginAPI)
+		store := mock_incident.NewMockStore(controller)
+		poster := mock_bot.NewMockPoster(controller)
+		logger := mock_bot.NewMockLogger(controller)
+		configService := mock_config.NewMockService(controller)
+		telemetryService := &telemetry.NoopTelemetry{}
+		scheduler := mock_incident.NewMockJobOnceScheduler(controller)
+
+		delivery := &incident.WebhookDelivery{
+			ID:          "delivery_id",
+			IncidentID:  "incident_id",
+			Status:      incident.WebhookDeliveryPending,
+			NumAttempts: 3,
+		}
+
+		store.EXPECT().GetWebhookDelivery(delivery.ID).Return(delivery, nil)
+
+		s := incident.NewService(client, store, poster, logger, configService, scheduler, telemetryService, nil)
+
+		s.HandleReminder(incident.WebhookPrefix + "delivery_id_2")
+	})
+}
+
+func TestAddChecklistItem(t *testing.T) {
+	t.Run("item with a due time gets a due date and reschedules the overdue tasks reminder", func(t *testing.T) {
+		controller := gomock.NewController(t)
+		pluginAPI := &plugintest.API{}
+		client := pluginapi.NewClient(pluginAPI)
+		store := mock_incident.NewMockStore(controller)
+		poster := mock_bot.NewMockPoster(controller)
+		logger := mock_bot.NewMockLogger(controller)
+		configService := mock_config.NewMockService(controller)
+		telemetryService := &telemetry.NoopTelemetry{}
+		scheduler := mock_incident.NewMockJobOnceScheduler(controller)
+
+		incdnt := &incident.Incident{
+			ID:         "incident_id",
+			ChannelID:  "channel_id",
+			CreateAt:   1,
+			Checklists: []playbook.Checklist{{Title: "Checklist"}},
+		}
+
+		before := model.GetMillis()
+		store.EXPECT().GetIncident(incdnt.ID).Return(incdnt, nil)
+		pluginAPI.On("HasPermissionToChannel", "user_id", incdnt.ChannelID, model.PERMISSION_READ_CHANNEL).Return(true)
+		store.EXPECT().UpdateIncident(gomock.AssignableToTypeOf(&incident.Incident{})).
+			Do(func(i *incident.Incident) {
+				require.Len(t, i.Checklists[0].Items, 1)
+				item := i.Checklists[0].Items[0]
+				require.GreaterOrEqual(t, item.DueAt, before+600*1000)
+				require.False(t, item.OverdueNotified)
+			})
+		scheduler.EXPECT().Cancel(incident.DueItemsPrefix + incdnt.ID)
+		scheduler.EXPECT().ScheduleOnce(incident.DueItemsPrefix+incdnt.ID, gomock.Any()).Return(nil, nil)
+		poster.EXPECT().PublishWebsocketEventToChannel(gomock.Any(), gomock.Any(), incdnt.ChannelID)
+
+		s := incident.NewService(client, store, poster, logger, configService, scheduler, telemetryService, nil)
+
+		err := s.AddChecklistItem(incdnt.ID, "user_id", 0, playbook.ChecklistItem{
+			Title:           "Item",
+			DueAfterSeconds: 600,
+			DueAt:           1,
+			OverdueNotified: true,
+		})
+		require.NoError(t, err)
+	})
+}
+
+func TestAddChecklistItemDropsAutomaticAction(t *testing.T) {
+	controller := gomock.NewController(t)
+	pluginAPI := &plugintest.API{}
+	client := pluginapi.NewClient(pluginAPI)
+	store := mock_incident.NewMockStore(controller)
+	poster := mock_bot.NewMockPoster(controller)
+	logger := mock_bot.NewMockLogger(controller)
+	configService := mock_config.NewMockService(controller)
+	telemetryService := &telemetry.NoopTelemetry{}
+	scheduler := mock_incident.NewMockJobOnceScheduler(controller)
+
+	incdnt := &incident.Incident{
+		ID:         "incident_id",
+		ChannelID:  "channel_id",
+		Checklists: []playbook.Checklist{{Title: "Checklist"}},
+	}
+
+	store.EXPECT().GetIncident(incdnt.ID).Return(incdnt, nil)
+	pluginAPI.On("HasPermissionToChannel", "user_id", incdnt.ChannelID, model.PERMISSION_READ_CHANNEL).Return(true)
+	store.EXPECT().UpdateIncident(gomock.AssignableToTypeOf(&incident.Incident{})).
+		Do(func(i *incident.Incident) {
+			item := i.Checklists[0].Items[0]
+			require.Equal(t, playbook.ItemAction{}, item.AutomaticAction)
+			require.Zero(t, item.ActionLastRun)
+		})
+	poster.EXPECT().PublishWebsocketEventToChannel(gomock.Any(), gomock.Any(), incdnt.ChannelID)
+
+	s := incident.NewService(client, store, poster, logger, configService, scheduler, telemetryService, nil)
+
+	err := s.AddChecklistItem(incdnt.ID, "user_id", 0, playbook.ChecklistItem{
+		Title: "Item",
+		AutomaticAction: playbook.ItemAction{
+			Trigger: playbook.ActionTriggerNextOpen,
+			Type:    playbook.ActionTypeInviteUsers,
+			UserIDs: []string{"other_user_id"},
+		},
+		ActionLastRun: 1,
+	})
+	require.NoError(t, err)
+}
+
+func TestModifyCheckedStateRunsAutomaticActions(t *testing.T) {
+	controller := gomock.NewController(t)
+	pluginAPI := &plugintest.API{}
+	client := pluginapi.NewClient(pluginAPI)
+	store := mock_incident.NewMockStore(controller)
+	poster := mock_bot.NewMockPoster(controller)
+	logger := mock_bot.NewMockLogger(controller)
+	configService := mock_config.NewMockService(controller)
+	telemetryService := &telemetry.NoopTelemetry{}
+	scheduler := mock_incident.NewMockJobOnceScheduler(controller)
+
+	incdnt := &incident.Incident{
+		ID:        "incident_id",
+		Name:      "Checkout errors",
+		TeamID:    "team_id",
+		ChannelID: "channel_id",
+		Checklists: []playbook.Checklist{
+			{
+				Title: "Triage",
+				Items: []playbook.ChecklistItem{
+					{
+						ID:    "notify",
+						Title: "Notify support",
+						AutomaticAction: playbook.ItemAction{
+							Trigger:   playbook.ActionTriggerChecked,
+							Type:      playbook.ActionTypePostMessage,
+							ChannelID: "support_channel_id",
+							Message:   "{{.IncidentName}} was triaged",
+						},
+					},
+					{
+						ID:    "invite",
+						Title: "Page the DBA",
+						AutomaticAction: playbook.ItemAction{
+							Trigger: playbook.ActionTriggerNextOpen,
+							Type:    playbook.ActionTypeInviteUsers,
+							UserIDs: []string{"dba_id"},
+						},
+					},
+				},
+			},
+			{
+				Title: "Communicate",
+				Items: []playbook.ChecklistItem{
+					{
+						ID:    "already_ran",
+						Title: "Post on the status page",
+						AutomaticAction: playbook.ItemAction{
+							Trigger: playbook.ActionTriggerNextOpen,
+							Type:    playbook.ActionTypePostMessage,
+							Message: "posted once",
+						},
+						ActionLastRun: 1,
+					},
+				},
+			},
+		},
+	}
+
+	store.EXPECT().GetIncident(incdnt.ID).Return(incdnt, nil).Times(4)
+	pluginAPI.On("HasPermissionToChannel", "user_id", incdnt.ChannelID, model.PERMISSION_READ_CHANNEL).Return(true)
+	pluginAPI.On("GetUser", "user_id").Return(&model.User{Id: "user_id", Username: "username"}, nil)
+	poster.EXPECT().PostMessage(incdnt.ChannelID, gomock.Any()).Return(&model.Post{Id: "post_id"}, nil)
+
+	// The checked trigger posts the rendered message; the next_open trigger invites the users.
+	poster.EXPECT().PostMessage("support_channel_id", "%s", "Checkout errors was triaged").Return(&model.Post{}, nil)
+	configService.EXPECT().GetConfiguration().Return(&config.Configuration{BotUserID: "bot_user_id"})
+	pluginAPI.On("AddUserToChannel", incdnt.ChannelID, "dba_id", "bot_user_id").Return(nil, nil)
+
+	var events []*incident.TimelineEvent
+	store.EXPECT().CreateTimelineEvent(gomock.AssignableToTypeOf(&incident.TimelineEvent{})).
+		DoAndReturn(func(event *incident.TimelineEvent) (*incident.TimelineEvent, error) {
+			events = append(events, event)
+			return event, nil
+		}).
+		Times(3)
+	store.EXPECT().UpdateIncident(gomock.AssignableToTypeOf(&incident.Incident{})).Times(3)
+	poster.EXPECT().PublishWebsocketEventToChannel(gomock.Any(), gomock.Any(), incdnt.ChannelID)
+
+	s := incident.NewService(client, store, poster, logger, configService, scheduler, telemetryService, nil)
+
+	err := s.ModifyCheckedState(incdnt.ID, "user_id", playbook.ChecklistItemStateClosed, 0, 0)
+	require.NoError(t, err)
+
+	require.NotZero(t, incdnt.Checklists[0].Items[0].ActionLastRun)
+	require.NotZero(t, incdnt.Checklists[0].Items[1].ActionLastRun)
+	// An action runs only once.
+	require.Equal(t, int64(1), incdnt.Checklists[1].Items[0].ActionLastRun)
+
+	require.Len(t, events, 3)
+	require.Equal(t, incident.TaskStateModified, events[0].EventType)
+	for _, event := range events[1:] {
+		require.Equal(t, incident.RanSlashCommand, event.EventType)
+		var details incident.ActionEventDetails
+		require.NoError(t, json.Unmarshal([]byte(event.Details), &details))
+		require.Equal(t, incident.ActionResultSucceeded, details.Result)
+	}
+}
+
+func TestModifyCheckedStateKeepsChangesOfAutomaticActions(t *testing.T) {
+	controller := gomock.NewController(t)
+	pluginAPI := &plugintest.API{}
+	client := pluginapi.NewClient(pluginAPI)
+	store := mock_incident.NewMockStore(controller)
+	poster := mock_bot.NewMockPoster(controller)
+	logger := mock_bot.NewMockLogger(controller)
+	configService := mock_config.NewMockService(controller)
+	telemetryService := &telemetry.NoopTelemetry{}
+	scheduler := mock_incident.NewMockJobOnceScheduler(controller)
+
+	stored := &incident.Incident{
+		ID:          "incident_id",
+		Name:        "Checkout errors",
+		TeamID:      "team_id",
+		ChannelID:   "channel_id",
+		OwnerUserID: "owner_id",
+		Checklists: []playbook.Checklist{
+			{
+				Title: "Triage",
+				Items: []playbook.ChecklistItem{
+					{
+						ID:      "hand_over",
+						Title:   "Hand over to the DBA",
+						Command: "/incident owner @dba",
+						AutomaticAction: playbook.ItemAction{
+							Trigger: playbook.ActionTriggerChecked,
+							Type:    playbook.ActionTypeRunCommand,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// The store hands out copies, like the database does.
+	store.EXPECT().GetIncident(stored.ID).
+		DoAndReturn(func(string) (*incident.Incident, error) {
+			return stored.Clone(), nil
+		}).
+		AnyTimes()
+	store.EXPECT().UpdateIncident(gomock.AssignableToTypeOf(&incident.Incident{})).
+		DoAndReturn(func(i *incident.Incident) error {
+			stored = i.Clone()
+			return nil
+		}).
+		AnyTimes()
+
+	pluginAPI.On("HasPermissionToChannel", "user_id", stored.ChannelID, model.PERMISSION_READ_CHANNEL).Return(true)
+	pluginAPI.On("GetUser", "user_id").Return(&model.User{Id: "user_id", Username: "username"}, nil)
+	poster.EXPECT().PostMessage(stored.ChannelID, gomock.Any()).Return(&model.Post{Id: "post_id"}, nil)
+
+	// The command changes the owner of the incident, as /incident owner does, and sees the run
+	// of its own action already recorded.
+	pluginAPI.On("ExecuteSlashCommand", mock.AnythingOfType("*model.CommandArgs")).
+		Run(func(args mock.Arguments) {
+			require.NotZero(t, stored.Checklists[0].Items[0].ActionLastRun)
+			changed := stored.Clone()
+			changed.OwnerUserID = "dba_id"
+			stored = changed
+		}).
+		Return(&model.CommandResponse{}, nil).
+		Once()
+
+	store.EXPECT().CreateTimelineEvent(gomock.AssignableToTypeOf(&incident.TimelineEvent{})).
+		DoAndReturn(func(event *incident.TimelineEvent) (*incident.TimelineEvent, error) {
+			return event, nil
+		}).
+		Times(2)
+	poster.EXPECT().PublishWebsocketEventToChannel(gomock.Any(), gomock.Any(), stored.ChannelID)
+
+	s := incident.NewService(client, store, poster, logger, configService, scheduler, telemetryService, nil)
+
+	err := s.ModifyCheckedState(stored.ID, "user_id", playbook.ChecklistItemStateClosed, 0, 0)
+	require.NoError(t, err)
+
+	pluginAPI.AssertNumberOfCalls(t, "ExecuteSlashCommand", 1)
+	require.Equal(t, "dba_id", stored.OwnerUserID)
+	require.Equal(t, playbook.ChecklistItemStateClosed, stored.Checklists[0].Items[0].State)
+	require.NotZero(t, stored.Checklists[0].Items[0].ActionLastRun)
+}
+
+func TestHandleOverdueActionItemsReminder(t *testing.T) {
+	controller := gomock.NewController(t)
+	pluginAPI := &plugintest.API{}
+	client := pluginapi.NewClient(pluginAPI)
+	store := mock_incident.NewMockStore(controller)
+	poster := mock_bot.NewMockPoster(controller)
+	logger := mock_bot.NewMockLogger(controller)
+	configService := mock_config.NewMockService(controller)
+	telemetryService := &telemetry.NoopTelemetry{}
+	scheduler := mock_incident.NewMockJobOnceScheduler(controller)
+
+	incdnt := &incident.Incident{
+		ID:            "incident_id",
+		Name:          "Checkout errors",
+		TeamID:        "team_id",
+		ChannelID:     "channel_id",
+		OwnerUserID:   "owner_id",
+		CurrentStatus: incident.StatusResolved,
+		ActionItems: []incident.ActionItem{
+			{ID: "reminded", Title: "Add an alert", OwnerUserID: "alice_id", DueAt: 1},
+			{ID: "failed", Title: "Fix the retries", OwnerUserID: "bob_id", DueAt: 1},
+		},
+	}
+
+	siteURL := "http://localhost:8065"
+	store.EXPECT().GetIncident(incdnt.ID).Return(incdnt, nil)
+	pluginAPI.On("GetConfig").Return(&model.Config{ServiceSettings: model.ServiceSettings{SiteURL: &siteURL}})
+	pluginAPI.On("GetTeam", incdnt.TeamID).Return(&model.Team{Id: incdnt.TeamID, Name: "team"}, nil)
+	pluginAPI.On("GetChannel", incdnt.ChannelID).Return(&model.Channel{Id: incdnt.ChannelID, Name: "channel"}, nil)
+	configService.EXPECT().GetManifest().Return(&model.Manifest{Id: "pluginId"})
+
+	poster.EXPECT().DM("alice_id", gomock.Any(), "Add an alert", incdnt.Name, gomock.Any()).Return(nil)
+	store.EXPECT().UpdateActionItem(gomock.AssignableToTypeOf(&incident.ActionItem{})).
+		Do(func(item *incident.ActionItem) {
+			require.Equal(t, "reminded", item.ID)
+			require.True(t, item.OverdueNotified)
+		})
+	poster.EXPECT().DM("bob_id", gomock.Any(), "Fix the retries", incdnt.Name, gomock.Any()).Return(fmt.Errorf("DM failed"))
+	logger.EXPECT().Errorf(gomock.Any())
+
+	// The reminder that failed is retried later, although no due date is left.
+	scheduled := make(chan time.Time, 1)
+	scheduler.EXPECT().ScheduleOnce(incident.ActionItemsPrefix+incdnt.ID, gomock.Any()).
+		Do(func(key string, at time.Time) {
+			scheduled <- at
+		}).
+		Return(nil, nil)
+
+	s := incident.NewService(client, store, poster, logger, configService, scheduler, telemetryService, nil)
+
+	before := time.Now()
+	s.HandleReminder(incident.ActionItemsPrefix + incdnt.ID)
+
+	require.False(t, incdnt.ActionItems[1].OverdueNotified)
+	select {
+	case at := <-scheduled:
+		require.True(t, at.After(before.Add(time.Minute)))
+	case <-time.After(time.Second * 5):
+		require.Fail(t, "did not schedule the retry of the action items reminder")
+	}
+}
+
+func TestHandleEscalation(t *testing.T) {
+	newIncident := func() *incident.Incident {
+		return &incident.Incident{
+			ID:                        "incident_id",
+			Name:                      "Checkout errors",
+			TeamID:                    "team_id",
+			ChannelID:                 "channel_id",
+			OwnerUserID:               "owner_id",
+			CurrentStatus:             incident.StatusReported,
+			AcknowledgeTimeoutSeconds: 300,
+			EscalationSteps: []playbook.EscalationStep{
+				{
+					UserIDs:       []string{"outsider_id", "user_id"},
+					GroupIDs:      []string{"group_id", "private_group_id"},
+					ReassignOwner: true,
+				},
+				{UserIDs: []string{"user_id"}},
+			},
+		}
+	}
+
+	t.Run("notifies the users who can view the team and schedules the next step", func(t *testing.T) {
+		controller := gomock.NewController(t)
+		pluginAPI := &plugintest.API{}
+		client := pluginapi.NewClient(pluginAPI)
+		store := mock_incident.NewMockStore(controller)
+		poster := mock_bot.NewMockPoster(controller)
+		logger := mock_bot.NewMockLogger(controller)
+		configService := mock_config.NewMockService(controller)
+		telemetryService := &telemetry.NoopTelemetry{}
+		scheduler := mock_incident.NewMockJobOnceScheduler(controller)
+
+		incdnt := newIncident()
+		store.EXPECT().GetIncident(incdnt.ID).Return(incdnt, nil).Times(2)
+		store.EXPECT().UpdateIncident(gomock.AssignableToTypeOf(&incident.Incident{})).
+			Do(func(i *incident.Incident) {
+				require.Equal(t, 1, i.EscalationLevel)
+			})
+
+		siteURL := "http://localhost:8065"
+		pluginAPI.On("GetConfig").Return(&model.Config{ServiceSettings: model.ServiceSettings{SiteURL: &siteURL}})
+		pluginAPI.On("GetTeam", incdnt.TeamID).Return(&model.Team{Id: incdnt.TeamID, Name: "team"}, nil)
+		pluginAPI.On("GetChannel", incdnt.ChannelID).Return(&model.Channel{Id: incdnt.ChannelID, Name: "checkout-errors"}, nil)
+		configService.EXPECT().GetManifest().Return(&model.Manifest{Id: "plugin_id"}).AnyTimes()
+		configService.EXPECT().GetConfiguration().Return(&config.Configuration{BotUserID: "bot_user_id"})
+
+		// The outsider cannot view the team, and the private group does not allow references.
+		pluginAPI.On("GetTeamMember", incdnt.TeamID, "outsider_id").Return(nil, &model.AppError{Message: "not a member"})
+		pluginAPI.On("GetTeamMember", incdnt.TeamID, "user_id").Return(&model.TeamMember{}, nil)
+		pluginAPI.On("GetTeamMember", incdnt.TeamID, "group_member_id").Return(&model.TeamMember{}, nil)
+		pluginAPI.On("GetGroup", "group_id").Return(&model.Group{Id: "group_id", AllowReference: true}, nil)
+		pluginAPI.On("GetGroup", "private_group_id").Return(&model.Group{Id: "private_group_id"}, nil)
+		pluginAPI.On("GetGroupMemberUsers", "group_id", 0, 1000).Return([]*model.User{{Id: "group_member_id"}}, nil)
+		pluginAPI.On("LogWarn", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		pluginAPI.On("LogWarn", mock.Anything, mock.Anything, mock.Anything)
+
+		for _, userID := range []string{"user_id", "group_member_id"} {
+			pluginAPI.On("GetUser", userID).Return(&model.User{Id: userID, Username: userID}, nil)
+			pluginAPI.On("AddUserToChannel", incdnt.ChannelID, userID, "bot_user_id").Return(nil, nil)
+			poster.EXPECT().DMWithAttachments(userID, gomock.Any()).Return(nil)
+		}
+
+		poster.EXPECT().PostMessage(incdnt.ChannelID, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(channelID, format string, args ...interface{}) (*model.Post, error) {
+				require.Contains(t, fmt.Sprintf(format, args...), "notified @user_id, @group_member_id")
+				return &model.Post{Id: "post_id"}, nil
+			})
+		store.EXPECT().CreateTimelineEvent(gomock.AssignableToTypeOf(&incident.TimelineEvent{})).
+			DoAndReturn(func(event *incident.TimelineEvent) (*incident.TimelineEvent, error) {
+				require.Equal(t, incident.IncidentEscalated, event.EventType)
+				return event, nil
+			})
+
+		// The first user of the step is not on the team, so the owner is not reassigned.
+		poster.EXPECT().PublishWebsocketEventToChannel(gomock.Any(), gomock.Any(), incdnt.ChannelID)
+		scheduler.EXPECT().ScheduleOnce(incident.EscalationPrefix+incdnt.ID+"_1", gomock.Any()).Return(nil, nil)
+
+		s := incident.NewService(client, store, poster, logger, configService, scheduler, telemetryService, nil)
+
+		s.HandleReminder(incident.EscalationPrefix + incdnt.ID)
+		require.Equal(t, "owner_id", incdnt.OwnerUserID)
+		pluginAPI.AssertNotCalled(t, "AddUserToChannel", incdnt.ChannelID, "outsider_id", "bot_user_id")
+	})
+
+	t.Run("stale step is skipped", func(t *testing.T) {
+		controller := gomock.NewController(t)
+		pluginAPI := &plugintest.API{}
+		client := pluginapi.NewClient(pluginAPI)
+		store := mock_incident.NewMockStore(controller)
+		poster := mock_bot.NewMockPoster(controller)
+		logger := mock_bot.NewMockLogger(controller)
+		configService := mock_config.NewMockService(controller)
+		telemetryService := &telemetry.NoopTelemetry{}
+		scheduler := mock_incident.NewMockJobOnceScheduler(controller)
+
+		incdnt := newIncident()
+		incdnt.EscalationLevel = 1
+		store.EXPECT().GetIncident(incdnt.ID).Return(incdnt, nil)
+
+		s := incident.NewService(client, store, poster, logger, configService, scheduler, telemetryService, nil)
+
+		s.HandleReminder(incident.EscalationPrefix + incdnt.ID + "_2")
+		require.Equal(t, 1, incdnt.EscalationLevel)
+	})
+
+	t.Run("stale first step is skipped", func(t *testing.T) {
+		controller := gomock.NewController(t)
+		pluginAPI := &plugintest.API{}
+		client := pluginapi.NewClient(pluginAPI)
+		store := mock_incident.NewMockStore(controller)
+		poster := mock_bot.NewMockPoster(controller)
+		logger := mock_bot.NewMockLogger(controller)
+		configService := mock_config.NewMockService(controller)
+		telemetryService := &telemetry.NoopTelemetry{}
+		scheduler := mock_incident.NewMockJobOnceScheduler(controller)
+
+		incdnt := newIncident()
+		incdnt.EscalationLevel = 1
+		store.EXPECT().GetIncident(incdnt.ID).Return(incdnt, nil)
+
+		s := incident.NewService(client, store, poster, logger, configService, scheduler, telemetryService, nil)
+
+		s.HandleReminder(incident.EscalationPrefix + incdnt.ID)
+		require.Equal(t, 1, incdnt.EscalationLevel)
+	})
+}
+
+func TestMergeIncident(t *testing.T) {
+	controller := gomock.NewController(t)
+	pluginAPI := &plugintest.API{}
+	client := pluginapi.NewClient(pluginAPI)
+	store := mock_incident.NewMockStore(controller)
+	poster := mock_bot.NewMockPoster(controller)
+	logger := mock_bot.NewMockLogger(controller)
+	configService := mock_config.NewMockService(controller)
+	telemetryService := &telemetry.NoopTelemetry{}
+	scheduler := mock_incident.NewMockJobOnceScheduler(controller)
+
+	source := &incident.Incident{
+		ID:            "source_id",
+		Name:          "Source",
+		TeamID:        "team_id",
+		ChannelID:     "source_channel_id",
+		CurrentStatus: incident.StatusActive,
+		TimelineEvents: []incident.TimelineEvent{
+			{ID: "update_event_id", EventType: incident.StatusUpdated, EventAt: 10, PostID: "update_post_id", Summary: "Reported to Active"},
+			{ID: "owner_event_id", EventType: incident.OwnerChanged, EventAt: 20, Summary: "changed the owner"},
+		},
+		StatusPosts: []incident.StatusPost{
+			{ID: "legacy_post_id", Status: incident.StatusReported, CreateAt: 5},
+			{ID: "deleted_post_id", Status: incident.StatusActive, CreateAt: 7, DeleteAt: 8},
+			{ID: "update_post_id", Status: incident.StatusActive, CreateAt: 10},
+		},
+	}
+
+	// An earlier attempt at the merge copied the first event before failing.
+	target := &incident.Incident{
+		ID:            "target_id",
+		Name:          "Target",
+		TeamID:        "team_id",
+		ChannelID:     "target_channel_id",
+		CurrentStatus: incident.StatusActive,
+		TimelineEvents: []incident.TimelineEvent{
+			{ID: "copied_event_id", EventType: incident.StatusUpdated, EventAt: 10, PostID: "update_post_id", Summary: "[Source] Reported to Active"},
+		},
+	}
+
+	store.EXPECT().GetIncident(source.ID).Return(source, nil).Times(2)
+	store.EXPECT().GetIncident(target.ID).Return(target, nil).Times(2)
+
+	var events []*incident.TimelineEvent
+	store.EXPECT().CreateTimelineEvent(gomock.AssignableToTypeOf(&incident.TimelineEvent{})).
+		DoAndReturn(func(event *incident.TimelineEvent) (*incident.TimelineEvent, error) {
+			events = append(events, event)
+			return event, nil
+		}).
+		Times(4)
+
+	// The status update without a timeline event is attributed to the author of its post.
+	pluginAPI.On("GetPost", "legacy_post_id").Return(&model.Post{Id: "legacy_post_id", UserId: "author_id"}, nil)
+
+	configService.EXPECT().GetConfiguration().Return(&config.Configuration{BotUserID: "bot_user_id"})
+	pluginAPI.On("GetChannelMembers", source.ChannelID, 0, 200).Return(&model.ChannelMembers{}, nil)
+	pluginAPI.On("GetConfig").Return(&model.Config{})
+	pluginAPI.On("LogWarn", mock.Anything)
+	pluginAPI.On("GetUser", "user_id").Return(&model.User{Id: "user_id", Username: "username"}, nil)
+	poster.EXPECT().PostMessage(source.ChannelID, gomock.Any()).Return(&model.Post{Id: "source_post_id"}, nil)
+	poster.EXPECT().PostMessage(target.ChannelID, gomock.Any()).Return(&model.Post{Id: "target_post_id"}, nil)
+
+	// The status posts of the source stay with it.
+	store.EXPECT().UpdateIncident(source)
+	store.EXPECT().UpdateStatus(gomock.AssignableToTypeOf(&incident.SQLStatusPost{})).
+		Do(func(statusPost *incident.SQLStatusPost) {
+			require.Equal(t, source.ID, statusPost.IncidentID)
+			require.Equal(t, incident.StatusMerged, statusPost.Status)
+		})
+	scheduler.EXPECT().Cancel(gomock.Any()).Times(3)
+	poster.EXPECT().PublishWebsocketEventToChannel(gomock.Any(), gomock.Any(), gomock.Any()).Times(2)
+
+	s := incident.NewService(client, store, poster, logger, configService, scheduler, telemetryService, nil)
+
+	err := s.MergeIncident(source.ID, "user_id", target.ID)
+	require.NoError(t, err)
+	require.Equal(t, target.ID, source.MergedIntoID)
+
+	require.Len(t, events, 4)
+	require.Equal(t, target.ID, events[0].IncidentID)
+	require.Equal(t, incident.OwnerChanged, events[0].EventType)
+	require.Equal(t, "[Source] changed the owner", events[0].Summary)
+
+	require.Equal(t, target.ID, events[1].IncidentID)
+	require.Equal(t, incident.StatusUpdated, events[1].EventType)
+	require.Equal(t, "legacy_post_id", events[1].PostID)
+	require.Equal(t, "author_id", events[1].SubjectUserID)
+	require.Equal(t, "[Source] Reported", events[1].Summary)
+
+	require.Equal(t, source.ID, events[2].IncidentID)
+	require.Equal(t, incident.IncidentMerged, events[2].EventType)
+	require.Equal(t, target.ID, events[3].IncidentID)
+	require.Equal(t, incident.IncidentMerged, events[3].EventType)
+}
+
+func TestOpenUpdateStatusDialog(t *testing.T) {
+	t.Run("status update template is rendered with the current values", func(t *testing.T) {
+		controller := gomock.NewController(t)
+		pluginAPI := &plugintest.API{}
+		client := pluginapi.NewClient(pluginAPI)
+		store := mock_incident.NewMockStore(controller)
+		poster := mock_bot.NewMockPoster(controller)
+		logger := mock_bot.NewMockLogger(controller)
+		configService := mock_config.NewMockService(controller)
+		telemetryService := &telemetry.NoopTelemetry{}
+		scheduler := mock_incident.NewMockJobOnceScheduler(controller)
+
+		incdnt := &incident.Incident{
+			ID:                   "incident_id",
+			Name:                 "Checkout errors",
+			ChannelID:            "channel_id",
+			OwnerUserID:          "owner_id",
+			CurrentStatus:        incident.StatusActive,
+			StatusUpdateTemplate: "**{{.IncidentName}}** is {{.Status}}, owned by @{{.Owner}}.",
+		}
+
+		store.EXPECT().GetIncident(incdnt.ID).Return(incdnt, nil)
+		pluginAPI.On("GetUser", "owner_id").Return(&model.User{Id: "owner_id", Username: "alice"}, nil)
+		pluginAPI.On("GetChannel", "").Return(nil, &model.AppError{Message: "not found"})
+		configService.EXPECT().GetManifest().Return(&model.Manifest{Id: "plugin_id"})
+
+		var request model.OpenDialogRequest
+		pluginAPI.On("OpenInteractiveDialog", mock.AnythingOfType("model.OpenDialogRequest")).
+			Run(func(args mock.Arguments) {
+				request = args.Get(0).(model.OpenDialogRequest)
+			}).
+			Return(nil)
+
+		s := incident.NewService(client, store, poster, logger, configService, scheduler, telemetryService, nil)
+
+		err := s.OpenUpdateStatusDialog(incdnt.ID, "trigger_id")
+		require.NoError(t, err)
+
+		var message string
+		for _, element := range request.Dialog.Elements {
+			if element.Name == incident.DialogFieldMessageKey {
+				message = element.Default
+			}
+		}
+		require.Equal(t, "**Checkout errors** is Active, owned by @alice.", message)
+	})
+
+	t.Run("dialog is not opened for a merged incident", func(t *testing.T) {
+		controller := gomock.NewController(t)
+		pluginAPI := &plugintest.API{}
+		client := pluginapi.NewClient(pluginAPI)
+		store := mock_incident.NewMockStore(controller)
+		poster := mock_bot.NewMockPoster(controller)
+		logger := mock_bot.NewMockLogger(controller)
+		configService := mock_config.NewMockService(controller)
+		telemetryService := &telemetry.NoopTelemetry{}
+		scheduler := mock_incident.NewMockJobOnceScheduler(controller)
+
+		incdnt := &incident.Incident{
+			ID:            "incident_id",
+			ChannelID:     "channel_id",
+			CurrentStatus: incident.StatusMerged,
+		}
+		store.EXPECT().GetIncident(incdnt.ID).Return(incdnt, nil)
+
+		s := incident.NewService(client, store, poster, logger, configService, scheduler, telemetryService, nil)
+
+		err := s.OpenUpdateStatusDialog(incdnt.ID, "trigger_id")
+		require.ErrorIs(t, err, incident.ErrIncidentMerged)
+		require.Equal(t, incident.StatusMerged, incdnt.CurrentStatus)
+	})
+}
+
+func TestImportIncident(t *testing.T) {
+	controller := gomock.NewController(t)
+	pluginAPI := &plugintest.API{}
+	client := pluginapi.NewClient(pluginAPI)
+	store := mock_incident.NewMockStore(controller)
+	poster := mock_bot.NewMockPoster(controller)
+	logger := mock_bot.NewMockLogger(controller)
+	configService := mock_config.NewMockService(controller)
+	telemetryService := &telemetry.NoopTelemetry{}
+	scheduler := mock_incident.NewMockJobOnceScheduler(controller)
+
+	incdnt := &incident.Incident{
+		Name:        "Incident",
+		ChannelID:   "channel_id",
+		FollowerIDs: []string{"user_id1", "user_id2"},
+	}
+	timelineEvents := []incident.TimelineEvent{{EventType: incident.IncidentCreated}}
+	statusPosts := []incident.StatusPost{{ID: "post_id", Status: incident.StatusResolved, CreateAt: 1}}
+
+	created := incdnt.Clone()
+	created.ID = "incident_id"
+	created.FollowerIDs = nil
+	gomock.InOrder(
+		store.EXPECT().CreateIncident(incdnt).Return(created, nil),
+		store.EXPECT().CreateTimelineEvent(gomock.AssignableToTypeOf(&incident.TimelineEvent{})).
+			DoAndReturn(func(event *incident.TimelineEvent) (*incident.TimelineEvent, error) {
+				require.Equal(t, "incident_id", event.IncidentID)
+				return event, nil
+			}),
+	)
+	store.EXPECT().AddStatusPosts("incident_id", statusPosts).Return(nil)
+	store.EXPECT().Follow("incident_id", "user_id1").Return(nil)
+	store.EXPECT().Follow("incident_id", "user_id2").Return(nil)
+
+	s := incident.NewService(client, store, poster, logger, configService, scheduler, telemetryService, nil)
+
+	imported, err := s.ImportIncident(incdnt, timelineEvents, statusPosts)
+	require.NoError(t, err)
+	require.Equal(t, statusPosts, imported.StatusPosts)
+	require.Equal(t, []string{"user_id1", "user_id2"}, imported.FollowerIDs)
+	require.Len(t, imported.TimelineEvents, 1)
 }
 
 func TestOpenCreateIncidentDialog(t *testing.T) {
@@ -542,6 +1526,46 @@ func TestOpenCreateIncidentDialog(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "custom fields named after their playbook",
+			args: args{
+				teamID:    "teamID",
+				ownerID:   "ownerID",
+				triggerID: "triggerID",
+				playbooks: []playbook.Playbook{
+					{
+						ID:           "outage",
+						Title:        "Outage",
+						CustomFields: []playbook.CustomField{{Key: "region", Name: "Region", Type: playbook.CustomFieldTypeText, Required: true}},
+					},
+					{
+						ID:           "breach",
+						Title:        "Breach",
+						CustomFields: []playbook.CustomField{{Key: "region", Name: "Region", Type: playbook.CustomFieldTypeSelect, Options: []string{"EU", "US"}}},
+					},
+				},
+			},
+			prepMocks: func(t *testing.T, store *mock_incident.MockStore, poster *mock_bot.MockPoster, api *plugintest.API, configService *mock_config.MockService) {
+				api.On("GetTeam", "teamID").
+					Return(&model.Team{Id: "teamID", Name: "Team"}, nil)
+				api.On("GetUser", "ownerID").
+					Return(&model.User{Id: "ownerID", Username: "User"}, nil)
+				api.On("GetConfig").
+					Return(&model.Config{ServiceSettings: model.ServiceSettings{SiteURL: model.NewString("")}})
+				configService.EXPECT().GetManifest().Return(&model.Manifest{Id: "pluginId"}).Times(2)
+				api.On("OpenInteractiveDialog", mock.AnythingOfType("model.OpenDialogRequest")).Return(nil).Run(func(args mock.Arguments) {
+					elements := args.Get(0).(model.OpenDialogRequest).Dialog.Elements
+					require.Len(t, elements, 5)
+					assert.Equal(t, incident.CustomFieldDialogElementName("outage", "region"), elements[3].Name)
+					assert.Equal(t, "text", elements[3].Type)
+					assert.True(t, elements[3].Optional)
+					assert.Contains(t, elements[3].HelpText, "Outage")
+					assert.Equal(t, incident.CustomFieldDialogElementName("breach", "region"), elements[4].Name)
+					assert.Equal(t, "select", elements[4].Type)
+				})
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -554,7 +1578,7 @@ func TestOpenCreateIncidentDialog(t *testing.T) {
 			configService := mock_config.NewMockService(controller)
 			telemetryService := &telemetry.NoopTelemetry{}
 			scheduler := mock_incident.NewMockJobOnceScheduler(controller)
-			service := incident.NewService(client, store, poster, logger, configService, scheduler, telemetryService)
+			service := incident.NewService(client, store, poster, logger, configService, scheduler, telemetryService, nil)
 
 			tt.prepMocks(t, store, poster, api, configService)
 
@@ -566,3 +1590,61 @@ func TestOpenCreateIncidentDialog(t *testing.T) {
 		})
 	}
 }
+
+func TestEditTimelineEvent(t *testing.T) {
+	newService := func(t *testing.T) (incident.Service, *mock_incident.MockStore, *mock_bot.MockPoster) {
+		controller := gomock.NewController(t)
+		pluginAPI := &plugintest.API{}
+		client := pluginapi.NewClient(pluginAPI)
+		store := mock_incident.NewMockStore(controller)
+		poster := mock_bot.NewMockPoster(controller)
+		logger := mock_bot.NewMockLogger(controller)
+		configService := mock_config.NewMockService(controller)
+		telemetryService := &telemetry.NoopTelemetry{}
+		scheduler := mock_incident.NewMockJobOnceScheduler(controller)
+
+		return incident.NewService(client, store, poster, logger, configService, scheduler, telemetryService, nil), store, poster
+	}
+
+	t.Run("time of an event without a summary is edited", func(t *testing.T) {
+		s, store, poster := newService(t)
+
+		incdnt := &incident.Incident{ID: "incident_id", ChannelID: "channel_id"}
+		event := &incident.TimelineEvent{
+			ID:         "event_id",
+			IncidentID: incdnt.ID,
+			EventAt:    1000,
+			EventType:  incident.IncidentCreated,
+		}
+
+		store.EXPECT().GetTimelineEvent(incdnt.ID, event.ID).Return(event, nil)
+		store.EXPECT().EditTimelineEvent(event, gomock.AssignableToTypeOf(&incident.TimelineEventEdit{})).
+			Do(func(edited *incident.TimelineEvent, edit *incident.TimelineEventEdit) {
+				require.Equal(t, int64(2000), edited.EventAt)
+				require.Empty(t, edited.Summary)
+				require.Equal(t, int64(1000), edit.PreviousEventAt)
+			})
+		store.EXPECT().GetIncident(incdnt.ID).Return(incdnt, nil).Times(2)
+		poster.EXPECT().PublishWebsocketEventToChannel(gomock.Any(), gomock.Any(), incdnt.ChannelID)
+
+		err := s.EditTimelineEvent(incdnt.ID, "user_id", event.ID, incident.TimelineEventOptions{EventAt: 2000})
+		require.NoError(t, err)
+	})
+
+	t.Run("summary cannot be edited to a blank one", func(t *testing.T) {
+		s, store, _ := newService(t)
+
+		event := &incident.TimelineEvent{
+			ID:         "event_id",
+			IncidentID: "incident_id",
+			EventAt:    1000,
+			EventType:  incident.ManualEvent,
+			Summary:    "Customer reported",
+		}
+
+		store.EXPECT().GetTimelineEvent(event.IncidentID, event.ID).Return(event, nil)
+
+		err := s.EditTimelineEvent(event.IncidentID, "user_id", event.ID, incident.TimelineEventOptions{EventAt: 1000, Summary: " \n"})
+		require.ErrorIs(t, err, incident.ErrMalformedTimelineEvent)
+	})
+}
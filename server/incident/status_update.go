@@ -0,0 +1,99 @@
+package incident
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/playbook"
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/timeutils"
+)
+
+// statusUpdateData is the data available to the status update template of an incident.
+type statusUpdateData struct {
+	IncidentName      string
+	Description       string
+	Status            string
+	Severity          string
+	Owner             string // Username of the owner.
+	Duration          string // Time since the incident started.
+	TasksDone         int
+	TasksTotal        int
+	ChecklistProgress string            // Tasks done out of the total, as "3/10".
+	SinceLastUpdate   string            // Time since the last status update; blank before the first one.
+	Fields            map[string]string // Values of the custom fields, indexed by key; multiple values are comma-separated.
+}
+
+// newStatusUpdateData returns the data of the status update template of theIncident at now.
+func newStatusUpdateData(theIncident *Incident, ownerUsername string, now time.Time) statusUpdateData {
+	data := statusUpdateData{
+		IncidentName: theIncident.Name,
+		Description:  theIncident.Description,
+		Status:       theIncident.CurrentStatus,
+		Severity:     theIncident.Severity,
+		Owner:        ownerUsername,
+		Duration:     timeutils.DurationString(timeutils.GetTimeForMillis(theIncident.CreateAt), now),
+		Fields:       map[string]string{},
+	}
+
+	for _, checklist := range theIncident.Checklists {
+		for _, item := range checklist.Items {
+			data.TasksTotal++
+			if item.State == playbook.ChecklistItemStateClosed {
+				data.TasksDone++
+			}
+		}
+	}
+	data.ChecklistProgress = fmt.Sprintf("%d/%d", data.TasksDone, data.TasksTotal)
+
+	if lastUpdate := findNewestNonDeletedStatusPost(theIncident.StatusPosts); lastUpdate != nil {
+		data.SinceLastUpdate = timeutils.DurationString(timeutils.GetTimeForMillis(lastUpdate.CreateAt), now)
+	}
+
+	for _, field := range theIncident.CustomFields {
+		data.Fields[field.Key] = strings.Join(theIncident.CustomFieldValues[field.Key], ", ")
+	}
+
+	return data
+}
+
+// renderStatusUpdate executes tmpl, the status update template of an incident, with data.
+func renderStatusUpdate(tmpl string, data statusUpdateData) (string, error) {
+	t, err := template.New("status_update").Parse(tmpl)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse status update")
+	}
+
+	var out bytes.Buffer
+	if err = t.Execute(&out, data); err != nil {
+		return "", errors.Wrap(err, "failed to execute status update")
+	}
+
+	return out.String(), nil
+}
+
+// renderStatusUpdateTemplate renders the status update template of theIncident with its current
+// values, to pre-fill a status update that the user then edits and posts as is. The template is
+// returned as is if it cannot be rendered.
+func (s *ServiceImpl) renderStatusUpdateTemplate(theIncident *Incident) string {
+	if theIncident.StatusUpdateTemplate == "" {
+		return ""
+	}
+
+	ownerUsername := ""
+	if owner, err := s.pluginAPI.User.Get(theIncident.OwnerUserID); err == nil {
+		ownerUsername = owner.Username
+	}
+
+	rendered, err := renderStatusUpdate(theIncident.StatusUpdateTemplate, newStatusUpdateData(theIncident, ownerUsername, time.Now()))
+	if err != nil {
+		s.pluginAPI.Log.Warn("failed to render the status update template", "incident_id", theIncident.ID, "error", err.Error())
+		return theIncident.StatusUpdateTemplate
+	}
+
+	return rendered
+}
@@ -0,0 +1,50 @@
+package incident
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/playbook"
+)
+
+func TestRenderStatusUpdate(t *testing.T) {
+	now := time.Date(2021, 5, 14, 12, 0, 0, 0, time.UTC)
+	theIncident := &Incident{
+		Name:          "Checkout errors",
+		CurrentStatus: StatusActive,
+		Severity:      SeverityHigh,
+		CreateAt:      now.Add(-2*time.Hour).UnixNano() / int64(time.Millisecond),
+		Checklists: []playbook.Checklist{
+			{Items: []playbook.ChecklistItem{
+				{State: playbook.ChecklistItemStateClosed},
+				{State: playbook.ChecklistItemStateOpen},
+			}},
+			{Items: []playbook.ChecklistItem{{State: playbook.ChecklistItemStateClosed}}},
+		},
+		StatusPosts: []StatusPost{
+			{CreateAt: now.Add(-90*time.Minute).UnixNano() / int64(time.Millisecond)},
+			{CreateAt: now.Add(-30*time.Minute).UnixNano() / int64(time.Millisecond)},
+			{CreateAt: now.Add(-10*time.Minute).UnixNano() / int64(time.Millisecond), DeleteAt: 1},
+		},
+		CustomFields:      []playbook.CustomField{{Key: "region"}, {Key: "services"}},
+		CustomFieldValues: map[string][]string{"services": {"cart", "payments"}},
+	}
+
+	data := newStatusUpdateData(theIncident, "alice", now)
+	require.Equal(t, 2, data.TasksDone)
+	require.Equal(t, 3, data.TasksTotal)
+
+	message, err := renderStatusUpdate(
+		"**{{.IncidentName}}** ({{.Status}}, {{.Severity}}) by @{{.Owner}}: {{.ChecklistProgress}} tasks done, "+
+			"last update {{.SinceLastUpdate}} ago. Services: {{.Fields.services}}. Region: {{.Fields.region}}.", data)
+	require.NoError(t, err)
+	require.Equal(t, "**Checkout errors** (Active, SEV2) by @alice: 2/3 tasks done, last update 30m ago. "+
+		"Services: cart, payments. Region: .", message)
+
+	_, err = renderStatusUpdate("{{.Commander}}", data)
+	require.Error(t, err)
+
+	require.Empty(t, newStatusUpdateData(&Incident{}, "", now).SinceLastUpdate)
+}
@@ -0,0 +1,172 @@
+package incident
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/pkg/errors"
+)
+
+const (
+	// MaxTimelineEventSummaryLength is the maximum number of characters of the summary of a timeline event.
+	MaxTimelineEventSummaryLength = 256
+
+	// MaxTimelineEventDetailsLength is the maximum number of characters of the details of a timeline event.
+	MaxTimelineEventDetailsLength = 4096
+)
+
+// ValidateTimelineEventOptions checks that options describe a valid timeline event: a summary,
+// a time that is not negative, and a summary and details that fit in the store.
+func ValidateTimelineEventOptions(options TimelineEventOptions) error {
+	if err := validateTimelineEventSummary(options.Summary); err != nil {
+		return err
+	}
+
+	return validateTimelineEventTimeAndDetails(options)
+}
+
+// validateTimelineEventSummary checks that summary is not blank and fits in the store.
+func validateTimelineEventSummary(summary string) error {
+	if strings.TrimSpace(summary) == "" {
+		return errors.New("summary must not be empty")
+	}
+	if utf8.RuneCountInString(summary) > MaxTimelineEventSummaryLength {
+		return errors.Errorf("summary must not be longer than %d characters", MaxTimelineEventSummaryLength)
+	}
+
+	return nil
+}
+
+// validateTimelineEventTimeAndDetails checks that the time of options is not negative and that
+// its details fit in the store.
+func validateTimelineEventTimeAndDetails(options TimelineEventOptions) error {
+	if utf8.RuneCountInString(options.Details) > MaxTimelineEventDetailsLength {
+		return errors.Errorf("details must not be longer than %d characters", MaxTimelineEventDetailsLength)
+	}
+	if options.EventAt < 0 {
+		return errors.New("event_at must not be negative")
+	}
+
+	return nil
+}
+
+// AddTimelineEvent adds an event that is not tied to a post to the timeline of incidentID, and
+// returns its ID.
+func (s *ServiceImpl) AddTimelineEvent(incidentID, userID string, options TimelineEventOptions) (string, error) {
+	if err := ValidateTimelineEventOptions(options); err != nil {
+		return "", errors.Wrap(ErrMalformedTimelineEvent, err.Error())
+	}
+
+	theIncident, err := s.store.GetIncident(incidentID)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to retrieve incident")
+	}
+
+	now := model.GetMillis()
+	if options.EventAt == 0 {
+		options.EventAt = now
+	}
+
+	event := &TimelineEvent{
+		IncidentID:    incidentID,
+		CreateAt:      now,
+		EventAt:       options.EventAt,
+		EventType:     ManualEvent,
+		Summary:       strings.TrimSpace(options.Summary),
+		Details:       strings.TrimSpace(options.Details),
+		SubjectUserID: userID,
+		CreatorUserID: userID,
+	}
+
+	if err = s.createTimelineEvent(theIncident, event); err != nil {
+		return "", errors.Wrap(err, "failed to create timeline event")
+	}
+
+	s.telemetry.AddTimelineEvent(theIncident, userID)
+
+	if err = s.sendIncidentToClient(incidentID); err != nil {
+		return "", err
+	}
+
+	return event.ID, nil
+}
+
+// EditTimelineEvent changes the time, summary and details of the timeline event eventID of
+// incidentID, and records the previous values. An edit changing nothing is not recorded. The
+// summary is only checked if it changes, since the events recorded by the plugin may have a blank
+// or longer summary.
+func (s *ServiceImpl) EditTimelineEvent(incidentID, userID, eventID string, options TimelineEventOptions) error {
+	if err := validateTimelineEventTimeAndDetails(options); err != nil {
+		return errors.Wrap(ErrMalformedTimelineEvent, err.Error())
+	}
+	if options.EventAt == 0 {
+		return errors.Wrap(ErrMalformedTimelineEvent, "event_at must be set")
+	}
+
+	event, err := s.store.GetTimelineEvent(incidentID, eventID)
+	if err != nil {
+		return err
+	}
+	if event.DeleteAt != 0 {
+		return errors.Wrapf(ErrNotFound, "timeline event with id (%s) was removed", eventID)
+	}
+
+	summary := strings.TrimSpace(options.Summary)
+	if summary != event.Summary {
+		if err = validateTimelineEventSummary(summary); err != nil {
+			return errors.Wrap(ErrMalformedTimelineEvent, err.Error())
+		}
+	}
+
+	details := strings.TrimSpace(options.Details)
+	if event.EventAt == options.EventAt && event.Summary == summary && event.Details == details {
+		return nil
+	}
+
+	edit := &TimelineEventEdit{
+		EventID:         eventID,
+		IncidentID:      incidentID,
+		EditorUserID:    userID,
+		EditAt:          model.GetMillis(),
+		PreviousEventAt: event.EventAt,
+		PreviousSummary: event.Summary,
+		PreviousDetails: event.Details,
+	}
+
+	event.EventAt = options.EventAt
+	event.Summary = summary
+	event.Details = details
+	if err = s.store.EditTimelineEvent(event, edit); err != nil {
+		return errors.Wrap(err, "failed to edit timeline event")
+	}
+
+	theIncident, err := s.store.GetIncident(incidentID)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve incident")
+	}
+
+	s.telemetry.EditTimelineEvent(theIncident, userID)
+
+	return s.sendIncidentToClient(incidentID)
+}
+
+// GetTimelineEventEdits returns the edits of the timeline event eventID of incidentID, oldest
+// first. The edits of a removed event can still be retrieved.
+func (s *ServiceImpl) GetTimelineEventEdits(incidentID, eventID string) ([]TimelineEventEdit, error) {
+	if _, err := s.store.GetTimelineEvent(incidentID, eventID); err != nil {
+		return nil, err
+	}
+
+	edits, err := s.store.GetTimelineEventEdits(eventID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the edits of the timeline event")
+	}
+
+	return edits, nil
+}
+
+// GetTimelineEvents returns a page of the timeline of incidentID, filtered by options.
+func (s *ServiceImpl) GetTimelineEvents(incidentID string, options TimelineFilterOptions) (*GetTimelineEventsResults, error) {
+	return s.store.GetTimelineEvents(incidentID, options)
+}
@@ -0,0 +1,321 @@
+package incident
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/timeutils"
+)
+
+// TimelineExportFormat is a file format to which the timeline of an incident can be exported.
+type TimelineExportFormat string
+
+const (
+	TimelineExportFormatMarkdown TimelineExportFormat = "md"
+	TimelineExportFormatCSV      TimelineExportFormat = "csv"
+	TimelineExportFormatICS      TimelineExportFormat = "ics"
+)
+
+// ContentType returns the MIME type of a timeline exported in format f.
+func (f TimelineExportFormat) ContentType() string {
+	switch f {
+	case TimelineExportFormatCSV:
+		return "text/csv"
+	case TimelineExportFormatICS:
+		return "text/calendar"
+	default:
+		return "text/markdown"
+	}
+}
+
+// TimelineExportOptions specifies the format of a timeline export, and which events it includes.
+type TimelineExportOptions struct {
+	Format     TimelineExportFormat
+	EventTypes []string // Types of the events to export; every type if empty.
+	Since      int64    // Events that happened before Since are left out; 0 for no lower bound.
+	Until      int64    // Events that happened after Until are left out; 0 for no upper bound.
+}
+
+// ValidateTimelineExportOptions checks options, and defaults their format to markdown.
+func ValidateTimelineExportOptions(options *TimelineExportOptions) error {
+	switch options.Format {
+	case "":
+		options.Format = TimelineExportFormatMarkdown
+	case TimelineExportFormatMarkdown, TimelineExportFormatCSV, TimelineExportFormatICS:
+	default:
+		return errors.Errorf("format must be one of %s, %s or %s", TimelineExportFormatMarkdown, TimelineExportFormatCSV, TimelineExportFormatICS)
+	}
+
+	for _, eventType := range options.EventTypes {
+		if !IsValidTimelineEventType(eventType) {
+			return errors.Errorf("unknown event type %s", eventType)
+		}
+	}
+
+	if options.Since < 0 || options.Until < 0 {
+		return errors.New("since and until must not be negative")
+	}
+	if options.Until != 0 && options.Since > options.Until {
+		return errors.New("since must not be after until")
+	}
+
+	return nil
+}
+
+// includes returns true if event passes the event type and time filters of options.
+func (options TimelineExportOptions) includes(event TimelineEvent) bool {
+	if options.Since != 0 && event.EventAt < options.Since {
+		return false
+	}
+	if options.Until != 0 && event.EventAt > options.Until {
+		return false
+	}
+	if len(options.EventTypes) == 0 {
+		return true
+	}
+	for _, eventType := range options.EventTypes {
+		if eventType == string(event.EventType) {
+			return true
+		}
+	}
+	return false
+}
+
+// TimelineExportFileName returns the name of the file to which the timeline of incidentID is
+// exported in format.
+func TimelineExportFileName(incidentID string, format TimelineExportFormat) string {
+	return fmt.Sprintf("timeline-%s.%s", incidentID, format)
+}
+
+// exportedTimelineEvent is a timeline event, with what an export shows of it besides its fields.
+type exportedTimelineEvent struct {
+	TimelineEvent
+	Actor         string // Display name of the user who created the event, or of its subject.
+	SinceReported string // Time between the report of the incident and the event; negative if the event happened before.
+}
+
+// reportedAt returns when theIncident was reported: the time of its incident_created event, or its
+// creation time if the event is missing.
+func (i *Incident) reportedAt() int64 {
+	for _, event := range i.TimelineEvents {
+		if event.EventType == IncidentCreated {
+			return event.EventAt
+		}
+	}
+	return i.CreateAt
+}
+
+// sinceReported returns the time between reportedAt and eventAt, prefixed with a minus sign if the
+// event happened before the report.
+func sinceReported(reportedAt, eventAt int64) string {
+	reported := timeutils.GetTimeForMillis(reportedAt)
+	at := timeutils.GetTimeForMillis(eventAt)
+	if at.Before(reported) {
+		return "-" + timeutils.DurationString(at, reported)
+	}
+	return timeutils.DurationString(reported, at)
+}
+
+// newExportedTimelineEvents returns the events of theIncident that pass the filters of options,
+// oldest first. displayNames are the display names of the users, indexed by user ID.
+func newExportedTimelineEvents(theIncident *Incident, options TimelineExportOptions, displayNames map[string]string) []exportedTimelineEvent {
+	reportedAt := theIncident.reportedAt()
+
+	exported := []exportedTimelineEvent{}
+	for _, event := range theIncident.TimelineEvents {
+		if event.DeleteAt != 0 || !options.includes(event) {
+			continue
+		}
+
+		actorID := event.CreatorUserID
+		if actorID == "" {
+			actorID = event.SubjectUserID
+		}
+
+		exported = append(exported, exportedTimelineEvent{
+			TimelineEvent: event,
+			Actor:         displayNames[actorID],
+			SinceReported: sinceReported(reportedAt, event.EventAt),
+		})
+	}
+
+	return exported
+}
+
+// marshalTimeline returns events, the exported timeline of theIncident, in format. now is the
+// time of the export.
+func marshalTimeline(theIncident *Incident, events []exportedTimelineEvent, format TimelineExportFormat, now time.Time) ([]byte, error) {
+	switch format {
+	case TimelineExportFormatCSV:
+		return marshalTimelineCSV(events)
+	case TimelineExportFormatICS:
+		return marshalTimelineICS(theIncident, events, now), nil
+	default:
+		return marshalTimelineMarkdown(theIncident, events), nil
+	}
+}
+
+// escapeMarkdownCell makes text fit in a cell of a markdown table.
+func escapeMarkdownCell(text string) string {
+	text = strings.ReplaceAll(text, "|", `\|`)
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func marshalTimelineMarkdown(theIncident *Incident, events []exportedTimelineEvent) []byte {
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "### Timeline of %s\n\n", theIncident.Name)
+	out.WriteString("| Event Time | Since Reported | Event Type | Actor | Summary | Details |\n")
+	out.WriteString("|:-----------|:---------------|:-----------|:------|:--------|:--------|\n")
+	for _, event := range events {
+		fmt.Fprintf(&out, "| %s | %s | %s | %s | %s | %s |\n",
+			timeutils.GetTimeForMillis(event.EventAt).UTC().Format("Jan 2, 2006 15:04 MST"),
+			event.SinceReported,
+			event.EventType,
+			escapeMarkdownCell(event.Actor),
+			escapeMarkdownCell(event.Summary),
+			escapeMarkdownCell(event.Details),
+		)
+	}
+
+	return out.Bytes()
+}
+
+// timelineCSVHeader lists the columns of a timeline exported as CSV.
+var timelineCSVHeader = []string{
+	"id",
+	"event_at",
+	"since_reported",
+	"event_type",
+	"actor",
+	"summary",
+	"details",
+	"post_id",
+}
+
+func marshalTimelineCSV(events []exportedTimelineEvent) ([]byte, error) {
+	var out bytes.Buffer
+	writer := csv.NewWriter(&out)
+	if err := writer.Write(timelineCSVHeader); err != nil {
+		return nil, errors.Wrap(err, "failed to write csv header")
+	}
+
+	for _, event := range events {
+		err := writer.Write([]string{
+			event.ID,
+			timeutils.GetTimeForMillis(event.EventAt).UTC().Format(time.RFC3339),
+			event.SinceReported,
+			string(event.EventType),
+			event.Actor,
+			event.Summary,
+			event.Details,
+			event.PostID,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to write csv row for timeline event %s", event.ID)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, errors.Wrap(err, "failed to write csv")
+	}
+
+	return out.Bytes(), nil
+}
+
+// icsTimeFormat is the format of the UTC times of an iCalendar file.
+const icsTimeFormat = "20060102T150405Z"
+
+// escapeICSText escapes text to be the value of a text property of an iCalendar file.
+func escapeICSText(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(text)
+}
+
+// writeICSLine writes line to out, folded into lines of at most 75 octets as iCalendar requires.
+func writeICSLine(out *bytes.Buffer, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		out.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts towards their length.
+		limit = 74
+	}
+	out.WriteString(line + "\r\n")
+}
+
+func marshalTimelineICS(theIncident *Incident, events []exportedTimelineEvent, now time.Time) []byte {
+	var out bytes.Buffer
+	writeICSLine(&out, "BEGIN:VCALENDAR")
+	writeICSLine(&out, "VERSION:2.0")
+	writeICSLine(&out, "PRODID:-//Mattermost//Incident Collaboration//EN")
+	writeICSLine(&out, "X-WR-CALNAME:"+escapeICSText("Timeline of "+theIncident.Name))
+	for _, event := range events {
+		summary := event.Summary
+		if summary == "" {
+			summary = string(event.EventType)
+		}
+
+		description := fmt.Sprintf("Event type: %s\nActor: %s\nSince reported: %s", event.EventType, event.Actor, event.SinceReported)
+		if event.Details != "" {
+			description += "\n\n" + event.Details
+		}
+
+		writeICSLine(&out, "BEGIN:VEVENT")
+		writeICSLine(&out, "UID:"+event.ID+"@"+theIncident.ID)
+		writeICSLine(&out, "DTSTAMP:"+now.UTC().Format(icsTimeFormat))
+		writeICSLine(&out, "DTSTART:"+timeutils.GetTimeForMillis(event.EventAt).UTC().Format(icsTimeFormat))
+		writeICSLine(&out, "SUMMARY:"+escapeICSText(summary))
+		writeICSLine(&out, "DESCRIPTION:"+escapeICSText(description))
+		writeICSLine(&out, "CATEGORIES:"+escapeICSText(string(event.EventType)))
+		writeICSLine(&out, "END:VEVENT")
+	}
+	writeICSLine(&out, "END:VCALENDAR")
+
+	return out.Bytes()
+}
+
+// ExportTimeline returns the timeline of incidentID in the format of options, restricted to the
+// events that pass their filters. Users who cannot be retrieved have a blank display name.
+func (s *ServiceImpl) ExportTimeline(incidentID string, options TimelineExportOptions) ([]byte, error) {
+	if err := ValidateTimelineExportOptions(&options); err != nil {
+		return nil, errors.Wrap(ErrMalformedTimelineExport, err.Error())
+	}
+
+	theIncident, err := s.store.GetIncident(incidentID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve incident")
+	}
+
+	nameFormat := model.SHOW_USERNAME
+	if setting := s.pluginAPI.Configuration.GetConfig().TeamSettings.TeammateNameDisplay; setting != nil {
+		nameFormat = *setting
+	}
+
+	displayNames := map[string]string{}
+	for _, event := range theIncident.TimelineEvents {
+		for _, userID := range []string{event.CreatorUserID, event.SubjectUserID} {
+			if _, ok := displayNames[userID]; ok || userID == "" {
+				continue
+			}
+			displayNames[userID] = ""
+			if user, err2 := s.pluginAPI.User.Get(userID); err2 == nil {
+				displayNames[userID] = user.GetDisplayName(nameFormat)
+			}
+		}
+	}
+
+	events := newExportedTimelineEvents(theIncident, options, displayNames)
+
+	return marshalTimeline(theIncident, events, options.Format, time.Now())
+}
@@ -0,0 +1,143 @@
+package incident
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateTimelineExportOptions(t *testing.T) {
+	options := TimelineExportOptions{}
+	require.NoError(t, ValidateTimelineExportOptions(&options))
+	require.Equal(t, TimelineExportFormatMarkdown, options.Format)
+
+	require.NoError(t, ValidateTimelineExportOptions(&TimelineExportOptions{
+		Format:     TimelineExportFormatICS,
+		EventTypes: []string{string(StatusUpdated), string(ManualEvent)},
+		Since:      100,
+		Until:      200,
+	}))
+	require.Error(t, ValidateTimelineExportOptions(&TimelineExportOptions{Format: "pdf"}))
+	require.Error(t, ValidateTimelineExportOptions(&TimelineExportOptions{EventTypes: []string{"unknown"}}))
+	require.Error(t, ValidateTimelineExportOptions(&TimelineExportOptions{Since: 200, Until: 100}))
+	require.Error(t, ValidateTimelineExportOptions(&TimelineExportOptions{Since: -1}))
+}
+
+func TestExportTimeline(t *testing.T) {
+	reportedAt := time.Date(2021, time.March, 4, 12, 0, 0, 0, time.UTC)
+	millis := func(d time.Duration) int64 {
+		return reportedAt.Add(d).UnixNano() / int64(time.Millisecond)
+	}
+
+	theIncident := &Incident{
+		ID:       "incident",
+		Name:     "Database | down",
+		CreateAt: millis(time.Minute),
+		TimelineEvents: []TimelineEvent{
+			{ID: "customer", EventAt: millis(-5 * time.Minute), EventType: ManualEvent, Summary: "Customer reported", Details: "By phone,\nto support.", SubjectUserID: "alice", CreatorUserID: "alice"},
+			{ID: "created", EventAt: millis(0), EventType: IncidentCreated, SubjectUserID: "alice"},
+			{ID: "status", EventAt: millis(90 * time.Minute), EventType: StatusUpdated, Summary: "Reported to Active", SubjectUserID: "bob"},
+			{ID: "post", EventAt: millis(2 * time.Hour), EventType: EventFromPost, Summary: "Found the cause", SubjectUserID: "bob", CreatorUserID: "alice"},
+		},
+	}
+	displayNames := map[string]string{"alice": "Alice Doe", "bob": "Bob Roe"}
+
+	t.Run("filters", func(t *testing.T) {
+		ids := func(events []exportedTimelineEvent) []string {
+			var ids []string
+			for _, event := range events {
+				ids = append(ids, event.ID)
+			}
+			return ids
+		}
+
+		events := newExportedTimelineEvents(theIncident, TimelineExportOptions{}, displayNames)
+		require.Equal(t, []string{"customer", "created", "status", "post"}, ids(events))
+		require.Equal(t, "Alice Doe", events[3].Actor)
+		require.Equal(t, "Bob Roe", events[2].Actor)
+		require.Equal(t, "-5m", events[0].SinceReported)
+		require.Equal(t, "1h 30m", events[2].SinceReported)
+
+		events = newExportedTimelineEvents(theIncident, TimelineExportOptions{EventTypes: []string{string(StatusUpdated), string(ManualEvent)}}, displayNames)
+		require.Equal(t, []string{"customer", "status"}, ids(events))
+
+		events = newExportedTimelineEvents(theIncident, TimelineExportOptions{Since: millis(0), Until: millis(90 * time.Minute)}, displayNames)
+		require.Equal(t, []string{"created", "status"}, ids(events))
+	})
+
+	events := newExportedTimelineEvents(theIncident, TimelineExportOptions{EventTypes: []string{string(ManualEvent), string(StatusUpdated)}}, displayNames)
+
+	t.Run("markdown", func(t *testing.T) {
+		out, err := marshalTimeline(theIncident, events, TimelineExportFormatMarkdown, time.Now())
+		require.NoError(t, err)
+
+		expected := "### Timeline of Database | down\n\n" +
+			"| Event Time | Since Reported | Event Type | Actor | Summary | Details |\n" +
+			"|:-----------|:---------------|:-----------|:------|:--------|:--------|\n" +
+			"| Mar 4, 2021 11:55 UTC | -5m | manual_event | Alice Doe | Customer reported | By phone, to support. |\n" +
+			"| Mar 4, 2021 13:30 UTC | 1h 30m | status_updated | Bob Roe | Reported to Active |  |\n"
+		require.Equal(t, expected, string(out))
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		out, err := marshalTimeline(theIncident, events, TimelineExportFormatCSV, time.Now())
+		require.NoError(t, err)
+
+		expected := "id,event_at,since_reported,event_type,actor,summary,details,post_id\n" +
+			"customer,2021-03-04T11:55:00Z,-5m,manual_event,Alice Doe,Customer reported,\"By phone,\nto support.\",\n" +
+			"status,2021-03-04T13:30:00Z,1h 30m,status_updated,Bob Roe,Reported to Active,,\n"
+		require.Equal(t, expected, string(out))
+	})
+
+	t.Run("ics", func(t *testing.T) {
+		out, err := marshalTimeline(theIncident, events, TimelineExportFormatICS, reportedAt.Add(3*time.Hour))
+		require.NoError(t, err)
+
+		expected := "BEGIN:VCALENDAR\r\n" +
+			"VERSION:2.0\r\n" +
+			"PRODID:-//Mattermost//Incident Collaboration//EN\r\n" +
+			"X-WR-CALNAME:Timeline of Database | down\r\n" +
+			"BEGIN:VEVENT\r\n" +
+			"UID:customer@incident\r\n" +
+			"DTSTAMP:20210304T150000Z\r\n" +
+			"DTSTART:20210304T115500Z\r\n" +
+			"SUMMARY:Customer reported\r\n" +
+			"DESCRIPTION:Event type: manual_event\\nActor: Alice Doe\\nSince reported: -5m\r\n" +
+			" \\n\\nBy phone\\,\\nto support.\r\n" +
+			"CATEGORIES:manual_event\r\n" +
+			"END:VEVENT\r\n" +
+			"BEGIN:VEVENT\r\n" +
+			"UID:status@incident\r\n" +
+			"DTSTAMP:20210304T150000Z\r\n" +
+			"DTSTART:20210304T133000Z\r\n" +
+			"SUMMARY:Reported to Active\r\n" +
+			"DESCRIPTION:Event type: status_updated\\nActor: Bob Roe\\nSince reported: 1h \r\n" +
+			" 30m\r\n" +
+			"CATEGORIES:status_updated\r\n" +
+			"END:VEVENT\r\n" +
+			"END:VCALENDAR\r\n"
+		require.Equal(t, expected, string(out))
+	})
+}
+
+func TestWriteICSLine(t *testing.T) {
+	var out bytes.Buffer
+	writeICSLine(&out, "SUMMARY:"+strings.Repeat("é", 100))
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\r\n"), "\r\n")
+	require.Len(t, lines, 3)
+	for i, line := range lines {
+		require.LessOrEqual(t, len(line), 75)
+		require.True(t, utf8.ValidString(line))
+		if i > 0 {
+			require.True(t, strings.HasPrefix(line, " "))
+		}
+	}
+
+	unfolded := lines[0] + strings.TrimPrefix(lines[1], " ") + strings.TrimPrefix(lines[2], " ")
+	require.Equal(t, "SUMMARY:"+strings.Repeat("é", 100), unfolded)
+}
@@ -0,0 +1,116 @@
+package incident
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/pkg/errors"
+)
+
+const (
+	// TimelinePerPageDefault is the number of timeline events in a page when none is requested.
+	TimelinePerPageDefault = 100
+
+	// TimelinePerPageMax is the maximum number of timeline events in a page.
+	TimelinePerPageMax = 1000
+)
+
+// TimelineFilterOptions specifies the page of the timeline of an incident to get, and which
+// events it includes.
+type TimelineFilterOptions struct {
+	// Cursor is the NextCursor of the previous page; blank for the first page.
+	Cursor  string `url:"cursor,omitempty"`
+	PerPage int    `url:"per_page,omitempty"`
+
+	// Direction orders the events by time: DirectionAsc, oldest first, or DirectionDesc;
+	// defaults to asc.
+	Direction string `url:"direction,omitempty"`
+
+	// EventTypes filters by event type. Defaults to empty (no filter).
+	EventTypes []string `url:"event_type,omitempty"`
+
+	// UserID filters to the events whose subject or creator is the given user. Defaults to
+	// blank (no filter).
+	UserID string `url:"user_id,omitempty"`
+
+	// ExcludeUserJoinedLeft leaves out the events of users joining or leaving the channel.
+	ExcludeUserJoinedLeft bool `url:"exclude_user_joined_left,omitempty"`
+}
+
+// GetTimelineEventsResults is a page of the timeline of an incident.
+type GetTimelineEventsResults struct {
+	HasMore    bool            `json:"has_more"`
+	NextCursor string          `json:"next_cursor"`
+	Items      []TimelineEvent `json:"items"`
+}
+
+// TimelineCursor is the position in the timeline of the last event of a page: the next page
+// starts after it.
+type TimelineCursor struct {
+	EventAt int64
+	ID      string
+}
+
+// String encodes c into an opaque cursor.
+func (c TimelineCursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(c.EventAt, 10) + ":" + c.ID))
+}
+
+// ParseTimelineCursor decodes a cursor encoded by TimelineCursor.String.
+func ParseTimelineCursor(cursor string) (TimelineCursor, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return TimelineCursor{}, errors.Wrap(err, "failed to decode cursor")
+	}
+
+	parts := strings.SplitN(string(decoded), ":", 2)
+	if len(parts) != 2 || !model.IsValidId(parts[1]) {
+		return TimelineCursor{}, errors.New("cursor is not of the form event_at:id")
+	}
+
+	eventAt, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return TimelineCursor{}, errors.Wrap(err, "failed to parse the time of the cursor")
+	}
+
+	return TimelineCursor{EventAt: eventAt, ID: parts[1]}, nil
+}
+
+// ValidateTimelineFilterOptions checks options and sets the default page size and direction.
+func ValidateTimelineFilterOptions(options *TimelineFilterOptions) error {
+	if options.PerPage == 0 {
+		options.PerPage = TimelinePerPageDefault
+	}
+	if options.PerPage < 0 || options.PerPage > TimelinePerPageMax {
+		return errors.Errorf("bad parameter 'per_page': must be between 1 and %d", TimelinePerPageMax)
+	}
+
+	switch strings.ToLower(options.Direction) {
+	case DirectionAsc, "":
+		options.Direction = DirectionAsc
+	case DirectionDesc:
+		options.Direction = DirectionDesc
+	default:
+		return errors.New("bad parameter 'direction'")
+	}
+
+	if options.Cursor != "" {
+		if _, err := ParseTimelineCursor(options.Cursor); err != nil {
+			return errors.Wrap(err, "bad parameter 'cursor'")
+		}
+	}
+
+	for _, eventType := range options.EventTypes {
+		if !IsValidTimelineEventType(eventType) {
+			return errors.Errorf("bad parameter 'event_type': unknown event type '%s'", eventType)
+		}
+	}
+
+	if options.UserID != "" && !model.IsValidId(options.UserID) {
+		return errors.New("bad parameter 'user_id': must be 26 characters")
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package incident
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimelineCursor(t *testing.T) {
+	cursor := TimelineCursor{EventAt: 1607774461321, ID: model.NewId()}
+
+	parsed, err := ParseTimelineCursor(cursor.String())
+	require.NoError(t, err)
+	require.Equal(t, cursor, parsed)
+
+	_, err = ParseTimelineCursor("not a cursor")
+	require.Error(t, err)
+
+	_, err = ParseTimelineCursor(TimelineCursor{EventAt: 1, ID: "short"}.String())
+	require.Error(t, err)
+}
+
+func TestValidateTimelineFilterOptions(t *testing.T) {
+	options := TimelineFilterOptions{}
+	require.NoError(t, ValidateTimelineFilterOptions(&options))
+	require.Equal(t, TimelinePerPageDefault, options.PerPage)
+	require.Equal(t, DirectionAsc, options.Direction)
+
+	options = TimelineFilterOptions{
+		Cursor:                TimelineCursor{EventAt: 1, ID: model.NewId()}.String(),
+		PerPage:               TimelinePerPageMax,
+		Direction:             "DESC",
+		EventTypes:            []string{string(ManualEvent), string(StatusUpdated)},
+		UserID:                model.NewId(),
+		ExcludeUserJoinedLeft: true,
+	}
+	require.NoError(t, ValidateTimelineFilterOptions(&options))
+	require.Equal(t, DirectionDesc, options.Direction)
+
+	require.Error(t, ValidateTimelineFilterOptions(&TimelineFilterOptions{PerPage: -1}))
+	require.Error(t, ValidateTimelineFilterOptions(&TimelineFilterOptions{PerPage: TimelinePerPageMax + 1}))
+	require.Error(t, ValidateTimelineFilterOptions(&TimelineFilterOptions{Direction: "sideways"}))
+	require.Error(t, ValidateTimelineFilterOptions(&TimelineFilterOptions{Cursor: "not a cursor"}))
+	require.Error(t, ValidateTimelineFilterOptions(&TimelineFilterOptions{EventTypes: []string{"unknown"}}))
+	require.Error(t, ValidateTimelineFilterOptions(&TimelineFilterOptions{UserID: "short"}))
+}
@@ -0,0 +1,22 @@
+package incident
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateTimelineEventOptions(t *testing.T) {
+	require.NoError(t, ValidateTimelineEventOptions(TimelineEventOptions{Summary: "Customer reported"}))
+	require.NoError(t, ValidateTimelineEventOptions(TimelineEventOptions{
+		EventAt: 1000,
+		Summary: strings.Repeat("é", MaxTimelineEventSummaryLength),
+		Details: strings.Repeat("d", MaxTimelineEventDetailsLength),
+	}))
+
+	require.Error(t, ValidateTimelineEventOptions(TimelineEventOptions{Summary: " \n"}))
+	require.Error(t, ValidateTimelineEventOptions(TimelineEventOptions{Summary: "summary", EventAt: -1}))
+	require.Error(t, ValidateTimelineEventOptions(TimelineEventOptions{Summary: strings.Repeat("s", MaxTimelineEventSummaryLength+1)}))
+	require.Error(t, ValidateTimelineEventOptions(TimelineEventOptions{Summary: "summary", Details: strings.Repeat("d", MaxTimelineEventDetailsLength+1)}))
+}
@@ -0,0 +1,374 @@
+package incident
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/pkg/errors"
+)
+
+// WebhookPrefix is the prefix of the scheduler keys used to attempt a webhook delivery.
+const WebhookPrefix = "webhook_"
+
+// Headers sent with every outgoing webhook request.
+const (
+	// WebhookSignatureHeader holds the hex encoded HMAC-SHA256 of the request body, keyed by the
+	// playbook's webhook secret, in the form "sha256=<signature>". Only sent if a secret is set.
+	WebhookSignatureHeader = "X-Incident-Collaboration-Signature"
+
+	// WebhookDeliveryHeader holds the ID of the delivery, which is the same across retries.
+	WebhookDeliveryHeader = "X-Incident-Collaboration-Delivery"
+)
+
+// Event types of an outgoing webhook delivery.
+const (
+	WebhookEventIncidentCreated = "incident_created"
+	WebhookEventStatusUpdated   = "status_updated"
+)
+
+// Statuses of an outgoing webhook delivery.
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed"
+)
+
+const (
+	// webhookMaxAttempts is the number of attempts made before a delivery is marked as failed.
+	webhookMaxAttempts = 5
+
+	// webhookInitialBackoff is the time to wait before the first retry. It doubles with every
+	// subsequent retry.
+	webhookInitialBackoff = 30 * time.Second
+
+	// MaxWebhookAttemptErrorLength is the maximum number of characters of the error of an attempt,
+	// as stored in IR_WebhookDeliveryAttempt.
+	MaxWebhookAttemptErrorLength = 1024
+)
+
+// WebhookDelivery is an outgoing webhook request sent on behalf of an incident, together with
+// every attempt made to deliver it.
+type WebhookDelivery struct {
+	ID            string                   `json:"id"`
+	IncidentID    string                   `json:"incident_id"`
+	EventType     string                   `json:"event_type"`
+	URL           string                   `json:"url"`
+	Payload       string                   `json:"-"`
+	Status        string                   `json:"status"`
+	NumAttempts   int                      `json:"num_attempts"`
+	NextAttemptAt int64                    `json:"next_attempt_at"` // 0 once the delivery succeeded or failed.
+	CreateAt      int64                    `json:"create_at"`
+	UpdateAt      int64                    `json:"update_at"`
+	Attempts      []WebhookDeliveryAttempt `json:"attempts"`
+}
+
+// WebhookDeliveryAttempt records the outcome of a single attempt to deliver a webhook.
+type WebhookDeliveryAttempt struct {
+	ID         string `json:"id"`
+	DeliveryID string `json:"delivery_id"`
+	IncidentID string `json:"incident_id"`
+	Attempt    int    `json:"attempt"`
+	StatusCode int    `json:"status_code"` // 0 if no response was received.
+	LatencyMS  int64  `json:"latency_ms"`
+	Error      string `json:"error"`
+	CreateAt   int64  `json:"create_at"`
+}
+
+func (d WebhookDelivery) MarshalJSON() ([]byte, error) {
+	type Alias WebhookDelivery
+
+	old := Alias(d)
+	// replace nils with empty slices for the frontend
+	if old.Attempts == nil {
+		old.Attempts = []WebhookDeliveryAttempt{}
+	}
+
+	return json.Marshal(old)
+}
+
+// SignWebhookPayload returns the value of the WebhookSignatureHeader for body signed with secret.
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookBackoff returns how long to wait before retrying a delivery that has failed
+// numAttempts times.
+func webhookBackoff(numAttempts int) time.Duration {
+	if numAttempts < 1 {
+		return 0
+	}
+
+	return webhookInitialBackoff * time.Duration(1<<uint(numAttempts-1))
+}
+
+// webhookAttemptKey returns the key of the job making the attempt number attempt of deliveryID.
+// The first attempt uses the bare delivery ID. Retries get a key of their own: a job cannot
+// reschedule itself under its own key, and scheduling under a new key from within the job keeps
+// the retry in the scheduler's persistent store, so that it survives a restart of the plugin.
+func webhookAttemptKey(deliveryID string, attempt int) string {
+	if attempt <= 1 {
+		return WebhookPrefix + deliveryID
+	}
+
+	return fmt.Sprintf("%s%s_%d", WebhookPrefix, deliveryID, attempt)
+}
+
+// truncateWebhookAttemptError shortens message to MaxWebhookAttemptErrorLength characters.
+func truncateWebhookAttemptError(message string) string {
+	if utf8.RuneCountInString(message) <= MaxWebhookAttemptErrorLength {
+		return message
+	}
+
+	return string([]rune(message)[:MaxWebhookAttemptErrorLength-3]) + "..."
+}
+
+// incidentURLs returns the URLs of theIncident's channel and of its details page.
+func (s *ServiceImpl) incidentURLs(theIncident *Incident) (channelURL, detailsURL string, err error) {
+	siteURL := s.pluginAPI.Configuration.GetConfig().ServiceSettings.SiteURL
+	if siteURL == nil {
+		s.pluginAPI.Log.Warn("cannot send webhook, please set siteURL")
+		return "", "", errors.New("siteURL not set")
+	}
+
+	team, err := s.pluginAPI.Team.Get(theIncident.TeamID)
+	if err != nil {
+		return "", "", err
+	}
+
+	channel, err := s.pluginAPI.Channel.Get(theIncident.ChannelID)
+	if err != nil {
+		return "", "", err
+	}
+
+	channelURL = getChannelURL(*siteURL, team.Name, channel.Name)
+	detailsURL = getDetailsURL(*siteURL, team.Name, s.configService.GetManifest().Id, theIncident.ID)
+
+	return channelURL, detailsURL, nil
+}
+
+// webhookPayload builds the body sent to the outgoing webhooks of theIncident.
+func (s *ServiceImpl) webhookPayload(theIncident Incident) ([]byte, error) {
+	channelURL, detailsURL, err := s.incidentURLs(&theIncident)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := struct {
+		Incident
+		ChannelURL string `json:"channel_url"`
+		DetailsURL string `json:"details_url"`
+	}{
+		Incident:   theIncident,
+		ChannelURL: channelURL,
+		DetailsURL: detailsURL,
+	}
+
+	return json.Marshal(payload)
+}
+
+// timelineEventWebhookPayload builds the body sent to the webhook subscriptions of theIncident
+// that receive event.
+func (s *ServiceImpl) timelineEventWebhookPayload(theIncident *Incident, event *TimelineEvent) ([]byte, error) {
+	channelURL, detailsURL, err := s.incidentURLs(theIncident)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := struct {
+		TimelineEvent
+		IncidentName string `json:"incident_name"`
+		ChannelURL   string `json:"channel_url"`
+		DetailsURL   string `json:"details_url"`
+	}{
+		TimelineEvent: *event,
+		IncidentName:  theIncident.Name,
+		ChannelURL:    channelURL,
+		DetailsURL:    detailsURL,
+	}
+
+	return json.Marshal(payload)
+}
+
+// queueWebhook stores a delivery of theIncident to url and schedules its first attempt.
+func (s *ServiceImpl) queueWebhook(theIncident Incident, eventType, url string) error {
+	body, err := s.webhookPayload(theIncident)
+	if err != nil {
+		return errors.Wrap(err, "failed to build webhook payload")
+	}
+
+	return s.scheduleWebhookDelivery(theIncident.ID, eventType, url, body)
+}
+
+// createTimelineEvent stores event in the timeline of theIncident, and queues a delivery of it to
+// every webhook subscription of theIncident that receives its type.
+func (s *ServiceImpl) createTimelineEvent(theIncident *Incident, event *TimelineEvent) error {
+	if _, err := s.store.CreateTimelineEvent(event); err != nil {
+		return err
+	}
+
+	for _, subscription := range theIncident.WebhookSubscriptions {
+		if !subscription.IsSubscribedTo(string(event.EventType)) {
+			continue
+		}
+
+		body, err := s.timelineEventWebhookPayload(theIncident, event)
+		if err == nil {
+			err = s.scheduleWebhookDelivery(theIncident.ID, string(event.EventType), subscription.URL, body)
+		}
+		if err != nil {
+			s.pluginAPI.Log.Warn("failed to queue webhook for timeline event", "webhook URL", subscription.URL, "event type", event.EventType, "error", err.Error())
+		}
+	}
+
+	return nil
+}
+
+// scheduleWebhookDelivery stores a delivery of body to url and schedules its first attempt. The
+// delivery is retried with an exponential backoff until it succeeds or webhookMaxAttempts is reached.
+func (s *ServiceImpl) scheduleWebhookDelivery(incidentID, eventType, url string, body []byte) error {
+	now := model.GetMillis()
+	delivery, err := s.store.CreateWebhookDelivery(&WebhookDelivery{
+		IncidentID:    incidentID,
+		EventType:     eventType,
+		URL:           url,
+		Payload:       string(body),
+		Status:        WebhookDeliveryPending,
+		NextAttemptAt: now,
+		CreateAt:      now,
+		UpdateAt:      now,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to store webhook delivery")
+	}
+
+	if _, err = s.scheduler.ScheduleOnce(webhookAttemptKey(delivery.ID, 1), time.Now()); err != nil {
+		return errors.Wrap(err, "failed to schedule webhook delivery")
+	}
+
+	return nil
+}
+
+// handleWebhookDelivery makes one attempt to deliver the delivery of key, built by
+// webhookAttemptKey, and schedules a retry if the attempt failed.
+func (s *ServiceImpl) handleWebhookDelivery(key string) {
+	// The attempt number is 0 for the bare delivery ID.
+	deliveryID, attemptNumber := splitNumberedKey(key)
+
+	delivery, err := s.store.GetWebhookDelivery(deliveryID)
+	if err != nil {
+		s.logger.Errorf(errors.Wrapf(err, "handleWebhookDelivery failed to get delivery id: %s", deliveryID).Error())
+		return
+	}
+
+	if delivery.Status != WebhookDeliveryPending {
+		return
+	}
+
+	// A job for an attempt that was already made, or that is not the next one, is stale.
+	if attemptNumber != 0 && attemptNumber != delivery.NumAttempts+1 {
+		return
+	}
+
+	theIncident, err := s.store.GetIncident(delivery.IncidentID)
+	if err != nil {
+		s.logger.Errorf(errors.Wrapf(err, "handleWebhookDelivery failed to get incident id: %s", delivery.IncidentID).Error())
+		return
+	}
+
+	attempt := s.attemptWebhookDelivery(delivery, theIncident.WebhookSecret)
+	if err = s.store.CreateWebhookDeliveryAttempt(attempt); err != nil {
+		s.logger.Errorf(errors.Wrapf(err, "failed to record attempt for webhook delivery id: %s", deliveryID).Error())
+	}
+
+	delivery.NumAttempts = attempt.Attempt
+	delivery.UpdateAt = attempt.CreateAt
+
+	var retryIn time.Duration
+	switch {
+	case attempt.Error == "":
+		delivery.Status = WebhookDeliveryDelivered
+		delivery.NextAttemptAt = 0
+	case delivery.NumAttempts >= webhookMaxAttempts:
+		delivery.Status = WebhookDeliveryFailed
+		delivery.NextAttemptAt = 0
+	default:
+		retryIn = webhookBackoff(delivery.NumAttempts)
+		delivery.NextAttemptAt = model.GetMillisForTime(time.Now().Add(retryIn))
+	}
+
+	if err = s.store.UpdateWebhookDelivery(delivery); err != nil {
+		s.logger.Errorf(errors.Wrapf(err, "failed to update webhook delivery id: %s", deliveryID).Error())
+		return
+	}
+
+	switch delivery.Status {
+	case WebhookDeliveryFailed:
+		s.pluginAPI.Log.Warn("failed to deliver webhook", "webhook URL", delivery.URL, "attempts", delivery.NumAttempts, "error", attempt.Error)
+		msg := fmt.Sprintf("The outgoing webhook for this incident (%s) failed after %d attempts: %s. Contact your System Admin for more information.",
+			strings.ReplaceAll(delivery.EventType, "_", " "), delivery.NumAttempts, attempt.Error)
+		_, _ = s.poster.PostMessage(theIncident.ChannelID, msg)
+
+	case WebhookDeliveryPending:
+		if _, err = s.scheduler.ScheduleOnce(webhookAttemptKey(deliveryID, delivery.NumAttempts+1), time.Now().Add(retryIn)); err != nil {
+			s.logger.Errorf(errors.Wrapf(err, "failed to schedule retry for webhook delivery id: %s", deliveryID).Error())
+		}
+	}
+}
+
+// attemptWebhookDelivery sends the payload of delivery, signed with secret if it is not blank, and
+// returns the outcome. It blocks until a response is received.
+func (s *ServiceImpl) attemptWebhookDelivery(delivery *WebhookDelivery, secret string) *WebhookDeliveryAttempt {
+	attempt := &WebhookDeliveryAttempt{
+		DeliveryID: delivery.ID,
+		IncidentID: delivery.IncidentID,
+		Attempt:    delivery.NumAttempts + 1,
+	}
+
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequest("POST", delivery.URL, bytes.NewReader(body))
+	if err != nil {
+		attempt.Error = truncateWebhookAttemptError(err.Error())
+		attempt.CreateAt = model.GetMillis()
+		return attempt
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookDeliveryHeader, delivery.ID)
+	if secret != "" {
+		req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(secret, body))
+	}
+
+	start := time.Now()
+	resp, err := s.httpClient.Do(req)
+	attempt.LatencyMS = time.Since(start).Milliseconds()
+	attempt.CreateAt = model.GetMillis()
+	if err != nil {
+		attempt.Error = truncateWebhookAttemptError(err.Error())
+		return attempt
+	}
+	defer resp.Body.Close()
+
+	attempt.StatusCode = resp.StatusCode
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		attempt.Error = fmt.Sprintf("response code is %d; expected a status code in the 2xx range", resp.StatusCode)
+	}
+
+	return attempt
+}
+
+// GetWebhookDeliveries returns the outgoing webhook deliveries of incidentID, with their attempts.
+func (s *ServiceImpl) GetWebhookDeliveries(incidentID string) ([]WebhookDelivery, error) {
+	return s.store.GetWebhookDeliveries(incidentID)
+}
@@ -0,0 +1,296 @@
+package oncall
+
+import (
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/pkg/errors"
+)
+
+// ErrNotFound used to indicate entity not found.
+var ErrNotFound = errors.New("not found")
+
+// maxShiftChanges bounds the number of layer and override boundaries walked through when
+// computing the upcoming shifts of a rotation.
+const maxShiftChanges = 10000
+
+// Rotation is an on-call schedule of a team. The user on call at a given moment is the one of
+// the override in effect, if any, or else the one of the last layer covering that moment.
+type Rotation struct {
+	ID          string     `json:"id"`
+	TeamID      string     `json:"team_id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Layers      []Layer    `json:"layers"`
+	Overrides   []Override `json:"overrides"`
+	CreateAt    int64      `json:"create_at"`
+	UpdateAt    int64      `json:"update_at"`
+	DeleteAt    int64      `json:"delete_at"`
+}
+
+// Layer hands the on-call duty to each of UserIDs in turn, for ShiftLengthSeconds each, starting
+// with the first user at StartAt. Layers later in Rotation.Layers take precedence over earlier
+// ones, so that, for example, a weekend layer can be put on top of a weekly one.
+type Layer struct {
+	Name               string   `json:"name"`
+	UserIDs            []string `json:"user_ids"`
+	StartAt            int64    `json:"start_at"`
+	EndAt              int64    `json:"end_at"` // 0 if the layer never ends.
+	ShiftLengthSeconds int64    `json:"shift_length_seconds"`
+}
+
+// Override puts UserID on call from StartAt to EndAt, whatever the layers say, for instance to
+// cover for someone on holiday. When overrides overlap, the one starting last wins.
+type Override struct {
+	ID      string `json:"id"`
+	UserID  string `json:"user_id"`
+	StartAt int64  `json:"start_at"`
+	EndAt   int64  `json:"end_at"`
+}
+
+// Shift is a period during which a single user is on call. UserID is empty if nobody is.
+type Shift struct {
+	UserID  string `json:"user_id"`
+	StartAt int64  `json:"start_at"`
+	EndAt   int64  `json:"end_at"` // 0 if the shift never ends.
+}
+
+// Clone returns a deep copy of the rotation.
+func (r Rotation) Clone() Rotation {
+	newRotation := r
+	newRotation.Layers = nil
+	for _, l := range r.Layers {
+		newLayer := l
+		newLayer.UserIDs = append([]string(nil), l.UserIDs...)
+		newRotation.Layers = append(newRotation.Layers, newLayer)
+	}
+	newRotation.Overrides = append([]Override(nil), r.Overrides...)
+	return newRotation
+}
+
+// Validate checks that the rotation is named, belongs to a team, and has valid layers and
+// overrides.
+func (r Rotation) Validate() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return errors.New("rotation has no name")
+	}
+
+	if !model.IsValidId(r.TeamID) {
+		return errors.New("rotation has no valid team ID")
+	}
+
+	for i, layer := range r.Layers {
+		if err := layer.Validate(); err != nil {
+			return errors.Wrapf(err, "invalid layer %d", i)
+		}
+	}
+
+	for i, override := range r.Overrides {
+		if err := override.Validate(); err != nil {
+			return errors.Wrapf(err, "invalid override %d", i)
+		}
+	}
+
+	return nil
+}
+
+// Validate checks that the layer has users, a start, an end after the start, if any, and shifts
+// of at least a minute.
+func (l Layer) Validate() error {
+	if len(l.UserIDs) == 0 {
+		return errors.New("layer has no users")
+	}
+
+	for _, userID := range l.UserIDs {
+		if !model.IsValidId(userID) {
+			return errors.Errorf("invalid user ID '%s'", userID)
+		}
+	}
+
+	if l.StartAt <= 0 {
+		return errors.New("layer has no start")
+	}
+
+	if l.EndAt != 0 && l.EndAt <= l.StartAt {
+		return errors.New("layer ends before it starts")
+	}
+
+	if l.ShiftLengthSeconds < 60 {
+		return errors.New("shifts must last at least a minute")
+	}
+
+	return nil
+}
+
+// Validate checks that the override has a user and ends after it starts.
+func (o Override) Validate() error {
+	if !model.IsValidId(o.UserID) {
+		return errors.Errorf("invalid user ID '%s'", o.UserID)
+	}
+
+	if o.StartAt <= 0 || o.EndAt <= o.StartAt {
+		return errors.New("override must start before it ends")
+	}
+
+	return nil
+}
+
+// UserOnCall returns the ID of the user on call at the given time, in milliseconds, or an empty
+// string if nobody is.
+func (r Rotation) UserOnCall(at int64) string {
+	var current *Override
+	for i, override := range r.Overrides {
+		if at < override.StartAt || at >= override.EndAt {
+			continue
+		}
+		if current == nil || override.StartAt >= current.StartAt {
+			current = &r.Overrides[i]
+		}
+	}
+	if current != nil {
+		return current.UserID
+	}
+
+	for i := len(r.Layers) - 1; i >= 0; i-- {
+		if userID := r.Layers[i].userOnCall(at); userID != "" {
+			return userID
+		}
+	}
+
+	return ""
+}
+
+// Shifts returns up to count consecutive shifts, starting with the one in progress at from. The
+// first shift is cut to start at from.
+func (r Rotation) Shifts(from int64, count int) []Shift {
+	var shifts []Shift
+	if count <= 0 {
+		return shifts
+	}
+
+	current := Shift{UserID: r.UserOnCall(from), StartAt: from}
+	at := from
+	for i := 0; i < maxShiftChanges; i++ {
+		next := r.nextChange(at)
+		if next == 0 {
+			break
+		}
+
+		if userID := r.UserOnCall(next); userID != current.UserID {
+			current.EndAt = next
+			shifts = append(shifts, current)
+			if len(shifts) == count {
+				return shifts
+			}
+			current = Shift{UserID: userID, StartAt: next}
+		}
+		at = next
+	}
+
+	return append(shifts, current)
+}
+
+// nextChange returns the earliest time after at when a layer or an override of the rotation
+// starts, ends or hands over to the next user, or 0 if there is none.
+func (r Rotation) nextChange(at int64) int64 {
+	var next int64
+	earliest := func(t int64) {
+		if t > at && (next == 0 || t < next) {
+			next = t
+		}
+	}
+
+	for _, layer := range r.Layers {
+		earliest(layer.nextChange(at))
+	}
+
+	for _, override := range r.Overrides {
+		earliest(override.StartAt)
+		earliest(override.EndAt)
+	}
+
+	return next
+}
+
+// userOnCall returns the user on call in the layer at the given time, or an empty string if the
+// layer does not cover it.
+func (l Layer) userOnCall(at int64) string {
+	if len(l.UserIDs) == 0 || l.ShiftLengthSeconds <= 0 || at < l.StartAt || (l.EndAt != 0 && at >= l.EndAt) {
+		return ""
+	}
+
+	shift := (at - l.StartAt) / (l.ShiftLengthSeconds * 1000)
+	return l.UserIDs[shift%int64(len(l.UserIDs))]
+}
+
+// nextChange returns the earliest time after at when the layer starts, ends or hands over to the
+// next user, or 0 if there is none.
+func (l Layer) nextChange(at int64) int64 {
+	if len(l.UserIDs) == 0 || l.ShiftLengthSeconds <= 0 || (l.EndAt != 0 && at >= l.EndAt) {
+		return 0
+	}
+
+	if at < l.StartAt {
+		return l.StartAt
+	}
+
+	// With a single user, the layer only changes when it ends.
+	if len(l.UserIDs) == 1 {
+		return l.EndAt
+	}
+
+	length := l.ShiftLengthSeconds * 1000
+	next := l.StartAt + ((at-l.StartAt)/length+1)*length
+	if l.EndAt != 0 && l.EndAt < next {
+		return l.EndAt
+	}
+
+	return next
+}
+
+// Service is the on-call service for managing rotations.
+type Service interface {
+	// Create creates a new rotation.
+	Create(rotation Rotation) (string, error)
+
+	// Get retrieves a rotation. Returns ErrNotFound if not found.
+	Get(id string) (Rotation, error)
+
+	// GetRotationsForTeam retrieves the rotations of a team that are not deleted.
+	GetRotationsForTeam(teamID string) ([]Rotation, error)
+
+	// Update updates a rotation.
+	Update(rotation Rotation) error
+
+	// Delete deletes a rotation.
+	Delete(id string) error
+
+	// AddOverride adds an override to a rotation and returns its ID.
+	AddOverride(rotationID string, override Override) (string, error)
+
+	// RemoveOverride removes an override from a rotation. Returns ErrNotFound if the rotation
+	// has no such override.
+	RemoveOverride(rotationID, overrideID string) error
+
+	// UserOnCall returns the ID of the user on call in a rotation at the given time, in
+	// milliseconds, or an empty string if nobody is, or if the rotation was deleted.
+	UserOnCall(rotationID string, at int64) (string, error)
+}
+
+// Store is an interface for storing rotations.
+type Store interface {
+	// Create creates a new rotation.
+	Create(rotation Rotation) (string, error)
+
+	// Get retrieves a rotation. Returns ErrNotFound if not found.
+	Get(id string) (Rotation, error)
+
+	// GetRotationsForTeam retrieves the rotations of a team that are not deleted.
+	GetRotationsForTeam(teamID string) ([]Rotation, error)
+
+	// Update updates a rotation.
+	Update(rotation Rotation) error
+
+	// Delete deletes a rotation.
+	Delete(id string) error
+}
@@ -0,0 +1,174 @@
+package oncall
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	alice = "aliceaaaaaaaaaaaaaaaaaaaaa"
+	bob   = "bobbbbbbbbbbbbbbbbbbbbbbbb"
+	carol = "carolccccccccccccccccccccc"
+	dave  = "davedddddddddddddddddddddd"
+
+	hour = int64(60 * 60 * 1000)
+	day  = 24 * hour
+)
+
+func TestUserOnCall(t *testing.T) {
+	weekly := Layer{
+		Name:               "Weekly",
+		UserIDs:            []string{alice, bob},
+		StartAt:            7 * day,
+		ShiftLengthSeconds: 7 * 24 * 60 * 60,
+	}
+	weekends := Layer{
+		Name:               "Weekends",
+		UserIDs:            []string{carol},
+		StartAt:            12 * day,
+		EndAt:              14 * day,
+		ShiftLengthSeconds: 2 * 24 * 60 * 60,
+	}
+
+	rotation := Rotation{
+		Layers: []Layer{weekly, weekends},
+		Overrides: []Override{
+			{ID: "1", UserID: dave, StartAt: 15 * day, EndAt: 17 * day},
+			{ID: "2", UserID: alice, StartAt: 16 * day, EndAt: 16*day + hour},
+		},
+	}
+
+	tests := []struct {
+		name string
+		at   int64
+		want string
+	}{
+		{name: "before any layer starts", at: day, want: ""},
+		{name: "first shift of the first layer", at: 7 * day, want: alice},
+		{name: "second shift of the first layer", at: 14*day + hour, want: bob},
+		{name: "back to the first user", at: 21 * day, want: alice},
+		{name: "later layer takes precedence", at: 13 * day, want: carol},
+		{name: "later layer has ended", at: 14 * day, want: bob},
+		{name: "override", at: 15 * day, want: dave},
+		{name: "override starting last wins", at: 16*day + 1, want: alice},
+		{name: "override has ended", at: 17 * day, want: bob},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, rotation.UserOnCall(tt.at))
+		})
+	}
+}
+
+func TestShifts(t *testing.T) {
+	rotation := Rotation{
+		Layers: []Layer{{
+			UserIDs:            []string{alice, bob},
+			StartAt:            day,
+			ShiftLengthSeconds: 24 * 60 * 60,
+		}},
+		Overrides: []Override{{ID: "1", UserID: alice, StartAt: 2 * day, EndAt: 2*day + hour}},
+	}
+
+	t.Run("upcoming shifts", func(t *testing.T) {
+		require.Equal(t, []Shift{
+			{UserID: alice, StartAt: day + hour, EndAt: 2*day + hour},
+			{UserID: bob, StartAt: 2*day + hour, EndAt: 3 * day},
+			{UserID: alice, StartAt: 3 * day, EndAt: 4 * day},
+		}, rotation.Shifts(day+hour, 3))
+	})
+
+	t.Run("nobody on call before the rotation starts", func(t *testing.T) {
+		require.Equal(t, []Shift{
+			{UserID: "", StartAt: 0, EndAt: day},
+			{UserID: alice, StartAt: day, EndAt: 2*day + hour},
+		}, rotation.Shifts(0, 2))
+	})
+
+	t.Run("single user layer with an end", func(t *testing.T) {
+		single := Rotation{Layers: []Layer{{
+			UserIDs:            []string{carol},
+			StartAt:            day,
+			EndAt:              30 * day,
+			ShiftLengthSeconds: 60 * 60,
+		}}}
+		require.Equal(t, []Shift{
+			{UserID: carol, StartAt: 2 * day, EndAt: 30 * day},
+			{UserID: "", StartAt: 30 * day, EndAt: 0},
+		}, single.Shifts(2*day, 5))
+	})
+}
+
+func TestValidate(t *testing.T) {
+	valid := Rotation{
+		Name:   "Primary",
+		TeamID: "teamtttttttttttttttttttttt",
+		Layers: []Layer{{
+			UserIDs:            []string{alice, bob},
+			StartAt:            day,
+			ShiftLengthSeconds: 24 * 60 * 60,
+		}},
+		Overrides: []Override{{UserID: carol, StartAt: day, EndAt: 2 * day}},
+	}
+	require.NoError(t, valid.Validate())
+
+	tests := []struct {
+		name   string
+		modify func(r *Rotation)
+	}{
+		{name: "no name", modify: func(r *Rotation) { r.Name = " " }},
+		{name: "no team", modify: func(r *Rotation) { r.TeamID = "" }},
+		{name: "layer without users", modify: func(r *Rotation) { r.Layers[0].UserIDs = nil }},
+		{name: "layer with an invalid user", modify: func(r *Rotation) { r.Layers[0].UserIDs = []string{"alice"} }},
+		{name: "layer ending before it starts", modify: func(r *Rotation) { r.Layers[0].EndAt = day - 1 }},
+		{name: "shifts too short", modify: func(r *Rotation) { r.Layers[0].ShiftLengthSeconds = 1 }},
+		{name: "override ending before it starts", modify: func(r *Rotation) { r.Overrides[0].EndAt = day }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rotation := valid.Clone()
+			tt.modify(&rotation)
+			require.Error(t, rotation.Validate())
+		})
+	}
+}
+
+// fakeStore is a Store holding a single rotation.
+type fakeStore struct {
+	Store
+	rotation Rotation
+}
+
+func (f *fakeStore) Get(id string) (Rotation, error) {
+	if id != f.rotation.ID {
+		return Rotation{}, ErrNotFound
+	}
+	return f.rotation, nil
+}
+
+func TestServiceUserOnCall(t *testing.T) {
+	store := &fakeStore{rotation: Rotation{
+		ID: "rotation",
+		Layers: []Layer{{
+			UserIDs:            []string{alice},
+			StartAt:            day,
+			ShiftLengthSeconds: 24 * 60 * 60,
+		}},
+	}}
+	s := NewService(store)
+
+	userID, err := s.UserOnCall("rotation", 2*day)
+	require.NoError(t, err)
+	require.Equal(t, alice, userID)
+
+	t.Run("nobody is on call in a deleted rotation", func(t *testing.T) {
+		store.rotation.DeleteAt = 3 * day
+
+		userID, err := s.UserOnCall("rotation", 2*day)
+		require.NoError(t, err)
+		require.Empty(t, userID)
+	})
+}
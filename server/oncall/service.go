@@ -0,0 +1,107 @@
+package oncall
+
+import (
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/pkg/errors"
+)
+
+type service struct {
+	store Store
+}
+
+// NewService returns a new on-call service
+func NewService(store Store) Service {
+	return &service{
+		store: store,
+	}
+}
+
+func (s *service) Create(rotation Rotation) (string, error) {
+	rotation.CreateAt = model.GetMillis()
+	rotation.UpdateAt = rotation.CreateAt
+	for i := range rotation.Overrides {
+		rotation.Overrides[i].ID = model.NewId()
+	}
+
+	return s.store.Create(rotation)
+}
+
+func (s *service) Get(id string) (Rotation, error) {
+	return s.store.Get(id)
+}
+
+func (s *service) GetRotationsForTeam(teamID string) ([]Rotation, error) {
+	return s.store.GetRotationsForTeam(teamID)
+}
+
+func (s *service) Update(rotation Rotation) error {
+	rotation.UpdateAt = model.GetMillis()
+	for i := range rotation.Overrides {
+		if rotation.Overrides[i].ID == "" {
+			rotation.Overrides[i].ID = model.NewId()
+		}
+	}
+
+	return s.store.Update(rotation)
+}
+
+func (s *service) Delete(id string) error {
+	return s.store.Delete(id)
+}
+
+func (s *service) AddOverride(rotationID string, override Override) (string, error) {
+	rotation, err := s.store.Get(rotationID)
+	if err != nil {
+		return "", err
+	}
+
+	override.ID = model.NewId()
+	rotation.Overrides = append(rotation.Overrides, override)
+	rotation.UpdateAt = model.GetMillis()
+
+	if err = s.store.Update(rotation); err != nil {
+		return "", errors.Wrapf(err, "failed to add override to rotation %s", rotationID)
+	}
+
+	return override.ID, nil
+}
+
+func (s *service) RemoveOverride(rotationID, overrideID string) error {
+	rotation, err := s.store.Get(rotationID)
+	if err != nil {
+		return err
+	}
+
+	overrides := make([]Override, 0, len(rotation.Overrides))
+	for _, override := range rotation.Overrides {
+		if override.ID != overrideID {
+			overrides = append(overrides, override)
+		}
+	}
+	if len(overrides) == len(rotation.Overrides) {
+		return errors.Wrapf(ErrNotFound, "rotation %s has no override %s", rotationID, overrideID)
+	}
+
+	rotation.Overrides = overrides
+	rotation.UpdateAt = model.GetMillis()
+
+	if err = s.store.Update(rotation); err != nil {
+		return errors.Wrapf(err, "failed to remove override from rotation %s", rotationID)
+	}
+
+	return nil
+}
+
+func (s *service) UserOnCall(rotationID string, at int64) (string, error) {
+	rotation, err := s.store.Get(rotationID)
+	if err != nil {
+		return "", err
+	}
+
+	// Nobody is on call in a deleted rotation.
+	if rotation.DeleteAt != 0 {
+		return "", nil
+	}
+
+	return rotation.UserOnCall(at), nil
+}
@@ -263,13 +263,14 @@ func CreatePlaybook(userID string, pbook playbook.Playbook, cfgService config.Se
 		)
 	}
 
-	if pbook.BroadcastChannelID != "" &&
-		!pluginAPI.User.HasPermissionToChannel(userID, pbook.BroadcastChannelID, model.PERMISSION_CREATE_POST) {
-		return errors.Errorf(
-			"userID %s does not have permission to create posts in the channel %s",
-			userID,
-			pbook.BroadcastChannelID,
-		)
+	for _, channelID := range pbook.BroadcastChannelIDs() {
+		if !pluginAPI.User.HasPermissionToChannel(userID, channelID, model.PERMISSION_CREATE_POST) {
+			return errors.Errorf(
+				"userID %s does not have permission to create posts in the channel %s",
+				userID,
+				channelID,
+			)
+		}
 	}
 
 	if !CanViewTeam(userID, pbook.TeamID, pluginAPI) {
@@ -300,6 +301,17 @@ func CreatePlaybook(userID string, pbook playbook.Playbook, cfgService config.Se
 		}
 	}
 
+	// Check all auto followers have permissions to the team.
+	for _, followerID := range pbook.AutoFollowerIDs {
+		if !pluginAPI.User.HasPermissionToTeam(followerID, pbook.TeamID, model.PERMISSION_VIEW_TEAM) {
+			return errors.Errorf(
+				"auto follower with ID %s does not have permission to playbook's team %s",
+				followerID,
+				pbook.TeamID,
+			)
+		}
+	}
+
 	for _, groupID := range pbook.InvitedGroupIDs {
 		group, err := pluginAPI.Group.Get(groupID)
 		if err != nil {
@@ -314,6 +326,32 @@ func CreatePlaybook(userID string, pbook playbook.Playbook, cfgService config.Se
 		}
 	}
 
+	// Check the automatic actions of the checklist items only post where the user can post, and
+	// only invite users with permissions to the team.
+	for _, checklist := range pbook.Checklists {
+		for _, item := range checklist.Items {
+			action := item.AutomaticAction
+			if action.ChannelID != "" &&
+				!pluginAPI.User.HasPermissionToChannel(userID, action.ChannelID, model.PERMISSION_CREATE_POST) {
+				return errors.Errorf(
+					"userID %s does not have permission to create posts in the channel %s",
+					userID,
+					action.ChannelID,
+				)
+			}
+
+			for _, inviteeID := range action.UserIDs {
+				if !pluginAPI.User.HasPermissionToTeam(inviteeID, pbook.TeamID, model.PERMISSION_VIEW_TEAM) {
+					return errors.Errorf(
+						"invited user with ID %s does not have permission to playbook's team %s",
+						inviteeID,
+						pbook.TeamID,
+					)
+				}
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -328,15 +366,41 @@ func PlaybookModify(userID string, pbook, oldPlaybook playbook.Playbook, cfgServ
 		return err
 	}
 
-	if pbook.BroadcastChannelID != "" &&
-		pbook.BroadcastChannelID != oldPlaybook.BroadcastChannelID &&
-		!pluginAPI.User.HasPermissionToChannel(userID, pbook.BroadcastChannelID, model.PERMISSION_CREATE_POST) {
-		return errors.Wrapf(
-			ErrNoPermissions,
-			"userID %s does not have permission to create posts in the channel %s",
-			userID,
-			pbook.BroadcastChannelID,
-		)
+	// Only the channels added to the playbook are checked, so that a user who cannot post in a
+	// channel someone else configured can still edit the rest of the playbook.
+	oldChannelIDs := map[string]bool{}
+	for _, channelID := range oldPlaybook.BroadcastChannelIDs() {
+		oldChannelIDs[channelID] = true
+	}
+
+	for _, channelID := range pbook.BroadcastChannelIDs() {
+		if !oldChannelIDs[channelID] &&
+			!pluginAPI.User.HasPermissionToChannel(userID, channelID, model.PERMISSION_CREATE_POST) {
+			return errors.Wrapf(
+				ErrNoPermissions,
+				"userID %s does not have permission to create posts in the channel %s",
+				userID,
+				channelID,
+			)
+		}
+	}
+
+	return nil
+}
+
+// PlaybookManageSecrets returns nil if userID can read and regenerate the secrets of pbook, such as
+// its inbound webhook token: system admins, and team admins with access to the playbook, can.
+func PlaybookManageSecrets(userID string, pbook playbook.Playbook, pluginAPI *pluginapi.Client) error {
+	if IsAdmin(userID, pluginAPI) {
+		return nil
+	}
+
+	if err := PlaybookAccess(userID, pbook, pluginAPI); err != nil {
+		return err
+	}
+
+	if !pluginAPI.User.HasPermissionToTeam(userID, pbook.TeamID, model.PERMISSION_MANAGE_TEAM) {
+		return errors.Wrapf(ErrNoPermissions, "userID %s to manage the secrets of playbook %s", userID, pbook.ID)
 	}
 
 	return nil
@@ -0,0 +1,88 @@
+package playbook
+
+import (
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/pkg/errors"
+)
+
+// Filters of a broadcast target, selecting the status updates broadcast to its channel.
+const (
+	// BroadcastFilterAll broadcasts every status update.
+	BroadcastFilterAll = "all"
+
+	// BroadcastFilterStatusChanges only broadcasts the status updates changing the status of the
+	// incident.
+	BroadcastFilterStatusChanges = "status_changes"
+)
+
+// BroadcastTarget is a channel where the creation and the status updates of the incidents created
+// from a playbook are broadcast, in addition to the broadcast channel of the playbook.
+type BroadcastTarget struct {
+	ChannelID string `json:"channel_id"`
+	Filter    string `json:"filter"`
+
+	// MinSeverity is the least severe level broadcast to the channel: with SEV2, only the SEV1 and
+	// SEV2 incidents are broadcast. Blank to broadcast the incidents of any severity, including
+	// the ones not classified yet.
+	MinSeverity string `json:"min_severity"`
+}
+
+// Validate checks that the channel ID and the filter of the target are valid. The severity is
+// checked by the caller, since the severity levels belong to the incidents.
+func (t BroadcastTarget) Validate() error {
+	if !model.IsValidId(t.ChannelID) {
+		return errors.Errorf("invalid channel id '%s'", t.ChannelID)
+	}
+
+	if t.Filter != BroadcastFilterAll && t.Filter != BroadcastFilterStatusChanges {
+		return errors.Errorf("unknown filter '%s'", t.Filter)
+	}
+
+	return nil
+}
+
+// ValidateBroadcastTargets checks that every target is valid, and that no channel is targeted twice.
+func ValidateBroadcastTargets(targets []BroadcastTarget) error {
+	channelIDs := make(map[string]bool, len(targets))
+	for i, target := range targets {
+		if err := target.Validate(); err != nil {
+			return errors.Wrapf(err, "target %d", i+1)
+		}
+
+		if channelIDs[target.ChannelID] {
+			return errors.Errorf("channel %s is targeted more than once", target.ChannelID)
+		}
+		channelIDs[target.ChannelID] = true
+	}
+
+	return nil
+}
+
+// CloneBroadcastTargets returns a copy of targets.
+func CloneBroadcastTargets(targets []BroadcastTarget) []BroadcastTarget {
+	if targets == nil {
+		return nil
+	}
+
+	return append([]BroadcastTarget(nil), targets...)
+}
+
+// BroadcastChannelIDs returns the IDs of the channels the playbook broadcasts its status updates
+// to: its broadcast channel and the channels of its broadcast targets, without duplicates.
+func (p Playbook) BroadcastChannelIDs() []string {
+	var channelIDs []string
+	seen := map[string]bool{"": true}
+	add := func(channelID string) {
+		if !seen[channelID] {
+			seen[channelID] = true
+			channelIDs = append(channelIDs, channelID)
+		}
+	}
+
+	add(p.BroadcastChannelID)
+	for _, target := range p.BroadcastTargets {
+		add(target.ChannelID)
+	}
+
+	return channelIDs
+}
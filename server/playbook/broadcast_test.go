@@ -0,0 +1,71 @@
+package playbook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateBroadcastTargets(t *testing.T) {
+	channelID := "channelccccccccccccccccccc"
+	otherChannelID := "otherchannelcccccccccccccc"
+
+	tests := []struct {
+		name    string
+		targets []BroadcastTarget
+		wantErr bool
+	}{
+		{
+			name: "no targets",
+		},
+		{
+			name: "valid targets",
+			targets: []BroadcastTarget{
+				{ChannelID: channelID, Filter: BroadcastFilterAll},
+				{ChannelID: otherChannelID, Filter: BroadcastFilterStatusChanges, MinSeverity: "SEV2"},
+			},
+		},
+		{
+			name:    "invalid channel id",
+			targets: []BroadcastTarget{{ChannelID: "channel", Filter: BroadcastFilterAll}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown filter",
+			targets: []BroadcastTarget{{ChannelID: channelID, Filter: "everything"}},
+			wantErr: true,
+		},
+		{
+			name: "duplicate channel",
+			targets: []BroadcastTarget{
+				{ChannelID: channelID, Filter: BroadcastFilterAll},
+				{ChannelID: channelID, Filter: BroadcastFilterStatusChanges},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateBroadcastTargets(tt.targets)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPlaybookBroadcastChannelIDs(t *testing.T) {
+	pbook := Playbook{
+		BroadcastChannelID: "broadcast",
+		BroadcastTargets: []BroadcastTarget{
+			{ChannelID: "engineering"},
+			{ChannelID: "broadcast"},
+		},
+	}
+	require.Equal(t, []string{"broadcast", "engineering"}, pbook.BroadcastChannelIDs())
+
+	require.Empty(t, Playbook{}.BroadcastChannelIDs())
+}
@@ -0,0 +1,218 @@
+package playbook
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/pkg/errors"
+)
+
+// Types of custom incident fields.
+const (
+	CustomFieldTypeText        = "text"
+	CustomFieldTypeSelect      = "select"
+	CustomFieldTypeMultiselect = "multiselect"
+	CustomFieldTypeUser        = "user"
+	CustomFieldTypeNumber      = "number"
+)
+
+// keyPattern is the pattern of the keys of custom fields and roles: they are used in dialog
+// element names, slash commands and filter parameters.
+var keyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,31}$`)
+
+// CustomFieldValueMaxLength is the maximum length, in characters, of a value of a custom field.
+const CustomFieldValueMaxLength = 512
+
+// CustomField is a typed field defined by a playbook, whose value is set on the incidents started
+// with the playbook. Select and multiselect fields take their values from Options; user fields hold
+// a user ID.
+type CustomField struct {
+	Key         string   `json:"key"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Type        string   `json:"type"`
+	Options     []string `json:"options"`
+	Required    bool     `json:"required"`
+}
+
+// Clone returns a deep copy of the field.
+func (f CustomField) Clone() CustomField {
+	newField := f
+	newField.Options = append([]string(nil), f.Options...)
+	return newField
+}
+
+// IsMultiValued returns true if the field can hold more than one value.
+func (f CustomField) IsMultiValued() bool {
+	return f.Type == CustomFieldTypeMultiselect
+}
+
+// Validate checks that the field has a valid key, a name and a known type, and that select and
+// multiselect fields have options.
+func (f CustomField) Validate() error {
+	if !keyPattern.MatchString(f.Key) {
+		return errors.Errorf("invalid key '%s': use up to 32 lowercase letters, digits and underscores, starting with a letter", f.Key)
+	}
+
+	if strings.TrimSpace(f.Name) == "" {
+		return errors.Errorf("field %s has no name", f.Key)
+	}
+
+	switch f.Type {
+	case CustomFieldTypeText, CustomFieldTypeUser, CustomFieldTypeNumber:
+	case CustomFieldTypeSelect, CustomFieldTypeMultiselect:
+		if len(f.Options) == 0 {
+			return errors.Errorf("field %s has no options", f.Key)
+		}
+		for _, option := range f.Options {
+			if strings.TrimSpace(option) == "" {
+				return errors.Errorf("field %s has a blank option", f.Key)
+			}
+		}
+	default:
+		return errors.Errorf("field %s has an unknown type %s", f.Key, f.Type)
+	}
+
+	return nil
+}
+
+// CloneCustomFields returns a deep copy of fields.
+func CloneCustomFields(fields []CustomField) []CustomField {
+	if fields == nil {
+		return nil
+	}
+
+	newFields := make([]CustomField, len(fields))
+	for i, field := range fields {
+		newFields[i] = field.Clone()
+	}
+	return newFields
+}
+
+// ValidateCustomFields checks that every field is valid, and that no two fields share a key.
+func ValidateCustomFields(fields []CustomField) error {
+	keys := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		if err := field.Validate(); err != nil {
+			return err
+		}
+		if keys[field.Key] {
+			return errors.Errorf("key %s is used by more than one field", field.Key)
+		}
+		keys[field.Key] = true
+	}
+
+	return nil
+}
+
+// ValidateCustomFieldCondition checks that condition, a valid condition on a custom field, checks
+// one of fields, and that the values of conditions on select and multiselect fields are options.
+func ValidateCustomFieldCondition(condition ItemCondition, fields []CustomField) error {
+	key := condition.CustomFieldKey()
+	for _, field := range fields {
+		if field.Key != key {
+			continue
+		}
+
+		if field.Type != CustomFieldTypeSelect && field.Type != CustomFieldTypeMultiselect {
+			return nil
+		}
+		for _, value := range condition.Values {
+			if !containsString(field.Options, value) {
+				return errors.Errorf("'%s' is not one of the options of custom field %s", value, key)
+			}
+		}
+		return nil
+	}
+
+	return errors.Errorf("unknown custom field %s", key)
+}
+
+// CustomFieldError is the error returned by ValidateCustomFieldValues, identifying the field
+// whose value is invalid.
+type CustomFieldError struct {
+	Key     string
+	Message string
+}
+
+func (e *CustomFieldError) Error() string {
+	return "custom field " + e.Key + ": " + e.Message
+}
+
+// ValidateCustomFieldValues checks that values, indexed by field key, are valid values of fields:
+// every key is the key of a field, every required field has a value, single-valued fields have at
+// most one value, values are not too long, select values are options, numbers parse and users are
+// user IDs. It returns a *CustomFieldError for the first invalid value.
+func ValidateCustomFieldValues(fields []CustomField, values map[string][]string) error {
+	byKey := make(map[string]CustomField, len(fields))
+	for _, field := range fields {
+		byKey[field.Key] = field
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if _, ok := byKey[key]; !ok {
+			return &CustomFieldError{Key: key, Message: "unknown field"}
+		}
+	}
+
+	for _, field := range fields {
+		fieldValues := values[field.Key]
+		if len(fieldValues) == 0 {
+			if field.Required {
+				return &CustomFieldError{Key: field.Key, Message: "a value is required"}
+			}
+			continue
+		}
+		if len(fieldValues) > 1 && !field.IsMultiValued() {
+			return &CustomFieldError{Key: field.Key, Message: "only one value is allowed"}
+		}
+
+		for _, value := range fieldValues {
+			if utf8.RuneCountInString(value) > CustomFieldValueMaxLength {
+				return &CustomFieldError{Key: field.Key, Message: "the value is longer than " + strconv.Itoa(CustomFieldValueMaxLength) + " characters"}
+			}
+
+			switch field.Type {
+			case CustomFieldTypeText:
+				if strings.TrimSpace(value) == "" {
+					return &CustomFieldError{Key: field.Key, Message: "the value is blank"}
+				}
+			case CustomFieldTypeSelect, CustomFieldTypeMultiselect:
+				if !containsString(field.Options, value) {
+					return &CustomFieldError{Key: field.Key, Message: "'" + value + "' is not one of the options"}
+				}
+			case CustomFieldTypeUser:
+				if !model.IsValidId(value) {
+					return &CustomFieldError{Key: field.Key, Message: "'" + value + "' is not a user ID"}
+				}
+			case CustomFieldTypeNumber:
+				if _, err := strconv.ParseFloat(value, 64); err != nil {
+					return &CustomFieldError{Key: field.Key, Message: "'" + value + "' is not a number"}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// CloneCustomFieldValues returns a deep copy of values.
+func CloneCustomFieldValues(values map[string][]string) map[string][]string {
+	if values == nil {
+		return nil
+	}
+
+	newValues := make(map[string][]string, len(values))
+	for key, fieldValues := range values {
+		newValues[key] = append([]string(nil), fieldValues...)
+	}
+	return newValues
+}
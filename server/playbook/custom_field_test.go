@@ -0,0 +1,169 @@
+package playbook
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateCustomFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		fields  []CustomField
+		wantErr bool
+	}{
+		{
+			name: "valid fields",
+			fields: []CustomField{
+				{Key: "region", Name: "Region", Type: CustomFieldTypeSelect, Options: []string{"eu", "us"}, Required: true},
+				{Key: "services", Name: "Services", Type: CustomFieldTypeMultiselect, Options: []string{"api", "database"}},
+				{Key: "customer_id", Name: "Customer", Type: CustomFieldTypeText},
+				{Key: "responder", Name: "Responder", Type: CustomFieldTypeUser},
+				{Key: "users_affected", Name: "Users affected", Type: CustomFieldTypeNumber},
+			},
+		},
+		{
+			name:    "invalid key",
+			fields:  []CustomField{{Key: "Region", Name: "Region", Type: CustomFieldTypeText}},
+			wantErr: true,
+		},
+		{
+			name:    "no name",
+			fields:  []CustomField{{Key: "region", Name: " ", Type: CustomFieldTypeText}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown type",
+			fields:  []CustomField{{Key: "region", Name: "Region", Type: "date"}},
+			wantErr: true,
+		},
+		{
+			name:    "select without options",
+			fields:  []CustomField{{Key: "region", Name: "Region", Type: CustomFieldTypeSelect}},
+			wantErr: true,
+		},
+		{
+			name:    "blank option",
+			fields:  []CustomField{{Key: "region", Name: "Region", Type: CustomFieldTypeMultiselect, Options: []string{"eu", ""}}},
+			wantErr: true,
+		},
+		{
+			name: "duplicate keys",
+			fields: []CustomField{
+				{Key: "region", Name: "Region", Type: CustomFieldTypeText},
+				{Key: "region", Name: "Other region", Type: CustomFieldTypeText},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCustomFields(tt.fields)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateCustomFieldValues(t *testing.T) {
+	fields := []CustomField{
+		{Key: "region", Name: "Region", Type: CustomFieldTypeSelect, Options: []string{"eu", "us"}, Required: true},
+		{Key: "services", Name: "Services", Type: CustomFieldTypeMultiselect, Options: []string{"api", "database"}},
+		{Key: "customer_id", Name: "Customer", Type: CustomFieldTypeText},
+		{Key: "responder", Name: "Responder", Type: CustomFieldTypeUser},
+		{Key: "users_affected", Name: "Users affected", Type: CustomFieldTypeNumber},
+	}
+
+	tests := []struct {
+		name       string
+		values     map[string][]string
+		invalidKey string
+	}{
+		{
+			name: "valid values",
+			values: map[string][]string{
+				"region":         {"eu"},
+				"services":       {"api", "database"},
+				"customer_id":    {"acme"},
+				"responder":      {"bqnbdf8uc0a8yz4i39qrpgkvtg"},
+				"users_affected": {"1200"},
+			},
+		},
+		{
+			name:   "only the required values",
+			values: map[string][]string{"region": {"us"}},
+		},
+		{
+			name:       "missing required value",
+			values:     map[string][]string{"customer_id": {"acme"}},
+			invalidKey: "region",
+		},
+		{
+			name:       "unknown field",
+			values:     map[string][]string{"region": {"eu"}, "severity": {"1"}},
+			invalidKey: "severity",
+		},
+		{
+			name:       "several values of a single-valued field",
+			values:     map[string][]string{"region": {"eu", "us"}},
+			invalidKey: "region",
+		},
+		{
+			name:       "value not among the options",
+			values:     map[string][]string{"region": {"eu"}, "services": {"api", "cache"}},
+			invalidKey: "services",
+		},
+		{
+			name:       "blank text",
+			values:     map[string][]string{"region": {"eu"}, "customer_id": {" "}},
+			invalidKey: "customer_id",
+		},
+		{
+			name:       "value too long",
+			values:     map[string][]string{"region": {"eu"}, "customer_id": {strings.Repeat("a", CustomFieldValueMaxLength+1)}},
+			invalidKey: "customer_id",
+		},
+		{
+			name:       "invalid user ID",
+			values:     map[string][]string{"region": {"eu"}, "responder": {"alice"}},
+			invalidKey: "responder",
+		},
+		{
+			name:       "invalid number",
+			values:     map[string][]string{"region": {"eu"}, "users_affected": {"many"}},
+			invalidKey: "users_affected",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCustomFieldValues(fields, tt.values)
+			if tt.invalidKey == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			var fieldErr *CustomFieldError
+			require.True(t, errors.As(err, &fieldErr))
+			require.Equal(t, tt.invalidKey, fieldErr.Key)
+		})
+	}
+}
+
+func TestValidateCustomFieldCondition(t *testing.T) {
+	fields := []CustomField{
+		{Key: "region", Name: "Region", Type: CustomFieldTypeSelect, Options: []string{"eu", "us"}},
+		{Key: "customer_id", Name: "Customer", Type: CustomFieldTypeText},
+	}
+
+	require.NoError(t, ValidateCustomFieldCondition(ItemCondition{Field: ConditionFieldCustomPrefix + "region", Operator: ConditionOperatorIs, Values: []string{"eu"}}, fields))
+	require.NoError(t, ValidateCustomFieldCondition(ItemCondition{Field: ConditionFieldCustomPrefix + "customer_id", Operator: ConditionOperatorContains, Values: []string{"acme"}}, fields))
+	require.EqualError(t, ValidateCustomFieldCondition(ItemCondition{Field: ConditionFieldCustomPrefix + "region", Operator: ConditionOperatorIs, Values: []string{"apac"}}, fields), "'apac' is not one of the options of custom field region")
+	require.EqualError(t, ValidateCustomFieldCondition(ItemCondition{Field: ConditionFieldCustomPrefix + "team", Operator: ConditionOperatorIs, Values: []string{"payments"}}, fields), "unknown custom field team")
+}
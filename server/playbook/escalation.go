@@ -0,0 +1,91 @@
+package playbook
+
+import (
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/pkg/errors"
+)
+
+// MinAcknowledgeTimeoutSeconds is the shortest time an owner is given to acknowledge an incident
+// before it is escalated.
+const MinAcknowledgeTimeoutSeconds = 60
+
+// EscalationStep is a step of the escalation chain of a playbook. When the owner of an incident
+// does not acknowledge it in time, the steps are taken one after another, each after the
+// acknowledgment timeout, until someone acknowledges the incident: the users of the step, and the
+// members of its groups, are added to the incident channel and asked to acknowledge it by DM.
+type EscalationStep struct {
+	UserIDs  []string `json:"user_ids"`
+	GroupIDs []string `json:"group_ids"`
+
+	// ReassignOwner makes the first of UserIDs the owner of the incident.
+	ReassignOwner bool `json:"reassign_owner"`
+}
+
+// Clone returns a deep copy of the step.
+func (e EscalationStep) Clone() EscalationStep {
+	newStep := e
+	newStep.UserIDs = append([]string(nil), e.UserIDs...)
+	newStep.GroupIDs = append([]string(nil), e.GroupIDs...)
+	return newStep
+}
+
+// Validate checks that the step notifies someone, that its IDs are valid, and that there is a user
+// to reassign the incident to if ReassignOwner is set.
+func (e EscalationStep) Validate() error {
+	if len(e.UserIDs) == 0 && len(e.GroupIDs) == 0 {
+		return errors.New("step has no users nor groups")
+	}
+
+	for _, userID := range e.UserIDs {
+		if !model.IsValidId(userID) {
+			return errors.Errorf("invalid user id '%s'", userID)
+		}
+	}
+
+	for _, groupID := range e.GroupIDs {
+		if !model.IsValidId(groupID) {
+			return errors.Errorf("invalid group id '%s'", groupID)
+		}
+	}
+
+	if e.ReassignOwner && len(e.UserIDs) == 0 {
+		return errors.New("step reassigns the owner but has no users")
+	}
+
+	return nil
+}
+
+// CloneEscalationSteps returns a deep copy of steps.
+func CloneEscalationSteps(steps []EscalationStep) []EscalationStep {
+	if steps == nil {
+		return nil
+	}
+
+	newSteps := make([]EscalationStep, len(steps))
+	for i, step := range steps {
+		newSteps[i] = step.Clone()
+	}
+
+	return newSteps
+}
+
+// ValidateEscalation checks that the acknowledgment timeout is either disabled or long enough, and
+// that every step of the escalation chain is valid. The chain needs a timeout to be taken.
+func ValidateEscalation(acknowledgeTimeoutSeconds int64, steps []EscalationStep) error {
+	if acknowledgeTimeoutSeconds < 0 ||
+		(acknowledgeTimeoutSeconds > 0 && acknowledgeTimeoutSeconds < MinAcknowledgeTimeoutSeconds) {
+		return errors.Errorf("acknowledgment timeout must be at least %d seconds, or 0 to disable it", MinAcknowledgeTimeoutSeconds)
+	}
+
+	if acknowledgeTimeoutSeconds == 0 && len(steps) > 0 {
+		return errors.New("escalation steps require an acknowledgment timeout")
+	}
+
+	for i, step := range steps {
+		if err := step.Validate(); err != nil {
+			return errors.Wrapf(err, "step %d", i+1)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package playbook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateEscalation(t *testing.T) {
+	userID := "useruuuuuuuuuuuuuuuuuuuuuu"
+	groupID := "groupggggggggggggggggggggg"
+
+	tests := []struct {
+		name    string
+		timeout int64
+		steps   []EscalationStep
+		wantErr bool
+	}{
+		{
+			name: "disabled",
+		},
+		{
+			name:    "timeout without steps",
+			timeout: 15 * 60,
+		},
+		{
+			name:    "valid steps",
+			timeout: 15 * 60,
+			steps: []EscalationStep{
+				{GroupIDs: []string{groupID}},
+				{UserIDs: []string{userID}, ReassignOwner: true},
+			},
+		},
+		{
+			name:    "timeout too short",
+			timeout: 10,
+			wantErr: true,
+		},
+		{
+			name:    "negative timeout",
+			timeout: -60,
+			wantErr: true,
+		},
+		{
+			name:    "steps without timeout",
+			steps:   []EscalationStep{{UserIDs: []string{userID}}},
+			wantErr: true,
+		},
+		{
+			name:    "step without users nor groups",
+			timeout: 15 * 60,
+			steps:   []EscalationStep{{}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid user",
+			timeout: 15 * 60,
+			steps:   []EscalationStep{{UserIDs: []string{"alice"}}},
+			wantErr: true,
+		},
+		{
+			name:    "reassign owner without users",
+			timeout: 15 * 60,
+			steps:   []EscalationStep{{GroupIDs: []string{groupID}, ReassignOwner: true}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateEscalation(tt.timeout, tt.steps)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
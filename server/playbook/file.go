@@ -0,0 +1,523 @@
+package playbook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// FileVersion is the version of the playbook file format written by NewFile. Files with another
+// version are rejected.
+const FileVersion = 1
+
+// Formats of a playbook file.
+const (
+	FileFormatJSON = "json"
+	FileFormatYAML = "yaml"
+)
+
+// File is the portable representation of a playbook, used to move a playbook between teams or
+// servers, or to keep it under version control. It holds no IDs: users and groups are referenced
+// by name, and channels by name within the team of the playbook. Checklist items refer to each
+// other by their Key, which is only set on the items that are prerequisites of other items.
+//
+// Secrets, such as the webhook secret and the inbound webhook token, are not part of the file, and
+// neither is the on-call rotation of the default owner, which only exists on its server.
+type File struct {
+	Version                              int                   `json:"version"`
+	Title                                string                `json:"title"`
+	Description                          string                `json:"description,omitempty"`
+	CreatePublicIncident                 bool                  `json:"create_public_incident"`
+	Checklists                           []FileChecklist       `json:"checklists"`
+	CustomFields                         []CustomField         `json:"custom_fields,omitempty"`
+	Roles                                []Role                `json:"roles,omitempty"`
+	Members                              []string              `json:"members,omitempty"`
+	BroadcastChannel                     string                `json:"broadcast_channel,omitempty"`
+	ReminderMessageTemplate              string                `json:"reminder_message_template,omitempty"`
+	ReminderTimerDefaultSeconds          int64                 `json:"reminder_timer_default_seconds,omitempty"`
+	InvitedUsers                         []string              `json:"invited_users,omitempty"`
+	InvitedGroups                        []string              `json:"invited_groups,omitempty"`
+	InviteUsersEnabled                   bool                  `json:"invite_users_enabled,omitempty"`
+	DefaultOwner                         string                `json:"default_owner,omitempty"`
+	DefaultOwnerEnabled                  bool                  `json:"default_owner_enabled,omitempty"`
+	AnnouncementChannel                  string                `json:"announcement_channel,omitempty"`
+	AnnouncementChannelEnabled           bool                  `json:"announcement_channel_enabled,omitempty"`
+	WebhookOnCreationURL                 string                `json:"webhook_on_creation_url,omitempty"`
+	WebhookOnCreationEnabled             bool                  `json:"webhook_on_creation_enabled,omitempty"`
+	MessageOnJoin                        string                `json:"message_on_join,omitempty"`
+	MessageOnJoinEnabled                 bool                  `json:"message_on_join_enabled,omitempty"`
+	RetrospectiveReminderIntervalSeconds int64                 `json:"retrospective_reminder_interval_seconds,omitempty"`
+	RetrospectiveTemplate                string                `json:"retrospective_template,omitempty"`
+	WebhookOnStatusUpdateURL             string                `json:"webhook_on_status_update_url,omitempty"`
+	WebhookOnStatusUpdateEnabled         bool                  `json:"webhook_on_status_update_enabled,omitempty"`
+	WebhookSubscriptions                 []WebhookSubscription `json:"webhook_subscriptions,omitempty"`
+	InboundWebhookEnabled                bool                  `json:"inbound_webhook_enabled,omitempty"`
+	InboundWebhookNameTemplate           string                `json:"inbound_webhook_name_template,omitempty"`
+	InboundWebhookDescriptionTemplate    string                `json:"inbound_webhook_description_template,omitempty"`
+	InboundWebhookOwnerTemplate          string                `json:"inbound_webhook_owner_template,omitempty"`
+	InboundWebhookFingerprintTemplate    string                `json:"inbound_webhook_fingerprint_template,omitempty"`
+	InboundWebhookAutoResolve            bool                  `json:"inbound_webhook_auto_resolve,omitempty"`
+	AcknowledgeTimeoutSeconds            int64                 `json:"acknowledge_timeout_seconds,omitempty"`
+	EscalationSteps                      []FileEscalationStep  `json:"escalation_steps,omitempty"`
+	StatusUpdateTemplate                 string                `json:"status_update_template,omitempty"`
+	BroadcastTargets                     []FileBroadcastTarget `json:"broadcast_targets,omitempty"`
+}
+
+// FileChecklist is a checklist in a playbook file.
+type FileChecklist struct {
+	Title string     `json:"title"`
+	Items []FileItem `json:"items"`
+}
+
+// FileItem is a checklist item in a playbook file.
+type FileItem struct {
+	Key             string          `json:"key,omitempty"`
+	Title           string          `json:"title"`
+	Description     string          `json:"description,omitempty"`
+	Command         string          `json:"command,omitempty"`
+	Assignee        string          `json:"assignee,omitempty"`
+	AssigneeRole    string          `json:"assignee_role,omitempty"` // Key of the role assigned to the item.
+	DueAfterSeconds int64           `json:"due_after_seconds,omitempty"`
+	Prerequisites   []string        `json:"prerequisites,omitempty"` // Keys of the items that must be closed first.
+	Condition       *ItemCondition  `json:"condition,omitempty"`
+	AutomaticAction *FileItemAction `json:"automatic_action,omitempty"`
+}
+
+// FileItemAction is the automatic action of a checklist item in a playbook file.
+type FileItemAction struct {
+	Trigger string   `json:"trigger"`
+	Type    string   `json:"type"`
+	Channel string   `json:"channel,omitempty"`
+	Message string   `json:"message,omitempty"`
+	Users   []string `json:"users,omitempty"`
+}
+
+// FileEscalationStep is a step of the escalation chain in a playbook file.
+type FileEscalationStep struct {
+	Users         []string `json:"users,omitempty"`
+	Groups        []string `json:"groups,omitempty"`
+	ReassignOwner bool     `json:"reassign_owner,omitempty"`
+}
+
+// FileBroadcastTarget is a broadcast target in a playbook file.
+type FileBroadcastTarget struct {
+	Channel     string `json:"channel"`
+	Filter      string `json:"filter"`
+	MinSeverity string `json:"min_severity,omitempty"`
+}
+
+// FileMapper maps the references of a playbook file: from IDs to names when writing a file, and
+// from names to IDs when reading one. Each function returns a blank string if the reference
+// cannot be mapped.
+type FileMapper struct {
+	User    func(string) string
+	Group   func(string) string
+	Channel func(string) string
+}
+
+// mapAll maps refs with mapFn, leaving out the references that cannot be mapped.
+func mapAll(refs []string, mapFn func(string) string) []string {
+	var mapped []string
+	for _, ref := range refs {
+		if newRef := mapFn(ref); newRef != "" {
+			mapped = append(mapped, newRef)
+		}
+	}
+	return mapped
+}
+
+// mapOne maps ref with mapFn, leaving blank references blank.
+func mapOne(ref string, mapFn func(string) string) string {
+	if ref == "" {
+		return ""
+	}
+	return mapFn(ref)
+}
+
+// NewFile returns the file of playbook, using mapper to map the IDs it references to names.
+func NewFile(playbook Playbook, mapper FileMapper) File {
+	// Give a key to every item that is a prerequisite, numbered after its position.
+	keys := map[string]string{}
+	for _, checklist := range playbook.Checklists {
+		for _, item := range checklist.Items {
+			for _, prerequisiteID := range item.PrerequisiteIDs {
+				keys[prerequisiteID] = ""
+			}
+		}
+	}
+
+	for i, checklist := range playbook.Checklists {
+		for j, item := range checklist.Items {
+			if _, ok := keys[item.ID]; ok {
+				keys[item.ID] = fmt.Sprintf("%d.%d", i+1, j+1)
+			}
+		}
+	}
+
+	checklists := make([]FileChecklist, 0, len(playbook.Checklists))
+	for _, checklist := range playbook.Checklists {
+		fileChecklist := FileChecklist{Title: checklist.Title, Items: []FileItem{}}
+		for _, item := range checklist.Items {
+			fileItem := FileItem{
+				Key:             keys[item.ID],
+				Title:           item.Title,
+				Description:     item.Description,
+				Command:         item.Command,
+				Assignee:        mapOne(item.AssigneeID, mapper.User),
+				AssigneeRole:    item.AssigneeRole,
+				DueAfterSeconds: item.DueAfterSeconds,
+			}
+			for _, prerequisiteID := range item.PrerequisiteIDs {
+				if key := keys[prerequisiteID]; key != "" {
+					fileItem.Prerequisites = append(fileItem.Prerequisites, key)
+				}
+			}
+			if !item.Condition.IsBlank() {
+				condition := item.Condition
+				condition.Values = append([]string(nil), item.Condition.Values...)
+				fileItem.Condition = &condition
+			}
+			if !item.AutomaticAction.IsBlank() {
+				fileItem.AutomaticAction = &FileItemAction{
+					Trigger: item.AutomaticAction.Trigger,
+					Type:    item.AutomaticAction.Type,
+					Channel: mapOne(item.AutomaticAction.ChannelID, mapper.Channel),
+					Message: item.AutomaticAction.Message,
+					Users:   mapAll(item.AutomaticAction.UserIDs, mapper.User),
+				}
+			}
+			fileChecklist.Items = append(fileChecklist.Items, fileItem)
+		}
+		checklists = append(checklists, fileChecklist)
+	}
+
+	var escalationSteps []FileEscalationStep
+	for _, step := range playbook.EscalationSteps {
+		escalationSteps = append(escalationSteps, FileEscalationStep{
+			Users:         mapAll(step.UserIDs, mapper.User),
+			Groups:        mapAll(step.GroupIDs, mapper.Group),
+			ReassignOwner: step.ReassignOwner,
+		})
+	}
+
+	var broadcastTargets []FileBroadcastTarget
+	for _, target := range playbook.BroadcastTargets {
+		broadcastTargets = append(broadcastTargets, FileBroadcastTarget{
+			Channel:     mapOne(target.ChannelID, mapper.Channel),
+			Filter:      target.Filter,
+			MinSeverity: target.MinSeverity,
+		})
+	}
+
+	return File{
+		Version:                              FileVersion,
+		Title:                                playbook.Title,
+		Description:                          playbook.Description,
+		CreatePublicIncident:                 playbook.CreatePublicIncident,
+		Checklists:                           checklists,
+		CustomFields:                         CloneCustomFields(playbook.CustomFields),
+		Roles:                                CloneRoles(playbook.Roles),
+		Members:                              mapAll(playbook.MemberIDs, mapper.User),
+		BroadcastChannel:                     mapOne(playbook.BroadcastChannelID, mapper.Channel),
+		ReminderMessageTemplate:              playbook.ReminderMessageTemplate,
+		ReminderTimerDefaultSeconds:          playbook.ReminderTimerDefaultSeconds,
+		InvitedUsers:                         mapAll(playbook.InvitedUserIDs, mapper.User),
+		InvitedGroups:                        mapAll(playbook.InvitedGroupIDs, mapper.Group),
+		InviteUsersEnabled:                   playbook.InviteUsersEnabled,
+		DefaultOwner:                         mapOne(playbook.DefaultOwnerID, mapper.User),
+		DefaultOwnerEnabled:                  playbook.DefaultOwnerEnabled,
+		AnnouncementChannel:                  mapOne(playbook.AnnouncementChannelID, mapper.Channel),
+		AnnouncementChannelEnabled:           playbook.AnnouncementChannelEnabled,
+		WebhookOnCreationURL:                 playbook.WebhookOnCreationURL,
+		WebhookOnCreationEnabled:             playbook.WebhookOnCreationEnabled,
+		MessageOnJoin:                        playbook.MessageOnJoin,
+		MessageOnJoinEnabled:                 playbook.MessageOnJoinEnabled,
+		RetrospectiveReminderIntervalSeconds: playbook.RetrospectiveReminderIntervalSeconds,
+		RetrospectiveTemplate:                playbook.RetrospectiveTemplate,
+		WebhookOnStatusUpdateURL:             playbook.WebhookOnStatusUpdateURL,
+		WebhookOnStatusUpdateEnabled:         playbook.WebhookOnStatusUpdateEnabled,
+		WebhookSubscriptions:                 CloneWebhookSubscriptions(playbook.WebhookSubscriptions),
+		InboundWebhookEnabled:                playbook.InboundWebhookEnabled,
+		InboundWebhookNameTemplate:           playbook.InboundWebhookNameTemplate,
+		InboundWebhookDescriptionTemplate:    playbook.InboundWebhookDescriptionTemplate,
+		InboundWebhookOwnerTemplate:          playbook.InboundWebhookOwnerTemplate,
+		InboundWebhookFingerprintTemplate:    playbook.InboundWebhookFingerprintTemplate,
+		InboundWebhookAutoResolve:            playbook.InboundWebhookAutoResolve,
+		AcknowledgeTimeoutSeconds:            playbook.AcknowledgeTimeoutSeconds,
+		EscalationSteps:                      escalationSteps,
+		StatusUpdateTemplate:                 playbook.StatusUpdateTemplate,
+		BroadcastTargets:                     broadcastTargets,
+	}
+}
+
+// Validate checks that the file has the supported version, the fields required by the playbook
+// schema, unique keys to which every prerequisite refers, valid custom fields and roles, and an
+// acknowledgment timeout if it has escalation steps.
+func (f File) Validate() error {
+	if f.Version != FileVersion {
+		return errors.Errorf("unsupported version %d; expected version %d", f.Version, FileVersion)
+	}
+
+	if f.Title == "" {
+		return errors.New("title is required")
+	}
+
+	keys := map[string]bool{}
+	for i, checklist := range f.Checklists {
+		if checklist.Title == "" {
+			return errors.Errorf("checklist %d has no title", i+1)
+		}
+
+		for j, item := range checklist.Items {
+			if item.Title == "" {
+				return errors.Errorf("item %d of checklist %s has no title", j+1, checklist.Title)
+			}
+
+			if item.Key == "" {
+				continue
+			}
+			if keys[item.Key] {
+				return errors.Errorf("key %s is used by more than one item", item.Key)
+			}
+			keys[item.Key] = true
+		}
+	}
+
+	for _, checklist := range f.Checklists {
+		for _, item := range checklist.Items {
+			for _, key := range item.Prerequisites {
+				if !keys[key] {
+					return errors.Errorf("item %s has an unknown prerequisite %s", item.Title, key)
+				}
+			}
+		}
+	}
+
+	if err := ValidateCustomFields(f.CustomFields); err != nil {
+		return errors.Wrap(err, "invalid custom fields")
+	}
+
+	if err := ValidateRoles(f.Roles, nil); err != nil {
+		return errors.Wrap(err, "invalid roles")
+	}
+
+	for _, checklist := range f.Checklists {
+		for _, item := range checklist.Items {
+			if _, ok := FindRole(f.Roles, item.AssigneeRole); item.AssigneeRole != "" && !ok {
+				return errors.Errorf("item %s is assigned to an unknown role %s", item.Title, item.AssigneeRole)
+			}
+		}
+	}
+
+	if f.AcknowledgeTimeoutSeconds == 0 && len(f.EscalationSteps) > 0 {
+		return errors.New("escalation steps require an acknowledgment timeout")
+	}
+
+	if err := ValidateStatusUpdateTemplate(f.StatusUpdateTemplate); err != nil {
+		return errors.Wrap(err, "invalid status update template")
+	}
+
+	for i, target := range f.BroadcastTargets {
+		if target.Filter != BroadcastFilterAll && target.Filter != BroadcastFilterStatusChanges {
+			return errors.Errorf("broadcast target %d has an unknown filter '%s'", i+1, target.Filter)
+		}
+	}
+
+	return nil
+}
+
+// Playbook returns the playbook of the file in teamID, using mapper to map the names it references
+// to IDs. References that cannot be mapped are left out, as are the broadcast targets whose channel
+// cannot be mapped, and the default owner and announcement channel are disabled if they cannot be
+// mapped. Every checklist item gets a new ID.
+func (f File) Playbook(teamID string, mapper FileMapper) Playbook {
+	itemIDs := map[string]string{}
+	checklists := make([]Checklist, 0, len(f.Checklists))
+	for _, fileChecklist := range f.Checklists {
+		checklist := Checklist{Title: fileChecklist.Title, Items: []ChecklistItem{}}
+		for _, fileItem := range fileChecklist.Items {
+			item := ChecklistItem{
+				ID:              model.NewId(),
+				Title:           fileItem.Title,
+				Description:     fileItem.Description,
+				Command:         fileItem.Command,
+				AssigneeID:      mapOne(fileItem.Assignee, mapper.User),
+				AssigneeRole:    fileItem.AssigneeRole,
+				DueAfterSeconds: fileItem.DueAfterSeconds,
+			}
+			if fileItem.Key != "" {
+				itemIDs[fileItem.Key] = item.ID
+			}
+			if fileItem.Condition != nil {
+				item.Condition = *fileItem.Condition
+				item.Condition.Values = append([]string(nil), fileItem.Condition.Values...)
+			}
+			if fileItem.AutomaticAction != nil {
+				item.AutomaticAction = ItemAction{
+					Trigger:   fileItem.AutomaticAction.Trigger,
+					Type:      fileItem.AutomaticAction.Type,
+					ChannelID: mapOne(fileItem.AutomaticAction.Channel, mapper.Channel),
+					Message:   fileItem.AutomaticAction.Message,
+					UserIDs:   mapAll(fileItem.AutomaticAction.Users, mapper.User),
+				}
+			}
+			checklist.Items = append(checklist.Items, item)
+		}
+		checklists = append(checklists, checklist)
+	}
+
+	for i, fileChecklist := range f.Checklists {
+		for j, fileItem := range fileChecklist.Items {
+			for _, key := range fileItem.Prerequisites {
+				checklists[i].Items[j].PrerequisiteIDs = append(checklists[i].Items[j].PrerequisiteIDs, itemIDs[key])
+			}
+		}
+	}
+
+	var escalationSteps []EscalationStep
+	for _, fileStep := range f.EscalationSteps {
+		escalationSteps = append(escalationSteps, EscalationStep{
+			UserIDs:       mapAll(fileStep.Users, mapper.User),
+			GroupIDs:      mapAll(fileStep.Groups, mapper.Group),
+			ReassignOwner: fileStep.ReassignOwner,
+		})
+	}
+
+	var broadcastTargets []BroadcastTarget
+	for _, fileTarget := range f.BroadcastTargets {
+		if channelID := mapOne(fileTarget.Channel, mapper.Channel); channelID != "" {
+			broadcastTargets = append(broadcastTargets, BroadcastTarget{
+				ChannelID:   channelID,
+				Filter:      fileTarget.Filter,
+				MinSeverity: fileTarget.MinSeverity,
+			})
+		}
+	}
+
+	playbook := Playbook{
+		Title:                                f.Title,
+		Description:                          f.Description,
+		TeamID:                               teamID,
+		CreatePublicIncident:                 f.CreatePublicIncident,
+		Checklists:                           checklists,
+		CustomFields:                         CloneCustomFields(f.CustomFields),
+		Roles:                                CloneRoles(f.Roles),
+		MemberIDs:                            mapAll(f.Members, mapper.User),
+		BroadcastChannelID:                   mapOne(f.BroadcastChannel, mapper.Channel),
+		ReminderMessageTemplate:              f.ReminderMessageTemplate,
+		ReminderTimerDefaultSeconds:          f.ReminderTimerDefaultSeconds,
+		InvitedUserIDs:                       mapAll(f.InvitedUsers, mapper.User),
+		InvitedGroupIDs:                      mapAll(f.InvitedGroups, mapper.Group),
+		InviteUsersEnabled:                   f.InviteUsersEnabled,
+		DefaultOwnerID:                       mapOne(f.DefaultOwner, mapper.User),
+		DefaultOwnerEnabled:                  f.DefaultOwnerEnabled,
+		AnnouncementChannelID:                mapOne(f.AnnouncementChannel, mapper.Channel),
+		AnnouncementChannelEnabled:           f.AnnouncementChannelEnabled,
+		WebhookOnCreationURL:                 f.WebhookOnCreationURL,
+		WebhookOnCreationEnabled:             f.WebhookOnCreationEnabled,
+		MessageOnJoin:                        f.MessageOnJoin,
+		MessageOnJoinEnabled:                 f.MessageOnJoinEnabled,
+		RetrospectiveReminderIntervalSeconds: f.RetrospectiveReminderIntervalSeconds,
+		RetrospectiveTemplate:                f.RetrospectiveTemplate,
+		WebhookOnStatusUpdateURL:             f.WebhookOnStatusUpdateURL,
+		WebhookOnStatusUpdateEnabled:         f.WebhookOnStatusUpdateEnabled,
+		WebhookSubscriptions:                 CloneWebhookSubscriptions(f.WebhookSubscriptions),
+		InboundWebhookEnabled:                f.InboundWebhookEnabled,
+		InboundWebhookNameTemplate:           f.InboundWebhookNameTemplate,
+		InboundWebhookDescriptionTemplate:    f.InboundWebhookDescriptionTemplate,
+		InboundWebhookOwnerTemplate:          f.InboundWebhookOwnerTemplate,
+		InboundWebhookFingerprintTemplate:    f.InboundWebhookFingerprintTemplate,
+		InboundWebhookAutoResolve:            f.InboundWebhookAutoResolve,
+		AcknowledgeTimeoutSeconds:            f.AcknowledgeTimeoutSeconds,
+		EscalationSteps:                      escalationSteps,
+		StatusUpdateTemplate:                 f.StatusUpdateTemplate,
+		BroadcastTargets:                     broadcastTargets,
+	}
+
+	if playbook.DefaultOwnerID == "" {
+		playbook.DefaultOwnerEnabled = false
+	}
+	if playbook.AnnouncementChannelID == "" {
+		playbook.AnnouncementChannelEnabled = false
+	}
+
+	return playbook
+}
+
+// MarshalFile encodes f in format, which is FileFormatJSON or FileFormatYAML. Both formats use the
+// same field names.
+func MarshalFile(f File, format string) ([]byte, error) {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal playbook file")
+	}
+
+	switch format {
+	case FileFormatJSON:
+		return data, nil
+	case FileFormatYAML:
+		// JSON is valid YAML: decoding it into a MapSlice keeps the order of the fields.
+		var document yaml.MapSlice
+		if err = yaml.Unmarshal(data, &document); err != nil {
+			return nil, errors.Wrap(err, "failed to convert playbook file to yaml")
+		}
+		return yaml.Marshal(document)
+	}
+
+	return nil, errors.Errorf("unknown format %s; expected %s or %s", format, FileFormatJSON, FileFormatYAML)
+}
+
+// UnmarshalFile decodes a file in format, which is FileFormatJSON or FileFormatYAML. Unknown fields
+// and fields of the wrong type are rejected. The file is not validated.
+func UnmarshalFile(data []byte, format string) (File, error) {
+	switch format {
+	case FileFormatJSON:
+	case FileFormatYAML:
+		var document interface{}
+		if err := yaml.Unmarshal(data, &document); err != nil {
+			return File{}, errors.Wrap(err, "failed to parse yaml")
+		}
+
+		converted, err := json.Marshal(yamlToJSON(document))
+		if err != nil {
+			return File{}, errors.Wrap(err, "failed to convert yaml to json")
+		}
+		data = converted
+	default:
+		return File{}, errors.Errorf("unknown format %s; expected %s or %s", format, FileFormatJSON, FileFormatYAML)
+	}
+
+	var f File
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&f); err != nil {
+		return File{}, errors.Wrap(err, "invalid playbook file")
+	}
+
+	return f, nil
+}
+
+// yamlToJSON converts the maps decoded from YAML, which may have keys of any type, to maps with
+// string keys that can be encoded to JSON.
+func yamlToJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			converted[fmt.Sprint(key)] = yamlToJSON(item)
+		}
+		return converted
+	case []interface{}:
+		converted := make([]interface{}, len(v))
+		for i, item := range v {
+			converted[i] = yamlToJSON(item)
+		}
+		return converted
+	}
+
+	return value
+}
@@ -0,0 +1,162 @@
+package playbook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFile_RoundTrip(t *testing.T) {
+	original := Playbook{
+		ID:                  "playbookid",
+		Title:               "Database outage",
+		TeamID:              "teamid",
+		MemberIDs:           []string{"aliceid", "deletedid"},
+		BroadcastChannelID:  "broadcastid",
+		DefaultOwnerID:      "deletedid",
+		DefaultOwnerEnabled: true,
+		Checklists: []Checklist{
+			{
+				ID:    "checklistid",
+				Title: "Triage",
+				Items: []ChecklistItem{
+					{ID: "snapshot", Title: "Snapshot the database", AssigneeID: "aliceid", DueAfterSeconds: 600},
+					{
+						ID:              "failover",
+						Title:           "Fail over",
+						PrerequisiteIDs: []string{"snapshot"},
+						Condition:       ItemCondition{Field: ConditionFieldSeverity, Operator: ConditionOperatorIs, Values: []string{"1"}},
+						AutomaticAction: ItemAction{Trigger: ActionTriggerChecked, Type: ActionTypeInviteUsers, UserIDs: []string{"aliceid"}},
+					},
+				},
+			},
+		},
+	}
+
+	toNames := map[string]string{"aliceid": "alice", "broadcastid": "incidents"}
+	file := NewFile(original, FileMapper{
+		User:    func(id string) string { return toNames[id] },
+		Group:   func(id string) string { return toNames[id] },
+		Channel: func(id string) string { return toNames[id] },
+	})
+
+	require.NoError(t, file.Validate())
+	require.Equal(t, []string{"alice"}, file.Members)
+	require.Equal(t, "incidents", file.BroadcastChannel)
+	require.Empty(t, file.DefaultOwner)
+	require.Equal(t, "1.1", file.Checklists[0].Items[0].Key)
+	require.Equal(t, []string{"1.1"}, file.Checklists[0].Items[1].Prerequisites)
+	require.Equal(t, []string{"alice"}, file.Checklists[0].Items[1].AutomaticAction.Users)
+
+	for _, format := range []string{FileFormatJSON, FileFormatYAML} {
+		t.Run(format, func(t *testing.T) {
+			data, err := MarshalFile(file, format)
+			require.NoError(t, err)
+
+			decoded, err := UnmarshalFile(data, format)
+			require.NoError(t, err)
+			require.Equal(t, file, decoded)
+		})
+	}
+
+	toIDs := map[string]string{"alice": "newaliceid", "incidents": "newbroadcastid"}
+	imported := file.Playbook("newteamid", FileMapper{
+		User:    func(name string) string { return toIDs[name] },
+		Group:   func(name string) string { return toIDs[name] },
+		Channel: func(name string) string { return toIDs[name] },
+	})
+
+	require.Empty(t, imported.ID)
+	require.Equal(t, "newteamid", imported.TeamID)
+	require.Equal(t, []string{"newaliceid"}, imported.MemberIDs)
+	require.Equal(t, "newbroadcastid", imported.BroadcastChannelID)
+	require.False(t, imported.DefaultOwnerEnabled)
+
+	items := imported.Checklists[0].Items
+	require.Equal(t, "newaliceid", items[0].AssigneeID)
+	require.Equal(t, int64(600), items[0].DueAfterSeconds)
+	require.NotEmpty(t, items[0].ID)
+	require.NotEqual(t, "snapshot", items[0].ID)
+	require.Equal(t, []string{items[0].ID}, items[1].PrerequisiteIDs)
+	require.Equal(t, original.Checklists[0].Items[1].Condition, items[1].Condition)
+	require.Equal(t, []string{"newaliceid"}, items[1].AutomaticAction.UserIDs)
+	require.NoError(t, ValidatePrerequisites(imported.Checklists))
+}
+
+func TestUnmarshalFile(t *testing.T) {
+	t.Run("yaml", func(t *testing.T) {
+		file, err := UnmarshalFile([]byte(`
+version: 1
+title: Database outage
+checklists:
+  - title: Triage
+    items:
+      - title: Snapshot the database
+        key: snapshot
+      - title: Fail over
+        prerequisites: [snapshot]
+`), FileFormatYAML)
+		require.NoError(t, err)
+		require.NoError(t, file.Validate())
+		require.Equal(t, "Database outage", file.Title)
+		require.Equal(t, []string{"snapshot"}, file.Checklists[0].Items[1].Prerequisites)
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		_, err := UnmarshalFile([]byte(`{"version": 1, "title": "Database outage", "team_id": "teamid"}`), FileFormatJSON)
+		require.Error(t, err)
+	})
+
+	t.Run("wrong type", func(t *testing.T) {
+		_, err := UnmarshalFile([]byte("version: 1\ntitle: [Database outage]\n"), FileFormatYAML)
+		require.Error(t, err)
+	})
+}
+
+func TestFile_Validate(t *testing.T) {
+	for name, tc := range map[string]struct {
+		file File
+		err  string
+	}{
+		"valid": {
+			file: File{Version: FileVersion, Title: "Outage", Checklists: []FileChecklist{
+				{Title: "Triage", Items: []FileItem{{Key: "a", Title: "first"}, {Title: "second", Prerequisites: []string{"a"}}}},
+			}},
+		},
+		"unsupported version": {
+			file: File{Version: 2, Title: "Outage"},
+			err:  "unsupported version 2; expected version 1",
+		},
+		"no title": {
+			file: File{Version: FileVersion},
+			err:  "title is required",
+		},
+		"item without title": {
+			file: File{Version: FileVersion, Title: "Outage", Checklists: []FileChecklist{
+				{Title: "Triage", Items: []FileItem{{}}},
+			}},
+			err: "item 1 of checklist Triage has no title",
+		},
+		"duplicate key": {
+			file: File{Version: FileVersion, Title: "Outage", Checklists: []FileChecklist{
+				{Title: "Triage", Items: []FileItem{{Key: "a", Title: "first"}, {Key: "a", Title: "second"}}},
+			}},
+			err: "key a is used by more than one item",
+		},
+		"unknown prerequisite": {
+			file: File{Version: FileVersion, Title: "Outage", Checklists: []FileChecklist{
+				{Title: "Triage", Items: []FileItem{{Title: "first", Prerequisites: []string{"z"}}}},
+			}},
+			err: "item first has an unknown prerequisite z",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := tc.file.Validate()
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
@@ -122,6 +122,36 @@ func (mr *MockServiceMockRecorder) GetPlaybooksForTeam(arg0, arg1, arg2 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPlaybooksForTeam", reflect.TypeOf((*MockService)(nil).GetPlaybooksForTeam), arg0, arg1, arg2)
 }
 
+// GetRevision mocks base method
+func (m *MockService) GetRevision(arg0 string, arg1 int) (playbook.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevision", arg0, arg1)
+	ret0, _ := ret[0].(playbook.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevision indicates an expected call of GetRevision
+func (mr *MockServiceMockRecorder) GetRevision(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevision", reflect.TypeOf((*MockService)(nil).GetRevision), arg0, arg1)
+}
+
+// GetRevisions mocks base method
+func (m *MockService) GetRevisions(arg0 string) ([]playbook.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevisions", arg0)
+	ret0, _ := ret[0].([]playbook.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevisions indicates an expected call of GetRevisions
+func (mr *MockServiceMockRecorder) GetRevisions(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevisions", reflect.TypeOf((*MockService)(nil).GetRevisions), arg0)
+}
+
 // Update mocks base method
 func (m *MockService) Update(arg0 playbook.Playbook, arg1 string) error {
 	m.ctrl.T.Helper()
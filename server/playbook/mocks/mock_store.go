@@ -48,6 +48,21 @@ func (mr *MockStoreMockRecorder) Create(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockStore)(nil).Create), arg0)
 }
 
+// CreateRevision mocks base method
+func (m *MockStore) CreateRevision(arg0 playbook.Playbook, arg1 string) (playbook.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRevision", arg0, arg1)
+	ret0, _ := ret[0].(playbook.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRevision indicates an expected call of CreateRevision
+func (mr *MockStoreMockRecorder) CreateRevision(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRevision", reflect.TypeOf((*MockStore)(nil).CreateRevision), arg0, arg1)
+}
+
 // Delete mocks base method
 func (m *MockStore) Delete(arg0 string) error {
 	m.ctrl.T.Helper()
@@ -122,6 +137,36 @@ func (mr *MockStoreMockRecorder) GetPlaybooksForTeam(arg0, arg1, arg2 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPlaybooksForTeam", reflect.TypeOf((*MockStore)(nil).GetPlaybooksForTeam), arg0, arg1, arg2)
 }
 
+// GetRevision mocks base method
+func (m *MockStore) GetRevision(arg0 string, arg1 int) (playbook.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevision", arg0, arg1)
+	ret0, _ := ret[0].(playbook.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevision indicates an expected call of GetRevision
+func (mr *MockStoreMockRecorder) GetRevision(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevision", reflect.TypeOf((*MockStore)(nil).GetRevision), arg0, arg1)
+}
+
+// GetRevisions mocks base method
+func (m *MockStore) GetRevisions(arg0 string) ([]playbook.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevisions", arg0)
+	ret0, _ := ret[0].([]playbook.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevisions indicates an expected call of GetRevisions
+func (mr *MockStoreMockRecorder) GetRevisions(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevisions", reflect.TypeOf((*MockStore)(nil).GetRevisions), arg0)
+}
+
 // Update mocks base method
 func (m *MockStore) Update(arg0 playbook.Playbook) error {
 	m.ctrl.T.Helper()
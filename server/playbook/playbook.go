@@ -2,7 +2,10 @@ package playbook
 
 import (
 	"encoding/json"
+	"strings"
+	"text/template"
 
+	"github.com/mattermost/mattermost-server/v5/model"
 	"github.com/pkg/errors"
 )
 
@@ -11,35 +14,77 @@ var ErrNotFound = errors.New("not found")
 
 // Playbook represents the planning before an incident type is initiated.
 type Playbook struct {
-	ID                                   string      `json:"id"`
-	Title                                string      `json:"title"`
-	Description                          string      `json:"description"`
-	TeamID                               string      `json:"team_id"`
-	CreatePublicIncident                 bool        `json:"create_public_incident"`
-	CreateAt                             int64       `json:"create_at"`
-	DeleteAt                             int64       `json:"delete_at"`
-	NumStages                            int64       `json:"num_stages"`
-	NumSteps                             int64       `json:"num_steps"`
-	Checklists                           []Checklist `json:"checklists"`
-	MemberIDs                            []string    `json:"member_ids"`
-	BroadcastChannelID                   string      `json:"broadcast_channel_id"`
-	ReminderMessageTemplate              string      `json:"reminder_message_template"`
-	ReminderTimerDefaultSeconds          int64       `json:"reminder_timer_default_seconds"`
-	InvitedUserIDs                       []string    `json:"invited_user_ids"`
-	InvitedGroupIDs                      []string    `json:"invited_group_ids"`
-	InviteUsersEnabled                   bool        `json:"invite_users_enabled"`
-	DefaultOwnerID                       string      `json:"default_owner_id"`
-	DefaultOwnerEnabled                  bool        `json:"default_owner_enabled"`
-	AnnouncementChannelID                string      `json:"announcement_channel_id"`
-	AnnouncementChannelEnabled           bool        `json:"announcement_channel_enabled"`
-	WebhookOnCreationURL                 string      `json:"webhook_on_creation_url"`
-	WebhookOnCreationEnabled             bool        `json:"webhook_on_creation_enabled"`
-	MessageOnJoin                        string      `json:"message_on_join"`
-	MessageOnJoinEnabled                 bool        `json:"message_on_join_enabled"`
-	RetrospectiveReminderIntervalSeconds int64       `json:"retrospective_reminder_interval_seconds"`
-	RetrospectiveTemplate                string      `json:"retrospective_template"`
-	WebhookOnStatusUpdateURL             string      `json:"webhook_on_status_update_url"`
-	WebhookOnStatusUpdateEnabled         bool        `json:"webhook_on_status_update_enabled"`
+	ID                                   string                `json:"id"`
+	Title                                string                `json:"title"`
+	Description                          string                `json:"description"`
+	TeamID                               string                `json:"team_id"`
+	CreatePublicIncident                 bool                  `json:"create_public_incident"`
+	CreateAt                             int64                 `json:"create_at"`
+	DeleteAt                             int64                 `json:"delete_at"`
+	NumStages                            int64                 `json:"num_stages"`
+	NumSteps                             int64                 `json:"num_steps"`
+	Checklists                           []Checklist           `json:"checklists"`
+	MemberIDs                            []string              `json:"member_ids"`
+	BroadcastChannelID                   string                `json:"broadcast_channel_id"`
+	ReminderMessageTemplate              string                `json:"reminder_message_template"`
+	ReminderTimerDefaultSeconds          int64                 `json:"reminder_timer_default_seconds"`
+	InvitedUserIDs                       []string              `json:"invited_user_ids"`
+	InvitedGroupIDs                      []string              `json:"invited_group_ids"`
+	AutoFollowerIDs                      []string              `json:"auto_follower_ids"` // Users who follow every incident created from the playbook.
+	InviteUsersEnabled                   bool                  `json:"invite_users_enabled"`
+	DefaultOwnerID                       string                `json:"default_owner_id"`
+	DefaultOwnerEnabled                  bool                  `json:"default_owner_enabled"`
+	DefaultOwnerRotationID               string                `json:"default_owner_rotation_id"` // On-call rotation whose user on call, if any, is the default owner instead of DefaultOwnerID.
+	AnnouncementChannelID                string                `json:"announcement_channel_id"`
+	AnnouncementChannelEnabled           bool                  `json:"announcement_channel_enabled"`
+	WebhookOnCreationURL                 string                `json:"webhook_on_creation_url"`
+	WebhookOnCreationEnabled             bool                  `json:"webhook_on_creation_enabled"`
+	MessageOnJoin                        string                `json:"message_on_join"`
+	MessageOnJoinEnabled                 bool                  `json:"message_on_join_enabled"`
+	RetrospectiveReminderIntervalSeconds int64                 `json:"retrospective_reminder_interval_seconds"`
+	RetrospectiveTemplate                string                `json:"retrospective_template"`
+	WebhookOnStatusUpdateURL             string                `json:"webhook_on_status_update_url"`
+	WebhookOnStatusUpdateEnabled         bool                  `json:"webhook_on_status_update_enabled"`
+	WebhookSecret                        string                `json:"webhook_secret"`
+	WebhookSubscriptions                 []WebhookSubscription `json:"webhook_subscriptions"`
+	InboundWebhookEnabled                bool                  `json:"inbound_webhook_enabled"`
+	InboundWebhookToken                  string                `json:"-"` // Only exposed to the admins of the playbook, by its inbound webhook token endpoints.
+	InboundWebhookNameTemplate           string                `json:"inbound_webhook_name_template"`
+	InboundWebhookDescriptionTemplate    string                `json:"inbound_webhook_description_template"`
+	InboundWebhookOwnerTemplate          string                `json:"inbound_webhook_owner_template"`
+	InboundWebhookFingerprintTemplate    string                `json:"inbound_webhook_fingerprint_template"`
+	InboundWebhookAutoResolve            bool                  `json:"inbound_webhook_auto_resolve"`
+	CustomFields                         []CustomField         `json:"custom_fields"`
+	Roles                                []Role                `json:"roles"`
+	AcknowledgeTimeoutSeconds            int64                 `json:"acknowledge_timeout_seconds"` // Time the owner has to acknowledge a new incident before it is escalated. 0 if there is no acknowledgment step.
+	EscalationSteps                      []EscalationStep      `json:"escalation_steps"`
+	StatusUpdateTemplate                 string                `json:"status_update_template"` // text/template pre-filling the status updates of the incidents, rendered when the update status dialog is opened.
+	BroadcastTargets                     []BroadcastTarget     `json:"broadcast_targets"`      // Channels the incidents are broadcast to in addition to BroadcastChannelID, each with its own filter.
+	Revision                             int                   `json:"revision"`               // Number of the latest revision. 0 if the playbook has no revision yet.
+}
+
+// WebhookSubscription is an outgoing webhook that receives the timeline events of an incident
+// whose type is one of EventTypes.
+type WebhookSubscription struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+}
+
+// Clone returns a deep copy of the subscription.
+func (w WebhookSubscription) Clone() WebhookSubscription {
+	newSubscription := w
+	newSubscription.EventTypes = append([]string(nil), w.EventTypes...)
+	return newSubscription
+}
+
+// IsSubscribedTo returns true if the subscription receives events of eventType.
+func (w WebhookSubscription) IsSubscribedTo(eventType string) bool {
+	for _, t := range w.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
 }
 
 func (p Playbook) Clone() Playbook {
@@ -56,13 +101,38 @@ func (p Playbook) Clone() Playbook {
 	if len(p.InvitedGroupIDs) != 0 {
 		newPlaybook.InvitedGroupIDs = append([]string(nil), p.InvitedGroupIDs...)
 	}
+	if len(p.AutoFollowerIDs) != 0 {
+		newPlaybook.AutoFollowerIDs = append([]string(nil), p.AutoFollowerIDs...)
+	}
+	if len(p.WebhookSubscriptions) != 0 {
+		newPlaybook.WebhookSubscriptions = CloneWebhookSubscriptions(p.WebhookSubscriptions)
+	}
+	if len(p.CustomFields) != 0 {
+		newPlaybook.CustomFields = CloneCustomFields(p.CustomFields)
+	}
+	if len(p.Roles) != 0 {
+		newPlaybook.Roles = CloneRoles(p.Roles)
+	}
+	if len(p.EscalationSteps) != 0 {
+		newPlaybook.EscalationSteps = CloneEscalationSteps(p.EscalationSteps)
+	}
+	if len(p.BroadcastTargets) != 0 {
+		newPlaybook.BroadcastTargets = CloneBroadcastTargets(p.BroadcastTargets)
+	}
 	return newPlaybook
 }
 
+// MaskedWebhookSecret replaces a non-empty WebhookSecret in the JSON of a playbook. The secret is
+// write-only: a playbook sent back with the masked value keeps its current secret.
+const MaskedWebhookSecret = "********"
+
 func (p Playbook) MarshalJSON() ([]byte, error) {
 	type Alias Playbook
 
 	old := Alias(p.Clone())
+	if old.WebhookSecret != "" {
+		old.WebhookSecret = MaskedWebhookSecret
+	}
 	// replace nils with empty slices for the frontend
 	if old.Checklists == nil {
 		old.Checklists = []Checklist{}
@@ -71,6 +141,17 @@ func (p Playbook) MarshalJSON() ([]byte, error) {
 		if cl.Items == nil {
 			old.Checklists[j].Items = []ChecklistItem{}
 		}
+		for k, item := range cl.Items {
+			if item.PrerequisiteIDs == nil {
+				old.Checklists[j].Items[k].PrerequisiteIDs = []string{}
+			}
+			if item.Condition.Values == nil {
+				old.Checklists[j].Items[k].Condition.Values = []string{}
+			}
+			if item.AutomaticAction.UserIDs == nil {
+				old.Checklists[j].Items[k].AutomaticAction.UserIDs = []string{}
+			}
+		}
 	}
 	if old.MemberIDs == nil {
 		old.MemberIDs = []string{}
@@ -81,10 +162,59 @@ func (p Playbook) MarshalJSON() ([]byte, error) {
 	if old.InvitedGroupIDs == nil {
 		old.InvitedGroupIDs = []string{}
 	}
+	if old.AutoFollowerIDs == nil {
+		old.AutoFollowerIDs = []string{}
+	}
+	if old.WebhookSubscriptions == nil {
+		old.WebhookSubscriptions = []WebhookSubscription{}
+	}
+	for j, sub := range old.WebhookSubscriptions {
+		if sub.EventTypes == nil {
+			old.WebhookSubscriptions[j].EventTypes = []string{}
+		}
+	}
+	if old.CustomFields == nil {
+		old.CustomFields = []CustomField{}
+	}
+	for j, field := range old.CustomFields {
+		if field.Options == nil {
+			old.CustomFields[j].Options = []string{}
+		}
+	}
+	if old.Roles == nil {
+		old.Roles = []Role{}
+	}
+	if old.EscalationSteps == nil {
+		old.EscalationSteps = []EscalationStep{}
+	}
+	for j, step := range old.EscalationSteps {
+		if step.UserIDs == nil {
+			old.EscalationSteps[j].UserIDs = []string{}
+		}
+		if step.GroupIDs == nil {
+			old.EscalationSteps[j].GroupIDs = []string{}
+		}
+	}
+	if old.BroadcastTargets == nil {
+		old.BroadcastTargets = []BroadcastTarget{}
+	}
 
 	return json.Marshal(old)
 }
 
+// CloneWebhookSubscriptions returns a deep copy of subscriptions.
+func CloneWebhookSubscriptions(subscriptions []WebhookSubscription) []WebhookSubscription {
+	if subscriptions == nil {
+		return nil
+	}
+
+	newSubscriptions := make([]WebhookSubscription, len(subscriptions))
+	for i, sub := range subscriptions {
+		newSubscriptions[i] = sub.Clone()
+	}
+	return newSubscriptions
+}
+
 // Checklist represents a checklist in a playbook
 type Checklist struct {
 	ID    string          `json:"id"`
@@ -95,22 +225,267 @@ type Checklist struct {
 func (c Checklist) Clone() Checklist {
 	newChecklist := c
 	newChecklist.Items = append([]ChecklistItem(nil), c.Items...)
+	for i, item := range newChecklist.Items {
+		if len(item.PrerequisiteIDs) != 0 {
+			newChecklist.Items[i].PrerequisiteIDs = append([]string(nil), item.PrerequisiteIDs...)
+		}
+		if len(item.Condition.Values) != 0 {
+			newChecklist.Items[i].Condition.Values = append([]string(nil), item.Condition.Values...)
+		}
+		if len(item.AutomaticAction.UserIDs) != 0 {
+			newChecklist.Items[i].AutomaticAction.UserIDs = append([]string(nil), item.AutomaticAction.UserIDs...)
+		}
+	}
 	return newChecklist
 }
 
+// NextOpenItem returns the index of the first item of the checklist that is not closed, or -1 if
+// every item is closed.
+func (c Checklist) NextOpenItem() int {
+	for i, item := range c.Items {
+		if item.State != ChecklistItemStateClosed {
+			return i
+		}
+	}
+	return -1
+}
+
 // ChecklistItem represents an item in a checklist
 type ChecklistItem struct {
-	ID                     string `json:"id"`
-	Title                  string `json:"title"`
-	State                  string `json:"state"`
-	StateModified          int64  `json:"state_modified"`
-	StateModifiedPostID    string `json:"state_modified_post_id"`
-	AssigneeID             string `json:"assignee_id"`
-	AssigneeModified       int64  `json:"assignee_modified"`
-	AssigneeModifiedPostID string `json:"assignee_modified_post_id"`
-	Command                string `json:"command"`
-	CommandLastRun         int64  `json:"command_last_run"`
-	Description            string `json:"description"`
+	ID                     string        `json:"id"`
+	Title                  string        `json:"title"`
+	State                  string        `json:"state"`
+	StateModified          int64         `json:"state_modified"`
+	StateModifiedPostID    string        `json:"state_modified_post_id"`
+	AssigneeID             string        `json:"assignee_id"`
+	AssigneeModified       int64         `json:"assignee_modified"`
+	AssigneeModifiedPostID string        `json:"assignee_modified_post_id"`
+	AssigneeRole           string        `json:"assignee_role"` // Key of the role assigned to the item instead of a specific user. Blank if the item is not assigned to a role.
+	Command                string        `json:"command"`
+	CommandLastRun         int64         `json:"command_last_run"`
+	Description            string        `json:"description"`
+	DueAfterSeconds        int64         `json:"due_after_seconds"` // Time allowed to complete the item once the incident starts. 0 if the item has no due date.
+	DueAt                  int64         `json:"due_at"`            // Set from DueAfterSeconds when the incident starts. 0 if the item has no due date.
+	OverdueNotified        bool          `json:"overdue_notified"`  // True once the assignee, or the owner, has been reminded of the overdue item.
+	PrerequisiteIDs        []string      `json:"prerequisite_ids"`  // IDs of the items that must be closed before this item can be started or closed.
+	Condition              ItemCondition `json:"condition"`         // Only incidents matching the condition get the item. A blank condition matches every incident.
+	AutomaticAction        ItemAction    `json:"automatic_action"`  // Run once when the item is checked or becomes the next open item, depending on its trigger.
+	ActionLastRun          int64         `json:"action_last_run"`   // 0 if the automatic action never ran.
+}
+
+// Triggers of the automatic action of a checklist item. An action without trigger never runs.
+const (
+	ActionTriggerChecked  = "checked"   // The item is closed.
+	ActionTriggerNextOpen = "next_open" // Every item before it in its checklist is closed.
+)
+
+// Types of automatic actions of a checklist item.
+const (
+	ActionTypeRunCommand  = "run_command"  // Run the slash command of the item.
+	ActionTypePostMessage = "post_message" // Post Message, a text/template, to ChannelID, or to the incident channel.
+	ActionTypeInviteUsers = "invite_users" // Invite UserIDs to the incident channel.
+)
+
+// ItemAction is an action run automatically when a checklist item reaches its trigger.
+type ItemAction struct {
+	Trigger   string   `json:"trigger"`
+	Type      string   `json:"type"`
+	ChannelID string   `json:"channel_id"`
+	Message   string   `json:"message"`
+	UserIDs   []string `json:"user_ids"`
+}
+
+// IsBlank returns true if the action has no trigger, in which case it never runs.
+func (a ItemAction) IsBlank() bool {
+	return a.Trigger == ""
+}
+
+// Validate checks that the action is blank, or that it has a known trigger and type with the
+// settings that type needs. The slash command run by a run_command action is the item's command.
+func (a ItemAction) Validate(command string) error {
+	if a.IsBlank() {
+		return nil
+	}
+
+	if a.Trigger != ActionTriggerChecked && a.Trigger != ActionTriggerNextOpen {
+		return errors.Errorf("unknown trigger %s", a.Trigger)
+	}
+
+	switch a.Type {
+	case ActionTypeRunCommand:
+		if strings.TrimSpace(command) == "" {
+			return errors.New("the item has no slash command to run")
+		}
+	case ActionTypePostMessage:
+		if strings.TrimSpace(a.Message) == "" {
+			return errors.New("the message to post is blank")
+		}
+		if _, err := template.New("message").Parse(a.Message); err != nil {
+			return errors.Wrap(err, "invalid message template")
+		}
+	case ActionTypeInviteUsers:
+		if len(a.UserIDs) == 0 {
+			return errors.New("there are no users to invite")
+		}
+	default:
+		return errors.Errorf("unknown action type %s", a.Type)
+	}
+
+	return nil
+}
+
+// Incident attributes that an ItemCondition can check.
+const (
+	ConditionFieldName        = "name"
+	ConditionFieldDescription = "description"
+	ConditionFieldSeverity    = "severity"
+)
+
+// ConditionFieldCustomPrefix prefixes the key of a custom field to check its values in an
+// ItemCondition, e.g. custom.region.
+const ConditionFieldCustomPrefix = "custom."
+
+// CustomFieldKey returns the key of the custom field checked by the condition, or a blank string
+// if it checks another attribute.
+func (c ItemCondition) CustomFieldKey() string {
+	if !strings.HasPrefix(c.Field, ConditionFieldCustomPrefix) {
+		return ""
+	}
+
+	return strings.TrimPrefix(c.Field, ConditionFieldCustomPrefix)
+}
+
+// Operators used to compare an incident attribute with the values of an ItemCondition.
+const (
+	ConditionOperatorIs       = "is"
+	ConditionOperatorIsNot    = "is_not"
+	ConditionOperatorContains = "contains"
+)
+
+// ItemCondition is a simple condition on an incident attribute, deciding whether a playbook
+// checklist item is included in the incidents started with the playbook.
+type ItemCondition struct {
+	Field    string   `json:"field"`
+	Operator string   `json:"operator"`
+	Values   []string `json:"values"`
+}
+
+// IsBlank returns true if the condition has no field, in which case it matches every incident.
+func (c ItemCondition) IsBlank() bool {
+	return c.Field == ""
+}
+
+// Matches returns true if the attributes of an incident, indexed by field name, match the
+// condition: one of the values of the attribute equals one of the values of the condition (is),
+// none of them does (is_not), or one of them contains one of the values, ignoring case (contains).
+// Custom fields may have several values; an attribute missing from fields has a single blank value.
+func (c ItemCondition) Matches(fields map[string][]string) bool {
+	if c.IsBlank() {
+		return true
+	}
+
+	values := fields[c.Field]
+	if len(values) == 0 {
+		values = []string{""}
+	}
+
+	switch c.Operator {
+	case ConditionOperatorIs, ConditionOperatorIsNot:
+		found := false
+		for _, value := range values {
+			if containsString(c.Values, value) {
+				found = true
+				break
+			}
+		}
+		return found == (c.Operator == ConditionOperatorIs)
+	case ConditionOperatorContains:
+		for _, value := range values {
+			for _, v := range c.Values {
+				if strings.Contains(strings.ToLower(value), strings.ToLower(v)) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// Validate checks that the condition is blank, or that it has a known field and operator and at
+// least one value. Conditions on custom fields must also be checked against the fields of the
+// playbook with ValidateCustomFieldCondition.
+func (c ItemCondition) Validate() error {
+	if c.IsBlank() {
+		return nil
+	}
+
+	switch c.Field {
+	case ConditionFieldName, ConditionFieldDescription, ConditionFieldSeverity:
+	default:
+		if !keyPattern.MatchString(c.CustomFieldKey()) {
+			return errors.Errorf("unknown field %s", c.Field)
+		}
+	}
+
+	switch c.Operator {
+	case ConditionOperatorIs, ConditionOperatorIsNot, ConditionOperatorContains:
+	default:
+		return errors.Errorf("unknown operator %s", c.Operator)
+	}
+
+	if len(c.Values) == 0 {
+		return errors.Errorf("condition on %s has no values", c.Field)
+	}
+
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterChecklists returns a copy of checklists with only the items whose condition matches the
+// incident attributes in fields. Prerequisites on the items left out are removed.
+func FilterChecklists(checklists []Checklist, fields map[string][]string) []Checklist {
+	if checklists == nil {
+		return nil
+	}
+
+	var removedIDs []string
+	newChecklists := make([]Checklist, 0, len(checklists))
+	for _, checklist := range checklists {
+		newChecklist := checklist.Clone()
+		items := newChecklist.Items
+		newChecklist.Items = items[:0]
+		for _, item := range items {
+			if !item.Condition.Matches(fields) {
+				removedIDs = append(removedIDs, item.ID)
+				continue
+			}
+			newChecklist.Items = append(newChecklist.Items, item)
+		}
+		newChecklists = append(newChecklists, newChecklist)
+	}
+
+	for _, removedID := range removedIDs {
+		if removedID != "" {
+			RemovePrerequisite(newChecklists, removedID)
+		}
+	}
+
+	return newChecklists
+}
+
+// IsOverdue returns true if the item has a due date that is not later than now, and it is not
+// closed yet.
+func (i ChecklistItem) IsOverdue(now int64) bool {
+	return i.DueAt != 0 && i.DueAt <= now && i.State != ChecklistItemStateClosed
 }
 
 type GetPlaybooksResults struct {
@@ -168,6 +543,13 @@ type Service interface {
 
 	// Delete deletes a playbook
 	Delete(playbook Playbook, userID string) error
+
+	// GetRevisions retrieves the revisions of a playbook, latest first, without their snapshot
+	// of the playbook.
+	GetRevisions(playbookID string) ([]Revision, error)
+
+	// GetRevision retrieves a revision of a playbook. Returns ErrNotFound if not found.
+	GetRevision(playbookID string, revision int) (Revision, error)
 }
 
 // Store is an interface for storing playbooks
@@ -191,6 +573,17 @@ type Store interface {
 
 	// Delete deletes a playbook
 	Delete(id string) error
+
+	// CreateRevision stores a revision of a playbook with a snapshot of pbook, numbered after the
+	// latest revision, and makes it the playbook's latest revision.
+	CreateRevision(pbook Playbook, userID string) (Revision, error)
+
+	// GetRevisions retrieves the revisions of a playbook, latest first, without their snapshot
+	// of the playbook.
+	GetRevisions(playbookID string) ([]Revision, error)
+
+	// GetRevision retrieves a revision of a playbook. Returns ErrNotFound if not found.
+	GetRevision(playbookID string, revision int) (Revision, error)
 }
 
 // Telemetry defines the methods that the Playbook service needs from the RudderTelemetry.
@@ -218,6 +611,112 @@ func IsValidChecklistItemState(state string) bool {
 		state == ChecklistItemStateOpen
 }
 
+// BlockingItems returns the prerequisites of item that are not closed yet. Prerequisites that are
+// not found in checklists, e.g. because they were removed, do not block the item.
+func BlockingItems(checklists []Checklist, item ChecklistItem) []ChecklistItem {
+	var blockingItems []ChecklistItem
+	for _, prerequisiteID := range item.PrerequisiteIDs {
+		for _, checklist := range checklists {
+			for _, other := range checklist.Items {
+				if other.ID == prerequisiteID && other.State != ChecklistItemStateClosed {
+					blockingItems = append(blockingItems, other)
+				}
+			}
+		}
+	}
+
+	return blockingItems
+}
+
+// RemovePrerequisite removes itemID from the prerequisites of every item in checklists.
+func RemovePrerequisite(checklists []Checklist, itemID string) {
+	for i := range checklists {
+		for j, item := range checklists[i].Items {
+			var prerequisiteIDs []string
+			for _, prerequisiteID := range item.PrerequisiteIDs {
+				if prerequisiteID != itemID {
+					prerequisiteIDs = append(prerequisiteIDs, prerequisiteID)
+				}
+			}
+			checklists[i].Items[j].PrerequisiteIDs = prerequisiteIDs
+		}
+	}
+}
+
+// AssignChecklistItemIDs gives a new ID to every item in checklists that has none, so that it can
+// be referenced as a prerequisite or by the automatic actions of the item.
+func AssignChecklistItemIDs(checklists []Checklist) {
+	for i := range checklists {
+		for j := range checklists[i].Items {
+			if checklists[i].Items[j].ID == "" {
+				checklists[i].Items[j].ID = model.NewId()
+			}
+		}
+	}
+}
+
+// ValidatePrerequisites checks that no two items in checklists have the same ID, that the
+// prerequisites of every item are items of the same checklists, and that no item depends on
+// itself, directly or through other items.
+func ValidatePrerequisites(checklists []Checklist) error {
+	items := make(map[string]ChecklistItem)
+	for _, checklist := range checklists {
+		for _, item := range checklist.Items {
+			if item.ID == "" {
+				continue
+			}
+			if _, ok := items[item.ID]; ok {
+				return errors.Errorf("item ID %s is used by more than one item", item.ID)
+			}
+			items[item.ID] = item
+		}
+	}
+
+	for _, checklist := range checklists {
+		for _, item := range checklist.Items {
+			for _, prerequisiteID := range item.PrerequisiteIDs {
+				if _, ok := items[prerequisiteID]; !ok {
+					return errors.Errorf("item %s has an unknown prerequisite %s", item.Title, prerequisiteID)
+				}
+			}
+		}
+	}
+
+	// Depth-first search, where an item that is reached again while still being visited closes a cycle.
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int)
+	var visit func(item ChecklistItem) error
+	visit = func(item ChecklistItem) error {
+		switch state[item.ID] {
+		case visiting:
+			return errors.Errorf("item %s depends on itself through its prerequisites", item.Title)
+		case visited:
+			return nil
+		}
+
+		state[item.ID] = visiting
+		for _, prerequisiteID := range item.PrerequisiteIDs {
+			if err := visit(items[prerequisiteID]); err != nil {
+				return err
+			}
+		}
+		state[item.ID] = visited
+
+		return nil
+	}
+
+	for _, item := range items {
+		if err := visit(item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func IsValidChecklistItemIndex(checklists []Checklist, checklistNum, itemNum int) bool {
 	return checklists != nil && checklistNum >= 0 && itemNum >= 0 && checklistNum < len(checklists) && itemNum < len(checklists[checklistNum].Items)
 }
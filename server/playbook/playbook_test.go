@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/mattermost/mattermost-server/v5/model"
 	"github.com/stretchr/testify/require"
 )
 
@@ -68,6 +69,17 @@ func TestPlaybook_MarshalJSON(t *testing.T) {
 			expected: []byte(`"checklists":[{"id":"checklist1","title":"checklist 1","items":[]}]`),
 			wantErr:  false,
 		},
+		{
+			name: "masks the webhook secret",
+			original: Playbook{
+				ID:            "playbookid",
+				Title:         "the playbook title",
+				TeamID:        "theteamid",
+				WebhookSecret: "thesecret",
+			},
+			expected: []byte(`"webhook_secret":"********"`),
+			wantErr:  false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -80,3 +92,204 @@ func TestPlaybook_MarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestValidatePrerequisites(t *testing.T) {
+	for name, tc := range map[string]struct {
+		checklists []Checklist
+		err        string
+	}{
+		"no prerequisites": {
+			checklists: []Checklist{
+				{Items: []ChecklistItem{{ID: "a"}, {ID: "b"}}},
+			},
+		},
+		"prerequisites across checklists": {
+			checklists: []Checklist{
+				{Items: []ChecklistItem{{ID: "a"}, {ID: "b", PrerequisiteIDs: []string{"a"}}}},
+				{Items: []ChecklistItem{{ID: "c", PrerequisiteIDs: []string{"a", "b"}}}},
+			},
+		},
+		"unknown prerequisite": {
+			checklists: []Checklist{
+				{Items: []ChecklistItem{{ID: "a", Title: "snapshot DB", PrerequisiteIDs: []string{"z"}}}},
+			},
+			err: "item snapshot DB has an unknown prerequisite z",
+		},
+		"depends on itself": {
+			checklists: []Checklist{
+				{Items: []ChecklistItem{{ID: "a", Title: "snapshot DB", PrerequisiteIDs: []string{"a"}}}},
+			},
+			err: "depends on itself",
+		},
+		"cycle across checklists": {
+			checklists: []Checklist{
+				{Items: []ChecklistItem{{ID: "a", PrerequisiteIDs: []string{"c"}}, {ID: "b", PrerequisiteIDs: []string{"a"}}}},
+				{Items: []ChecklistItem{{ID: "c", PrerequisiteIDs: []string{"b"}}}},
+			},
+			err: "depends on itself",
+		},
+		"duplicate item IDs across checklists": {
+			checklists: []Checklist{
+				{Items: []ChecklistItem{{ID: "a"}, {ID: "b"}}},
+				{Items: []ChecklistItem{{ID: "a"}}},
+			},
+			err: "item ID a is used by more than one item",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := ValidatePrerequisites(tc.checklists)
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
+
+func TestAssignChecklistItemIDs(t *testing.T) {
+	checklists := []Checklist{
+		{Items: []ChecklistItem{{ID: "a"}, {}}},
+		{Items: []ChecklistItem{{}}},
+	}
+
+	AssignChecklistItemIDs(checklists)
+
+	require.Equal(t, "a", checklists[0].Items[0].ID)
+	require.True(t, model.IsValidId(checklists[0].Items[1].ID))
+	require.True(t, model.IsValidId(checklists[1].Items[0].ID))
+	require.NotEqual(t, checklists[0].Items[1].ID, checklists[1].Items[0].ID)
+	require.NoError(t, ValidatePrerequisites(checklists))
+}
+
+func TestBlockingItems(t *testing.T) {
+	checklists := []Checklist{
+		{
+			Items: []ChecklistItem{
+				{ID: "snapshot", Title: "snapshot DB", State: ChecklistItemStateClosed},
+				{ID: "drain", Title: "drain traffic", State: ChecklistItemStateInProgress},
+				{ID: "failover", Title: "failover DB", PrerequisiteIDs: []string{"snapshot", "drain", "removed"}},
+			},
+		},
+	}
+
+	blockingItems := BlockingItems(checklists, checklists[0].Items[2])
+	require.Len(t, blockingItems, 1)
+	require.Equal(t, "drain", blockingItems[0].ID)
+
+	RemovePrerequisite(checklists, "drain")
+	require.Equal(t, []string{"snapshot", "removed"}, checklists[0].Items[2].PrerequisiteIDs)
+	require.Empty(t, BlockingItems(checklists, checklists[0].Items[2]))
+}
+
+func TestItemCondition_Matches(t *testing.T) {
+	fields := map[string][]string{
+		ConditionFieldName:                     {"Checkout errors"},
+		ConditionFieldDescription:              {"Payments are failing in the EU region"},
+		ConditionFieldSeverity:                 {"SEV1"},
+		ConditionFieldCustomPrefix + "regions": {"eu-west", "us-east"},
+	}
+
+	for name, tc := range map[string]struct {
+		condition ItemCondition
+		expected  bool
+	}{
+		"blank condition":                     {ItemCondition{}, true},
+		"is matches":                          {ItemCondition{Field: ConditionFieldSeverity, Operator: ConditionOperatorIs, Values: []string{"SEV1", "SEV2"}}, true},
+		"is does not match":                   {ItemCondition{Field: ConditionFieldSeverity, Operator: ConditionOperatorIs, Values: []string{"SEV3"}}, false},
+		"is_not matches":                      {ItemCondition{Field: ConditionFieldSeverity, Operator: ConditionOperatorIsNot, Values: []string{"SEV3", "SEV4"}}, true},
+		"is_not does not":                     {ItemCondition{Field: ConditionFieldSeverity, Operator: ConditionOperatorIsNot, Values: []string{"SEV1"}}, false},
+		"contains ignores case":               {ItemCondition{Field: ConditionFieldDescription, Operator: ConditionOperatorContains, Values: []string{"database", "PAYMENTS"}}, true},
+		"contains no keyword":                 {ItemCondition{Field: ConditionFieldName, Operator: ConditionOperatorContains, Values: []string{"latency"}}, false},
+		"unknown operator":                    {ItemCondition{Field: ConditionFieldName, Operator: "matches", Values: []string{".*"}}, false},
+		"is matches one of several values":    {ItemCondition{Field: ConditionFieldCustomPrefix + "regions", Operator: ConditionOperatorIs, Values: []string{"us-east"}}, true},
+		"is_not does not match any value":     {ItemCondition{Field: ConditionFieldCustomPrefix + "regions", Operator: ConditionOperatorIsNot, Values: []string{"eu-west"}}, false},
+		"contains matches one of the values":  {ItemCondition{Field: ConditionFieldCustomPrefix + "regions", Operator: ConditionOperatorContains, Values: []string{"EAST"}}, true},
+		"missing custom field is blank":       {ItemCondition{Field: ConditionFieldCustomPrefix + "team", Operator: ConditionOperatorIs, Values: []string{""}}, true},
+		"missing custom field matches is_not": {ItemCondition{Field: ConditionFieldCustomPrefix + "team", Operator: ConditionOperatorIsNot, Values: []string{"payments"}}, true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.condition.Matches(fields))
+		})
+	}
+}
+
+func TestItemCondition_Validate(t *testing.T) {
+	require.NoError(t, ItemCondition{}.Validate())
+	require.NoError(t, ItemCondition{Field: ConditionFieldSeverity, Operator: ConditionOperatorIs, Values: []string{"SEV1"}}.Validate())
+	require.EqualError(t, ItemCondition{Field: "team", Operator: ConditionOperatorIs, Values: []string{"a"}}.Validate(), "unknown field team")
+	require.NoError(t, ItemCondition{Field: ConditionFieldCustomPrefix + "team", Operator: ConditionOperatorIs, Values: []string{"a"}}.Validate())
+	require.EqualError(t, ItemCondition{Field: ConditionFieldCustomPrefix + "Team!", Operator: ConditionOperatorIs, Values: []string{"a"}}.Validate(), "unknown field custom.Team!")
+	require.EqualError(t, ItemCondition{Field: ConditionFieldName, Operator: "~", Values: []string{"a"}}.Validate(), "unknown operator ~")
+	require.EqualError(t, ItemCondition{Field: ConditionFieldName, Operator: ConditionOperatorContains}.Validate(), "condition on name has no values")
+}
+
+func TestFilterChecklists(t *testing.T) {
+	sev1Only := ItemCondition{Field: ConditionFieldSeverity, Operator: ConditionOperatorIs, Values: []string{"SEV1"}}
+	checklists := []Checklist{
+		{
+			Title: "Triage",
+			Items: []ChecklistItem{
+				{ID: "page", Title: "page the VP", Condition: sev1Only},
+				{ID: "ack", Title: "acknowledge"},
+			},
+		},
+		{
+			Title: "Escalate",
+			Items: []ChecklistItem{
+				{ID: "status", Title: "post on status page", Condition: sev1Only, PrerequisiteIDs: []string{"ack"}},
+				{ID: "notify", Title: "notify customers", PrerequisiteIDs: []string{"page", "ack"}},
+			},
+		},
+	}
+
+	filtered := FilterChecklists(checklists, map[string][]string{ConditionFieldSeverity: {"SEV3"}})
+	require.Len(t, filtered, 2)
+	require.Equal(t, "Triage", filtered[0].Title)
+	require.Len(t, filtered[0].Items, 1)
+	require.Equal(t, "ack", filtered[0].Items[0].ID)
+	require.Len(t, filtered[1].Items, 1)
+	require.Equal(t, "notify", filtered[1].Items[0].ID)
+	require.Equal(t, []string{"ack"}, filtered[1].Items[0].PrerequisiteIDs)
+
+	// The original checklists are left untouched.
+	require.Len(t, checklists[0].Items, 2)
+	require.Equal(t, []string{"page", "ack"}, checklists[1].Items[1].PrerequisiteIDs)
+
+	filtered = FilterChecklists(checklists, map[string][]string{ConditionFieldSeverity: {"SEV1"}})
+	require.Len(t, filtered[0].Items, 2)
+	require.Len(t, filtered[1].Items, 2)
+}
+
+func TestItemAction_Validate(t *testing.T) {
+	require.NoError(t, ItemAction{}.Validate(""))
+	require.NoError(t, ItemAction{Trigger: ActionTriggerChecked, Type: ActionTypeRunCommand}.Validate("/jira create"))
+	require.NoError(t, ItemAction{Trigger: ActionTriggerNextOpen, Type: ActionTypePostMessage, Message: "{{.IncidentName}} needs a DBA"}.Validate(""))
+	require.NoError(t, ItemAction{Trigger: ActionTriggerNextOpen, Type: ActionTypeInviteUsers, UserIDs: []string{"dba"}}.Validate(""))
+
+	require.EqualError(t, ItemAction{Trigger: "assigned", Type: ActionTypeRunCommand}.Validate("/jira create"), "unknown trigger assigned")
+	require.EqualError(t, ItemAction{Trigger: ActionTriggerChecked, Type: "email"}.Validate(""), "unknown action type email")
+	require.EqualError(t, ItemAction{Trigger: ActionTriggerChecked, Type: ActionTypeRunCommand}.Validate(" "), "the item has no slash command to run")
+	require.EqualError(t, ItemAction{Trigger: ActionTriggerChecked, Type: ActionTypePostMessage}.Validate(""), "the message to post is blank")
+	require.Error(t, ItemAction{Trigger: ActionTriggerChecked, Type: ActionTypePostMessage, Message: "{{.IncidentName"}.Validate(""))
+	require.EqualError(t, ItemAction{Trigger: ActionTriggerChecked, Type: ActionTypeInviteUsers}.Validate(""), "there are no users to invite")
+}
+
+func TestChecklist_NextOpenItem(t *testing.T) {
+	checklist := Checklist{
+		Items: []ChecklistItem{
+			{State: ChecklistItemStateClosed},
+			{State: ChecklistItemStateInProgress},
+			{State: ChecklistItemStateOpen},
+		},
+	}
+	require.Equal(t, 1, checklist.NextOpenItem())
+
+	checklist.Items[1].State = ChecklistItemStateClosed
+	require.Equal(t, 2, checklist.NextOpenItem())
+
+	checklist.Items[2].State = ChecklistItemStateClosed
+	require.Equal(t, -1, checklist.NextOpenItem())
+	require.Equal(t, -1, Checklist{}.NextOpenItem())
+}
@@ -0,0 +1,226 @@
+package playbook
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// Revision is an immutable snapshot of a playbook, stored every time the playbook is created,
+// updated or restored. Revisions of a playbook are numbered from 1.
+type Revision struct {
+	ID         string   `json:"id"`
+	PlaybookID string   `json:"playbook_id"`
+	Revision   int      `json:"revision"`
+	UserID     string   `json:"user_id"` // Author of the revision. Blank for the revision recorded for a playbook created before revisions existed.
+	CreateAt   int64    `json:"create_at"`
+	Playbook   Playbook `json:"playbook"`
+}
+
+// RestoredPlaybook returns current with the settings and checklists of the snapshot in r. What
+// identifies the playbook and its secrets, which may have been rotated since, are kept from current.
+func (r Revision) RestoredPlaybook(current Playbook) Playbook {
+	restored := r.Playbook.Clone()
+	restored.ID = current.ID
+	restored.TeamID = current.TeamID
+	restored.CreateAt = current.CreateAt
+	restored.DeleteAt = current.DeleteAt
+	restored.WebhookSecret = current.WebhookSecret
+	restored.InboundWebhookToken = current.InboundWebhookToken
+	restored.Revision = current.Revision
+
+	return restored
+}
+
+// Kinds of changes of a checklist or checklist item between two revisions.
+const (
+	ChangeAdded    = "added"
+	ChangeRemoved  = "removed"
+	ChangeModified = "modified"
+)
+
+// FieldChange is the change of a field between two revisions. Old and New are the values of the
+// field, as in the JSON representation of the playbook.
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// ElementChange is the change of a checklist or checklist item between two revisions. Fields is
+// only set if the element was modified.
+type ElementChange struct {
+	ID     string        `json:"id"`
+	Title  string        `json:"title"`
+	Change string        `json:"change"`
+	Fields []FieldChange `json:"fields,omitempty"`
+}
+
+// Diff holds the changes between two revisions of a playbook.
+type Diff struct {
+	From       int             `json:"from"`
+	To         int             `json:"to"`
+	Fields     []FieldChange   `json:"fields"`
+	Checklists []ElementChange `json:"checklists"`
+	Items      []ElementChange `json:"items"`
+}
+
+// diffIgnoredFields are the fields of a playbook that are not compared: they identify the
+// playbook, are derived from its checklists, or are compared separately.
+var diffIgnoredFields = []string{"id", "create_at", "delete_at", "num_stages", "num_steps", "revision", "checklists"}
+
+// DiffRevisions compares two revisions of a playbook, field by field, checklist by checklist and
+// checklist item by checklist item. Checklists and items are matched by ID or, lacking an ID, by
+// title. An item moved to another checklist has a change of its checklist field, holding the
+// titles of the checklists.
+func DiffRevisions(from, to Revision) (Diff, error) {
+	diff := Diff{
+		From:       from.Revision,
+		To:         to.Revision,
+		Fields:     []FieldChange{},
+		Checklists: []ElementChange{},
+		Items:      []ElementChange{},
+	}
+
+	fields, err := diffFields(from.Playbook, to.Playbook, diffIgnoredFields...)
+	if err != nil {
+		return Diff{}, err
+	}
+	diff.Fields = fields
+
+	fromChecklists := map[string]Checklist{}
+	for _, checklist := range from.Playbook.Checklists {
+		fromChecklists[elementKey(checklist.ID, checklist.Title)] = checklist
+	}
+	toChecklists := map[string]bool{}
+	for _, checklist := range to.Playbook.Checklists {
+		key := elementKey(checklist.ID, checklist.Title)
+		toChecklists[key] = true
+
+		old, ok := fromChecklists[key]
+		if !ok {
+			diff.Checklists = append(diff.Checklists, ElementChange{ID: checklist.ID, Title: checklist.Title, Change: ChangeAdded})
+			continue
+		}
+		if old.Title != checklist.Title {
+			diff.Checklists = append(diff.Checklists, ElementChange{
+				ID:     checklist.ID,
+				Title:  checklist.Title,
+				Change: ChangeModified,
+				Fields: []FieldChange{{Field: "title", Old: old.Title, New: checklist.Title}},
+			})
+		}
+	}
+	for _, checklist := range from.Playbook.Checklists {
+		if !toChecklists[elementKey(checklist.ID, checklist.Title)] {
+			diff.Checklists = append(diff.Checklists, ElementChange{ID: checklist.ID, Title: checklist.Title, Change: ChangeRemoved})
+		}
+	}
+
+	type locatedItem struct {
+		item           ChecklistItem
+		checklistKey   string
+		checklistTitle string
+	}
+	fromItems := map[string]locatedItem{}
+	for _, checklist := range from.Playbook.Checklists {
+		for _, item := range checklist.Items {
+			fromItems[elementKey(item.ID, item.Title)] = locatedItem{item, elementKey(checklist.ID, checklist.Title), checklist.Title}
+		}
+	}
+	toItems := map[string]bool{}
+	for _, checklist := range to.Playbook.Checklists {
+		for _, item := range checklist.Items {
+			key := elementKey(item.ID, item.Title)
+			toItems[key] = true
+
+			old, ok := fromItems[key]
+			if !ok {
+				diff.Items = append(diff.Items, ElementChange{ID: item.ID, Title: item.Title, Change: ChangeAdded})
+				continue
+			}
+
+			itemFields, itemErr := diffFields(old.item, item, "id")
+			if itemErr != nil {
+				return Diff{}, itemErr
+			}
+			if old.checklistKey != elementKey(checklist.ID, checklist.Title) {
+				itemFields = append(itemFields, FieldChange{Field: "checklist", Old: old.checklistTitle, New: checklist.Title})
+			}
+			if len(itemFields) > 0 {
+				diff.Items = append(diff.Items, ElementChange{ID: item.ID, Title: item.Title, Change: ChangeModified, Fields: itemFields})
+			}
+		}
+	}
+	for _, checklist := range from.Playbook.Checklists {
+		for _, item := range checklist.Items {
+			if !toItems[elementKey(item.ID, item.Title)] {
+				diff.Items = append(diff.Items, ElementChange{ID: item.ID, Title: item.Title, Change: ChangeRemoved})
+			}
+		}
+	}
+
+	return diff, nil
+}
+
+// elementKey identifies a checklist or checklist item by its ID or, lacking an ID, its title.
+func elementKey(id, title string) string {
+	if id != "" {
+		return "id:" + id
+	}
+	return "title:" + title
+}
+
+// diffFields compares the fields of the JSON representations of from and to, except ignored, and
+// returns the changed fields sorted by name.
+func diffFields(from, to interface{}, ignored ...string) ([]FieldChange, error) {
+	fromFields, err := jsonFields(from)
+	if err != nil {
+		return nil, err
+	}
+	toFields, err := jsonFields(to)
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range ignored {
+		delete(fromFields, field)
+		delete(toFields, field)
+	}
+
+	names := map[string]bool{}
+	for name := range fromFields {
+		names[name] = true
+	}
+	for name := range toFields {
+		names[name] = true
+	}
+
+	changes := []FieldChange{}
+	for name := range names {
+		if !reflect.DeepEqual(fromFields[name], toFields[name]) {
+			changes = append(changes, FieldChange{Field: name, Old: fromFields[name], New: toFields[name]})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Field < changes[j].Field
+	})
+
+	return changes, nil
+}
+
+// jsonFields returns the fields of the JSON representation of value.
+func jsonFields(value interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal value to compare")
+	}
+
+	var fields map[string]interface{}
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal value to compare")
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,132 @@
+package playbook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffRevisions(t *testing.T) {
+	from := Revision{
+		Revision: 1,
+		Playbook: Playbook{
+			ID:                          "playbookid",
+			Title:                       "Database outage",
+			ReminderTimerDefaultSeconds: 3600,
+			NumStages:                   2,
+			Revision:                    1,
+			Checklists: []Checklist{
+				{
+					ID:    "triage",
+					Title: "Triage",
+					Items: []ChecklistItem{
+						{ID: "snapshot", Title: "Snapshot the database"},
+						{ID: "failover", Title: "Fail over"},
+					},
+				},
+				{
+					Title: "Recovery",
+					Items: []ChecklistItem{
+						{Title: "Restore replicas"},
+					},
+				},
+			},
+		},
+	}
+
+	to := Revision{
+		Revision: 2,
+		Playbook: Playbook{
+			ID:                          "playbookid",
+			Title:                       "Database outage",
+			ReminderTimerDefaultSeconds: 1800,
+			NumStages:                   2,
+			Revision:                    2,
+			Checklists: []Checklist{
+				{
+					ID:    "triage",
+					Title: "Investigation",
+					Items: []ChecklistItem{
+						{ID: "snapshot", Title: "Snapshot the database", AssigneeID: "aliceid"},
+						{Title: "Page the DBA"},
+					},
+				},
+				{
+					Title: "Failover",
+					Items: []ChecklistItem{
+						{ID: "failover", Title: "Fail over"},
+					},
+				},
+			},
+		},
+	}
+
+	diff, err := DiffRevisions(from, to)
+	require.NoError(t, err)
+	require.Equal(t, 1, diff.From)
+	require.Equal(t, 2, diff.To)
+
+	require.Equal(t, []FieldChange{
+		{Field: "reminder_timer_default_seconds", Old: float64(3600), New: float64(1800)},
+	}, diff.Fields)
+
+	require.Equal(t, []ElementChange{
+		{ID: "triage", Title: "Investigation", Change: ChangeModified, Fields: []FieldChange{{Field: "title", Old: "Triage", New: "Investigation"}}},
+		{Title: "Failover", Change: ChangeAdded},
+		{Title: "Recovery", Change: ChangeRemoved},
+	}, diff.Checklists)
+
+	require.Equal(t, []ElementChange{
+		{ID: "snapshot", Title: "Snapshot the database", Change: ChangeModified, Fields: []FieldChange{{Field: "assignee_id", Old: "", New: "aliceid"}}},
+		{Title: "Page the DBA", Change: ChangeAdded},
+		{ID: "failover", Title: "Fail over", Change: ChangeModified, Fields: []FieldChange{{Field: "checklist", Old: "Triage", New: "Failover"}}},
+		{Title: "Restore replicas", Change: ChangeRemoved},
+	}, diff.Items)
+
+	t.Run("no changes", func(t *testing.T) {
+		diff, err := DiffRevisions(from, from)
+		require.NoError(t, err)
+		require.Empty(t, diff.Fields)
+		require.Empty(t, diff.Checklists)
+		require.Empty(t, diff.Items)
+	})
+}
+
+func TestRestoredPlaybook(t *testing.T) {
+	revision := Revision{
+		Revision: 1,
+		Playbook: Playbook{
+			ID:                  "playbookid",
+			Title:               "Database outage",
+			TeamID:              "teamid",
+			Revision:            1,
+			WebhookSecret:       "old secret",
+			InboundWebhookToken: "old token",
+			Checklists: []Checklist{
+				{Title: "Triage", Items: []ChecklistItem{{ID: "snapshot", Title: "Snapshot the database"}}},
+			},
+		},
+	}
+	current := Playbook{
+		ID:                  "playbookid",
+		Title:               "Database incident",
+		TeamID:              "teamid",
+		CreateAt:            1000,
+		Revision:            4,
+		WebhookSecret:       "new secret",
+		InboundWebhookToken: "new token",
+	}
+
+	restored := revision.RestoredPlaybook(current)
+
+	require.Equal(t, "Database outage", restored.Title)
+	require.Equal(t, revision.Playbook.Checklists, restored.Checklists)
+	require.Equal(t, int64(1000), restored.CreateAt)
+	require.Equal(t, 4, restored.Revision)
+	require.Equal(t, "new secret", restored.WebhookSecret)
+	require.Equal(t, "new token", restored.InboundWebhookToken)
+
+	// The snapshot is not shared with the restored playbook.
+	restored.Checklists[0].Items[0].Title = "Changed"
+	require.Equal(t, "Snapshot the database", revision.Playbook.Checklists[0].Items[0].Title)
+}
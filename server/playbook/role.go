@@ -0,0 +1,74 @@
+package playbook
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Role is a named role defined by a playbook, such as scribe or comms lead, to which users are
+// assigned in the incidents started with the playbook. Checklist items can be assigned to a role
+// instead of a specific user.
+type Role struct {
+	Key         string `json:"key"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Validate checks that the role has a valid key and a name.
+func (r Role) Validate() error {
+	if !keyPattern.MatchString(r.Key) {
+		return errors.Errorf("invalid key '%s': use up to 32 lowercase letters, digits and underscores, starting with a letter", r.Key)
+	}
+
+	if strings.TrimSpace(r.Name) == "" {
+		return errors.Errorf("role %s has no name", r.Key)
+	}
+
+	return nil
+}
+
+// CloneRoles returns a copy of roles.
+func CloneRoles(roles []Role) []Role {
+	if roles == nil {
+		return nil
+	}
+
+	return append([]Role(nil), roles...)
+}
+
+// FindRole returns the role of roles with the given key, or false if there is none.
+func FindRole(roles []Role, key string) (Role, bool) {
+	for _, role := range roles {
+		if role.Key == key {
+			return role, true
+		}
+	}
+
+	return Role{}, false
+}
+
+// ValidateRoles checks that every role is valid, that no two roles share a key, and that the
+// checklist items assigned to a role are assigned to one of roles.
+func ValidateRoles(roles []Role, checklists []Checklist) error {
+	keys := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		if err := role.Validate(); err != nil {
+			return err
+		}
+		if keys[role.Key] {
+			return errors.Errorf("key %s is used by more than one role", role.Key)
+		}
+		keys[role.Key] = true
+	}
+
+	for _, checklist := range checklists {
+		for _, item := range checklist.Items {
+			if item.AssigneeRole != "" && !keys[item.AssigneeRole] {
+				return errors.Errorf("item %s is assigned to an unknown role %s", item.Title, item.AssigneeRole)
+			}
+		}
+	}
+
+	return nil
+}
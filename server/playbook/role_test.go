@@ -0,0 +1,83 @@
+package playbook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateRoles(t *testing.T) {
+	roles := []Role{
+		{Key: "commander", Name: "Commander"},
+		{Key: "comms_lead", Name: "Comms lead", Description: "Keeps the stakeholders informed."},
+	}
+
+	tests := []struct {
+		name       string
+		roles      []Role
+		checklists []Checklist
+		wantErr    bool
+	}{
+		{
+			name:  "valid roles",
+			roles: roles,
+			checklists: []Checklist{
+				{Title: "Triage", Items: []ChecklistItem{
+					{Title: "Notify customers", AssigneeRole: "comms_lead"},
+					{Title: "Snapshot the database"},
+				}},
+			},
+		},
+		{
+			name: "no roles",
+		},
+		{
+			name:    "invalid key",
+			roles:   []Role{{Key: "comms lead", Name: "Comms lead"}},
+			wantErr: true,
+		},
+		{
+			name:    "no name",
+			roles:   []Role{{Key: "scribe"}},
+			wantErr: true,
+		},
+		{
+			name:    "duplicate keys",
+			roles:   []Role{{Key: "scribe", Name: "Scribe"}, {Key: "scribe", Name: "Note taker"}},
+			wantErr: true,
+		},
+		{
+			name:  "item assigned to an unknown role",
+			roles: roles,
+			checklists: []Checklist{
+				{Title: "Triage", Items: []ChecklistItem{{Title: "Take notes", AssigneeRole: "scribe"}}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRoles(tt.roles, tt.checklists)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFindRole(t *testing.T) {
+	roles := []Role{{Key: "commander", Name: "Commander"}, {Key: "scribe", Name: "Scribe"}}
+
+	role, ok := FindRole(roles, "scribe")
+	require.True(t, ok)
+	require.Equal(t, "Scribe", role.Name)
+
+	_, ok = FindRole(roles, "comms_lead")
+	require.False(t, ok)
+
+	_, ok = FindRole(roles, "")
+	require.False(t, ok)
+}
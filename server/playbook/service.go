@@ -36,6 +36,10 @@ func (s *service) Create(playbook Playbook, userID string) (string, error) {
 	}
 	playbook.ID = newID
 
+	if _, err = s.store.CreateRevision(playbook, userID); err != nil {
+		return "", errors.Wrapf(err, "failed to store the first revision of playbook %s", newID)
+	}
+
 	s.telemetry.CreatePlaybook(playbook, userID)
 
 	s.poster.PublishWebsocketEventToTeam(playbookCreatedWSEvent, map[string]interface{}{
@@ -62,10 +66,27 @@ func (s *service) GetNumPlaybooksForTeam(teamID string) (int, error) {
 }
 
 func (s *service) Update(playbook Playbook, userID string) error {
-	if err := s.store.Update(playbook); err != nil {
+	current, err := s.store.Get(playbook.ID)
+	if err != nil {
+		return err
+	}
+
+	// Playbooks created before revisions existed get a first revision of their state before this
+	// update, so that it can be restored.
+	if current.Revision == 0 {
+		if _, err = s.store.CreateRevision(current, ""); err != nil {
+			return errors.Wrapf(err, "failed to store the first revision of playbook %s", playbook.ID)
+		}
+	}
+
+	if err = s.store.Update(playbook); err != nil {
 		return err
 	}
 
+	if _, err = s.store.CreateRevision(playbook, userID); err != nil {
+		return errors.Wrapf(err, "failed to store a revision of playbook %s", playbook.ID)
+	}
+
 	s.telemetry.UpdatePlaybook(playbook, userID)
 
 	return nil
@@ -88,3 +109,11 @@ func (s *service) Delete(playbook Playbook, userID string) error {
 
 	return nil
 }
+
+func (s *service) GetRevisions(playbookID string) ([]Revision, error) {
+	return s.store.GetRevisions(playbookID)
+}
+
+func (s *service) GetRevision(playbookID string, revision int) (Revision, error) {
+	return s.store.GetRevision(playbookID, revision)
+}
@@ -0,0 +1,17 @@
+package playbook
+
+import (
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+// ValidateStatusUpdateTemplate checks that tmpl, the status update template of a playbook, can be
+// parsed. A blank template is valid: the incidents of the playbook then have no template.
+func ValidateStatusUpdateTemplate(tmpl string) error {
+	if _, err := template.New("status_update").Parse(tmpl); err != nil {
+		return errors.Wrap(err, "failed to parse status update template")
+	}
+
+	return nil
+}
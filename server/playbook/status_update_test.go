@@ -0,0 +1,13 @@
+package playbook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateStatusUpdateTemplate(t *testing.T) {
+	require.NoError(t, ValidateStatusUpdateTemplate(""))
+	require.NoError(t, ValidateStatusUpdateTemplate("**{{.IncidentName}}**: {{.ChecklistProgress}}, last update {{.SinceLastUpdate}} ago."))
+	require.Error(t, ValidateStatusUpdateTemplate("{{.IncidentName"))
+}
@@ -4,10 +4,12 @@ import (
 	"net/http"
 
 	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/api"
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/archive"
 	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/bot"
 	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/command"
 	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/config"
 	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/incident"
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/oncall"
 	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/playbook"
 	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/sqlstore"
 	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/telemetry"
@@ -37,6 +39,7 @@ type Plugin struct {
 	config          *config.ServiceImpl
 	incidentService incident.Service
 	playbookService playbook.Service
+	onCallService   oncall.Service
 	bot             *bot.Bot
 }
 
@@ -132,11 +135,14 @@ func (p *Plugin) OnActivate() error {
 	incidentStore := sqlstore.NewIncidentStore(apiClient, p.bot, sqlStore)
 	playbookStore := sqlstore.NewPlaybookStore(apiClient, p.bot, sqlStore)
 	statsStore := sqlstore.NewStatsStore(apiClient, p.bot, sqlStore)
+	rotationStore := sqlstore.NewRotationStore(apiClient, p.bot, sqlStore)
 
 	p.handler = api.NewHandler(pluginAPIClient, p.config, p.bot)
 
 	scheduler := cluster.GetJobOnceScheduler(p.API)
 
+	p.onCallService = oncall.NewService(rotationStore)
+
 	p.incidentService = incident.NewService(
 		pluginAPIClient,
 		incidentStore,
@@ -145,6 +151,7 @@ func (p *Plugin) OnActivate() error {
 		p.config,
 		scheduler,
 		telemetryClient,
+		p.onCallService,
 	)
 
 	if err = scheduler.SetCallback(p.incidentService.HandleReminder); err != nil {
@@ -159,6 +166,7 @@ func (p *Plugin) OnActivate() error {
 	api.NewPlaybookHandler(
 		p.handler.APIRouter,
 		p.playbookService,
+		p.onCallService,
 		pluginAPIClient,
 		p.bot,
 		p.config,
@@ -172,6 +180,22 @@ func (p *Plugin) OnActivate() error {
 		p.bot,
 		p.config,
 	)
+	api.NewAlertHandler(
+		p.handler.HooksRouter,
+		p.incidentService,
+		p.playbookService,
+		pluginAPIClient,
+		p.bot,
+		p.bot,
+		p.config,
+	)
+	api.NewOnCallHandler(p.handler.APIRouter, p.onCallService, pluginAPIClient, p.bot)
+	api.NewArchiveHandler(
+		p.handler.APIRouter,
+		archive.NewService(pluginAPIClient, p.incidentService, p.playbookService),
+		pluginAPIClient,
+		p.bot,
+	)
 	api.NewStatsHandler(p.handler.APIRouter, pluginAPIClient, p.bot, statsStore, p.playbookService)
 	api.NewBotHandler(p.handler.APIRouter, pluginAPIClient, p.bot, p.bot, p.config)
 	api.NewTelemetryHandler(p.handler.APIRouter, p.incidentService, pluginAPIClient, p.bot, telemetryClient, telemetryClient, p.config)
@@ -207,7 +231,7 @@ func (p *Plugin) OnConfigurationChange() error {
 
 // ExecuteCommand executes a command that has been previously registered via the RegisterCommand.
 func (p *Plugin) ExecuteCommand(c *plugin.Context, args *model.CommandArgs) (*model.CommandResponse, *model.AppError) {
-	runner := command.NewCommandRunner(c, args, pluginapi.NewClient(p.API), p.bot, p.bot, p.incidentService, p.playbookService, p.config)
+	runner := command.NewCommandRunner(c, args, pluginapi.NewClient(p.API), p.bot, p.bot, p.incidentService, p.playbookService, p.onCallService, p.config)
 
 	if err := runner.Execute(); err != nil {
 		return nil, model.NewAppError("IncidentCollaborationPlugin.ExecuteCommand", "Unable to execute command.", nil, err.Error(), http.StatusInternalServerError)
@@ -0,0 +1,167 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"math"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/incident"
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/permissions"
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/pkg/errors"
+)
+
+// CreateActionItem stores a new action item and returns it with its new ID.
+func (s *incidentStore) CreateActionItem(item *incident.ActionItem) (*incident.ActionItem, error) {
+	if item.IncidentID == "" {
+		return nil, errors.New("needs the ID of the incident")
+	}
+	if item.CreateAt == 0 {
+		item.CreateAt = model.GetMillis()
+	}
+	if item.UpdateAt == 0 {
+		item.UpdateAt = item.CreateAt
+	}
+	item.ID = model.NewId()
+
+	_, err := s.store.execBuilder(s.store.db, sq.
+		Insert("IR_ActionItem").
+		SetMap(map[string]interface{}{
+			"ID":              item.ID,
+			"IncidentID":      item.IncidentID,
+			"Title":           item.Title,
+			"OwnerUserID":     item.OwnerUserID,
+			"DueAt":           item.DueAt,
+			"CompletedAt":     item.CompletedAt,
+			"OverdueNotified": item.OverdueNotified,
+			"CreatorUserID":   item.CreatorUserID,
+			"CreateAt":        item.CreateAt,
+			"UpdateAt":        item.UpdateAt,
+		}))
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to insert action item")
+	}
+
+	return item, nil
+}
+
+// UpdateActionItem updates the title, owner, due date and completion of an existing action item.
+func (s *incidentStore) UpdateActionItem(item *incident.ActionItem) error {
+	if item.ID == "" {
+		return errors.New("ID should not be empty")
+	}
+
+	_, err := s.store.execBuilder(s.store.db, sq.
+		Update("IR_ActionItem").
+		SetMap(map[string]interface{}{
+			"Title":           item.Title,
+			"OwnerUserID":     item.OwnerUserID,
+			"DueAt":           item.DueAt,
+			"CompletedAt":     item.CompletedAt,
+			"OverdueNotified": item.OverdueNotified,
+			"UpdateAt":        item.UpdateAt,
+		}).
+		Where(sq.Eq{"ID": item.ID}))
+
+	if err != nil {
+		return errors.Wrapf(err, "failed to update action item with id '%s'", item.ID)
+	}
+
+	return nil
+}
+
+// DeleteActionItem deletes the action item with the ID itemID.
+func (s *incidentStore) DeleteActionItem(itemID string) error {
+	if itemID == "" {
+		return errors.New("ID cannot be empty")
+	}
+
+	_, err := s.store.execBuilder(s.store.db, sq.
+		Delete("IR_ActionItem").
+		Where(sq.Eq{"ID": itemID}))
+
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete action item with id '%s'", itemID)
+	}
+
+	return nil
+}
+
+// GetActionItems returns filtered action items across the incidents the requester can view, most
+// urgent first, and the total count before paging.
+func (s *incidentStore) GetActionItems(requesterInfo permissions.RequesterInfo, options incident.ActionItemFilterOptions) (*incident.GetActionItemsResults, error) {
+	if err := incident.ValidateActionItemOptions(&options); err != nil {
+		return nil, err
+	}
+
+	filter := sq.And{sq.Eq{"i.TeamID": options.TeamID}}
+	if permissionsExpr := s.buildPermissionsExpr(requesterInfo); permissionsExpr != nil {
+		filter = append(filter, permissionsExpr)
+	}
+	if options.IncidentID != "" {
+		filter = append(filter, sq.Eq{"ai.IncidentID": options.IncidentID})
+	}
+	if options.OwnerID != "" {
+		filter = append(filter, sq.Eq{"ai.OwnerUserID": options.OwnerID})
+	}
+	switch options.Status {
+	case incident.ActionItemStatusOpen:
+		filter = append(filter, sq.Eq{"ai.CompletedAt": 0})
+	case incident.ActionItemStatusDone:
+		filter = append(filter, sq.NotEq{"ai.CompletedAt": 0})
+	case incident.ActionItemStatusOverdue:
+		filter = append(filter, sq.Eq{"ai.CompletedAt": 0}, sq.NotEq{"ai.DueAt": 0}, sq.LtOrEq{"ai.DueAt": model.GetMillis()})
+	}
+
+	// Items without a due date come last.
+	queryForResults := s.actionItemSelect.
+		Join("IR_Incident AS i ON (i.ID = ai.IncidentID)").
+		Where(filter).
+		OrderBy("CASE WHEN ai.DueAt = 0 THEN 1 ELSE 0 END", "ai.DueAt ASC", "ai.CreateAt ASC", "ai.ID ASC").
+		Offset(uint64(options.Page * options.PerPage)).
+		Limit(uint64(options.PerPage))
+
+	queryForTotal := s.store.builder.
+		Select("COUNT(*)").
+		From("IR_ActionItem AS ai").
+		Join("IR_Incident AS i ON (i.ID = ai.IncidentID)").
+		Where(filter)
+
+	var items []incident.ActionItem
+	if err := s.store.selectBuilder(s.store.db, &items, queryForResults); err != nil {
+		return nil, errors.Wrap(err, "failed to query for action items")
+	}
+
+	var total int
+	if err := s.store.getBuilder(s.store.db, &total, queryForTotal); err != nil {
+		return nil, errors.Wrap(err, "failed to get total count")
+	}
+	pageCount := int(math.Ceil(float64(total) / float64(options.PerPage)))
+	hasMore := options.Page+1 < pageCount
+
+	if items == nil {
+		items = []incident.ActionItem{}
+	}
+
+	return &incident.GetActionItemsResults{
+		TotalCount: total,
+		PageCount:  pageCount,
+		HasMore:    hasMore,
+		Items:      items,
+	}, nil
+}
+
+// getActionItemsForIncident returns the action items of incidentID, oldest first.
+func (s *incidentStore) getActionItemsForIncident(q sqlx.Queryer, incidentID string) ([]incident.ActionItem, error) {
+	var items []incident.ActionItem
+	err := s.store.selectBuilder(q, &items, s.actionItemSelect.
+		Where(sq.Eq{"ai.IncidentID": incidentID}).
+		OrderBy("ai.CreateAt ASC", "ai.ID ASC"))
+	if err != nil && err != sql.ErrNoRows {
+		return nil, errors.Wrapf(err, "failed to get action items for incident with id '%s'", incidentID)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/incident"
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/permissions"
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIncidentStore_ActionItems(t *testing.T) {
+	for _, driverName := range driverNames {
+		db := setupTestDB(t, driverName)
+		iStore := setupIncidentStore(t, db)
+		_, store := setupSQLStore(t, db)
+		setupChannelsTable(t, db)
+		setupPostsTable(t, db)
+
+		teamID := model.NewId()
+		ownerID := model.NewId()
+		admin := permissions.RequesterInfo{UserID: model.NewId(), IsAdmin: true}
+
+		createIncident := func(t *testing.T) *incident.Incident {
+			inc := NewBuilder(nil).
+				WithName("incident with action items").
+				WithTeamID(teamID).
+				ToIncident()
+			inc, err := iStore.CreateIncident(inc)
+			require.NoError(t, err)
+			createIncidentChannel(t, store, inc)
+			return inc
+		}
+
+		t.Run("Create, retrieve with the incident, update and delete", func(t *testing.T) {
+			inc := createIncident(t)
+
+			item, err := iStore.CreateActionItem(&incident.ActionItem{
+				IncidentID:    inc.ID,
+				Title:         "Add disk alerts",
+				OwnerUserID:   ownerID,
+				DueAt:         1000,
+				CreatorUserID: "creator",
+				CreateAt:      100,
+			})
+			require.NoError(t, err)
+			require.NotEmpty(t, item.ID)
+
+			got, err := iStore.GetIncident(inc.ID)
+			require.NoError(t, err)
+			require.Equal(t, []incident.ActionItem{*item}, got.ActionItems)
+
+			item.CompletedAt = 2000
+			item.OverdueNotified = true
+			item.UpdateAt = 2000
+			err = iStore.UpdateActionItem(item)
+			require.NoError(t, err)
+
+			got, err = iStore.GetIncident(inc.ID)
+			require.NoError(t, err)
+			require.Equal(t, []incident.ActionItem{*item}, got.ActionItems)
+
+			err = iStore.DeleteActionItem(item.ID)
+			require.NoError(t, err)
+
+			got, err = iStore.GetIncident(inc.ID)
+			require.NoError(t, err)
+			require.Empty(t, got.ActionItems)
+		})
+
+		t.Run("Filter across incidents", func(t *testing.T) {
+			inc := createIncident(t)
+			other := createIncident(t)
+
+			now := model.GetMillis()
+			overdue, err := iStore.CreateActionItem(&incident.ActionItem{
+				IncidentID: inc.ID, Title: "overdue", OwnerUserID: ownerID, DueAt: now - 1000,
+			})
+			require.NoError(t, err)
+			open, err := iStore.CreateActionItem(&incident.ActionItem{
+				IncidentID: other.ID, Title: "open",
+			})
+			require.NoError(t, err)
+			done, err := iStore.CreateActionItem(&incident.ActionItem{
+				IncidentID: inc.ID, Title: "done", OwnerUserID: ownerID, DueAt: now - 2000, CompletedAt: now,
+			})
+			require.NoError(t, err)
+
+			results, err := iStore.GetActionItems(admin, incident.ActionItemFilterOptions{TeamID: teamID})
+			require.NoError(t, err)
+			require.Equal(t, 3, results.TotalCount)
+			require.Equal(t, []string{done.ID, overdue.ID, open.ID}, actionItemIDs(results.Items))
+
+			results, err = iStore.GetActionItems(admin, incident.ActionItemFilterOptions{TeamID: teamID, Status: incident.ActionItemStatusOverdue})
+			require.NoError(t, err)
+			require.Equal(t, []string{overdue.ID}, actionItemIDs(results.Items))
+
+			results, err = iStore.GetActionItems(admin, incident.ActionItemFilterOptions{TeamID: teamID, Status: incident.ActionItemStatusOpen})
+			require.NoError(t, err)
+			require.Equal(t, []string{overdue.ID, open.ID}, actionItemIDs(results.Items))
+
+			results, err = iStore.GetActionItems(admin, incident.ActionItemFilterOptions{TeamID: teamID, OwnerID: ownerID, PerPage: 1})
+			require.NoError(t, err)
+			require.Equal(t, 2, results.TotalCount)
+			require.True(t, results.HasMore)
+			require.Equal(t, []string{done.ID}, actionItemIDs(results.Items))
+
+			results, err = iStore.GetActionItems(admin, incident.ActionItemFilterOptions{TeamID: teamID, IncidentID: other.ID})
+			require.NoError(t, err)
+			require.Equal(t, []string{open.ID}, actionItemIDs(results.Items))
+
+			results, err = iStore.GetActionItems(admin, incident.ActionItemFilterOptions{TeamID: model.NewId()})
+			require.NoError(t, err)
+			require.Empty(t, results.Items)
+		})
+	}
+}
+
+func actionItemIDs(items []incident.ActionItem) []string {
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	return ids
+}
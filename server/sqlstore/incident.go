@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"sort"
 	"strings"
 	"time"
 
@@ -30,6 +31,14 @@ const (
 type sqlIncident struct {
 	incident.Incident
 	ChecklistsJSON              json.RawMessage
+	WebhookSubscriptionsJSON    json.RawMessage
+	CustomFieldsJSON            json.RawMessage
+	CustomFieldValuesJSON       json.RawMessage
+	RolesJSON                   json.RawMessage
+	RoleAssignmentsJSON         json.RawMessage
+	EscalationStepsJSON         json.RawMessage
+	BroadcastTargetsJSON        json.RawMessage
+	RetrospectiveSectionsJSON   json.RawMessage
 	ConcatenatedInvitedUserIDs  string
 	ConcatenatedInvitedGroupIDs string
 }
@@ -43,6 +52,13 @@ type incidentStore struct {
 	incidentSelect       sq.SelectBuilder
 	statusPostsSelect    sq.SelectBuilder
 	timelineEventsSelect sq.SelectBuilder
+
+	webhookDeliverySelect        sq.SelectBuilder
+	webhookDeliveryAttemptSelect sq.SelectBuilder
+
+	linkSelect sq.SelectBuilder
+
+	actionItemSelect sq.SelectBuilder
 }
 
 // Ensure incidentStore implements the incident.Store interface.
@@ -58,11 +74,18 @@ func NewIncidentStore(pluginAPI PluginAPIClient, log bot.Logger, sqlStore *SQLSt
 	// When adding an Incident column #1: add to this select
 	incidentSelect := sqlStore.builder.
 		Select("i.ID", "c.DisplayName AS Name", "i.Description", "i.CommanderUserID AS OwnerUserID", "i.TeamID", "i.ChannelID",
-			"i.CreateAt", "i.EndAt", "i.DeleteAt", "i.PostID", "i.PlaybookID", "i.ReporterUserID", "i.CurrentStatus",
+			"i.CreateAt", "i.EndAt", "i.DeleteAt", "i.PostID", "i.PlaybookID", "i.PlaybookRevision", "i.ReporterUserID", "i.CurrentStatus",
 			"i.ChecklistsJSON", "COALESCE(i.ReminderPostID, '') ReminderPostID", "i.PreviousReminder", "i.BroadcastChannelID",
 			"COALESCE(ReminderMessageTemplate, '') ReminderMessageTemplate", "ConcatenatedInvitedUserIDs", "ConcatenatedInvitedGroupIDs", "DefaultCommanderID AS DefaultOwnerID",
 			"AnnouncementChannelID", "WebhookOnCreationURL", "Retrospective", "MessageOnJoin", "RetrospectivePublishedAt", "RetrospectiveReminderIntervalSeconds",
-			"RetrospectiveWasCanceled", "WebhookOnStatusUpdateURL").
+			"RetrospectiveWasCanceled", "WebhookOnStatusUpdateURL", "Severity", "WebhookSecret",
+			"COALESCE(i.WebhookSubscriptionsJSON, '[]') WebhookSubscriptionsJSON", "i.AlertFingerprint",
+			"COALESCE(i.CustomFieldsJSON, '[]') CustomFieldsJSON", "COALESCE(i.CustomFieldValuesJSON, '{}') CustomFieldValuesJSON",
+			"COALESCE(i.RolesJSON, '[]') RolesJSON", "COALESCE(i.RoleAssignmentsJSON, '{}') RoleAssignmentsJSON",
+			"i.AcknowledgeTimeoutSeconds", "COALESCE(i.EscalationStepsJSON, '[]') EscalationStepsJSON", "i.AcknowledgedAt", "i.EscalationLevel",
+			"COALESCE(i.MergedIntoID, '') MergedIntoID", "COALESCE(i.StatusUpdateTemplate, '') StatusUpdateTemplate",
+			"COALESCE(i.BroadcastTargetsJSON, '[]') BroadcastTargetsJSON", "COALESCE(i.RetrospectiveSectionsJSON, '{}') RetrospectiveSectionsJSON",
+			"COALESCE(i.RetrospectiveTemplate, '') RetrospectiveTemplate").
 		From("IR_Incident AS i").
 		Join("Channels AS c ON (c.Id = i.ChannelId)")
 
@@ -98,6 +121,24 @@ func NewIncidentStore(pluginAPI PluginAPIClient, log bot.Logger, sqlStore *SQLSt
 		).
 		From("IR_TimelineEvent as te")
 
+	webhookDeliverySelect := sqlStore.builder.
+		Select("ID", "IncidentID", "EventType", "URL", "Payload", "Status", "NumAttempts",
+			"NextAttemptAt", "CreateAt", "UpdateAt").
+		From("IR_WebhookDelivery")
+
+	webhookDeliveryAttemptSelect := sqlStore.builder.
+		Select("ID", "DeliveryID", "IncidentID", "Attempt", "StatusCode", "LatencyMS", "Error", "CreateAt").
+		From("IR_WebhookDeliveryAttempt")
+
+	linkSelect := sqlStore.builder.
+		Select("ID", "IncidentID", "LinkedIncidentID", "Type", "CreatorUserID", "CreateAt").
+		From("IR_IncidentLink")
+
+	actionItemSelect := sqlStore.builder.
+		Select("ai.ID", "ai.IncidentID", "ai.Title", "ai.OwnerUserID", "ai.DueAt", "ai.CompletedAt",
+			"ai.OverdueNotified", "ai.CreatorUserID", "ai.CreateAt", "ai.UpdateAt").
+		From("IR_ActionItem AS ai")
+
 	return &incidentStore{
 		pluginAPI:            pluginAPI,
 		log:                  log,
@@ -106,6 +147,13 @@ func NewIncidentStore(pluginAPI PluginAPIClient, log bot.Logger, sqlStore *SQLSt
 		incidentSelect:       incidentSelect,
 		statusPostsSelect:    statusPostsSelect,
 		timelineEventsSelect: timelineEventsSelect,
+
+		webhookDeliverySelect:        webhookDeliverySelect,
+		webhookDeliveryAttemptSelect: webhookDeliveryAttemptSelect,
+
+		linkSelect: linkSelect,
+
+		actionItemSelect: actionItemSelect,
 	}
 }
 
@@ -144,6 +192,12 @@ func (s *incidentStore) GetIncidents(requesterInfo permissions.RequesterInfo, op
 		queryForTotal = queryForTotal.Where(sq.Eq{"i.CurrentStatus": options.Statuses})
 	}
 
+	// Merged incidents are hidden unless their status is asked for
+	if options.Status == "" && len(options.Statuses) == 0 {
+		queryForResults = queryForResults.Where(sq.NotEq{"i.CurrentStatus": incident.StatusMerged})
+		queryForTotal = queryForTotal.Where(sq.NotEq{"i.CurrentStatus": incident.StatusMerged})
+	}
+
 	if options.OwnerID != "" {
 		queryForResults = queryForResults.Where(sq.Eq{"i.CommanderUserID": options.OwnerID})
 		queryForTotal = queryForTotal.Where(sq.Eq{"i.CommanderUserID": options.OwnerID})
@@ -167,6 +221,46 @@ func (s *incidentStore) GetIncidents(requesterInfo permissions.RequesterInfo, op
 		queryForTotal = queryForTotal.Where(sq.Eq{"i.PlaybookID": options.PlaybookID})
 	}
 
+	if options.Severity != "" {
+		queryForResults = queryForResults.Where(sq.Eq{"i.Severity": options.Severity})
+		queryForTotal = queryForTotal.Where(sq.Eq{"i.Severity": options.Severity})
+	}
+
+	customFieldKeys := make([]string, 0, len(options.CustomFields))
+	for key := range options.CustomFields {
+		customFieldKeys = append(customFieldKeys, key)
+	}
+	sort.Strings(customFieldKeys)
+	for _, key := range customFieldKeys {
+		customFieldClause := s.queryBuilder.
+			Select("1").
+			Prefix("EXISTS(").
+			From("IR_IncidentCustomFieldValue AS cfv").
+			Where("cfv.IncidentID = i.ID").
+			Where(sq.Eq{"cfv.FieldKey": key}).
+			Where(sq.Eq{"cfv.Value": options.CustomFields[key]}).
+			Suffix(")")
+
+		queryForResults = queryForResults.Where(customFieldClause)
+		queryForTotal = queryForTotal.Where(customFieldClause)
+	}
+
+	if options.Role != "" {
+		roleClause := s.queryBuilder.
+			Select("1").
+			Prefix("EXISTS(").
+			From("IR_IncidentRoleAssignment AS ra").
+			Where("ra.IncidentID = i.ID").
+			Where(sq.Eq{"ra.RoleKey": options.Role}).
+			Suffix(")")
+		if options.RoleUserID != "" {
+			roleClause = roleClause.Where(sq.Eq{"ra.UserID": options.RoleUserID})
+		}
+
+		queryForResults = queryForResults.Where(roleClause)
+		queryForTotal = queryForTotal.Where(roleClause)
+	}
+
 	// TODO: do we need to sanitize (replace any '%'s in the search term)?
 	if options.SearchTerm != "" {
 		column := "c.DisplayName"
@@ -183,6 +277,10 @@ func (s *incidentStore) GetIncidents(requesterInfo permissions.RequesterInfo, op
 		queryForTotal = queryForTotal.Where(sq.Like{column: fmt.Sprint("%", searchString, "%")})
 	}
 
+	if options.Sort == "Severity" {
+		// Incidents without a severity come last, whatever the direction.
+		queryForResults = queryForResults.OrderBy("CASE WHEN i.Severity = '' THEN 1 ELSE 0 END")
+	}
 	queryForResults = queryForResults.OrderBy(fmt.Sprintf("%s %s", options.Sort, options.Direction))
 
 	tx, err := s.store.db.Beginx()
@@ -262,8 +360,14 @@ func (s *incidentStore) CreateIncident(newIncident *incident.Incident) (out *inc
 		return nil, err
 	}
 
+	tx, err := s.store.db.Beginx()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not begin transaction")
+	}
+	defer s.store.finalizeTransaction(tx)
+
 	// When adding an Incident column #2: add to the SetMap
-	_, err = s.store.execBuilder(s.store.db, sq.
+	_, err = s.store.execBuilder(tx, sq.
 		Insert("IR_Incident").
 		SetMap(map[string]interface{}{
 			"ID":                                   rawIncident.ID,
@@ -277,6 +381,7 @@ func (s *incidentStore) CreateIncident(newIncident *incident.Incident) (out *inc
 			"EndAt":                                rawIncident.EndAt,
 			"PostID":                               rawIncident.PostID,
 			"PlaybookID":                           rawIncident.PlaybookID,
+			"PlaybookRevision":                     rawIncident.PlaybookRevision,
 			"ChecklistsJSON":                       rawIncident.ChecklistsJSON,
 			"ReminderPostID":                       rawIncident.ReminderPostID,
 			"PreviousReminder":                     rawIncident.PreviousReminder,
@@ -294,6 +399,23 @@ func (s *incidentStore) CreateIncident(newIncident *incident.Incident) (out *inc
 			"RetrospectiveReminderIntervalSeconds": rawIncident.RetrospectiveReminderIntervalSeconds,
 			"RetrospectiveWasCanceled":             rawIncident.RetrospectiveWasCanceled,
 			"WebhookOnStatusUpdateURL":             rawIncident.WebhookOnStatusUpdateURL,
+			"Severity":                             rawIncident.Severity,
+			"WebhookSecret":                        rawIncident.WebhookSecret,
+			"WebhookSubscriptionsJSON":             rawIncident.WebhookSubscriptionsJSON,
+			"CustomFieldsJSON":                     rawIncident.CustomFieldsJSON,
+			"CustomFieldValuesJSON":                rawIncident.CustomFieldValuesJSON,
+			"RolesJSON":                            rawIncident.RolesJSON,
+			"RoleAssignmentsJSON":                  rawIncident.RoleAssignmentsJSON,
+			"AcknowledgeTimeoutSeconds":            rawIncident.AcknowledgeTimeoutSeconds,
+			"EscalationStepsJSON":                  rawIncident.EscalationStepsJSON,
+			"AcknowledgedAt":                       rawIncident.AcknowledgedAt,
+			"EscalationLevel":                      rawIncident.EscalationLevel,
+			"AlertFingerprint":                     rawIncident.AlertFingerprint,
+			"MergedIntoID":                         rawIncident.MergedIntoID,
+			"StatusUpdateTemplate":                 rawIncident.StatusUpdateTemplate,
+			"BroadcastTargetsJSON":                 rawIncident.BroadcastTargetsJSON,
+			"RetrospectiveSectionsJSON":            rawIncident.RetrospectiveSectionsJSON,
+			"RetrospectiveTemplate":                rawIncident.RetrospectiveTemplate,
 			// Preserved for backwards compatibility with v1.2
 			"ActiveStage":      0,
 			"ActiveStageTitle": "",
@@ -305,6 +427,18 @@ func (s *incidentStore) CreateIncident(newIncident *incident.Incident) (out *inc
 		return nil, errors.Wrapf(err, "failed to store new incident")
 	}
 
+	if err = s.updateCustomFieldValues(tx, incidentCopy.ID, incidentCopy.CustomFieldValues); err != nil {
+		return nil, errors.Wrapf(err, "failed to store custom field values of new incident")
+	}
+
+	if err = s.updateRoleAssignments(tx, incidentCopy.ID, incidentCopy.RoleAssignments); err != nil {
+		return nil, errors.Wrapf(err, "failed to store role assignments of new incident")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "could not commit transaction")
+	}
+
 	return incidentCopy, nil
 }
 
@@ -322,8 +456,14 @@ func (s *incidentStore) UpdateIncident(newIncident *incident.Incident) error {
 		return err
 	}
 
+	tx, err := s.store.db.Beginx()
+	if err != nil {
+		return errors.Wrap(err, "could not begin transaction")
+	}
+	defer s.store.finalizeTransaction(tx)
+
 	// When adding an Incident column #3: add to this SetMap (if it is a column that can be updated)
-	_, err = s.store.execBuilder(s.store.db, sq.
+	_, err = s.store.execBuilder(tx, sq.
 		Update("IR_Incident").
 		SetMap(map[string]interface{}{
 			"Name":                                 "",
@@ -345,6 +485,23 @@ func (s *incidentStore) UpdateIncident(newIncident *incident.Incident) error {
 			"RetrospectiveReminderIntervalSeconds": rawIncident.RetrospectiveReminderIntervalSeconds,
 			"RetrospectiveWasCanceled":             rawIncident.RetrospectiveWasCanceled,
 			"WebhookOnStatusUpdateURL":             rawIncident.WebhookOnStatusUpdateURL,
+			"Severity":                             rawIncident.Severity,
+			"WebhookSecret":                        rawIncident.WebhookSecret,
+			"WebhookSubscriptionsJSON":             rawIncident.WebhookSubscriptionsJSON,
+			"CustomFieldsJSON":                     rawIncident.CustomFieldsJSON,
+			"CustomFieldValuesJSON":                rawIncident.CustomFieldValuesJSON,
+			"RolesJSON":                            rawIncident.RolesJSON,
+			"RoleAssignmentsJSON":                  rawIncident.RoleAssignmentsJSON,
+			"AcknowledgeTimeoutSeconds":            rawIncident.AcknowledgeTimeoutSeconds,
+			"EscalationStepsJSON":                  rawIncident.EscalationStepsJSON,
+			"AcknowledgedAt":                       rawIncident.AcknowledgedAt,
+			"EscalationLevel":                      rawIncident.EscalationLevel,
+			"AlertFingerprint":                     rawIncident.AlertFingerprint,
+			"MergedIntoID":                         rawIncident.MergedIntoID,
+			"StatusUpdateTemplate":                 rawIncident.StatusUpdateTemplate,
+			"BroadcastTargetsJSON":                 rawIncident.BroadcastTargetsJSON,
+			"RetrospectiveSectionsJSON":            rawIncident.RetrospectiveSectionsJSON,
+			"RetrospectiveTemplate":                rawIncident.RetrospectiveTemplate,
 		}).
 		Where(sq.Eq{"ID": rawIncident.ID}))
 
@@ -352,6 +509,151 @@ func (s *incidentStore) UpdateIncident(newIncident *incident.Incident) error {
 		return errors.Wrapf(err, "failed to update incident with id '%s'", rawIncident.ID)
 	}
 
+	if err = s.updateCustomFieldValues(tx, rawIncident.ID, rawIncident.CustomFieldValues); err != nil {
+		return errors.Wrapf(err, "failed to update custom field values of incident with id '%s'", rawIncident.ID)
+	}
+
+	if err = s.updateRoleAssignments(tx, rawIncident.ID, rawIncident.RoleAssignments); err != nil {
+		return errors.Wrapf(err, "failed to update role assignments of incident with id '%s'", rawIncident.ID)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.Wrap(err, "could not commit transaction")
+	}
+
+	return nil
+}
+
+// updateCustomFieldValues updates the rows of IR_IncidentCustomFieldValue of the incident to hold
+// one row per value in values, rewriting only the rows of the fields whose values changed. The
+// values themselves are read from IR_Incident: these rows only serve to filter incidents by custom
+// field value.
+func (s *incidentStore) updateCustomFieldValues(e sqlx.Ext, incidentID string, values map[string][]string) error {
+	var rows []struct {
+		FieldKey string
+		Value    string
+	}
+	err := s.store.selectBuilder(e, &rows, s.queryBuilder.
+		Select("FieldKey", "Value").
+		From("IR_IncidentCustomFieldValue").
+		Where(sq.Eq{"IncidentID": incidentID}))
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	current := map[string][]string{}
+	for _, row := range rows {
+		current[row.FieldKey] = append(current[row.FieldKey], row.Value)
+	}
+
+	changedKeys := []string{}
+	for key := range current {
+		if _, ok := values[key]; !ok {
+			changedKeys = append(changedKeys, key)
+		}
+	}
+	for key, fieldValues := range values {
+		if !sameValues(current[key], fieldValues) {
+			changedKeys = append(changedKeys, key)
+		}
+	}
+
+	for _, key := range changedKeys {
+		if _, err := s.store.execBuilder(e, sq.
+			Delete("IR_IncidentCustomFieldValue").
+			Where(sq.Eq{"IncidentID": incidentID, "FieldKey": key})); err != nil {
+			return err
+		}
+
+		for _, value := range values[key] {
+			if _, err := s.store.execBuilder(e, sq.
+				Insert("IR_IncidentCustomFieldValue").
+				SetMap(map[string]interface{}{
+					"IncidentID": incidentID,
+					"FieldKey":   key,
+					"Value":      value,
+				})); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// sameValues returns whether a and b hold the same values, in any order.
+func sameValues(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	sortedA := append([]string(nil), a...)
+	sortedB := append([]string(nil), b...)
+	sort.Strings(sortedA)
+	sort.Strings(sortedB)
+	for i := range sortedA {
+		if sortedA[i] != sortedB[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// updateRoleAssignments updates the rows of IR_IncidentRoleAssignment of the incident to hold one
+// row per user holding a role in assignments, rewriting only the rows of the roles whose users
+// changed. The assignments themselves are read from IR_Incident: these rows only serve to filter
+// incidents by role.
+func (s *incidentStore) updateRoleAssignments(e sqlx.Ext, incidentID string, assignments map[string][]string) error {
+	var rows []struct {
+		RoleKey string
+		UserID  string
+	}
+	err := s.store.selectBuilder(e, &rows, s.queryBuilder.
+		Select("RoleKey", "UserID").
+		From("IR_IncidentRoleAssignment").
+		Where(sq.Eq{"IncidentID": incidentID}))
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	current := map[string][]string{}
+	for _, row := range rows {
+		current[row.RoleKey] = append(current[row.RoleKey], row.UserID)
+	}
+
+	changedKeys := []string{}
+	for roleKey := range current {
+		if _, ok := assignments[roleKey]; !ok {
+			changedKeys = append(changedKeys, roleKey)
+		}
+	}
+	for roleKey, userIDs := range assignments {
+		if !sameValues(current[roleKey], userIDs) {
+			changedKeys = append(changedKeys, roleKey)
+		}
+	}
+
+	for _, roleKey := range changedKeys {
+		if _, err := s.store.execBuilder(e, sq.
+			Delete("IR_IncidentRoleAssignment").
+			Where(sq.Eq{"IncidentID": incidentID, "RoleKey": roleKey})); err != nil {
+			return err
+		}
+
+		for _, userID := range assignments[roleKey] {
+			if _, err := s.store.execBuilder(e, sq.
+				Insert("IR_IncidentRoleAssignment").
+				SetMap(map[string]interface{}{
+					"IncidentID": incidentID,
+					"RoleKey":    roleKey,
+					"UserID":     userID,
+				})); err != nil {
+				return err
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -392,6 +694,29 @@ func (s *incidentStore) UpdateStatus(statusPost *incident.SQLStatusPost) error {
 	return nil
 }
 
+// AddStatusPosts adds existing status posts to incidentID, without changing its current status.
+func (s *incidentStore) AddStatusPosts(incidentID string, statusPosts []incident.StatusPost) error {
+	if incidentID == "" {
+		return errors.New("needs incident ID")
+	}
+	if len(statusPosts) == 0 {
+		return nil
+	}
+
+	insert := sq.
+		Insert("IR_StatusPosts").
+		Columns("IncidentID", "PostID", "Status")
+	for _, statusPost := range statusPosts {
+		insert = insert.Values(incidentID, statusPost.ID, statusPost.Status)
+	}
+
+	if _, err := s.store.execBuilder(s.store.db, insert); err != nil {
+		return errors.Wrapf(err, "failed to add status posts to incident with id '%s'", incidentID)
+	}
+
+	return nil
+}
+
 // CreateTimelineEvent creates the timeline event
 func (s *incidentStore) CreateTimelineEvent(event *incident.TimelineEvent) (*incident.TimelineEvent, error) {
 	if event.IncidentID == "" {
@@ -435,6 +760,10 @@ func (s *incidentStore) CreateTimelineEvent(event *incident.TimelineEvent) (*inc
 
 // UpdateTimelineEvent updates (or inserts) the timeline event
 func (s *incidentStore) UpdateTimelineEvent(event *incident.TimelineEvent) error {
+	return s.updateTimelineEvent(s.store.db, event)
+}
+
+func (s *incidentStore) updateTimelineEvent(e execer, event *incident.TimelineEvent) error {
 	if event.ID == "" {
 		return errors.New("needs event ID")
 	}
@@ -450,7 +779,7 @@ func (s *incidentStore) UpdateTimelineEvent(event *incident.TimelineEvent) error
 		eventType = legacyEventTypeCommanderChanged
 	}
 
-	_, err := s.store.execBuilder(s.store.db, sq.
+	_, err := s.store.execBuilder(e, sq.
 		Update("IR_TimelineEvent").
 		SetMap(map[string]interface{}{
 			"IncidentID":    event.IncidentID,
@@ -513,6 +842,21 @@ func (s *incidentStore) GetIncident(incidentID string) (out *incident.Incident,
 		return nil, err
 	}
 
+	links, err := s.getLinkedIncidents(tx, incidentID)
+	if err != nil {
+		return nil, err
+	}
+
+	followerIDs, err := s.getFollowerIDs(tx, incidentID)
+	if err != nil {
+		return nil, err
+	}
+
+	actionItems, err := s.getActionItemsForIncident(tx, incidentID)
+	if err != nil {
+		return nil, err
+	}
+
 	if err = tx.Commit(); err != nil {
 		return out, errors.Wrap(err, "could not commit transaction")
 	}
@@ -522,6 +866,9 @@ func (s *incidentStore) GetIncident(incidentID string) (out *incident.Incident,
 	}
 
 	out.TimelineEvents = append(out.TimelineEvents, timelineEvents...)
+	out.Links = append(out.Links, links...)
+	out.FollowerIDs = append(out.FollowerIDs, followerIDs...)
+	out.ActionItems = append(out.ActionItems, actionItems...)
 
 	return out, nil
 }
@@ -576,6 +923,29 @@ func (s *incidentStore) GetIncidentIDForChannel(channelID string) (string, error
 	return id, nil
 }
 
+// GetIncidentIDForAlert gets the most recent incident that is neither resolved nor archived,
+// started by playbookID for the alert with the given fingerprint.
+func (s *incidentStore) GetIncidentIDForAlert(playbookID, fingerprint string) (string, error) {
+	query := s.queryBuilder.
+		Select("i.ID").
+		From("IR_Incident i").
+		Where(sq.Eq{"i.PlaybookID": playbookID}).
+		Where(sq.Eq{"i.AlertFingerprint": fingerprint}).
+		Where(sq.NotEq{"i.CurrentStatus": []string{incident.StatusResolved, incident.StatusArchived, incident.StatusMerged}}).
+		OrderBy("i.CreateAt DESC").
+		Limit(1)
+
+	var id string
+	err := s.store.getBuilder(s.store.db, &id, query)
+	if err == sql.ErrNoRows {
+		return "", errors.Wrapf(incident.ErrNotFound, "no ongoing incident for alert with fingerprint '%s'", fingerprint)
+	} else if err != nil {
+		return "", errors.Wrapf(err, "failed to get incident for alert with fingerprint '%s'", fingerprint)
+	}
+
+	return id, nil
+}
+
 // GetAllIncidentMembersCount returns the count of all members of an incident since the
 // beginning of the incident, excluding bots.
 func (s *incidentStore) GetAllIncidentMembersCount(channelID string) (int64, error) {
@@ -627,7 +997,7 @@ func (s *incidentStore) NukeDB() (err error) {
 	}
 	defer s.store.finalizeTransaction(tx)
 
-	if _, err := tx.Exec("DROP TABLE IF EXISTS IR_PlaybookMember,  IR_StatusPosts, IR_Incident, IR_Playbook, IR_System, IR_TimelineEvent"); err != nil {
+	if _, err := tx.Exec("DROP TABLE IF EXISTS IR_PlaybookMember,  IR_StatusPosts, IR_Incident, IR_Playbook, IR_System, IR_TimelineEvent, IR_WebhookDelivery, IR_WebhookDeliveryAttempt, IR_PlaybookRevision, IR_IncidentCustomFieldValue, IR_IncidentRoleAssignment, IR_OnCallRotation, IR_IncidentLink, IR_IncidentFollower, IR_ActionItem, IR_TimelineEventEdit"); err != nil {
 		return errors.Wrap(err, "could not delete all IR tables")
 	}
 
@@ -747,6 +1117,78 @@ func (s *incidentStore) toIncident(rawIncident sqlIncident) (*incident.Incident,
 		return nil, errors.Wrapf(err, "failed to unmarshal checklists json for incident id: %s", rawIncident.ID)
 	}
 
+	if len(rawIncident.WebhookSubscriptionsJSON) > 0 {
+		if err := json.Unmarshal(rawIncident.WebhookSubscriptionsJSON, &i.WebhookSubscriptions); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal webhook subscriptions json for incident id: %s", rawIncident.ID)
+		}
+	}
+
+	if len(rawIncident.CustomFieldsJSON) > 0 {
+		var customFields []playbook.CustomField
+		if err := json.Unmarshal(rawIncident.CustomFieldsJSON, &customFields); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal custom fields json for incident id: %s", rawIncident.ID)
+		}
+		if len(customFields) > 0 {
+			i.CustomFields = customFields
+		}
+	}
+
+	if len(rawIncident.CustomFieldValuesJSON) > 0 {
+		var customFieldValues map[string][]string
+		if err := json.Unmarshal(rawIncident.CustomFieldValuesJSON, &customFieldValues); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal custom field values json for incident id: %s", rawIncident.ID)
+		}
+		if len(customFieldValues) > 0 {
+			i.CustomFieldValues = customFieldValues
+		}
+	}
+
+	if len(rawIncident.RolesJSON) > 0 {
+		var roles []playbook.Role
+		if err := json.Unmarshal(rawIncident.RolesJSON, &roles); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal roles json for incident id: %s", rawIncident.ID)
+		}
+		if len(roles) > 0 {
+			i.Roles = roles
+		}
+	}
+
+	if len(rawIncident.RoleAssignmentsJSON) > 0 {
+		var roleAssignments map[string][]string
+		if err := json.Unmarshal(rawIncident.RoleAssignmentsJSON, &roleAssignments); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal role assignments json for incident id: %s", rawIncident.ID)
+		}
+		if len(roleAssignments) > 0 {
+			i.RoleAssignments = roleAssignments
+		}
+	}
+
+	if len(rawIncident.EscalationStepsJSON) > 0 {
+		var escalationSteps []playbook.EscalationStep
+		if err := json.Unmarshal(rawIncident.EscalationStepsJSON, &escalationSteps); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal escalation steps json for incident id: %s", rawIncident.ID)
+		}
+		if len(escalationSteps) > 0 {
+			i.EscalationSteps = escalationSteps
+		}
+	}
+
+	if len(rawIncident.BroadcastTargetsJSON) > 0 {
+		var broadcastTargets []playbook.BroadcastTarget
+		if err := json.Unmarshal(rawIncident.BroadcastTargetsJSON, &broadcastTargets); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal broadcast targets json for incident id: %s", rawIncident.ID)
+		}
+		if len(broadcastTargets) > 0 {
+			i.BroadcastTargets = broadcastTargets
+		}
+	}
+
+	if len(rawIncident.RetrospectiveSectionsJSON) > 0 {
+		if err := json.Unmarshal(rawIncident.RetrospectiveSectionsJSON, &i.RetrospectiveSections); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal retrospective sections json for incident id: %s", rawIncident.ID)
+		}
+	}
+
 	i.InvitedUserIDs = []string(nil)
 	if rawIncident.ConcatenatedInvitedUserIDs != "" {
 		i.InvitedUserIDs = strings.Split(rawIncident.ConcatenatedInvitedUserIDs, ",")
@@ -767,9 +1209,57 @@ func toSQLIncident(origIncident incident.Incident) (*sqlIncident, error) {
 		return nil, errors.Wrapf(err, "failed to marshal checklist json for incident id: '%s'", origIncident.ID)
 	}
 
+	webhookSubscriptionsJSON, err := json.Marshal(origIncident.WebhookSubscriptions)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal webhook subscriptions json for incident id: '%s'", origIncident.ID)
+	}
+
+	customFieldsJSON, err := json.Marshal(origIncident.CustomFields)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal custom fields json for incident id: '%s'", origIncident.ID)
+	}
+
+	customFieldValuesJSON, err := json.Marshal(origIncident.CustomFieldValues)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal custom field values json for incident id: '%s'", origIncident.ID)
+	}
+
+	rolesJSON, err := json.Marshal(origIncident.Roles)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal roles json for incident id: '%s'", origIncident.ID)
+	}
+
+	roleAssignmentsJSON, err := json.Marshal(origIncident.RoleAssignments)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal role assignments json for incident id: '%s'", origIncident.ID)
+	}
+
+	escalationStepsJSON, err := json.Marshal(origIncident.EscalationSteps)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal escalation steps json for incident id: '%s'", origIncident.ID)
+	}
+
+	broadcastTargetsJSON, err := json.Marshal(origIncident.BroadcastTargets)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal broadcast targets json for incident id: '%s'", origIncident.ID)
+	}
+
+	retrospectiveSectionsJSON, err := json.Marshal(origIncident.RetrospectiveSections)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal retrospective sections json for incident id: '%s'", origIncident.ID)
+	}
+
 	return &sqlIncident{
 		Incident:                    origIncident,
 		ChecklistsJSON:              checklistsJSON,
+		WebhookSubscriptionsJSON:    webhookSubscriptionsJSON,
+		CustomFieldsJSON:            customFieldsJSON,
+		CustomFieldValuesJSON:       customFieldValuesJSON,
+		RolesJSON:                   rolesJSON,
+		RoleAssignmentsJSON:         roleAssignmentsJSON,
+		EscalationStepsJSON:         escalationStepsJSON,
+		BroadcastTargetsJSON:        broadcastTargetsJSON,
+		RetrospectiveSectionsJSON:   retrospectiveSectionsJSON,
 		ConcatenatedInvitedUserIDs:  strings.Join(origIncident.InvitedUserIDs, ","),
 		ConcatenatedInvitedGroupIDs: strings.Join(origIncident.InvitedGroupIDs, ","),
 	}, nil
@@ -0,0 +1,69 @@
+package sqlstore
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/pkg/errors"
+)
+
+// Follow subscribes userID to the updates of incidentID. Following an incident twice does nothing.
+func (s *incidentStore) Follow(incidentID, userID string) error {
+	if incidentID == "" || userID == "" {
+		return errors.New("needs the IDs of the incident and the user")
+	}
+
+	insertExpr := `
+INSERT INTO IR_IncidentFollower(IncidentID, UserID, CreateAt)
+    SELECT ?, ?, ?
+    WHERE NOT EXISTS (
+        SELECT 1 FROM IR_IncidentFollower
+            WHERE IncidentID = ? AND UserID = ?
+    );`
+	if s.store.db.DriverName() == model.DATABASE_DRIVER_MYSQL {
+		insertExpr = `
+INSERT INTO IR_IncidentFollower(IncidentID, UserID, CreateAt)
+    SELECT ?, ?, ? FROM DUAL
+    WHERE NOT EXISTS (
+        SELECT 1 FROM IR_IncidentFollower
+            WHERE IncidentID = ? AND UserID = ?
+    );`
+	}
+
+	_, err := s.store.execBuilder(s.store.db, sq.Expr(insertExpr,
+		incidentID, userID, model.GetMillis(), incidentID, userID))
+	if err != nil {
+		return errors.Wrapf(err, "failed to add follower '%s' to incident '%s'", userID, incidentID)
+	}
+
+	return nil
+}
+
+// Unfollow unsubscribes userID from the updates of incidentID.
+func (s *incidentStore) Unfollow(incidentID, userID string) error {
+	_, err := s.store.execBuilder(s.store.db, sq.
+		Delete("IR_IncidentFollower").
+		Where(sq.Eq{"IncidentID": incidentID, "UserID": userID}))
+	if err != nil {
+		return errors.Wrapf(err, "failed to remove follower '%s' from incident '%s'", userID, incidentID)
+	}
+
+	return nil
+}
+
+// getFollowerIDs returns the IDs of the followers of incidentID, in the order they followed it.
+func (s *incidentStore) getFollowerIDs(q sqlx.Queryer, incidentID string) ([]string, error) {
+	var followerIDs []string
+	err := s.store.selectBuilder(q, &followerIDs, s.queryBuilder.
+		Select("UserID").
+		From("IR_IncidentFollower").
+		Where(sq.Eq{"IncidentID": incidentID}).
+		OrderBy("CreateAt ASC"))
+	if err != nil && err != sql.ErrNoRows {
+		return nil, errors.Wrapf(err, "failed to get followers for incident with id '%s'", incidentID)
+	}
+
+	return followerIDs, nil
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIncidentStore_Followers(t *testing.T) {
+	for _, driverName := range driverNames {
+		db := setupTestDB(t, driverName)
+		iStore := setupIncidentStore(t, db)
+		_, store := setupSQLStore(t, db)
+		setupChannelsTable(t, db)
+		setupPostsTable(t, db)
+
+		t.Run("Follow, retrieve and unfollow", func(t *testing.T) {
+			inc := NewBuilder(nil).
+				WithName("followed incident").
+				ToIncident()
+
+			inc, err := iStore.CreateIncident(inc)
+			require.NoError(t, err)
+			createIncidentChannel(t, store, inc)
+
+			got, err := iStore.GetIncident(inc.ID)
+			require.NoError(t, err)
+			require.Empty(t, got.FollowerIDs)
+
+			err = iStore.Follow(inc.ID, "user1")
+			require.NoError(t, err)
+			err = iStore.Follow(inc.ID, "user2")
+			require.NoError(t, err)
+
+			// Following twice does nothing.
+			err = iStore.Follow(inc.ID, "user1")
+			require.NoError(t, err)
+
+			got, err = iStore.GetIncident(inc.ID)
+			require.NoError(t, err)
+			require.ElementsMatch(t, []string{"user1", "user2"}, got.FollowerIDs)
+
+			err = iStore.Unfollow(inc.ID, "user1")
+			require.NoError(t, err)
+
+			got, err = iStore.GetIncident(inc.ID)
+			require.NoError(t, err)
+			require.Equal(t, []string{"user2"}, got.FollowerIDs)
+		})
+
+		t.Run("Follow requires both IDs", func(t *testing.T) {
+			err := iStore.Follow("", "user1")
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package sqlstore
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/incident"
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/pkg/errors"
+)
+
+// CreateLink stores a new link between two incidents and returns it with its new ID.
+func (s *incidentStore) CreateLink(link *incident.Link) (*incident.Link, error) {
+	if link.IncidentID == "" || link.LinkedIncidentID == "" {
+		return nil, errors.New("needs the IDs of both incidents")
+	}
+	if link.CreateAt == 0 {
+		link.CreateAt = model.GetMillis()
+	}
+	link.ID = model.NewId()
+
+	_, err := s.store.execBuilder(s.store.db, sq.
+		Insert("IR_IncidentLink").
+		SetMap(map[string]interface{}{
+			"ID":               link.ID,
+			"IncidentID":       link.IncidentID,
+			"LinkedIncidentID": link.LinkedIncidentID,
+			"Type":             link.Type,
+			"CreatorUserID":    link.CreatorUserID,
+			"CreateAt":         link.CreateAt,
+		}))
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to insert link")
+	}
+
+	return link, nil
+}
+
+// DeleteLink deletes the link with the ID linkID.
+func (s *incidentStore) DeleteLink(linkID string) error {
+	if linkID == "" {
+		return errors.New("ID cannot be empty")
+	}
+
+	_, err := s.store.execBuilder(s.store.db, sq.
+		Delete("IR_IncidentLink").
+		Where(sq.Eq{"ID": linkID}))
+
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete link with id '%s'", linkID)
+	}
+
+	return nil
+}
+
+// getLinkedIncidents returns the links of incidentID, seen from it, oldest first.
+func (s *incidentStore) getLinkedIncidents(q sqlx.Queryer, incidentID string) ([]incident.LinkedIncident, error) {
+	var links []incident.Link
+	err := s.store.selectBuilder(q, &links, s.linkSelect.
+		Where(sq.Or{sq.Eq{"IncidentID": incidentID}, sq.Eq{"LinkedIncidentID": incidentID}}).
+		OrderBy("CreateAt ASC"))
+	if err == sql.ErrNoRows || len(links) == 0 {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "failed to get links for incident with id '%s'", incidentID)
+	}
+
+	otherIDs := make([]string, 0, len(links))
+	for _, link := range links {
+		if link.IncidentID == incidentID {
+			otherIDs = append(otherIDs, link.LinkedIncidentID)
+		} else {
+			otherIDs = append(otherIDs, link.IncidentID)
+		}
+	}
+
+	var others []struct {
+		ID            string
+		Name          string
+		ChannelID     string
+		CurrentStatus string
+	}
+	err = s.store.selectBuilder(q, &others, s.queryBuilder.
+		Select("i.ID", "c.DisplayName AS Name", "i.ChannelID", "i.CurrentStatus").
+		From("IR_Incident AS i").
+		Join("Channels AS c ON (c.Id = i.ChannelId)").
+		Where(sq.Eq{"i.ID": otherIDs}))
+	if err != nil && err != sql.ErrNoRows {
+		return nil, errors.Wrapf(err, "failed to get the incidents linked to incident with id '%s'", incidentID)
+	}
+
+	linkedIncidents := make([]incident.LinkedIncident, 0, len(links))
+	for i, link := range links {
+		linkedIncident := incident.LinkedIncident{
+			LinkID:        link.ID,
+			Type:          link.Type,
+			IncidentID:    otherIDs[i],
+			CreatorUserID: link.CreatorUserID,
+			CreateAt:      link.CreateAt,
+		}
+		if link.LinkedIncidentID == incidentID {
+			linkedIncident.Type = incident.InverseLinkType(link.Type)
+		}
+		for _, other := range others {
+			if other.ID == otherIDs[i] {
+				linkedIncident.Name = other.Name
+				linkedIncident.ChannelID = other.ChannelID
+				linkedIncident.CurrentStatus = other.CurrentStatus
+			}
+		}
+		linkedIncidents = append(linkedIncidents, linkedIncident)
+	}
+
+	return linkedIncidents, nil
+}
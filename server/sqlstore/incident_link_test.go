@@ -0,0 +1,98 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/incident"
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIncidentStore_Links(t *testing.T) {
+	for _, driverName := range driverNames {
+		db := setupTestDB(t, driverName)
+		iStore := setupIncidentStore(t, db)
+		_, store := setupSQLStore(t, db)
+		setupChannelsTable(t, db)
+		setupPostsTable(t, db)
+
+		t.Run("Create, retrieve from both incidents and delete links", func(t *testing.T) {
+			createAt := model.GetMillis()
+			parent := NewBuilder(nil).
+				WithName("parent incident").
+				WithCreateAt(createAt).
+				ToIncident()
+			child := NewBuilder(nil).
+				WithName("child incident").
+				WithCreateAt(createAt + 1).
+				ToIncident()
+
+			parent, err := iStore.CreateIncident(parent)
+			require.NoError(t, err)
+			createIncidentChannel(t, store, parent)
+
+			child, err = iStore.CreateIncident(child)
+			require.NoError(t, err)
+			createIncidentChannel(t, store, child)
+
+			link, err := iStore.CreateLink(&incident.Link{
+				IncidentID:       child.ID,
+				LinkedIncidentID: parent.ID,
+				Type:             incident.LinkChildOf,
+				CreatorUserID:    "user",
+				CreateAt:         createAt + 2,
+			})
+			require.NoError(t, err)
+			require.NotEmpty(t, link.ID)
+
+			gotChild, err := iStore.GetIncident(child.ID)
+			require.NoError(t, err)
+			require.Equal(t, []incident.LinkedIncident{{
+				LinkID:        link.ID,
+				Type:          incident.LinkChildOf,
+				IncidentID:    parent.ID,
+				Name:          parent.Name,
+				ChannelID:     parent.ChannelID,
+				CurrentStatus: parent.CurrentStatus,
+				CreatorUserID: "user",
+				CreateAt:      createAt + 2,
+			}}, gotChild.Links)
+
+			gotParent, err := iStore.GetIncident(parent.ID)
+			require.NoError(t, err)
+			require.Equal(t, []incident.LinkedIncident{{
+				LinkID:        link.ID,
+				Type:          incident.LinkParentOf,
+				IncidentID:    child.ID,
+				Name:          child.Name,
+				ChannelID:     child.ChannelID,
+				CurrentStatus: child.CurrentStatus,
+				CreatorUserID: "user",
+				CreateAt:      createAt + 2,
+			}}, gotParent.Links)
+
+			err = iStore.DeleteLink(link.ID)
+			require.NoError(t, err)
+
+			gotParent, err = iStore.GetIncident(parent.ID)
+			require.NoError(t, err)
+			require.Nil(t, gotParent.Links)
+
+			gotChild, err = iStore.GetIncident(child.ID)
+			require.NoError(t, err)
+			require.Nil(t, gotChild.Links)
+		})
+
+		t.Run("Create link without incidents", func(t *testing.T) {
+			_, err := iStore.CreateLink(&incident.Link{Type: incident.LinkRelated})
+			require.Error(t, err)
+		})
+
+		t.Run("Delete link without ID", func(t *testing.T) {
+			require.Error(t, iStore.DeleteLink(""))
+		})
+	}
+}
@@ -101,6 +101,7 @@ func TestGetIncidents(t *testing.T) {
 		WithCreateAt(199).
 		WithChecklists([]int{7}).
 		WithPlaybookID("playbook1").
+		WithSeverity(incident.SeverityHigh).
 		ToIncident()
 
 	inc03 := *NewBuilder(nil).
@@ -140,6 +141,7 @@ func TestGetIncidents(t *testing.T) {
 		WithTeamID(team2id).
 		WithCreateAt(444).
 		WithChecklists([]int{4}).
+		WithSeverity(incident.SeverityHigh).
 		ToIncident()
 
 	inc07 := *NewBuilder(nil).
@@ -279,6 +281,25 @@ func TestGetIncidents(t *testing.T) {
 			},
 			ExpectedErr: nil,
 		},
+		{
+			Name: "team2 - sort by Severity, blank severities last - admin",
+			RequesterInfo: permissions.RequesterInfo{
+				UserID:  lucy.ID,
+				IsAdmin: true,
+			},
+			Options: incident.FilterOptions{
+				TeamID:    team2id,
+				Sort:      incident.SortBySeverity,
+				Direction: incident.DirectionAsc,
+			},
+			Want: incident.GetIncidentsResults{
+				TotalCount: 2,
+				PageCount:  1,
+				HasMore:    false,
+				Items:      []incident.Incident{inc06, inc07},
+			},
+			ExpectedErr: nil,
+		},
 		{
 			Name: "no paging, team3, sort by Name",
 			RequesterInfo: permissions.RequesterInfo{
@@ -632,6 +653,19 @@ func TestGetIncidents(t *testing.T) {
 			Want:        incident.GetIncidentsResults{},
 			ExpectedErr: errors.New("bad parameter 'owner_id': must be 26 characters or blank"),
 		},
+		{
+			Name: "bad severity",
+			RequesterInfo: permissions.RequesterInfo{
+				UserID:  lucy.ID,
+				IsAdmin: true,
+			},
+			Options: incident.FilterOptions{
+				TeamID:   team1id,
+				Severity: "SEV9",
+			},
+			Want:        incident.GetIncidentsResults{},
+			ExpectedErr: errors.New("bad parameter 'severity': must be a valid severity level or blank"),
+		},
 		{
 			Name: "team1 - desc - Bob (in all channels)",
 			RequesterInfo: permissions.RequesterInfo{
@@ -805,6 +839,24 @@ func TestGetIncidents(t *testing.T) {
 			},
 			ExpectedErr: nil,
 		},
+		{
+			Name: "team1 - severity SEV2 - admin",
+			RequesterInfo: permissions.RequesterInfo{
+				UserID:  lucy.ID,
+				IsAdmin: true,
+			},
+			Options: incident.FilterOptions{
+				TeamID:   team1id,
+				Severity: incident.SeverityHigh,
+			},
+			Want: incident.GetIncidentsResults{
+				TotalCount: 1,
+				PageCount:  1,
+				HasMore:    false,
+				Items:      []incident.Incident{inc02},
+			},
+			ExpectedErr: nil,
+		},
 	}
 
 	for _, driverName := range driverNames {
@@ -1291,6 +1343,88 @@ func TestGetIncidentIDForChannel(t *testing.T) {
 	}
 }
 
+func TestGetIncidentIDForAlert(t *testing.T) {
+	for _, driverName := range driverNames {
+		db := setupTestDB(t, driverName)
+		_, store := setupSQLStore(t, db)
+		incidentStore := setupIncidentStore(t, db)
+		setupChannelsTable(t, db)
+
+		playbookID := model.NewId()
+
+		t.Run("retrieve the ongoing incident for an alert", func(t *testing.T) {
+			resolved := NewBuilder(t).
+				WithPlaybookID(playbookID).
+				WithAlertFingerprint("fingerprint1").
+				WithCurrentStatus("Resolved").
+				ToIncident()
+			ongoing := NewBuilder(t).
+				WithPlaybookID(playbookID).
+				WithAlertFingerprint("fingerprint1").
+				ToIncident()
+			otherPlaybook := NewBuilder(t).
+				WithPlaybookID(model.NewId()).
+				WithAlertFingerprint("fingerprint1").
+				ToIncident()
+
+			for _, i := range []*incident.Incident{resolved, ongoing, otherPlaybook} {
+				_, err := incidentStore.CreateIncident(i)
+				require.NoError(t, err)
+				createIncidentChannel(t, store, i)
+			}
+
+			id, err := incidentStore.GetIncidentIDForAlert(playbookID, "fingerprint1")
+			require.NoError(t, err)
+			require.Equal(t, ongoing.ID, id)
+		})
+
+		t.Run("fail to retrieve an incident for an unknown alert", func(t *testing.T) {
+			id, err := incidentStore.GetIncidentIDForAlert(playbookID, "unknown")
+			require.ErrorIs(t, err, incident.ErrNotFound)
+			require.Equal(t, "", id)
+		})
+	}
+}
+
+func TestGetIncidentsHidesMerged(t *testing.T) {
+	for _, driverName := range driverNames {
+		db := setupTestDB(t, driverName)
+		_, store := setupSQLStore(t, db)
+		incidentStore := setupIncidentStore(t, db)
+		setupChannelsTable(t, db)
+		setupPostsTable(t, db)
+
+		teamID := model.NewId()
+		active := NewBuilder(t).WithTeamID(teamID).ToIncident()
+		merged := NewBuilder(t).WithTeamID(teamID).WithCurrentStatus(incident.StatusMerged).ToIncident()
+		for _, i := range []*incident.Incident{active, merged} {
+			_, err := incidentStore.CreateIncident(i)
+			require.NoError(t, err)
+			createIncidentChannel(t, store, i)
+		}
+
+		requesterInfo := permissions.RequesterInfo{UserID: "testID", IsAdmin: true}
+
+		t.Run("merged incidents are hidden by default", func(t *testing.T) {
+			result, err := incidentStore.GetIncidents(requesterInfo, incident.FilterOptions{TeamID: teamID})
+			require.NoError(t, err)
+			require.Equal(t, 1, result.TotalCount)
+			require.Len(t, result.Items, 1)
+			require.Equal(t, active.ID, result.Items[0].ID)
+		})
+
+		t.Run("merged incidents are listed when asked for", func(t *testing.T) {
+			result, err := incidentStore.GetIncidents(requesterInfo, incident.FilterOptions{
+				TeamID: teamID,
+				Status: incident.StatusMerged,
+			})
+			require.NoError(t, err)
+			require.Len(t, result.Items, 1)
+			require.Equal(t, merged.ID, result.Items[0].ID)
+		})
+	}
+}
+
 func TestGetOwners(t *testing.T) {
 	team1id := model.NewId()
 	team2id := model.NewId()
@@ -1400,6 +1534,7 @@ func TestGetOwners(t *testing.T) {
 		WithTeamID(team2id).
 		WithCreateAt(444).
 		WithChecklists([]int{4}).
+		WithSeverity(incident.SeverityHigh).
 		ToIncident()
 
 	inc07 := *NewBuilder(nil).
@@ -1846,9 +1981,140 @@ func (ib *IncidentBuilder) WithPlaybookID(id string) *IncidentBuilder {
 	return ib
 }
 
+func (ib *IncidentBuilder) WithSeverity(severity string) *IncidentBuilder {
+	ib.i.Severity = severity
+
+	return ib
+}
+
+func (ib *IncidentBuilder) WithAlertFingerprint(fingerprint string) *IncidentBuilder {
+	ib.i.AlertFingerprint = fingerprint
+
+	return ib
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
 	}
 	return b
 }
+
+func TestIncidentStore_CustomFieldValues(t *testing.T) {
+	for _, driverName := range driverNames {
+		db := setupTestDB(t, driverName)
+		iStore := setupIncidentStore(t, db)
+		_, store := setupSQLStore(t, db)
+		setupChannelsTable(t, db)
+		setupPostsTable(t, db)
+
+		getRows := func(t *testing.T, incidentID string) []string {
+			var rows []struct {
+				FieldKey string
+				Value    string
+			}
+			err := store.selectBuilder(store.db, &rows, store.builder.
+				Select("FieldKey", "Value").
+				From("IR_IncidentCustomFieldValue").
+				Where(sq.Eq{"IncidentID": incidentID}))
+			require.NoError(t, err)
+
+			values := []string{}
+			for _, row := range rows {
+				values = append(values, row.FieldKey+"="+row.Value)
+			}
+			return values
+		}
+
+		t.Run("only the rows of the changed fields are rewritten", func(t *testing.T) {
+			inc := NewBuilder(nil).
+				WithName("incident with custom fields").
+				ToIncident()
+			inc.CustomFieldValues = map[string][]string{
+				"env":    {"prod"},
+				"region": {"eu", "us"},
+			}
+
+			inc, err := iStore.CreateIncident(inc)
+			require.NoError(t, err)
+			createIncidentChannel(t, store, inc)
+			require.ElementsMatch(t, []string{"env=prod", "region=eu", "region=us"}, getRows(t, inc.ID))
+
+			inc.CustomFieldValues = map[string][]string{
+				"env":    {"staging"},
+				"region": {"us", "eu"},
+			}
+			err = iStore.UpdateIncident(inc)
+			require.NoError(t, err)
+			require.ElementsMatch(t, []string{"env=staging", "region=eu", "region=us"}, getRows(t, inc.ID))
+
+			inc.CustomFieldValues = map[string][]string{"region": {"us"}}
+			err = iStore.UpdateIncident(inc)
+			require.NoError(t, err)
+			require.Equal(t, []string{"region=us"}, getRows(t, inc.ID))
+		})
+	}
+}
+
+func TestSameValues(t *testing.T) {
+	require.True(t, sameValues(nil, []string{}))
+	require.True(t, sameValues([]string{"a", "b"}, []string{"b", "a"}))
+	require.False(t, sameValues([]string{"a", "a"}, []string{"a", "b"}))
+	require.False(t, sameValues([]string{"a"}, []string{"a", "b"}))
+}
+
+func TestIncidentStore_RoleAssignments(t *testing.T) {
+	for _, driverName := range driverNames {
+		db := setupTestDB(t, driverName)
+		iStore := setupIncidentStore(t, db)
+		_, store := setupSQLStore(t, db)
+		setupChannelsTable(t, db)
+		setupPostsTable(t, db)
+
+		getRows := func(t *testing.T, incidentID string) []string {
+			var rows []struct {
+				RoleKey string
+				UserID  string
+			}
+			err := store.selectBuilder(store.db, &rows, store.builder.
+				Select("RoleKey", "UserID").
+				From("IR_IncidentRoleAssignment").
+				Where(sq.Eq{"IncidentID": incidentID}))
+			require.NoError(t, err)
+
+			assignments := []string{}
+			for _, row := range rows {
+				assignments = append(assignments, row.RoleKey+"="+row.UserID)
+			}
+			return assignments
+		}
+
+		t.Run("only the rows of the changed roles are rewritten", func(t *testing.T) {
+			inc := NewBuilder(nil).
+				WithName("incident with roles").
+				ToIncident()
+			inc.RoleAssignments = map[string][]string{
+				"comms":  {"user1"},
+				"scribe": {"user2", "user3"},
+			}
+
+			inc, err := iStore.CreateIncident(inc)
+			require.NoError(t, err)
+			createIncidentChannel(t, store, inc)
+			require.ElementsMatch(t, []string{"comms=user1", "scribe=user2", "scribe=user3"}, getRows(t, inc.ID))
+
+			inc.RoleAssignments = map[string][]string{
+				"comms":  {"user4"},
+				"scribe": {"user3", "user2"},
+			}
+			err = iStore.UpdateIncident(inc)
+			require.NoError(t, err)
+			require.ElementsMatch(t, []string{"comms=user4", "scribe=user2", "scribe=user3"}, getRows(t, inc.ID))
+
+			inc.RoleAssignments = map[string][]string{"scribe": {"user3"}}
+			err = iStore.UpdateIncident(inc)
+			require.NoError(t, err)
+			require.Equal(t, []string{"scribe=user3"}, getRows(t, inc.ID))
+		})
+	}
+}
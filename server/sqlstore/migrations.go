@@ -830,6 +830,910 @@ var migrations = []Migration{
 				}
 			}
 
+			return nil
+		},
+	},
+	{
+		fromVersion: semver.MustParse("0.20.0"),
+		toVersion:   semver.MustParse("0.21.0"),
+		migrationFunc: func(e sqlx.Ext, sqlStore *SQLStore) error {
+			if e.DriverName() == model.DATABASE_DRIVER_MYSQL {
+				if err := addColumnToMySQLTable(e, "IR_Incident", "Severity", "TEXT"); err != nil {
+					return errors.Wrapf(err, "failed adding column Severity to table IR_Incident")
+				}
+				if _, err := e.Exec("UPDATE IR_Incident SET Severity = '' WHERE Severity IS NULL"); err != nil {
+					return errors.Wrapf(err, "failed setting default value in column Severity of table IR_Incident")
+				}
+			} else {
+				if err := addColumnToPGTable(e, "IR_Incident", "Severity", "TEXT DEFAULT ''"); err != nil {
+					return errors.Wrapf(err, "failed adding column Severity to table IR_Incident")
+				}
+			}
+
+			return nil
+		},
+	},
+	{
+		fromVersion: semver.MustParse("0.21.0"),
+		toVersion:   semver.MustParse("0.22.0"),
+		migrationFunc: func(e sqlx.Ext, sqlStore *SQLStore) error {
+			if e.DriverName() == model.DATABASE_DRIVER_MYSQL {
+				if err := addColumnToMySQLTable(e, "IR_Playbook", "WebhookSecret", "TEXT"); err != nil {
+					return errors.Wrapf(err, "failed adding column WebhookSecret to table IR_Playbook")
+				}
+				if _, err := e.Exec("UPDATE IR_Playbook SET WebhookSecret = '' WHERE WebhookSecret IS NULL"); err != nil {
+					return errors.Wrapf(err, "failed setting default value in column WebhookSecret of table IR_Playbook")
+				}
+
+				if err := addColumnToMySQLTable(e, "IR_Incident", "WebhookSecret", "TEXT"); err != nil {
+					return errors.Wrapf(err, "failed adding column WebhookSecret to table IR_Incident")
+				}
+				if _, err := e.Exec("UPDATE IR_Incident SET WebhookSecret = '' WHERE WebhookSecret IS NULL"); err != nil {
+					return errors.Wrapf(err, "failed setting default value in column WebhookSecret of table IR_Incident")
+				}
+
+				if _, err := e.Exec(`
+					CREATE TABLE IF NOT EXISTS IR_WebhookDelivery
+					(
+						ID            VARCHAR(26)  NOT NULL,
+						IncidentID    VARCHAR(26)  NOT NULL REFERENCES IR_Incident(ID),
+						EventType     VARCHAR(32)  NOT NULL DEFAULT '',
+						URL           TEXT         NOT NULL,
+						Payload       MEDIUMTEXT   NOT NULL,
+						Status        VARCHAR(32)  NOT NULL DEFAULT '',
+						NumAttempts   INT          NOT NULL DEFAULT 0,
+						NextAttemptAt BIGINT       NOT NULL DEFAULT 0,
+						CreateAt      BIGINT       NOT NULL,
+						UpdateAt      BIGINT       NOT NULL DEFAULT 0,
+						PRIMARY KEY (ID),
+						INDEX IR_WebhookDelivery_IncidentID (IncidentID)
+					)
+				` + MySQLCharset); err != nil {
+					return errors.Wrapf(err, "failed creating table IR_WebhookDelivery")
+				}
+
+				if _, err := e.Exec(`
+					CREATE TABLE IF NOT EXISTS IR_WebhookDeliveryAttempt
+					(
+						ID         VARCHAR(26)   NOT NULL,
+						DeliveryID VARCHAR(26)   NOT NULL REFERENCES IR_WebhookDelivery(ID),
+						IncidentID VARCHAR(26)   NOT NULL REFERENCES IR_Incident(ID),
+						Attempt    INT           NOT NULL DEFAULT 0,
+						StatusCode INT           NOT NULL DEFAULT 0,
+						LatencyMS  BIGINT        NOT NULL DEFAULT 0,
+						Error      VARCHAR(1024) NOT NULL DEFAULT '',
+						CreateAt   BIGINT        NOT NULL,
+						PRIMARY KEY (ID),
+						INDEX IR_WebhookDeliveryAttempt_DeliveryID (DeliveryID),
+						INDEX IR_WebhookDeliveryAttempt_IncidentID (IncidentID)
+					)
+				` + MySQLCharset); err != nil {
+					return errors.Wrapf(err, "failed creating table IR_WebhookDeliveryAttempt")
+				}
+			} else {
+				if err := addColumnToPGTable(e, "IR_Playbook", "WebhookSecret", "TEXT DEFAULT ''"); err != nil {
+					return errors.Wrapf(err, "failed adding column WebhookSecret to table IR_Playbook")
+				}
+
+				if err := addColumnToPGTable(e, "IR_Incident", "WebhookSecret", "TEXT DEFAULT ''"); err != nil {
+					return errors.Wrapf(err, "failed adding column WebhookSecret to table IR_Incident")
+				}
+
+				if _, err := e.Exec(`
+					CREATE TABLE IF NOT EXISTS IR_WebhookDelivery
+					(
+						ID            TEXT   PRIMARY KEY,
+						IncidentID    TEXT   NOT NULL REFERENCES IR_Incident(ID),
+						EventType     TEXT   NOT NULL DEFAULT '',
+						URL           TEXT   NOT NULL,
+						Payload       TEXT   NOT NULL,
+						Status        TEXT   NOT NULL DEFAULT '',
+						NumAttempts   BIGINT NOT NULL DEFAULT 0,
+						NextAttemptAt BIGINT NOT NULL DEFAULT 0,
+						CreateAt      BIGINT NOT NULL,
+						UpdateAt      BIGINT NOT NULL DEFAULT 0
+					)
+				`); err != nil {
+					return errors.Wrapf(err, "failed creating table IR_WebhookDelivery")
+				}
+
+				if _, err := e.Exec(`
+					CREATE TABLE IF NOT EXISTS IR_WebhookDeliveryAttempt
+					(
+						ID         TEXT   PRIMARY KEY,
+						DeliveryID TEXT   NOT NULL REFERENCES IR_WebhookDelivery(ID),
+						IncidentID TEXT   NOT NULL REFERENCES IR_Incident(ID),
+						Attempt    BIGINT NOT NULL DEFAULT 0,
+						StatusCode BIGINT NOT NULL DEFAULT 0,
+						LatencyMS  BIGINT NOT NULL DEFAULT 0,
+						Error      TEXT   NOT NULL DEFAULT '',
+						CreateAt   BIGINT NOT NULL
+					)
+				`); err != nil {
+					return errors.Wrapf(err, "failed creating table IR_WebhookDeliveryAttempt")
+				}
+
+				if _, err := e.Exec(createPGIndex("IR_WebhookDelivery_IncidentID", "IR_WebhookDelivery", "IncidentID")); err != nil {
+					return errors.Wrapf(err, "failed creating index IR_WebhookDelivery_IncidentID")
+				}
+				if _, err := e.Exec(createPGIndex("IR_WebhookDeliveryAttempt_DeliveryID", "IR_WebhookDeliveryAttempt", "DeliveryID")); err != nil {
+					return errors.Wrapf(err, "failed creating index IR_WebhookDeliveryAttempt_DeliveryID")
+				}
+				if _, err := e.Exec(createPGIndex("IR_WebhookDeliveryAttempt_IncidentID", "IR_WebhookDeliveryAttempt", "IncidentID")); err != nil {
+					return errors.Wrapf(err, "failed creating index IR_WebhookDeliveryAttempt_IncidentID")
+				}
+			}
+
+			return nil
+		},
+	},
+	{
+		fromVersion: semver.MustParse("0.22.0"),
+		toVersion:   semver.MustParse("0.23.0"),
+		migrationFunc: func(e sqlx.Ext, sqlStore *SQLStore) error {
+			if e.DriverName() == model.DATABASE_DRIVER_MYSQL {
+				if err := addColumnToMySQLTable(e, "IR_Playbook", "WebhookSubscriptionsJSON", "TEXT"); err != nil {
+					return errors.Wrapf(err, "failed adding column WebhookSubscriptionsJSON to table IR_Playbook")
+				}
+				if _, err := e.Exec("UPDATE IR_Playbook SET WebhookSubscriptionsJSON = '[]' WHERE WebhookSubscriptionsJSON IS NULL"); err != nil {
+					return errors.Wrapf(err, "failed setting default value in column WebhookSubscriptionsJSON of table IR_Playbook")
+				}
+
+				if err := addColumnToMySQLTable(e, "IR_Incident", "WebhookSubscriptionsJSON", "TEXT"); err != nil {
+					return errors.Wrapf(err, "failed adding column WebhookSubscriptionsJSON to table IR_Incident")
+				}
+				if _, err := e.Exec("UPDATE IR_Incident SET WebhookSubscriptionsJSON = '[]' WHERE WebhookSubscriptionsJSON IS NULL"); err != nil {
+					return errors.Wrapf(err, "failed setting default value in column WebhookSubscriptionsJSON of table IR_Incident")
+				}
+			} else {
+				if err := addColumnToPGTable(e, "IR_Playbook", "WebhookSubscriptionsJSON", "JSON DEFAULT '[]'"); err != nil {
+					return errors.Wrapf(err, "failed adding column WebhookSubscriptionsJSON to table IR_Playbook")
+				}
+
+				if err := addColumnToPGTable(e, "IR_Incident", "WebhookSubscriptionsJSON", "JSON DEFAULT '[]'"); err != nil {
+					return errors.Wrapf(err, "failed adding column WebhookSubscriptionsJSON to table IR_Incident")
+				}
+			}
+
+			return nil
+		},
+	},
+	{
+		fromVersion: semver.MustParse("0.23.0"),
+		toVersion:   semver.MustParse("0.24.0"),
+		migrationFunc: func(e sqlx.Ext, sqlStore *SQLStore) error {
+			if e.DriverName() == model.DATABASE_DRIVER_MYSQL {
+				if err := addColumnToMySQLTable(e, "IR_Playbook", "InboundWebhookEnabled", "BOOLEAN DEFAULT FALSE"); err != nil {
+					return errors.Wrapf(err, "failed adding column InboundWebhookEnabled to table IR_Playbook")
+				}
+
+				if err := addColumnToMySQLTable(e, "IR_Playbook", "InboundWebhookToken", "TEXT"); err != nil {
+					return errors.Wrapf(err, "failed adding column InboundWebhookToken to table IR_Playbook")
+				}
+				if _, err := e.Exec("UPDATE IR_Playbook SET InboundWebhookToken = '' WHERE InboundWebhookToken IS NULL"); err != nil {
+					return errors.Wrapf(err, "failed setting default value in column InboundWebhookToken of table IR_Playbook")
+				}
+
+				if err := addColumnToMySQLTable(e, "IR_Playbook", "InboundWebhookNameTemplate", "TEXT"); err != nil {
+					return errors.Wrapf(err, "failed adding column InboundWebhookNameTemplate to table IR_Playbook")
+				}
+				if _, err := e.Exec("UPDATE IR_Playbook SET InboundWebhookNameTemplate = '' WHERE InboundWebhookNameTemplate IS NULL"); err != nil {
+					return errors.Wrapf(err, "failed setting default value in column InboundWebhookNameTemplate of table IR_Playbook")
+				}
+
+				if err := addColumnToMySQLTable(e, "IR_Playbook", "InboundWebhookDescriptionTemplate", "TEXT"); err != nil {
+					return errors.Wrapf(err, "failed adding column InboundWebhookDescriptionTemplate to table IR_Playbook")
+				}
+				if _, err := e.Exec("UPDATE IR_Playbook SET InboundWebhookDescriptionTemplate = '' WHERE InboundWebhookDescriptionTemplate IS NULL"); err != nil {
+					return errors.Wrapf(err, "failed setting default value in column InboundWebhookDescriptionTemplate of table IR_Playbook")
+				}
+
+				if err := addColumnToMySQLTable(e, "IR_Playbook", "InboundWebhookOwnerTemplate", "TEXT"); err != nil {
+					return errors.Wrapf(err, "failed adding column InboundWebhookOwnerTemplate to table IR_Playbook")
+				}
+				if _, err := e.Exec("UPDATE IR_Playbook SET InboundWebhookOwnerTemplate = '' WHERE InboundWebhookOwnerTemplate IS NULL"); err != nil {
+					return errors.Wrapf(err, "failed setting default value in column InboundWebhookOwnerTemplate of table IR_Playbook")
+				}
+
+				if err := addColumnToMySQLTable(e, "IR_Playbook", "InboundWebhookFingerprintTemplate", "TEXT"); err != nil {
+					return errors.Wrapf(err, "failed adding column InboundWebhookFingerprintTemplate to table IR_Playbook")
+				}
+				if _, err := e.Exec("UPDATE IR_Playbook SET InboundWebhookFingerprintTemplate = '' WHERE InboundWebhookFingerprintTemplate IS NULL"); err != nil {
+					return errors.Wrapf(err, "failed setting default value in column InboundWebhookFingerprintTemplate of table IR_Playbook")
+				}
+
+				if err := addColumnToMySQLTable(e, "IR_Playbook", "InboundWebhookAutoResolve", "BOOLEAN DEFAULT FALSE"); err != nil {
+					return errors.Wrapf(err, "failed adding column InboundWebhookAutoResolve to table IR_Playbook")
+				}
+
+				if err := addColumnToMySQLTable(e, "IR_Incident", "AlertFingerprint", "TEXT"); err != nil {
+					return errors.Wrapf(err, "failed adding column AlertFingerprint to table IR_Incident")
+				}
+				if _, err := e.Exec("UPDATE IR_Incident SET AlertFingerprint = '' WHERE AlertFingerprint IS NULL"); err != nil {
+					return errors.Wrapf(err, "failed setting default value in column AlertFingerprint of table IR_Incident")
+				}
+			} else {
+				if err := addColumnToPGTable(e, "IR_Playbook", "InboundWebhookEnabled", "BOOLEAN DEFAULT FALSE"); err != nil {
+					return errors.Wrapf(err, "failed adding column InboundWebhookEnabled to table IR_Playbook")
+				}
+
+				if err := addColumnToPGTable(e, "IR_Playbook", "InboundWebhookToken", "TEXT DEFAULT ''"); err != nil {
+					return errors.Wrapf(err, "failed adding column InboundWebhookToken to table IR_Playbook")
+				}
+
+				if err := addColumnToPGTable(e, "IR_Playbook", "InboundWebhookNameTemplate", "TEXT DEFAULT ''"); err != nil {
+					return errors.Wrapf(err, "failed adding column InboundWebhookNameTemplate to table IR_Playbook")
+				}
+
+				if err := addColumnToPGTable(e, "IR_Playbook", "InboundWebhookDescriptionTemplate", "TEXT DEFAULT ''"); err != nil {
+					return errors.Wrapf(err, "failed adding column InboundWebhookDescriptionTemplate to table IR_Playbook")
+				}
+
+				if err := addColumnToPGTable(e, "IR_Playbook", "InboundWebhookOwnerTemplate", "TEXT DEFAULT ''"); err != nil {
+					return errors.Wrapf(err, "failed adding column InboundWebhookOwnerTemplate to table IR_Playbook")
+				}
+
+				if err := addColumnToPGTable(e, "IR_Playbook", "InboundWebhookFingerprintTemplate", "TEXT DEFAULT ''"); err != nil {
+					return errors.Wrapf(err, "failed adding column InboundWebhookFingerprintTemplate to table IR_Playbook")
+				}
+
+				if err := addColumnToPGTable(e, "IR_Playbook", "InboundWebhookAutoResolve", "BOOLEAN DEFAULT FALSE"); err != nil {
+					return errors.Wrapf(err, "failed adding column InboundWebhookAutoResolve to table IR_Playbook")
+				}
+
+				if err := addColumnToPGTable(e, "IR_Incident", "AlertFingerprint", "TEXT DEFAULT ''"); err != nil {
+					return errors.Wrapf(err, "failed adding column AlertFingerprint to table IR_Incident")
+				}
+			}
+
+			return nil
+		},
+	},
+	{
+		fromVersion: semver.MustParse("0.24.0"),
+		toVersion:   semver.MustParse("0.25.0"),
+		migrationFunc: func(e sqlx.Ext, sqlStore *SQLStore) error {
+			if e.DriverName() == model.DATABASE_DRIVER_MYSQL {
+				if err := addColumnToMySQLTable(e, "IR_Playbook", "Revision", "INT NOT NULL DEFAULT 0"); err != nil {
+					return errors.Wrapf(err, "failed adding column Revision to table IR_Playbook")
+				}
+
+				if err := addColumnToMySQLTable(e, "IR_Incident", "PlaybookRevision", "INT NOT NULL DEFAULT 0"); err != nil {
+					return errors.Wrapf(err, "failed adding column PlaybookRevision to table IR_Incident")
+				}
+
+				if _, err := e.Exec(`
+					CREATE TABLE IF NOT EXISTS IR_PlaybookRevision
+					(
+						ID           VARCHAR(26) NOT NULL,
+						PlaybookID   VARCHAR(26) NOT NULL REFERENCES IR_Playbook(ID),
+						Revision     INT         NOT NULL,
+						UserID       VARCHAR(26) NOT NULL DEFAULT '',
+						CreateAt     BIGINT      NOT NULL,
+						PlaybookJSON MEDIUMTEXT  NOT NULL,
+						PRIMARY KEY (ID),
+						UNIQUE INDEX IR_PlaybookRevision_PlaybookID_Revision (PlaybookID, Revision)
+					)
+				` + MySQLCharset); err != nil {
+					return errors.Wrapf(err, "failed creating table IR_PlaybookRevision")
+				}
+			} else {
+				if err := addColumnToPGTable(e, "IR_Playbook", "Revision", "INT NOT NULL DEFAULT 0"); err != nil {
+					return errors.Wrapf(err, "failed adding column Revision to table IR_Playbook")
+				}
+
+				if err := addColumnToPGTable(e, "IR_Incident", "PlaybookRevision", "INT NOT NULL DEFAULT 0"); err != nil {
+					return errors.Wrapf(err, "failed adding column PlaybookRevision to table IR_Incident")
+				}
+
+				if _, err := e.Exec(`
+					CREATE TABLE IF NOT EXISTS IR_PlaybookRevision
+					(
+						ID           TEXT   PRIMARY KEY,
+						PlaybookID   TEXT   NOT NULL REFERENCES IR_Playbook(ID),
+						Revision     INT    NOT NULL,
+						UserID       TEXT   NOT NULL DEFAULT '',
+						CreateAt     BIGINT NOT NULL,
+						PlaybookJSON JSON   NOT NULL,
+						UNIQUE (PlaybookID, Revision)
+					)
+				`); err != nil {
+					return errors.Wrapf(err, "failed creating table IR_PlaybookRevision")
+				}
+			}
+
+			return nil
+		},
+	},
+	{
+		fromVersion: semver.MustParse("0.25.0"),
+		toVersion:   semver.MustParse("0.26.0"),
+		migrationFunc: func(e sqlx.Ext, sqlStore *SQLStore) error {
+			if e.DriverName() == model.DATABASE_DRIVER_MYSQL {
+				if err := addColumnToMySQLTable(e, "IR_Playbook", "CustomFieldsJSON", "TEXT"); err != nil {
+					return errors.Wrapf(err, "failed adding column CustomFieldsJSON to table IR_Playbook")
+				}
+				if _, err := e.Exec("UPDATE IR_Playbook SET CustomFieldsJSON = '[]' WHERE CustomFieldsJSON IS NULL"); err != nil {
+					return errors.Wrapf(err, "failed setting default value in column CustomFieldsJSON of table IR_Playbook")
+				}
+
+				if err := addColumnToMySQLTable(e, "IR_Incident", "CustomFieldsJSON", "TEXT"); err != nil {
+					return errors.Wrapf(err, "failed adding column CustomFieldsJSON to table IR_Incident")
+				}
+				if _, err := e.Exec("UPDATE IR_Incident SET CustomFieldsJSON = '[]' WHERE CustomFieldsJSON IS NULL"); err != nil {
+					return errors.Wrapf(err, "failed setting default value in column CustomFieldsJSON of table IR_Incident")
+				}
+
+				if err := addColumnToMySQLTable(e, "IR_Incident", "CustomFieldValuesJSON", "TEXT"); err != nil {
+					return errors.Wrapf(err, "failed adding column CustomFieldValuesJSON to table IR_Incident")
+				}
+				if _, err := e.Exec("UPDATE IR_Incident SET CustomFieldValuesJSON = '{}' WHERE CustomFieldValuesJSON IS NULL"); err != nil {
+					return errors.Wrapf(err, "failed setting default value in column CustomFieldValuesJSON of table IR_Incident")
+				}
+
+				if _, err := e.Exec(`
+					CREATE TABLE IF NOT EXISTS IR_IncidentCustomFieldValue
+					(
+						IncidentID VARCHAR(26)  NOT NULL REFERENCES IR_Incident(ID),
+						FieldKey   VARCHAR(32)  NOT NULL,
+						Value      VARCHAR(512) NOT NULL,
+						INDEX IR_IncidentCustomFieldValue_IncidentID (IncidentID),
+						INDEX IR_IncidentCustomFieldValue_FieldKey_Value (FieldKey, Value)
+					)
+				` + MySQLCharset); err != nil {
+					return errors.Wrapf(err, "failed creating table IR_IncidentCustomFieldValue")
+				}
+			} else {
+				if err := addColumnToPGTable(e, "IR_Playbook", "CustomFieldsJSON", "JSON DEFAULT '[]'"); err != nil {
+					return errors.Wrapf(err, "failed adding column CustomFieldsJSON to table IR_Playbook")
+				}
+
+				if err := addColumnToPGTable(e, "IR_Incident", "CustomFieldsJSON", "JSON DEFAULT '[]'"); err != nil {
+					return errors.Wrapf(err, "failed adding column CustomFieldsJSON to table IR_Incident")
+				}
+
+				if err := addColumnToPGTable(e, "IR_Incident", "CustomFieldValuesJSON", "JSON DEFAULT '{}'"); err != nil {
+					return errors.Wrapf(err, "failed adding column CustomFieldValuesJSON to table IR_Incident")
+				}
+
+				if _, err := e.Exec(`
+					CREATE TABLE IF NOT EXISTS IR_IncidentCustomFieldValue
+					(
+						IncidentID TEXT NOT NULL REFERENCES IR_Incident(ID),
+						FieldKey   TEXT NOT NULL,
+						Value      TEXT NOT NULL
+					)
+				`); err != nil {
+					return errors.Wrapf(err, "failed creating table IR_IncidentCustomFieldValue")
+				}
+
+				if _, err := e.Exec(createPGIndex("IR_IncidentCustomFieldValue_IncidentID", "IR_IncidentCustomFieldValue", "IncidentID")); err != nil {
+					return errors.Wrapf(err, "failed creating index IR_IncidentCustomFieldValue_IncidentID")
+				}
+
+				if _, err := e.Exec(createPGIndex("IR_IncidentCustomFieldValue_FieldKey_Value", "IR_IncidentCustomFieldValue", "FieldKey, Value")); err != nil {
+					return errors.Wrapf(err, "failed creating index IR_IncidentCustomFieldValue_FieldKey_Value")
+				}
+			}
+
+			return nil
+		},
+	},
+	{
+		fromVersion: semver.MustParse("0.26.0"),
+		toVersion:   semver.MustParse("0.27.0"),
+		migrationFunc: func(e sqlx.Ext, sqlStore *SQLStore) error {
+			if e.DriverName() == model.DATABASE_DRIVER_MYSQL {
+				if err := addColumnToMySQLTable(e, "IR_Playbook", "RolesJSON", "TEXT"); err != nil {
+					return errors.Wrapf(err, "failed adding column RolesJSON to table IR_Playbook")
+				}
+				if _, err := e.Exec("UPDATE IR_Playbook SET RolesJSON = '[]' WHERE RolesJSON IS NULL"); err != nil {
+					return errors.Wrapf(err, "failed setting default value in column RolesJSON of table IR_Playbook")
+				}
+
+				if err := addColumnToMySQLTable(e, "IR_Incident", "RolesJSON", "TEXT"); err != nil {
+					return errors.Wrapf(err, "failed adding column RolesJSON to table IR_Incident")
+				}
+				if _, err := e.Exec("UPDATE IR_Incident SET RolesJSON = '[]' WHERE RolesJSON IS NULL"); err != nil {
+					return errors.Wrapf(err, "failed setting default value in column RolesJSON of table IR_Incident")
+				}
+
+				if err := addColumnToMySQLTable(e, "IR_Incident", "RoleAssignmentsJSON", "TEXT"); err != nil {
+					return errors.Wrapf(err, "failed adding column RoleAssignmentsJSON to table IR_Incident")
+				}
+				if _, err := e.Exec("UPDATE IR_Incident SET RoleAssignmentsJSON = '{}' WHERE RoleAssignmentsJSON IS NULL"); err != nil {
+					return errors.Wrapf(err, "failed setting default value in column RoleAssignmentsJSON of table IR_Incident")
+				}
+
+				if _, err := e.Exec(`
+					CREATE TABLE IF NOT EXISTS IR_IncidentRoleAssignment
+					(
+						IncidentID VARCHAR(26) NOT NULL REFERENCES IR_Incident(ID),
+						RoleKey    VARCHAR(32) NOT NULL,
+						UserID     VARCHAR(26) NOT NULL,
+						INDEX IR_IncidentRoleAssignment_IncidentID (IncidentID),
+						INDEX IR_IncidentRoleAssignment_RoleKey_UserID (RoleKey, UserID)
+					)
+				` + MySQLCharset); err != nil {
+					return errors.Wrapf(err, "failed creating table IR_IncidentRoleAssignment")
+				}
+			} else {
+				if err := addColumnToPGTable(e, "IR_Playbook", "RolesJSON", "JSON DEFAULT '[]'"); err != nil {
+					return errors.Wrapf(err, "failed adding column RolesJSON to table IR_Playbook")
+				}
+
+				if err := addColumnToPGTable(e, "IR_Incident", "RolesJSON", "JSON DEFAULT '[]'"); err != nil {
+					return errors.Wrapf(err, "failed adding column RolesJSON to table IR_Incident")
+				}
+
+				if err := addColumnToPGTable(e, "IR_Incident", "RoleAssignmentsJSON", "JSON DEFAULT '{}'"); err != nil {
+					return errors.Wrapf(err, "failed adding column RoleAssignmentsJSON to table IR_Incident")
+				}
+
+				if _, err := e.Exec(`
+					CREATE TABLE IF NOT EXISTS IR_IncidentRoleAssignment
+					(
+						IncidentID TEXT NOT NULL REFERENCES IR_Incident(ID),
+						RoleKey    TEXT NOT NULL,
+						UserID     TEXT NOT NULL
+					)
+				`); err != nil {
+					return errors.Wrapf(err, "failed creating table IR_IncidentRoleAssignment")
+				}
+
+				if _, err := e.Exec(createPGIndex("IR_IncidentRoleAssignment_IncidentID", "IR_IncidentRoleAssignment", "IncidentID")); err != nil {
+					return errors.Wrapf(err, "failed creating index IR_IncidentRoleAssignment_IncidentID")
+				}
+
+				if _, err := e.Exec(createPGIndex("IR_IncidentRoleAssignment_RoleKey_UserID", "IR_IncidentRoleAssignment", "RoleKey, UserID")); err != nil {
+					return errors.Wrapf(err, "failed creating index IR_IncidentRoleAssignment_RoleKey_UserID")
+				}
+			}
+
+			return nil
+		},
+	},
+	{
+		fromVersion: semver.MustParse("0.27.0"),
+		toVersion:   semver.MustParse("0.28.0"),
+		migrationFunc: func(e sqlx.Ext, sqlStore *SQLStore) error {
+			if e.DriverName() == model.DATABASE_DRIVER_MYSQL {
+				if err := addColumnToMySQLTable(e, "IR_Playbook", "DefaultOwnerRotationID", "VARCHAR(26) DEFAULT ''"); err != nil {
+					return errors.Wrapf(err, "failed adding column DefaultOwnerRotationID to table IR_Playbook")
+				}
+
+				if _, err := e.Exec(`
+					CREATE TABLE IF NOT EXISTS IR_OnCallRotation
+					(
+						ID            VARCHAR(26)  NOT NULL,
+						TeamID        VARCHAR(26)  NOT NULL,
+						Name          VARCHAR(512) NOT NULL,
+						Description   TEXT         NOT NULL,
+						LayersJSON    TEXT         NOT NULL,
+						OverridesJSON TEXT         NOT NULL,
+						CreateAt      BIGINT       NOT NULL,
+						UpdateAt      BIGINT       NOT NULL DEFAULT 0,
+						DeleteAt      BIGINT       NOT NULL DEFAULT 0,
+						PRIMARY KEY (ID),
+						INDEX IR_OnCallRotation_TeamID (TeamID)
+					)
+				` + MySQLCharset); err != nil {
+					return errors.Wrapf(err, "failed creating table IR_OnCallRotation")
+				}
+			} else {
+				if err := addColumnToPGTable(e, "IR_Playbook", "DefaultOwnerRotationID", "TEXT DEFAULT ''"); err != nil {
+					return errors.Wrapf(err, "failed adding column DefaultOwnerRotationID to table IR_Playbook")
+				}
+
+				if _, err := e.Exec(`
+					CREATE TABLE IF NOT EXISTS IR_OnCallRotation
+					(
+						ID            TEXT   PRIMARY KEY,
+						TeamID        TEXT   NOT NULL,
+						Name          TEXT   NOT NULL,
+						Description   TEXT   NOT NULL,
+						LayersJSON    JSON   NOT NULL,
+						OverridesJSON JSON   NOT NULL,
+						CreateAt      BIGINT NOT NULL,
+						UpdateAt      BIGINT NOT NULL DEFAULT 0,
+						DeleteAt      BIGINT NOT NULL DEFAULT 0
+					)
+				`); err != nil {
+					return errors.Wrapf(err, "failed creating table IR_OnCallRotation")
+				}
+
+				if _, err := e.Exec(createPGIndex("IR_OnCallRotation_TeamID", "IR_OnCallRotation", "TeamID")); err != nil {
+					return errors.Wrapf(err, "failed creating index IR_OnCallRotation_TeamID")
+				}
+			}
+
+			return nil
+		},
+	},
+	{
+		fromVersion: semver.MustParse("0.28.0"),
+		toVersion:   semver.MustParse("0.29.0"),
+		migrationFunc: func(e sqlx.Ext, sqlStore *SQLStore) error {
+			if e.DriverName() == model.DATABASE_DRIVER_MYSQL {
+				for _, table := range []string{"IR_Playbook", "IR_Incident"} {
+					if err := addColumnToMySQLTable(e, table, "AcknowledgeTimeoutSeconds", "BIGINT NOT NULL DEFAULT 0"); err != nil {
+						return errors.Wrapf(err, "failed adding column AcknowledgeTimeoutSeconds to table %s", table)
+					}
+
+					if err := addColumnToMySQLTable(e, table, "EscalationStepsJSON", "TEXT"); err != nil {
+						return errors.Wrapf(err, "failed adding column EscalationStepsJSON to table %s", table)
+					}
+					if _, err := e.Exec("UPDATE " + table + " SET EscalationStepsJSON = '[]' WHERE EscalationStepsJSON IS NULL"); err != nil {
+						return errors.Wrapf(err, "failed setting default value in column EscalationStepsJSON of table %s", table)
+					}
+				}
+
+				if err := addColumnToMySQLTable(e, "IR_Incident", "AcknowledgedAt", "BIGINT NOT NULL DEFAULT 0"); err != nil {
+					return errors.Wrapf(err, "failed adding column AcknowledgedAt to table IR_Incident")
+				}
+
+				if err := addColumnToMySQLTable(e, "IR_Incident", "EscalationLevel", "INT NOT NULL DEFAULT 0"); err != nil {
+					return errors.Wrapf(err, "failed adding column EscalationLevel to table IR_Incident")
+				}
+			} else {
+				for _, table := range []string{"IR_Playbook", "IR_Incident"} {
+					if err := addColumnToPGTable(e, table, "AcknowledgeTimeoutSeconds", "BIGINT NOT NULL DEFAULT 0"); err != nil {
+						return errors.Wrapf(err, "failed adding column AcknowledgeTimeoutSeconds to table %s", table)
+					}
+
+					if err := addColumnToPGTable(e, table, "EscalationStepsJSON", "JSON DEFAULT '[]'"); err != nil {
+						return errors.Wrapf(err, "failed adding column EscalationStepsJSON to table %s", table)
+					}
+				}
+
+				if err := addColumnToPGTable(e, "IR_Incident", "AcknowledgedAt", "BIGINT NOT NULL DEFAULT 0"); err != nil {
+					return errors.Wrapf(err, "failed adding column AcknowledgedAt to table IR_Incident")
+				}
+
+				if err := addColumnToPGTable(e, "IR_Incident", "EscalationLevel", "INT NOT NULL DEFAULT 0"); err != nil {
+					return errors.Wrapf(err, "failed adding column EscalationLevel to table IR_Incident")
+				}
+			}
+
+			return nil
+		},
+	},
+	{
+		fromVersion: semver.MustParse("0.29.0"),
+		toVersion:   semver.MustParse("0.30.0"),
+		migrationFunc: func(e sqlx.Ext, sqlStore *SQLStore) error {
+			if e.DriverName() == model.DATABASE_DRIVER_MYSQL {
+				if _, err := e.Exec(`
+					CREATE TABLE IF NOT EXISTS IR_IncidentLink
+					(
+						ID               VARCHAR(26) NOT NULL,
+						IncidentID       VARCHAR(26) NOT NULL,
+						LinkedIncidentID VARCHAR(26) NOT NULL,
+						Type             VARCHAR(32) NOT NULL,
+						CreatorUserID    VARCHAR(26) NOT NULL,
+						CreateAt         BIGINT      NOT NULL,
+						PRIMARY KEY (ID),
+						INDEX IR_IncidentLink_IncidentID (IncidentID),
+						INDEX IR_IncidentLink_LinkedIncidentID (LinkedIncidentID)
+					)
+				` + MySQLCharset); err != nil {
+					return errors.Wrapf(err, "failed creating table IR_IncidentLink")
+				}
+			} else {
+				if _, err := e.Exec(`
+					CREATE TABLE IF NOT EXISTS IR_IncidentLink
+					(
+						ID               TEXT   PRIMARY KEY,
+						IncidentID       TEXT   NOT NULL,
+						LinkedIncidentID TEXT   NOT NULL,
+						Type             TEXT   NOT NULL,
+						CreatorUserID    TEXT   NOT NULL,
+						CreateAt         BIGINT NOT NULL
+					)
+				`); err != nil {
+					return errors.Wrapf(err, "failed creating table IR_IncidentLink")
+				}
+
+				if _, err := e.Exec(createPGIndex("IR_IncidentLink_IncidentID", "IR_IncidentLink", "IncidentID")); err != nil {
+					return errors.Wrapf(err, "failed creating index IR_IncidentLink_IncidentID")
+				}
+
+				if _, err := e.Exec(createPGIndex("IR_IncidentLink_LinkedIncidentID", "IR_IncidentLink", "LinkedIncidentID")); err != nil {
+					return errors.Wrapf(err, "failed creating index IR_IncidentLink_LinkedIncidentID")
+				}
+			}
+
+			return nil
+		},
+	},
+	{
+		fromVersion: semver.MustParse("0.30.0"),
+		toVersion:   semver.MustParse("0.31.0"),
+		migrationFunc: func(e sqlx.Ext, sqlStore *SQLStore) error {
+			if e.DriverName() == model.DATABASE_DRIVER_MYSQL {
+				if err := addColumnToMySQLTable(e, "IR_Incident", "MergedIntoID", "VARCHAR(26) DEFAULT ''"); err != nil {
+					return errors.Wrapf(err, "failed adding column MergedIntoID to table IR_Incident")
+				}
+			} else {
+				if err := addColumnToPGTable(e, "IR_Incident", "MergedIntoID", "TEXT DEFAULT ''"); err != nil {
+					return errors.Wrapf(err, "failed adding column MergedIntoID to table IR_Incident")
+				}
+			}
+
+			return nil
+		},
+	},
+	{
+		fromVersion: semver.MustParse("0.31.0"),
+		toVersion:   semver.MustParse("0.32.0"),
+		migrationFunc: func(e sqlx.Ext, sqlStore *SQLStore) error {
+			if e.DriverName() == model.DATABASE_DRIVER_MYSQL {
+				for _, table := range []string{"IR_Playbook", "IR_Incident"} {
+					if err := addColumnToMySQLTable(e, table, "StatusUpdateTemplate", "TEXT"); err != nil {
+						return errors.Wrapf(err, "failed adding column StatusUpdateTemplate to table %s", table)
+					}
+					if _, err := e.Exec("UPDATE " + table + " SET StatusUpdateTemplate = '' WHERE StatusUpdateTemplate IS NULL"); err != nil {
+						return errors.Wrapf(err, "failed setting default value in column StatusUpdateTemplate of table %s", table)
+					}
+				}
+			} else {
+				for _, table := range []string{"IR_Playbook", "IR_Incident"} {
+					if err := addColumnToPGTable(e, table, "StatusUpdateTemplate", "TEXT DEFAULT ''"); err != nil {
+						return errors.Wrapf(err, "failed adding column StatusUpdateTemplate to table %s", table)
+					}
+				}
+			}
+
+			return nil
+		},
+	},
+	{
+		fromVersion: semver.MustParse("0.32.0"),
+		toVersion:   semver.MustParse("0.33.0"),
+		migrationFunc: func(e sqlx.Ext, sqlStore *SQLStore) error {
+			if e.DriverName() == model.DATABASE_DRIVER_MYSQL {
+				for _, table := range []string{"IR_Playbook", "IR_Incident"} {
+					if err := addColumnToMySQLTable(e, table, "BroadcastTargetsJSON", "TEXT"); err != nil {
+						return errors.Wrapf(err, "failed adding column BroadcastTargetsJSON to table %s", table)
+					}
+					if _, err := e.Exec("UPDATE " + table + " SET BroadcastTargetsJSON = '[]' WHERE BroadcastTargetsJSON IS NULL"); err != nil {
+						return errors.Wrapf(err, "failed setting default value in column BroadcastTargetsJSON of table %s", table)
+					}
+				}
+			} else {
+				for _, table := range []string{"IR_Playbook", "IR_Incident"} {
+					if err := addColumnToPGTable(e, table, "BroadcastTargetsJSON", "JSON DEFAULT '[]'"); err != nil {
+						return errors.Wrapf(err, "failed adding column BroadcastTargetsJSON to table %s", table)
+					}
+				}
+			}
+
+			return nil
+		},
+	},
+	{
+		fromVersion: semver.MustParse("0.33.0"),
+		toVersion:   semver.MustParse("0.34.0"),
+		migrationFunc: func(e sqlx.Ext, sqlStore *SQLStore) error {
+			if e.DriverName() == model.DATABASE_DRIVER_MYSQL {
+				if _, err := e.Exec(`
+					CREATE TABLE IF NOT EXISTS IR_IncidentFollower
+					(
+						IncidentID VARCHAR(26) NOT NULL,
+						UserID     VARCHAR(26) NOT NULL,
+						CreateAt   BIGINT      NOT NULL,
+						PRIMARY KEY (IncidentID, UserID)
+					)
+				` + MySQLCharset); err != nil {
+					return errors.Wrapf(err, "failed creating table IR_IncidentFollower")
+				}
+
+				if err := addColumnToMySQLTable(e, "IR_Playbook", "ConcatenatedAutoFollowerIDs", "TEXT"); err != nil {
+					return errors.Wrapf(err, "failed adding column ConcatenatedAutoFollowerIDs to table IR_Playbook")
+				}
+				if _, err := e.Exec("UPDATE IR_Playbook SET ConcatenatedAutoFollowerIDs = '' WHERE ConcatenatedAutoFollowerIDs IS NULL"); err != nil {
+					return errors.Wrapf(err, "failed setting default value in column ConcatenatedAutoFollowerIDs of table IR_Playbook")
+				}
+			} else {
+				if _, err := e.Exec(`
+					CREATE TABLE IF NOT EXISTS IR_IncidentFollower
+					(
+						IncidentID TEXT   NOT NULL,
+						UserID     TEXT   NOT NULL,
+						CreateAt   BIGINT NOT NULL,
+						PRIMARY KEY (IncidentID, UserID)
+					)
+				`); err != nil {
+					return errors.Wrapf(err, "failed creating table IR_IncidentFollower")
+				}
+
+				if err := addColumnToPGTable(e, "IR_Playbook", "ConcatenatedAutoFollowerIDs", "TEXT DEFAULT ''"); err != nil {
+					return errors.Wrapf(err, "failed adding column ConcatenatedAutoFollowerIDs to table IR_Playbook")
+				}
+			}
+
+			return nil
+		},
+	},
+	{
+		fromVersion: semver.MustParse("0.34.0"),
+		toVersion:   semver.MustParse("0.35.0"),
+		migrationFunc: func(e sqlx.Ext, sqlStore *SQLStore) error {
+			if e.DriverName() == model.DATABASE_DRIVER_MYSQL {
+				if _, err := e.Exec(`
+					CREATE TABLE IF NOT EXISTS IR_ActionItem
+					(
+						ID              VARCHAR(26) NOT NULL,
+						IncidentID      VARCHAR(26) NOT NULL,
+						Title           TEXT        NOT NULL,
+						OwnerUserID     VARCHAR(26) NOT NULL DEFAULT '',
+						DueAt           BIGINT      NOT NULL DEFAULT 0,
+						CompletedAt     BIGINT      NOT NULL DEFAULT 0,
+						OverdueNotified BOOLEAN     NOT NULL DEFAULT FALSE,
+						CreatorUserID   VARCHAR(26) NOT NULL,
+						CreateAt        BIGINT      NOT NULL,
+						UpdateAt        BIGINT      NOT NULL,
+						PRIMARY KEY (ID),
+						INDEX IR_ActionItem_IncidentID (IncidentID),
+						INDEX IR_ActionItem_OwnerUserID (OwnerUserID)
+					)
+				` + MySQLCharset); err != nil {
+					return errors.Wrapf(err, "failed creating table IR_ActionItem")
+				}
+
+				if err := addColumnToMySQLTable(e, "IR_Incident", "RetrospectiveSectionsJSON", "TEXT"); err != nil {
+					return errors.Wrapf(err, "failed adding column RetrospectiveSectionsJSON to table IR_Incident")
+				}
+				if _, err := e.Exec("UPDATE IR_Incident SET RetrospectiveSectionsJSON = '{}' WHERE RetrospectiveSectionsJSON IS NULL"); err != nil {
+					return errors.Wrapf(err, "failed setting default value in column RetrospectiveSectionsJSON of table IR_Incident")
+				}
+			} else {
+				if _, err := e.Exec(`
+					CREATE TABLE IF NOT EXISTS IR_ActionItem
+					(
+						ID              TEXT    PRIMARY KEY,
+						IncidentID      TEXT    NOT NULL,
+						Title           TEXT    NOT NULL,
+						OwnerUserID     TEXT    NOT NULL DEFAULT '',
+						DueAt           BIGINT  NOT NULL DEFAULT 0,
+						CompletedAt     BIGINT  NOT NULL DEFAULT 0,
+						OverdueNotified BOOLEAN NOT NULL DEFAULT FALSE,
+						CreatorUserID   TEXT    NOT NULL,
+						CreateAt        BIGINT  NOT NULL,
+						UpdateAt        BIGINT  NOT NULL
+					)
+				`); err != nil {
+					return errors.Wrapf(err, "failed creating table IR_ActionItem")
+				}
+
+				if _, err := e.Exec(createPGIndex("IR_ActionItem_IncidentID", "IR_ActionItem", "IncidentID")); err != nil {
+					return errors.Wrapf(err, "failed creating index IR_ActionItem_IncidentID")
+				}
+
+				if _, err := e.Exec(createPGIndex("IR_ActionItem_OwnerUserID", "IR_ActionItem", "OwnerUserID")); err != nil {
+					return errors.Wrapf(err, "failed creating index IR_ActionItem_OwnerUserID")
+				}
+
+				if err := addColumnToPGTable(e, "IR_Incident", "RetrospectiveSectionsJSON", "JSON DEFAULT '{}'"); err != nil {
+					return errors.Wrapf(err, "failed adding column RetrospectiveSectionsJSON to table IR_Incident")
+				}
+			}
+
+			return nil
+		},
+	},
+	{
+		fromVersion: semver.MustParse("0.35.0"),
+		toVersion:   semver.MustParse("0.36.0"),
+		migrationFunc: func(e sqlx.Ext, sqlStore *SQLStore) error {
+			if e.DriverName() == model.DATABASE_DRIVER_MYSQL {
+				if err := addColumnToMySQLTable(e, "IR_Incident", "RetrospectiveTemplate", "TEXT"); err != nil {
+					return errors.Wrapf(err, "failed adding column RetrospectiveTemplate to table IR_Incident")
+				}
+				if _, err := e.Exec("UPDATE IR_Incident SET RetrospectiveTemplate = '' WHERE RetrospectiveTemplate IS NULL"); err != nil {
+					return errors.Wrapf(err, "failed setting default value in column RetrospectiveTemplate of table IR_Incident")
+				}
+			} else {
+				if err := addColumnToPGTable(e, "IR_Incident", "RetrospectiveTemplate", "TEXT DEFAULT ''"); err != nil {
+					return errors.Wrapf(err, "failed adding column RetrospectiveTemplate to table IR_Incident")
+				}
+			}
+
+			return nil
+		},
+	},
+	{
+		fromVersion: semver.MustParse("0.36.0"),
+		toVersion:   semver.MustParse("0.37.0"),
+		migrationFunc: func(e sqlx.Ext, sqlStore *SQLStore) error {
+			if e.DriverName() == model.DATABASE_DRIVER_MYSQL {
+				if _, err := e.Exec(`
+					CREATE TABLE IF NOT EXISTS IR_TimelineEventEdit
+					(
+						ID              VARCHAR(26) NOT NULL,
+						EventID         VARCHAR(26) NOT NULL,
+						IncidentID      VARCHAR(26) NOT NULL,
+						EditorUserID    VARCHAR(26) NOT NULL,
+						EditAt          BIGINT      NOT NULL,
+						PreviousEventAt BIGINT      NOT NULL,
+						PreviousSummary TEXT        NOT NULL,
+						PreviousDetails TEXT        NOT NULL,
+						PRIMARY KEY (ID),
+						INDEX IR_TimelineEventEdit_EventID (EventID)
+					)
+				` + MySQLCharset); err != nil {
+					return errors.Wrapf(err, "failed creating table IR_TimelineEventEdit")
+				}
+			} else {
+				if _, err := e.Exec(`
+					CREATE TABLE IF NOT EXISTS IR_TimelineEventEdit
+					(
+						ID              TEXT   PRIMARY KEY,
+						EventID         TEXT   NOT NULL,
+						IncidentID      TEXT   NOT NULL,
+						EditorUserID    TEXT   NOT NULL,
+						EditAt          BIGINT NOT NULL,
+						PreviousEventAt BIGINT NOT NULL,
+						PreviousSummary TEXT   NOT NULL,
+						PreviousDetails TEXT   NOT NULL
+					)
+				`); err != nil {
+					return errors.Wrapf(err, "failed creating table IR_TimelineEventEdit")
+				}
+
+				if _, err := e.Exec(createPGIndex("IR_TimelineEventEdit_EventID", "IR_TimelineEventEdit", "EventID")); err != nil {
+					return errors.Wrapf(err, "failed creating index IR_TimelineEventEdit_EventID")
+				}
+			}
+
+			return nil
+		},
+	},
+	{
+		fromVersion: semver.MustParse("0.37.0"),
+		toVersion:   semver.MustParse("0.38.0"),
+		migrationFunc: func(e sqlx.Ext, sqlStore *SQLStore) error {
+			// Indexes for listing the timeline of an incident page by page, in the order of
+			// the events and optionally filtered by event type.
+			indexes := []struct {
+				name    string
+				columns string
+			}{
+				{"IR_TimelineEvent_IncidentID_EventAt", "IncidentID, EventAt, ID"},
+				{"IR_TimelineEvent_IncidentID_EventType", "IncidentID, EventType"},
+			}
+
+			for _, index := range indexes {
+				if e.DriverName() == model.DATABASE_DRIVER_MYSQL {
+					if err := createMySQLIndex(e, index.name, "IR_TimelineEvent", index.columns); err != nil {
+						return errors.Wrapf(err, "failed creating index %s", index.name)
+					}
+				} else {
+					if _, err := e.Exec(createPGIndex(index.name, "IR_TimelineEvent", index.columns)); err != nil {
+						return errors.Wrapf(err, "failed creating index %s", index.name)
+					}
+				}
+			}
+
+			return nil
+		},
+	},
+	{
+		fromVersion: semver.MustParse("0.38.0"),
+		toVersion:   semver.MustParse("0.39.0"),
+		migrationFunc: func(e sqlx.Ext, sqlStore *SQLStore) error {
+			// Index for finding the incident started for an alert by its playbook and fingerprint.
+			if e.DriverName() == model.DATABASE_DRIVER_MYSQL {
+				// AlertFingerprint is a TEXT column, so MySQL only indexes a prefix of it.
+				if err := createMySQLIndex(e, "IR_Incident_PlaybookID_AlertFingerprint", "IR_Incident", "PlaybookID, AlertFingerprint(255)"); err != nil {
+					return errors.Wrapf(err, "failed creating index IR_Incident_PlaybookID_AlertFingerprint")
+				}
+			} else {
+				if _, err := e.Exec(createPGIndex("IR_Incident_PlaybookID_AlertFingerprint", "IR_Incident", "PlaybookID, AlertFingerprint")); err != nil {
+					return errors.Wrapf(err, "failed creating index IR_Incident_PlaybookID_AlertFingerprint")
+				}
+			}
+
 			return nil
 		},
 	},
@@ -0,0 +1,206 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/bot"
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/oncall"
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/pkg/errors"
+)
+
+type sqlRotation struct {
+	oncall.Rotation
+	LayersJSON    json.RawMessage
+	OverridesJSON json.RawMessage
+}
+
+// rotationStore is a sql store for on-call rotations. Use NewRotationStore to create it.
+type rotationStore struct {
+	pluginAPI      PluginAPIClient
+	log            bot.Logger
+	store          *SQLStore
+	queryBuilder   sq.StatementBuilderType
+	rotationSelect sq.SelectBuilder
+}
+
+// Ensure rotationStore implements the oncall.Store interface.
+var _ oncall.Store = (*rotationStore)(nil)
+
+// NewRotationStore creates a new store for the on-call service.
+func NewRotationStore(pluginAPI PluginAPIClient, log bot.Logger, sqlStore *SQLStore) oncall.Store {
+	rotationSelect := sqlStore.builder.
+		Select("ID", "TeamID", "Name", "Description", "LayersJSON", "OverridesJSON",
+			"CreateAt", "UpdateAt", "DeleteAt").
+		From("IR_OnCallRotation")
+
+	return &rotationStore{
+		pluginAPI:      pluginAPI,
+		log:            log,
+		store:          sqlStore,
+		queryBuilder:   sqlStore.builder,
+		rotationSelect: rotationSelect,
+	}
+}
+
+// Create creates a new rotation
+func (r *rotationStore) Create(rotation oncall.Rotation) (string, error) {
+	if rotation.ID != "" {
+		return "", errors.New("ID should be empty")
+	}
+	rotation.ID = model.NewId()
+
+	rawRotation, err := toSQLRotation(rotation)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = r.store.execBuilder(r.store.db, sq.
+		Insert("IR_OnCallRotation").
+		SetMap(map[string]interface{}{
+			"ID":            rawRotation.ID,
+			"TeamID":        rawRotation.TeamID,
+			"Name":          rawRotation.Name,
+			"Description":   rawRotation.Description,
+			"LayersJSON":    rawRotation.LayersJSON,
+			"OverridesJSON": rawRotation.OverridesJSON,
+			"CreateAt":      rawRotation.CreateAt,
+			"UpdateAt":      rawRotation.UpdateAt,
+			"DeleteAt":      rawRotation.DeleteAt,
+		}))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to store new rotation")
+	}
+
+	return rawRotation.ID, nil
+}
+
+// Get retrieves a rotation
+func (r *rotationStore) Get(id string) (oncall.Rotation, error) {
+	if id == "" {
+		return oncall.Rotation{}, errors.New("ID cannot be empty")
+	}
+
+	var rawRotation sqlRotation
+	err := r.store.getBuilder(r.store.db, &rawRotation, r.rotationSelect.Where(sq.Eq{"ID": id}))
+	if err == sql.ErrNoRows {
+		return oncall.Rotation{}, errors.Wrapf(oncall.ErrNotFound, "rotation does not exist for id '%s'", id)
+	} else if err != nil {
+		return oncall.Rotation{}, errors.Wrapf(err, "failed to get rotation by id '%s'", id)
+	}
+
+	return toRotation(rawRotation)
+}
+
+// GetRotationsForTeam retrieves the rotations of a team that are not deleted.
+func (r *rotationStore) GetRotationsForTeam(teamID string) ([]oncall.Rotation, error) {
+	var rawRotations []sqlRotation
+	err := r.store.selectBuilder(r.store.db, &rawRotations, r.rotationSelect.
+		Where(sq.Eq{"TeamID": teamID}).
+		Where(sq.Eq{"DeleteAt": 0}).
+		OrderBy("Name ASC"))
+	if err == sql.ErrNoRows {
+		return []oncall.Rotation{}, nil
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "failed to get rotations for team '%s'", teamID)
+	}
+
+	rotations := make([]oncall.Rotation, 0, len(rawRotations))
+	for _, rawRotation := range rawRotations {
+		rotation, err := toRotation(rawRotation)
+		if err != nil {
+			return nil, err
+		}
+		rotations = append(rotations, rotation)
+	}
+
+	return rotations, nil
+}
+
+// Update updates a rotation
+func (r *rotationStore) Update(rotation oncall.Rotation) error {
+	if rotation.ID == "" {
+		return errors.New("id should not be empty")
+	}
+
+	rawRotation, err := toSQLRotation(rotation)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.store.execBuilder(r.store.db, sq.
+		Update("IR_OnCallRotation").
+		SetMap(map[string]interface{}{
+			"Name":          rawRotation.Name,
+			"Description":   rawRotation.Description,
+			"LayersJSON":    rawRotation.LayersJSON,
+			"OverridesJSON": rawRotation.OverridesJSON,
+			"UpdateAt":      rawRotation.UpdateAt,
+			"DeleteAt":      rawRotation.DeleteAt,
+		}).
+		Where(sq.Eq{"ID": rawRotation.ID}))
+	if err != nil {
+		return errors.Wrapf(err, "failed to update rotation with id '%s'", rawRotation.ID)
+	}
+
+	return nil
+}
+
+// Delete deletes a rotation.
+func (r *rotationStore) Delete(id string) error {
+	if id == "" {
+		return errors.New("ID cannot be empty")
+	}
+
+	_, err := r.store.execBuilder(r.store.db, sq.
+		Update("IR_OnCallRotation").
+		Set("DeleteAt", model.GetMillis()).
+		Where(sq.Eq{"ID": id}))
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete rotation with id '%s'", id)
+	}
+
+	return nil
+}
+
+func toSQLRotation(rotation oncall.Rotation) (*sqlRotation, error) {
+	layersJSON, err := json.Marshal(rotation.Layers)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal layers json for rotation id: '%s'", rotation.ID)
+	}
+
+	overridesJSON, err := json.Marshal(rotation.Overrides)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal overrides json for rotation id: '%s'", rotation.ID)
+	}
+
+	return &sqlRotation{
+		Rotation:      rotation,
+		LayersJSON:    layersJSON,
+		OverridesJSON: overridesJSON,
+	}, nil
+}
+
+func toRotation(rawRotation sqlRotation) (oncall.Rotation, error) {
+	rotation := rawRotation.Rotation
+
+	var layers []oncall.Layer
+	if err := json.Unmarshal(rawRotation.LayersJSON, &layers); err != nil {
+		return oncall.Rotation{}, errors.Wrapf(err, "failed to unmarshal layers json for rotation id: '%s'", rotation.ID)
+	}
+	if len(layers) > 0 {
+		rotation.Layers = layers
+	}
+
+	var overrides []oncall.Override
+	if err := json.Unmarshal(rawRotation.OverridesJSON, &overrides); err != nil {
+		return oncall.Rotation{}, errors.Wrapf(err, "failed to unmarshal overrides json for rotation id: '%s'", rotation.ID)
+	}
+	if len(overrides) > 0 {
+		rotation.Overrides = overrides
+	}
+
+	return rotation, nil
+}
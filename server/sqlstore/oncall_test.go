@@ -0,0 +1,113 @@
+package sqlstore
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jmoiron/sqlx"
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/oncall"
+	mock_sqlstore "github.com/mattermost/mattermost-plugin-incident-collaboration/server/sqlstore/mocks"
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotationStore(t *testing.T) {
+	team1 := model.NewId()
+	team2 := model.NewId()
+	alice := model.NewId()
+	bob := model.NewId()
+
+	for _, driverName := range driverNames {
+		db := setupTestDB(t, driverName)
+		rStore := setupRotationStore(t, db)
+
+		primary := oncall.Rotation{
+			TeamID:      team1,
+			Name:        "Primary",
+			Description: "Weekly rotation of the backend team",
+			Layers: []oncall.Layer{{
+				Name:               "Weekly",
+				UserIDs:            []string{alice, bob},
+				StartAt:            1000,
+				ShiftLengthSeconds: 7 * 24 * 60 * 60,
+			}},
+			CreateAt: 1000,
+			UpdateAt: 1000,
+		}
+		secondary := oncall.Rotation{
+			TeamID:   team1,
+			Name:     "Secondary",
+			CreateAt: 2000,
+			UpdateAt: 2000,
+		}
+		otherTeam := oncall.Rotation{
+			TeamID:   team2,
+			Name:     "Other team",
+			CreateAt: 3000,
+			UpdateAt: 3000,
+		}
+
+		t.Run(driverName+" - create and get", func(t *testing.T) {
+			for _, rotation := range []*oncall.Rotation{&primary, &secondary, &otherTeam} {
+				id, err := rStore.Create(*rotation)
+				require.NoError(t, err)
+				rotation.ID = id
+			}
+
+			actual, err := rStore.Get(primary.ID)
+			require.NoError(t, err)
+			require.Equal(t, primary, actual)
+
+			actual, err = rStore.Get(secondary.ID)
+			require.NoError(t, err)
+			require.Equal(t, secondary, actual)
+		})
+
+		t.Run(driverName+" - get unknown rotation", func(t *testing.T) {
+			_, err := rStore.Get(model.NewId())
+			require.True(t, errors.Is(err, oncall.ErrNotFound))
+		})
+
+		t.Run(driverName+" - update", func(t *testing.T) {
+			primary.Overrides = []oncall.Override{{ID: model.NewId(), UserID: bob, StartAt: 5000, EndAt: 6000}}
+			primary.UpdateAt = 4000
+			require.NoError(t, rStore.Update(primary))
+
+			actual, err := rStore.Get(primary.ID)
+			require.NoError(t, err)
+			require.Equal(t, primary, actual)
+		})
+
+		t.Run(driverName+" - get rotations for team, without the deleted ones", func(t *testing.T) {
+			rotations, err := rStore.GetRotationsForTeam(team1)
+			require.NoError(t, err)
+			require.Equal(t, []oncall.Rotation{primary, secondary}, rotations)
+
+			require.NoError(t, rStore.Delete(secondary.ID))
+
+			rotations, err = rStore.GetRotationsForTeam(team1)
+			require.NoError(t, err)
+			require.Equal(t, []oncall.Rotation{primary}, rotations)
+
+			rotations, err = rStore.GetRotationsForTeam(model.NewId())
+			require.NoError(t, err)
+			require.Empty(t, rotations)
+		})
+	}
+}
+
+func setupRotationStore(t *testing.T, db *sqlx.DB) oncall.Store {
+	mockCtrl := gomock.NewController(t)
+
+	kvAPI := mock_sqlstore.NewMockKVAPI(mockCtrl)
+	configAPI := mock_sqlstore.NewMockConfigurationAPI(mockCtrl)
+	pluginAPIClient := PluginAPIClient{
+		KV:            kvAPI,
+		Configuration: configAPI,
+	}
+
+	logger, sqlStore := setupSQLStore(t, db)
+
+	return NewRotationStore(pluginAPIClient, logger, sqlStore)
+}
@@ -17,8 +17,14 @@ import (
 type sqlPlaybook struct {
 	playbook.Playbook
 	ChecklistsJSON              json.RawMessage
+	WebhookSubscriptionsJSON    json.RawMessage
+	CustomFieldsJSON            json.RawMessage
+	RolesJSON                   json.RawMessage
+	EscalationStepsJSON         json.RawMessage
+	BroadcastTargetsJSON        json.RawMessage
 	ConcatenatedInvitedUserIDs  string
 	ConcatenatedInvitedGroupIDs string
+	ConcatenatedAutoFollowerIDs string
 }
 
 // playbookStore is a sql store for playbooks. Use NewPlaybookStore to create it.
@@ -46,14 +52,22 @@ func NewPlaybookStore(pluginAPI PluginAPIClient, log bot.Logger, sqlStore *SQLSt
 			"DeleteAt", "NumStages", "NumSteps", "BroadcastChannelID",
 			"COALESCE(ReminderMessageTemplate, '') ReminderMessageTemplate", "ReminderTimerDefaultSeconds",
 			"ConcatenatedInvitedUserIDs", "ConcatenatedInvitedGroupIDs", "InviteUsersEnabled",
+			"COALESCE(ConcatenatedAutoFollowerIDs, '') ConcatenatedAutoFollowerIDs",
 			"DefaultCommanderID AS DefaultOwnerID", "DefaultCommanderEnabled AS DefaultOwnerEnabled",
+			"COALESCE(DefaultOwnerRotationID, '') DefaultOwnerRotationID",
 			"AnnouncementChannelID", "AnnouncementChannelEnabled",
 			"WebhookOnCreationURL", "WebhookOnCreationEnabled",
 			"MessageOnJoin", "MessageOnJoinEnabled",
 			"RetrospectiveReminderIntervalSeconds",
 			"RetrospectiveTemplate",
 			"WebhookOnStatusUpdateURL",
-			"WebhookOnStatusUpdateEnabled").
+			"WebhookOnStatusUpdateEnabled",
+			"WebhookSecret",
+			"InboundWebhookEnabled", "InboundWebhookToken",
+			"InboundWebhookNameTemplate", "InboundWebhookDescriptionTemplate",
+			"InboundWebhookOwnerTemplate", "InboundWebhookFingerprintTemplate",
+			"InboundWebhookAutoResolve", "Revision", "AcknowledgeTimeoutSeconds",
+			"COALESCE(StatusUpdateTemplate, '') StatusUpdateTemplate").
 		From("IR_Playbook")
 
 	memberIDsSelect := sqlStore.builder.
@@ -106,10 +120,12 @@ func (p *playbookStore) Create(pbook playbook.Playbook) (id string, err error) {
 			"ReminderMessageTemplate":              rawPlaybook.ReminderMessageTemplate,
 			"ReminderTimerDefaultSeconds":          rawPlaybook.ReminderTimerDefaultSeconds,
 			"ConcatenatedInvitedUserIDs":           rawPlaybook.ConcatenatedInvitedUserIDs,
+			"ConcatenatedAutoFollowerIDs":          rawPlaybook.ConcatenatedAutoFollowerIDs,
 			"ConcatenatedInvitedGroupIDs":          rawPlaybook.ConcatenatedInvitedGroupIDs,
 			"InviteUsersEnabled":                   rawPlaybook.InviteUsersEnabled,
 			"DefaultCommanderID":                   rawPlaybook.DefaultOwnerID,
 			"DefaultCommanderEnabled":              rawPlaybook.DefaultOwnerEnabled,
+			"DefaultOwnerRotationID":               rawPlaybook.DefaultOwnerRotationID,
 			"AnnouncementChannelID":                rawPlaybook.AnnouncementChannelID,
 			"AnnouncementChannelEnabled":           rawPlaybook.AnnouncementChannelEnabled,
 			"WebhookOnCreationURL":                 rawPlaybook.WebhookOnCreationURL,
@@ -120,6 +136,21 @@ func (p *playbookStore) Create(pbook playbook.Playbook) (id string, err error) {
 			"RetrospectiveTemplate":                rawPlaybook.RetrospectiveTemplate,
 			"WebhookOnStatusUpdateURL":             rawPlaybook.WebhookOnStatusUpdateURL,
 			"WebhookOnStatusUpdateEnabled":         rawPlaybook.WebhookOnStatusUpdateEnabled,
+			"WebhookSecret":                        rawPlaybook.WebhookSecret,
+			"WebhookSubscriptionsJSON":             rawPlaybook.WebhookSubscriptionsJSON,
+			"CustomFieldsJSON":                     rawPlaybook.CustomFieldsJSON,
+			"RolesJSON":                            rawPlaybook.RolesJSON,
+			"AcknowledgeTimeoutSeconds":            rawPlaybook.AcknowledgeTimeoutSeconds,
+			"EscalationStepsJSON":                  rawPlaybook.EscalationStepsJSON,
+			"BroadcastTargetsJSON":                 rawPlaybook.BroadcastTargetsJSON,
+			"StatusUpdateTemplate":                 rawPlaybook.StatusUpdateTemplate,
+			"InboundWebhookEnabled":                rawPlaybook.InboundWebhookEnabled,
+			"InboundWebhookToken":                  rawPlaybook.InboundWebhookToken,
+			"InboundWebhookNameTemplate":           rawPlaybook.InboundWebhookNameTemplate,
+			"InboundWebhookDescriptionTemplate":    rawPlaybook.InboundWebhookDescriptionTemplate,
+			"InboundWebhookOwnerTemplate":          rawPlaybook.InboundWebhookOwnerTemplate,
+			"InboundWebhookFingerprintTemplate":    rawPlaybook.InboundWebhookFingerprintTemplate,
+			"InboundWebhookAutoResolve":            rawPlaybook.InboundWebhookAutoResolve,
 		}))
 	if err != nil {
 		return "", errors.Wrap(err, "failed to store new playbook")
@@ -149,7 +180,9 @@ func (p *playbookStore) Get(id string) (out playbook.Playbook, err error) {
 	defer p.store.finalizeTransaction(tx)
 
 	withChecklistsSelect := p.playbookSelect.
-		Columns("ChecklistsJSON").
+		Columns("ChecklistsJSON", "WebhookSubscriptionsJSON", "COALESCE(CustomFieldsJSON, '[]') CustomFieldsJSON",
+			"COALESCE(RolesJSON, '[]') RolesJSON", "COALESCE(EscalationStepsJSON, '[]') EscalationStepsJSON",
+			"COALESCE(BroadcastTargetsJSON, '[]') BroadcastTargetsJSON").
 		From("IR_Playbook")
 
 	var rawPlaybook sqlPlaybook
@@ -325,10 +358,12 @@ func (p *playbookStore) Update(updated playbook.Playbook) (err error) {
 			"ReminderMessageTemplate":              rawPlaybook.ReminderMessageTemplate,
 			"ReminderTimerDefaultSeconds":          rawPlaybook.ReminderTimerDefaultSeconds,
 			"ConcatenatedInvitedUserIDs":           rawPlaybook.ConcatenatedInvitedUserIDs,
+			"ConcatenatedAutoFollowerIDs":          rawPlaybook.ConcatenatedAutoFollowerIDs,
 			"ConcatenatedInvitedGroupIDs":          rawPlaybook.ConcatenatedInvitedGroupIDs,
 			"InviteUsersEnabled":                   rawPlaybook.InviteUsersEnabled,
 			"DefaultCommanderID":                   rawPlaybook.DefaultOwnerID,
 			"DefaultCommanderEnabled":              rawPlaybook.DefaultOwnerEnabled,
+			"DefaultOwnerRotationID":               rawPlaybook.DefaultOwnerRotationID,
 			"AnnouncementChannelID":                rawPlaybook.AnnouncementChannelID,
 			"AnnouncementChannelEnabled":           rawPlaybook.AnnouncementChannelEnabled,
 			"WebhookOnCreationURL":                 rawPlaybook.WebhookOnCreationURL,
@@ -339,6 +374,21 @@ func (p *playbookStore) Update(updated playbook.Playbook) (err error) {
 			"RetrospectiveTemplate":                rawPlaybook.RetrospectiveTemplate,
 			"WebhookOnStatusUpdateURL":             rawPlaybook.WebhookOnStatusUpdateURL,
 			"WebhookOnStatusUpdateEnabled":         rawPlaybook.WebhookOnStatusUpdateEnabled,
+			"WebhookSecret":                        rawPlaybook.WebhookSecret,
+			"WebhookSubscriptionsJSON":             rawPlaybook.WebhookSubscriptionsJSON,
+			"CustomFieldsJSON":                     rawPlaybook.CustomFieldsJSON,
+			"RolesJSON":                            rawPlaybook.RolesJSON,
+			"AcknowledgeTimeoutSeconds":            rawPlaybook.AcknowledgeTimeoutSeconds,
+			"EscalationStepsJSON":                  rawPlaybook.EscalationStepsJSON,
+			"BroadcastTargetsJSON":                 rawPlaybook.BroadcastTargetsJSON,
+			"StatusUpdateTemplate":                 rawPlaybook.StatusUpdateTemplate,
+			"InboundWebhookEnabled":                rawPlaybook.InboundWebhookEnabled,
+			"InboundWebhookToken":                  rawPlaybook.InboundWebhookToken,
+			"InboundWebhookNameTemplate":           rawPlaybook.InboundWebhookNameTemplate,
+			"InboundWebhookDescriptionTemplate":    rawPlaybook.InboundWebhookDescriptionTemplate,
+			"InboundWebhookOwnerTemplate":          rawPlaybook.InboundWebhookOwnerTemplate,
+			"InboundWebhookFingerprintTemplate":    rawPlaybook.InboundWebhookFingerprintTemplate,
+			"InboundWebhookAutoResolve":            rawPlaybook.InboundWebhookAutoResolve,
 		}).
 		Where(sq.Eq{"ID": rawPlaybook.ID}))
 
@@ -443,11 +493,42 @@ func toSQLPlaybook(origPlaybook playbook.Playbook) (*sqlPlaybook, error) {
 		return nil, errors.Wrapf(err, "failed to marshal checklist json for incident id: '%s'", origPlaybook.ID)
 	}
 
+	webhookSubscriptionsJSON, err := json.Marshal(origPlaybook.WebhookSubscriptions)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal webhook subscriptions json for playbook id: '%s'", origPlaybook.ID)
+	}
+
+	customFieldsJSON, err := json.Marshal(origPlaybook.CustomFields)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal custom fields json for playbook id: '%s'", origPlaybook.ID)
+	}
+
+	rolesJSON, err := json.Marshal(origPlaybook.Roles)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal roles json for playbook id: '%s'", origPlaybook.ID)
+	}
+
+	escalationStepsJSON, err := json.Marshal(origPlaybook.EscalationSteps)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal escalation steps json for playbook id: '%s'", origPlaybook.ID)
+	}
+
+	broadcastTargetsJSON, err := json.Marshal(origPlaybook.BroadcastTargets)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal broadcast targets json for playbook id: '%s'", origPlaybook.ID)
+	}
+
 	return &sqlPlaybook{
 		Playbook:                    origPlaybook,
 		ChecklistsJSON:              checklistsJSON,
+		WebhookSubscriptionsJSON:    webhookSubscriptionsJSON,
+		CustomFieldsJSON:            customFieldsJSON,
+		RolesJSON:                   rolesJSON,
+		EscalationStepsJSON:         escalationStepsJSON,
+		BroadcastTargetsJSON:        broadcastTargetsJSON,
 		ConcatenatedInvitedUserIDs:  strings.Join(origPlaybook.InvitedUserIDs, ","),
 		ConcatenatedInvitedGroupIDs: strings.Join(origPlaybook.InvitedGroupIDs, ","),
+		ConcatenatedAutoFollowerIDs: strings.Join(origPlaybook.AutoFollowerIDs, ","),
 	}, nil
 }
 
@@ -457,6 +538,52 @@ func toPlaybook(rawPlaybook sqlPlaybook) (playbook.Playbook, error) {
 		return playbook.Playbook{}, errors.Wrapf(err, "failed to unmarshal checklists json for playbook id: '%s'", p.ID)
 	}
 
+	if len(rawPlaybook.WebhookSubscriptionsJSON) > 0 {
+		if err := json.Unmarshal(rawPlaybook.WebhookSubscriptionsJSON, &p.WebhookSubscriptions); err != nil {
+			return playbook.Playbook{}, errors.Wrapf(err, "failed to unmarshal webhook subscriptions json for playbook id: '%s'", p.ID)
+		}
+	}
+
+	if len(rawPlaybook.CustomFieldsJSON) > 0 {
+		var customFields []playbook.CustomField
+		if err := json.Unmarshal(rawPlaybook.CustomFieldsJSON, &customFields); err != nil {
+			return playbook.Playbook{}, errors.Wrapf(err, "failed to unmarshal custom fields json for playbook id: '%s'", p.ID)
+		}
+		if len(customFields) > 0 {
+			p.CustomFields = customFields
+		}
+	}
+
+	if len(rawPlaybook.RolesJSON) > 0 {
+		var roles []playbook.Role
+		if err := json.Unmarshal(rawPlaybook.RolesJSON, &roles); err != nil {
+			return playbook.Playbook{}, errors.Wrapf(err, "failed to unmarshal roles json for playbook id: '%s'", p.ID)
+		}
+		if len(roles) > 0 {
+			p.Roles = roles
+		}
+	}
+
+	if len(rawPlaybook.EscalationStepsJSON) > 0 {
+		var escalationSteps []playbook.EscalationStep
+		if err := json.Unmarshal(rawPlaybook.EscalationStepsJSON, &escalationSteps); err != nil {
+			return playbook.Playbook{}, errors.Wrapf(err, "failed to unmarshal escalation steps json for playbook id: '%s'", p.ID)
+		}
+		if len(escalationSteps) > 0 {
+			p.EscalationSteps = escalationSteps
+		}
+	}
+
+	if len(rawPlaybook.BroadcastTargetsJSON) > 0 {
+		var broadcastTargets []playbook.BroadcastTarget
+		if err := json.Unmarshal(rawPlaybook.BroadcastTargetsJSON, &broadcastTargets); err != nil {
+			return playbook.Playbook{}, errors.Wrapf(err, "failed to unmarshal broadcast targets json for playbook id: '%s'", p.ID)
+		}
+		if len(broadcastTargets) > 0 {
+			p.BroadcastTargets = broadcastTargets
+		}
+	}
+
 	p.InvitedUserIDs = []string(nil)
 	if rawPlaybook.ConcatenatedInvitedUserIDs != "" {
 		p.InvitedUserIDs = strings.Split(rawPlaybook.ConcatenatedInvitedUserIDs, ",")
@@ -467,6 +594,11 @@ func toPlaybook(rawPlaybook sqlPlaybook) (playbook.Playbook, error) {
 		p.InvitedGroupIDs = strings.Split(rawPlaybook.ConcatenatedInvitedGroupIDs, ",")
 	}
 
+	p.AutoFollowerIDs = []string(nil)
+	if rawPlaybook.ConcatenatedAutoFollowerIDs != "" {
+		p.AutoFollowerIDs = strings.Split(rawPlaybook.ConcatenatedAutoFollowerIDs, ",")
+	}
+
 	return p, nil
 }
 
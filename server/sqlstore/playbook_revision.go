@@ -0,0 +1,126 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/playbook"
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/pkg/errors"
+)
+
+type sqlPlaybookRevision struct {
+	playbook.Revision
+	PlaybookJSON json.RawMessage
+}
+
+// CreateRevision stores a revision of a playbook with a snapshot of pbook, numbered after the
+// latest revision, and makes it the playbook's latest revision.
+func (p *playbookStore) CreateRevision(pbook playbook.Playbook, userID string) (playbook.Revision, error) {
+	if pbook.ID == "" {
+		return playbook.Revision{}, errors.New("playbook ID should not be empty")
+	}
+
+	tx, err := p.store.db.Beginx()
+	if err != nil {
+		return playbook.Revision{}, errors.Wrap(err, "could not begin transaction")
+	}
+	defer p.store.finalizeTransaction(tx)
+
+	var latest int
+	err = p.store.getBuilder(tx, &latest, p.store.builder.
+		Select("COALESCE(MAX(Revision), 0)").
+		From("IR_PlaybookRevision").
+		Where(sq.Eq{"PlaybookID": pbook.ID}))
+	if err != nil {
+		return playbook.Revision{}, errors.Wrapf(err, "failed to get the latest revision of playbook with id '%s'", pbook.ID)
+	}
+
+	revision := playbook.Revision{
+		ID:         model.NewId(),
+		PlaybookID: pbook.ID,
+		Revision:   latest + 1,
+		UserID:     userID,
+		CreateAt:   model.GetMillis(),
+		Playbook:   pbook.Clone(),
+	}
+	revision.Playbook.Revision = revision.Revision
+
+	playbookJSON, err := json.Marshal(revision.Playbook)
+	if err != nil {
+		return playbook.Revision{}, errors.Wrapf(err, "failed to marshal revision of playbook with id '%s'", pbook.ID)
+	}
+
+	_, err = p.store.execBuilder(tx, sq.
+		Insert("IR_PlaybookRevision").
+		SetMap(map[string]interface{}{
+			"ID":           revision.ID,
+			"PlaybookID":   revision.PlaybookID,
+			"Revision":     revision.Revision,
+			"UserID":       revision.UserID,
+			"CreateAt":     revision.CreateAt,
+			"PlaybookJSON": playbookJSON,
+		}))
+	if err != nil {
+		return playbook.Revision{}, errors.Wrapf(err, "failed to store revision of playbook with id '%s'", pbook.ID)
+	}
+
+	_, err = p.store.execBuilder(tx, sq.
+		Update("IR_Playbook").
+		Set("Revision", revision.Revision).
+		Where(sq.Eq{"ID": pbook.ID}))
+	if err != nil {
+		return playbook.Revision{}, errors.Wrapf(err, "failed to update the revision of playbook with id '%s'", pbook.ID)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return playbook.Revision{}, errors.Wrap(err, "could not commit transaction")
+	}
+
+	return revision, nil
+}
+
+// GetRevisions retrieves the revisions of a playbook, latest first, without their snapshot of
+// the playbook.
+func (p *playbookStore) GetRevisions(playbookID string) ([]playbook.Revision, error) {
+	var revisions []playbook.Revision
+	err := p.store.selectBuilder(p.store.db, &revisions, p.store.builder.
+		Select("ID", "PlaybookID", "Revision", "UserID", "CreateAt").
+		From("IR_PlaybookRevision").
+		Where(sq.Eq{"PlaybookID": playbookID}).
+		OrderBy("Revision DESC"))
+	if err == sql.ErrNoRows {
+		return []playbook.Revision{}, nil
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "failed to get revisions of playbook with id '%s'", playbookID)
+	}
+
+	if revisions == nil {
+		revisions = []playbook.Revision{}
+	}
+
+	return revisions, nil
+}
+
+// GetRevision retrieves a revision of a playbook.
+func (p *playbookStore) GetRevision(playbookID string, revision int) (playbook.Revision, error) {
+	var rawRevision sqlPlaybookRevision
+	err := p.store.getBuilder(p.store.db, &rawRevision, p.store.builder.
+		Select("ID", "PlaybookID", "Revision", "UserID", "CreateAt", "PlaybookJSON").
+		From("IR_PlaybookRevision").
+		Where(sq.Eq{"PlaybookID": playbookID}).
+		Where(sq.Eq{"Revision": revision}))
+	if err == sql.ErrNoRows {
+		return playbook.Revision{}, errors.Wrapf(playbook.ErrNotFound, "revision %d of playbook with id '%s' does not exist", revision, playbookID)
+	} else if err != nil {
+		return playbook.Revision{}, errors.Wrapf(err, "failed to get revision %d of playbook with id '%s'", revision, playbookID)
+	}
+
+	out := rawRevision.Revision
+	if err = json.Unmarshal(rawRevision.PlaybookJSON, &out.Playbook); err != nil {
+		return playbook.Revision{}, errors.Wrapf(err, "failed to unmarshal revision %d of playbook with id '%s'", revision, playbookID)
+	}
+
+	return out, nil
+}
@@ -196,6 +196,33 @@ func TestGetPlaybook(t *testing.T) {
 			require.NoError(t, err)
 			require.Equal(t, expected, actual)
 		})
+
+		t.Run(driverName+" - set and retrieve playbook with webhook subscriptions", func(t *testing.T) {
+			pb11 := NewPBBuilder().
+				WithTitle("playbook 11").
+				WithTeamID(team1id).
+				WithCreateAt(900).
+				WithWebhookSubscriptions([]playbook.WebhookSubscription{
+					{URL: "https://example.com/owners", EventTypes: []string{"owner_changed"}},
+					{URL: "https://example.com/tasks", EventTypes: []string{"task_state_modified", "assignee_changed"}},
+				}).
+				ToPlaybook()
+			id, err := playbookStore.Create(pb11)
+			require.NoError(t, err)
+			expected := pb11.Clone()
+			expected.ID = id
+
+			actual, err := playbookStore.Get(id)
+			require.NoError(t, err)
+			require.Equal(t, expected, actual)
+
+			actual.WebhookSubscriptions = actual.WebhookSubscriptions[1:]
+			require.NoError(t, playbookStore.Update(actual))
+
+			updated, err := playbookStore.Get(id)
+			require.NoError(t, err)
+			require.Equal(t, actual.WebhookSubscriptions, updated.WebhookSubscriptions)
+		})
 	}
 }
 
@@ -1282,6 +1309,12 @@ func (p *PlaybookBuilder) WithMembers(members []userInfo) *PlaybookBuilder {
 	return p
 }
 
+func (p *PlaybookBuilder) WithWebhookSubscriptions(subscriptions []playbook.WebhookSubscription) *PlaybookBuilder {
+	p.WebhookSubscriptions = subscriptions
+
+	return p
+}
+
 func (p *PlaybookBuilder) ToPlaybook() playbook.Playbook {
 	return *p.Playbook
 }
@@ -0,0 +1,89 @@
+package sqlstore
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/incident"
+	"github.com/pkg/errors"
+)
+
+// GetTimelineEvents returns a page of the timeline of incidentID, filtered by options. Events
+// are ordered by time, then by ID, so that the cursor of a page is the position of its last
+// event, and the next page is found through the IR_TimelineEvent_IncidentID_EventAt index
+// however many events precede it.
+func (s *incidentStore) GetTimelineEvents(incidentID string, options incident.TimelineFilterOptions) (*incident.GetTimelineEventsResults, error) {
+	if err := incident.ValidateTimelineFilterOptions(&options); err != nil {
+		return nil, err
+	}
+
+	filter := sq.And{sq.Eq{"te.IncidentID": incidentID}, sq.Eq{"te.DeleteAt": 0}}
+
+	if len(options.EventTypes) > 0 {
+		eventTypes := append([]string{}, options.EventTypes...)
+		for _, eventType := range options.EventTypes {
+			if eventType == string(incident.OwnerChanged) {
+				eventTypes = append(eventTypes, legacyEventTypeCommanderChanged)
+			}
+		}
+		filter = append(filter, sq.Eq{"te.EventType": eventTypes})
+	}
+	if options.ExcludeUserJoinedLeft {
+		filter = append(filter, sq.NotEq{"te.EventType": incident.UserJoinedLeft})
+	}
+	if options.UserID != "" {
+		filter = append(filter, sq.Or{
+			sq.Eq{"te.SubjectUserID": options.UserID},
+			sq.Eq{"te.CreatorUserID": options.UserID},
+		})
+	}
+
+	order := []string{"te.EventAt ASC", "te.ID ASC"}
+	if options.Direction == incident.DirectionDesc {
+		order = []string{"te.EventAt DESC", "te.ID DESC"}
+	}
+
+	if options.Cursor != "" {
+		cursor, err := incident.ParseTimelineCursor(options.Cursor)
+		if err != nil {
+			return nil, errors.Wrap(err, "bad parameter 'cursor'")
+		}
+
+		if options.Direction == incident.DirectionDesc {
+			filter = append(filter, sq.Or{
+				sq.Lt{"te.EventAt": cursor.EventAt},
+				sq.And{sq.Eq{"te.EventAt": cursor.EventAt}, sq.Lt{"te.ID": cursor.ID}},
+			})
+		} else {
+			filter = append(filter, sq.Or{
+				sq.Gt{"te.EventAt": cursor.EventAt},
+				sq.And{sq.Eq{"te.EventAt": cursor.EventAt}, sq.Gt{"te.ID": cursor.ID}},
+			})
+		}
+	}
+
+	// Fetch one more event than requested to know whether there is a next page.
+	query := s.timelineEventsSelect.
+		Where(filter).
+		OrderBy(order...).
+		Limit(uint64(options.PerPage + 1))
+
+	var events []incident.TimelineEvent
+	err := s.store.selectBuilder(s.store.db, &events, query)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, errors.Wrapf(err, "failed to get timeline events for incident with id '%s'", incidentID)
+	}
+
+	results := &incident.GetTimelineEventsResults{Items: []incident.TimelineEvent{}}
+	if len(events) > options.PerPage {
+		results.HasMore = true
+		events = events[:options.PerPage]
+	}
+	if len(events) > 0 {
+		last := events[len(events)-1]
+		results.NextCursor = incident.TimelineCursor{EventAt: last.EventAt, ID: last.ID}.String()
+		results.Items = events
+	}
+
+	return results, nil
+}
@@ -0,0 +1,74 @@
+package sqlstore
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/incident"
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/pkg/errors"
+)
+
+// EditTimelineEvent updates event and stores edit, the record of its previous values, in the
+// same transaction.
+func (s *incidentStore) EditTimelineEvent(event *incident.TimelineEvent, edit *incident.TimelineEventEdit) error {
+	if edit.EventID != event.ID {
+		return errors.New("edit must record the previous values of event")
+	}
+	if edit.EditAt == 0 {
+		edit.EditAt = model.GetMillis()
+	}
+	edit.ID = model.NewId()
+
+	tx, err := s.store.db.Beginx()
+	if err != nil {
+		return errors.Wrap(err, "could not begin transaction")
+	}
+	defer s.store.finalizeTransaction(tx)
+
+	if err = s.updateTimelineEvent(tx, event); err != nil {
+		return err
+	}
+
+	_, err = s.store.execBuilder(tx, sq.
+		Insert("IR_TimelineEventEdit").
+		SetMap(map[string]interface{}{
+			"ID":              edit.ID,
+			"EventID":         edit.EventID,
+			"IncidentID":      edit.IncidentID,
+			"EditorUserID":    edit.EditorUserID,
+			"EditAt":          edit.EditAt,
+			"PreviousEventAt": edit.PreviousEventAt,
+			"PreviousSummary": edit.PreviousSummary,
+			"PreviousDetails": edit.PreviousDetails,
+		}))
+	if err != nil {
+		return errors.Wrap(err, "failed to insert timeline event edit")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.Wrap(err, "could not commit transaction")
+	}
+
+	return nil
+}
+
+// GetTimelineEventEdits returns the edits of the timeline event eventID, oldest first.
+func (s *incidentStore) GetTimelineEventEdits(eventID string) ([]incident.TimelineEventEdit, error) {
+	var edits []incident.TimelineEventEdit
+	err := s.store.selectBuilder(s.store.db, &edits, s.store.builder.
+		Select("ID", "EventID", "IncidentID", "EditorUserID", "EditAt",
+			"PreviousEventAt", "PreviousSummary", "PreviousDetails").
+		From("IR_TimelineEventEdit").
+		Where(sq.Eq{"EventID": eventID}).
+		OrderBy("EditAt ASC", "ID ASC"))
+	if err != nil && err != sql.ErrNoRows {
+		return nil, errors.Wrapf(err, "failed to get the edits of timeline event with id '%s'", eventID)
+	}
+
+	if edits == nil {
+		edits = []incident.TimelineEventEdit{}
+	}
+
+	return edits, nil
+}
@@ -4,6 +4,7 @@
 package sqlstore
 
 import (
+	"sort"
 	"testing"
 
 	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/incident"
@@ -200,3 +201,214 @@ func TestIncidentStore_UpdateTimelineEvent(t *testing.T) {
 		})
 	}
 }
+
+func TestIncidentStore_EditTimelineEvent(t *testing.T) {
+	for _, driverName := range driverNames {
+		db := setupTestDB(t, driverName)
+		iStore := setupIncidentStore(t, db)
+		_, store := setupSQLStore(t, db)
+		setupChannelsTable(t, db)
+		setupPostsTable(t, db)
+
+		t.Run("Edit a timeline event twice and retrieve the edits", func(t *testing.T) {
+			createAt := model.GetMillis()
+			inc01 := NewBuilder(nil).
+				WithName("incident 1").
+				WithCreateAt(createAt).
+				ToIncident()
+
+			incdnt, err := iStore.CreateIncident(inc01)
+			require.NoError(t, err)
+
+			createIncidentChannel(t, store, inc01)
+
+			event := &incident.TimelineEvent{
+				IncidentID:    incdnt.ID,
+				CreateAt:      createAt,
+				EventAt:       createAt - 1000,
+				EventType:     incident.ManualEvent,
+				Summary:       "customer reported",
+				SubjectUserID: "testuserID",
+				CreatorUserID: "testuserID",
+			}
+			_, err = iStore.CreateTimelineEvent(event)
+			require.NoError(t, err)
+
+			edits, err := iStore.GetTimelineEventEdits(event.ID)
+			require.NoError(t, err)
+			require.Empty(t, edits)
+
+			edit1 := &incident.TimelineEventEdit{
+				EventID:         event.ID,
+				IncidentID:      incdnt.ID,
+				EditorUserID:    "editor1",
+				EditAt:          createAt + 1,
+				PreviousEventAt: event.EventAt,
+				PreviousSummary: event.Summary,
+				PreviousDetails: event.Details,
+			}
+			event.EventAt = createAt - 2000
+			event.Summary = "customer reported an outage"
+			err = iStore.EditTimelineEvent(event, edit1)
+			require.NoError(t, err)
+
+			edit2 := &incident.TimelineEventEdit{
+				EventID:         event.ID,
+				IncidentID:      incdnt.ID,
+				EditorUserID:    "editor2",
+				EditAt:          createAt + 2,
+				PreviousEventAt: event.EventAt,
+				PreviousSummary: event.Summary,
+				PreviousDetails: event.Details,
+			}
+			event.Details = "by phone"
+			err = iStore.EditTimelineEvent(event, edit2)
+			require.NoError(t, err)
+
+			retIncident, err := iStore.GetIncident(incdnt.ID)
+			require.NoError(t, err)
+			require.Equal(t, []incident.TimelineEvent{*event}, retIncident.TimelineEvents)
+
+			edits, err = iStore.GetTimelineEventEdits(event.ID)
+			require.NoError(t, err)
+			require.Equal(t, []incident.TimelineEventEdit{*edit1, *edit2}, edits)
+		})
+
+		t.Run("Edit must belong to the event", func(t *testing.T) {
+			err := iStore.EditTimelineEvent(&incident.TimelineEvent{ID: "event"}, &incident.TimelineEventEdit{EventID: "other"})
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestIncidentStore_GetTimelineEvents(t *testing.T) {
+	for _, driverName := range driverNames {
+		db := setupTestDB(t, driverName)
+		iStore := setupIncidentStore(t, db)
+		_, store := setupSQLStore(t, db)
+		setupChannelsTable(t, db)
+		setupPostsTable(t, db)
+
+		createAt := model.GetMillis()
+		inc01 := NewBuilder(nil).
+			WithName("incident 1").
+			WithCreateAt(createAt).
+			ToIncident()
+
+		incdnt, err := iStore.CreateIncident(inc01)
+		require.NoError(t, err)
+
+		createIncidentChannel(t, store, inc01)
+
+		alice := model.NewId()
+		bob := model.NewId()
+
+		// Two events share each time, so that pages must be ordered by ID as well.
+		var events []incident.TimelineEvent
+		for i, eventType := range []incident.TimelineEventType{
+			incident.IncidentCreated,
+			incident.UserJoinedLeft,
+			incident.UserJoinedLeft,
+			incident.OwnerChanged,
+			incident.ManualEvent,
+			incident.UserJoinedLeft,
+		} {
+			event := &incident.TimelineEvent{
+				IncidentID:    incdnt.ID,
+				CreateAt:      createAt,
+				EventAt:       createAt + int64(i/2),
+				EventType:     eventType,
+				SubjectUserID: alice,
+			}
+			if eventType == incident.ManualEvent {
+				event.SubjectUserID = bob
+				event.CreatorUserID = bob
+			}
+			_, err = iStore.CreateTimelineEvent(event)
+			require.NoError(t, err)
+			events = append(events, *event)
+		}
+
+		removed := &incident.TimelineEvent{
+			IncidentID: incdnt.ID,
+			CreateAt:   createAt,
+			DeleteAt:   createAt,
+			EventAt:    createAt,
+			EventType:  incident.ManualEvent,
+		}
+		_, err = iStore.CreateTimelineEvent(removed)
+		require.NoError(t, err)
+
+		sortEvents := func(events []incident.TimelineEvent) []incident.TimelineEvent {
+			sorted := append([]incident.TimelineEvent{}, events...)
+			sort.Slice(sorted, func(i, j int) bool {
+				if sorted[i].EventAt != sorted[j].EventAt {
+					return sorted[i].EventAt < sorted[j].EventAt
+				}
+				return sorted[i].ID < sorted[j].ID
+			})
+			return sorted
+		}
+
+		getAll := func(t *testing.T, options incident.TimelineFilterOptions) []incident.TimelineEvent {
+			var all []incident.TimelineEvent
+			for {
+				results, err := iStore.GetTimelineEvents(incdnt.ID, options)
+				require.NoError(t, err)
+				require.LessOrEqual(t, len(results.Items), options.PerPage)
+				all = append(all, results.Items...)
+				if !results.HasMore {
+					return all
+				}
+				options.Cursor = results.NextCursor
+			}
+		}
+
+		t.Run("pages follow each other", func(t *testing.T) {
+			require.Equal(t, sortEvents(events), getAll(t, incident.TimelineFilterOptions{PerPage: 2}))
+			require.Equal(t, sortEvents(events), getAll(t, incident.TimelineFilterOptions{PerPage: 4}))
+
+			desc := getAll(t, incident.TimelineFilterOptions{PerPage: 4, Direction: incident.DirectionDesc})
+			expected := sortEvents(events)
+			for i, j := 0, len(expected)-1; i < j; i, j = i+1, j-1 {
+				expected[i], expected[j] = expected[j], expected[i]
+			}
+			require.Equal(t, expected, desc)
+		})
+
+		t.Run("last page", func(t *testing.T) {
+			results, err := iStore.GetTimelineEvents(incdnt.ID, incident.TimelineFilterOptions{PerPage: len(events)})
+			require.NoError(t, err)
+			require.False(t, results.HasMore)
+			require.Len(t, results.Items, len(events))
+
+			results, err = iStore.GetTimelineEvents(incdnt.ID, incident.TimelineFilterOptions{Cursor: results.NextCursor})
+			require.NoError(t, err)
+			require.False(t, results.HasMore)
+			require.Empty(t, results.Items)
+			require.Empty(t, results.NextCursor)
+		})
+
+		t.Run("filters", func(t *testing.T) {
+			withoutJoinedLeft := getAll(t, incident.TimelineFilterOptions{PerPage: 1, ExcludeUserJoinedLeft: true})
+			require.Equal(t, sortEvents([]incident.TimelineEvent{events[0], events[3], events[4]}), withoutJoinedLeft)
+
+			owner := getAll(t, incident.TimelineFilterOptions{PerPage: 1, EventTypes: []string{string(incident.OwnerChanged)}})
+			require.Equal(t, []incident.TimelineEvent{events[3]}, owner)
+
+			byBob := getAll(t, incident.TimelineFilterOptions{PerPage: 1, UserID: bob})
+			require.Equal(t, []incident.TimelineEvent{events[4]}, byBob)
+
+			aliceJoined := getAll(t, incident.TimelineFilterOptions{PerPage: 1, UserID: alice, EventTypes: []string{string(incident.UserJoinedLeft)}})
+			require.Equal(t, sortEvents([]incident.TimelineEvent{events[1], events[2], events[5]}), aliceJoined)
+		})
+
+		t.Run("bad options", func(t *testing.T) {
+			_, err := iStore.GetTimelineEvents(incdnt.ID, incident.TimelineFilterOptions{Cursor: "not a cursor"})
+			require.Error(t, err)
+
+			_, err = iStore.GetTimelineEvents(incdnt.ID, incident.TimelineFilterOptions{EventTypes: []string{"unknown"}})
+			require.Error(t, err)
+		})
+	}
+}
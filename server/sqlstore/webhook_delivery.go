@@ -0,0 +1,159 @@
+package sqlstore
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/incident"
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/pkg/errors"
+)
+
+// CreateWebhookDelivery stores a new outgoing webhook delivery and returns it with its new ID.
+func (s *incidentStore) CreateWebhookDelivery(delivery *incident.WebhookDelivery) (*incident.WebhookDelivery, error) {
+	if delivery.IncidentID == "" {
+		return nil, errors.New("needs incident ID")
+	}
+	if delivery.URL == "" {
+		return nil, errors.New("needs URL")
+	}
+	if delivery.CreateAt == 0 {
+		delivery.CreateAt = model.GetMillis()
+	}
+	delivery.ID = model.NewId()
+
+	_, err := s.store.execBuilder(s.store.db, sq.
+		Insert("IR_WebhookDelivery").
+		SetMap(map[string]interface{}{
+			"ID":            delivery.ID,
+			"IncidentID":    delivery.IncidentID,
+			"EventType":     delivery.EventType,
+			"URL":           delivery.URL,
+			"Payload":       delivery.Payload,
+			"Status":        delivery.Status,
+			"NumAttempts":   delivery.NumAttempts,
+			"NextAttemptAt": delivery.NextAttemptAt,
+			"CreateAt":      delivery.CreateAt,
+			"UpdateAt":      delivery.UpdateAt,
+		}))
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to insert webhook delivery")
+	}
+
+	return delivery, nil
+}
+
+// UpdateWebhookDelivery updates the status and retry information of an existing webhook delivery.
+func (s *incidentStore) UpdateWebhookDelivery(delivery *incident.WebhookDelivery) error {
+	if delivery.ID == "" {
+		return errors.New("needs delivery ID")
+	}
+
+	_, err := s.store.execBuilder(s.store.db, sq.
+		Update("IR_WebhookDelivery").
+		SetMap(map[string]interface{}{
+			"Status":        delivery.Status,
+			"NumAttempts":   delivery.NumAttempts,
+			"NextAttemptAt": delivery.NextAttemptAt,
+			"UpdateAt":      delivery.UpdateAt,
+		}).
+		Where(sq.Eq{"ID": delivery.ID}))
+
+	if err != nil {
+		return errors.Wrapf(err, "failed to update webhook delivery with id '%s'", delivery.ID)
+	}
+
+	return nil
+}
+
+// GetWebhookDelivery gets a webhook delivery by ID, without its attempts.
+func (s *incidentStore) GetWebhookDelivery(deliveryID string) (*incident.WebhookDelivery, error) {
+	var delivery incident.WebhookDelivery
+
+	err := s.store.getBuilder(s.store.db, &delivery, s.webhookDeliverySelect.Where(sq.Eq{"ID": deliveryID}))
+	if err == sql.ErrNoRows {
+		return nil, errors.Wrapf(incident.ErrNotFound, "webhook delivery with id '%s' does not exist", deliveryID)
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "failed to get webhook delivery with id '%s'", deliveryID)
+	}
+
+	return &delivery, nil
+}
+
+// GetWebhookDeliveries returns the webhook deliveries of incidentID with their attempts,
+// most recent first.
+func (s *incidentStore) GetWebhookDeliveries(incidentID string) ([]incident.WebhookDelivery, error) {
+	tx, err := s.store.db.Beginx()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not begin transaction")
+	}
+	defer s.store.finalizeTransaction(tx)
+
+	var deliveries []incident.WebhookDelivery
+	err = s.store.selectBuilder(tx, &deliveries, s.webhookDeliverySelect.
+		Where(sq.Eq{"IncidentID": incidentID}).
+		OrderBy("CreateAt DESC"))
+	if err != nil && err != sql.ErrNoRows {
+		return nil, errors.Wrapf(err, "failed to get webhook deliveries for incident with id '%s'", incidentID)
+	}
+
+	var attempts []incident.WebhookDeliveryAttempt
+	err = s.store.selectBuilder(tx, &attempts, s.webhookDeliveryAttemptSelect.
+		Where(sq.Eq{"IncidentID": incidentID}).
+		OrderBy("Attempt ASC"))
+	if err != nil && err != sql.ErrNoRows {
+		return nil, errors.Wrapf(err, "failed to get webhook delivery attempts for incident with id '%s'", incidentID)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "could not commit transaction")
+	}
+
+	deliveryToAttempts := make(map[string][]incident.WebhookDeliveryAttempt)
+	for _, attempt := range attempts {
+		deliveryToAttempts[attempt.DeliveryID] = append(deliveryToAttempts[attempt.DeliveryID], attempt)
+	}
+	for i, delivery := range deliveries {
+		deliveries[i].Attempts = deliveryToAttempts[delivery.ID]
+	}
+
+	if deliveries == nil {
+		deliveries = []incident.WebhookDelivery{}
+	}
+
+	return deliveries, nil
+}
+
+// CreateWebhookDeliveryAttempt records an attempt to deliver a webhook.
+func (s *incidentStore) CreateWebhookDeliveryAttempt(attempt *incident.WebhookDeliveryAttempt) error {
+	if attempt.DeliveryID == "" {
+		return errors.New("needs delivery ID")
+	}
+	if attempt.IncidentID == "" {
+		return errors.New("needs incident ID")
+	}
+	if attempt.CreateAt == 0 {
+		attempt.CreateAt = model.GetMillis()
+	}
+	attempt.ID = model.NewId()
+
+	_, err := s.store.execBuilder(s.store.db, sq.
+		Insert("IR_WebhookDeliveryAttempt").
+		SetMap(map[string]interface{}{
+			"ID":         attempt.ID,
+			"DeliveryID": attempt.DeliveryID,
+			"IncidentID": attempt.IncidentID,
+			"Attempt":    attempt.Attempt,
+			"StatusCode": attempt.StatusCode,
+			"LatencyMS":  attempt.LatencyMS,
+			"Error":      attempt.Error,
+			"CreateAt":   attempt.CreateAt,
+		}))
+
+	if err != nil {
+		return errors.Wrap(err, "failed to insert webhook delivery attempt")
+	}
+
+	return nil
+}
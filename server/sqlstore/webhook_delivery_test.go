@@ -0,0 +1,110 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-plugin-incident-collaboration/server/incident"
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIncidentStore_WebhookDeliveries(t *testing.T) {
+	for _, driverName := range driverNames {
+		db := setupTestDB(t, driverName)
+		iStore := setupIncidentStore(t, db)
+		_, store := setupSQLStore(t, db)
+		setupChannelsTable(t, db)
+		setupPostsTable(t, db)
+
+		t.Run("Save, update and retrieve deliveries with their attempts", func(t *testing.T) {
+			createAt := model.GetMillis()
+			inc01 := NewBuilder(nil).
+				WithName("incident 1").
+				WithCreateAt(createAt).
+				ToIncident()
+
+			incdnt, err := iStore.CreateIncident(inc01)
+			require.NoError(t, err)
+
+			createIncidentChannel(t, store, inc01)
+
+			delivery1, err := iStore.CreateWebhookDelivery(&incident.WebhookDelivery{
+				IncidentID:    incdnt.ID,
+				EventType:     incident.WebhookEventIncidentCreated,
+				URL:           "https://example.com/created",
+				Payload:       `{"id": "1"}`,
+				Status:        incident.WebhookDeliveryPending,
+				NextAttemptAt: createAt,
+				CreateAt:      createAt,
+				UpdateAt:      createAt,
+			})
+			require.NoError(t, err)
+			require.NotEmpty(t, delivery1.ID)
+
+			delivery2, err := iStore.CreateWebhookDelivery(&incident.WebhookDelivery{
+				IncidentID:    incdnt.ID,
+				EventType:     incident.WebhookEventStatusUpdated,
+				URL:           "https://example.com/updated",
+				Payload:       `{"id": "2"}`,
+				Status:        incident.WebhookDeliveryPending,
+				NextAttemptAt: createAt + 1,
+				CreateAt:      createAt + 1,
+				UpdateAt:      createAt + 1,
+			})
+			require.NoError(t, err)
+
+			attempt1 := &incident.WebhookDeliveryAttempt{
+				DeliveryID: delivery1.ID,
+				IncidentID: incdnt.ID,
+				Attempt:    1,
+				StatusCode: 503,
+				LatencyMS:  120,
+				Error:      "response code is 503; expected a status code in the 2xx range",
+				CreateAt:   createAt + 2,
+			}
+			require.NoError(t, iStore.CreateWebhookDeliveryAttempt(attempt1))
+
+			attempt2 := &incident.WebhookDeliveryAttempt{
+				DeliveryID: delivery1.ID,
+				IncidentID: incdnt.ID,
+				Attempt:    2,
+				StatusCode: 200,
+				LatencyMS:  80,
+				CreateAt:   createAt + 3,
+			}
+			require.NoError(t, iStore.CreateWebhookDeliveryAttempt(attempt2))
+
+			delivery1.Status = incident.WebhookDeliveryDelivered
+			delivery1.NumAttempts = 2
+			delivery1.NextAttemptAt = 0
+			delivery1.UpdateAt = createAt + 3
+			require.NoError(t, iStore.UpdateWebhookDelivery(delivery1))
+
+			retrieved, err := iStore.GetWebhookDelivery(delivery1.ID)
+			require.NoError(t, err)
+			require.Equal(t, delivery1, retrieved)
+
+			deliveries, err := iStore.GetWebhookDeliveries(incdnt.ID)
+			require.NoError(t, err)
+			require.Len(t, deliveries, 2)
+
+			delivery1.Attempts = []incident.WebhookDeliveryAttempt{*attempt1, *attempt2}
+			require.Equal(t, *delivery2, deliveries[0])
+			require.Equal(t, *delivery1, deliveries[1])
+		})
+
+		t.Run("Get a non-existing delivery", func(t *testing.T) {
+			_, err := iStore.GetWebhookDelivery(model.NewId())
+			require.ErrorIs(t, err, incident.ErrNotFound)
+		})
+
+		t.Run("No deliveries for an incident", func(t *testing.T) {
+			deliveries, err := iStore.GetWebhookDeliveries(model.NewId())
+			require.NoError(t, err)
+			require.Empty(t, deliveries)
+		})
+	}
+}
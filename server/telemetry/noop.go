@@ -46,6 +46,14 @@ func (t *NoopTelemetry) AddPostToTimeline(*incident.Incident, string) {
 func (t *NoopTelemetry) RemoveTimelineEvent(*incident.Incident, string) {
 }
 
+// AddTimelineEvent does nothing
+func (t *NoopTelemetry) AddTimelineEvent(*incident.Incident, string) {
+}
+
+// EditTimelineEvent does nothing
+func (t *NoopTelemetry) EditTimelineEvent(*incident.Incident, string) {
+}
+
 // AddTask does nothing.
 func (t *NoopTelemetry) AddTask(string, string, playbook.ChecklistItem) {
 }
@@ -34,6 +34,8 @@ const (
 	actionUpdateRetrospective      = "update_retrospective"
 	actionPublishRetrospective     = "publish_retrospective"
 	actionRemoveTimelineEvent      = "remove_timeline_event"
+	actionAddTimelineEvent         = "add_timeline_event"
+	actionEditTimelineEvent        = "edit_timeline_event"
 
 	eventTasks                = "tasks"
 	actionAddTask             = "add_task"
@@ -200,6 +202,20 @@ func (t *RudderTelemetry) RemoveTimelineEvent(incdnt *incident.Incident, userID
 	t.track(eventIncident, properties)
 }
 
+// AddTimelineEvent tracks userID creating a timeline event that is not tied to a post.
+func (t *RudderTelemetry) AddTimelineEvent(incdnt *incident.Incident, userID string) {
+	properties := incidentProperties(incdnt, userID)
+	properties["Action"] = actionAddTimelineEvent
+	t.track(eventIncident, properties)
+}
+
+// EditTimelineEvent tracks userID editing a timeline event.
+func (t *RudderTelemetry) EditTimelineEvent(incdnt *incident.Incident, userID string) {
+	properties := incidentProperties(incdnt, userID)
+	properties["Action"] = actionEditTimelineEvent
+	t.track(eventIncident, properties)
+}
+
 func taskProperties(incidentID, userID string, task playbook.ChecklistItem) map[string]interface{} {
 	return map[string]interface{}{
 		"IncidentID":     incidentID,
@@ -280,6 +296,8 @@ func (t *RudderTelemetry) UpdateRetrospective(incident *incident.Incident, userI
 func (t *RudderTelemetry) PublishRetrospective(incident *incident.Incident, userID string) {
 	properties := incidentProperties(incident, userID)
 	properties["Action"] = actionPublishRetrospective
+	properties["HasRetrospectiveSections"] = !incident.RetrospectiveSections.IsEmpty()
+	properties["NumActionItems"] = len(incident.ActionItems)
 	t.track(eventTasks, properties)
 }
 
@@ -308,9 +326,12 @@ func playbookProperties(pbook playbook.Playbook, userID string) map[string]inter
 		"NumSlashCommands":            totalChecklistItemsWithCommands,
 		"NumMembers":                  len(pbook.MemberIDs),
 		"BroadcastChannelID":          pbook.BroadcastChannelID,
+		"NumBroadcastTargets":         len(pbook.BroadcastTargets),
 		"UsesReminderMessageTemplate": pbook.ReminderMessageTemplate != "",
+		"UsesStatusUpdateTemplate":    pbook.StatusUpdateTemplate != "",
 		"ReminderTimerDefaultSeconds": pbook.ReminderTimerDefaultSeconds,
 		"NumInvitedUserIDs":           len(pbook.InvitedUserIDs),
+		"NumAutoFollowers":            len(pbook.AutoFollowerIDs),
 		"NumInvitedGroupIDs":          len(pbook.InvitedGroupIDs),
 		"InviteUsersEnabled":          pbook.InviteUsersEnabled,
 		"DefaultCommanderID":          pbook.DefaultOwnerID,
@@ -180,6 +180,12 @@ const RHSTimelineEventItem = (props: Props) => {
         summaryTitle = 'Retrospective canceled by ' + props.event.subject_display_name;
         testid = TimelineEventType.CanceledRetrospective;
         break;
+    case TimelineEventType.ManualEvent:
+        iconClass = 'icon icon-pencil-outline';
+        summaryTitle = props.event.summary;
+        summary = props.event.details;
+        testid = TimelineEventType.ManualEvent;
+        break;
     }
 
     return (
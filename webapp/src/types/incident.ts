@@ -3,7 +3,7 @@
 
 import {TimelineEvent, TimelineEventType} from 'src/types/rhs';
 
-import {Checklist, isChecklist} from './playbook';
+import {Checklist, CustomField, isChecklist, Role} from './playbook';
 
 export interface Incident {
     id: string;
@@ -20,7 +20,18 @@ export interface Incident {
     active_stage_title: string;
     post_id: string;
     playbook_id: string;
+    playbook_revision: number;
     checklists: Checklist[];
+    custom_fields: CustomField[];
+    custom_field_values: Record<string, string[]>;
+    roles: Role[];
+    role_assignments: Record<string, string[]>;
+    acknowledged_at: number;
+    escalation_level: number;
+    links: LinkedIncident[];
+    merged_into_incident_id: string;
+    follower_ids: string[];
+    status_update_template: string;
     status_posts: StatusPost[];
     current_status: IncidentStatus;
     reminder_post_id: string;
@@ -30,6 +41,70 @@ export interface Incident {
     retrospective_published_at: number;
     retrospective_was_canceled: boolean;
     retrospective_reminder_interval_seconds: number;
+    retrospective_sections: RetrospectiveSections;
+    action_items: ActionItem[];
+    retrospective_template: string;
+}
+
+export enum LinkType {
+    Related = 'related',
+    DuplicateOf = 'duplicate_of',
+    DuplicatedBy = 'duplicated_by',
+    ParentOf = 'parent_of',
+    ChildOf = 'child_of',
+}
+
+export interface LinkedIncident {
+    link_id: string;
+    type: LinkType;
+    incident_id: string;
+    name: string;
+    channel_id: string;
+    current_status: IncidentStatus;
+    creator_user_id: string;
+    create_at: number;
+}
+
+export interface RetrospectiveSections {
+    summary: string;
+    root_cause: string;
+    impact: string;
+    what_went_well: string;
+}
+
+export interface ActionItem {
+    id: string;
+    incident_id: string;
+    title: string;
+    owner_user_id: string;
+    due_at: number;
+    completed_at: number;
+    overdue_notified: boolean;
+    creator_user_id: string;
+    create_at: number;
+    update_at: number;
+}
+
+export enum ActionItemStatus {
+    Open = 'open',
+    Done = 'done',
+    Overdue = 'overdue',
+}
+
+export interface FetchActionItemsParams {
+    team_id: string;
+    page?: number;
+    per_page?: number;
+    incident_id?: string;
+    owner_user_id?: string;
+    status?: ActionItemStatus;
+}
+
+export interface FetchActionItemsReturn {
+    total_count: number;
+    page_count: number;
+    has_more: boolean;
+    items: ActionItem[];
 }
 
 export interface StatusPost {
@@ -60,6 +135,7 @@ export enum IncidentStatus {
     Active = 'Active',
     Resolved = 'Resolved',
     Archived = 'Archived',
+    Merged = 'Merged',
     Old = '',
 }
 
@@ -135,7 +211,7 @@ export function incidentCurrentStatus(incident: Incident): IncidentStatus {
 
 export function incidentIsActive(incident: Incident): boolean {
     const currentStatus = incidentCurrentStatus(incident);
-    return currentStatus !== IncidentStatus.Archived;
+    return currentStatus !== IncidentStatus.Archived && currentStatus !== IncidentStatus.Merged;
 }
 
 export interface FetchIncidentsParams {
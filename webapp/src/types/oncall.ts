@@ -0,0 +1,35 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+export interface Rotation {
+    id?: string;
+    team_id: string;
+    name: string;
+    description: string;
+    layers: RotationLayer[];
+    overrides: RotationOverride[];
+    create_at?: number;
+    update_at?: number;
+    delete_at?: number;
+}
+
+export interface RotationLayer {
+    name: string;
+    user_ids: string[];
+    start_at: number;
+    end_at: number;
+    shift_length_seconds: number;
+}
+
+export interface RotationOverride {
+    id?: string;
+    user_id: string;
+    start_at: number;
+    end_at: number;
+}
+
+export interface Shift {
+    user_id: string;
+    start_at: number;
+    end_at: number;
+}
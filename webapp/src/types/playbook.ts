@@ -12,11 +12,13 @@ export interface Playbook {
     broadcast_channel_id: string;
     reminder_message_template: string;
     reminder_timer_default_seconds: number;
+    status_update_template: string;
     invited_user_ids: string[];
     invited_group_ids: string[];
     invite_users_enabled: boolean;
     default_owner_id: string;
     default_owner_enabled: boolean;
+    default_owner_rotation_id?: string;
     announcement_channel_id: string;
     announcement_channel_enabled: boolean;
     webhook_on_creation_url: string;
@@ -27,6 +29,13 @@ export interface Playbook {
     message_on_join_enabled: boolean;
     retrospective_reminder_interval_seconds: number;
     retrospective_template: string;
+    custom_fields?: CustomField[];
+    roles?: Role[];
+    acknowledge_timeout_seconds?: number;
+    escalation_steps?: EscalationStep[];
+    broadcast_targets?: BroadcastTarget[];
+    auto_follower_ids?: string[];
+    revision?: number;
 }
 
 export interface PlaybookNoChecklist {
@@ -61,6 +70,46 @@ export interface Checklist {
     items: ChecklistItem[];
 }
 
+export enum CustomFieldType {
+    Text = 'text',
+    Select = 'select',
+    Multiselect = 'multiselect',
+    User = 'user',
+    Number = 'number',
+}
+
+export interface CustomField {
+    key: string;
+    name: string;
+    description: string;
+    type: CustomFieldType;
+    options: string[];
+    required: boolean;
+}
+
+export interface Role {
+    key: string;
+    name: string;
+    description: string;
+}
+
+export interface EscalationStep {
+    user_ids: string[];
+    group_ids: string[];
+    reassign_owner: boolean;
+}
+
+export enum BroadcastFilter {
+    All = 'all',
+    StatusChanges = 'status_changes',
+}
+
+export interface BroadcastTarget {
+    channel_id: string;
+    filter: BroadcastFilter;
+    min_severity: string;
+}
+
 export enum ChecklistItemState {
     Open = '',
     InProgress = 'in_progress',
@@ -76,6 +125,7 @@ export interface ChecklistItem {
     assignee_id?: string;
     assignee_modified?: number;
     assignee_modified_post_id?: string;
+    assignee_role?: string;
     command: string;
     command_last_run: number;
 }
@@ -91,6 +141,7 @@ export function emptyPlaybook(): Playbook {
         broadcast_channel_id: '',
         reminder_message_template: '',
         reminder_timer_default_seconds: 0,
+        status_update_template: '',
         invited_user_ids: [],
         invited_group_ids: [],
         invite_users_enabled: false,
@@ -146,6 +197,7 @@ export function isPlaybook(arg: any): arg is Playbook {
         typeof arg.broadcast_channel_id === 'string' &&
         typeof arg.reminder_message_template == 'string' &&
         typeof arg.reminder_timer_default_seconds == 'number' &&
+        typeof arg.status_update_template === 'string' &&
         arg.invited_user_ids && Array.isArray(arg.invited_user_ids) && arg.checklists.every((id: any) => typeof id === 'string') &&
         arg.invited_group_ids && Array.isArray(arg.invited_group_ids) && arg.checklists.every((id: any) => typeof id === 'string') &&
         typeof arg.invite_users_enabled === 'boolean' &&
@@ -23,6 +23,10 @@ export enum TimelineEventType {
     UserJoinedLeft = 'user_joined_left',
     PublishedRetrospective = 'published_retrospective',
     CanceledRetrospective = 'canceled_retrospective',
+    OwnerAcknowledged = 'owner_acknowledged',
+    IncidentEscalated = 'incident_escalated',
+    IncidentMerged = 'incident_merged',
+    ManualEvent = 'manual_event',
 }
 
 export interface TimelineEvent {
@@ -40,6 +44,17 @@ export interface TimelineEvent {
     subject_display_name?: string;
 }
 
+export interface TimelineEventEdit {
+    id: string;
+    event_id: string;
+    incident_id: string;
+    editor_user_id: string;
+    edit_at: number;
+    previous_event_at: number;
+    previous_summary: string;
+    previous_details: string;
+}
+
 export interface TimelineEventsFilter {
     all: boolean;
     owner_changed: boolean;